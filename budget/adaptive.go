@@ -0,0 +1,202 @@
+package budget
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+// Feedback is implemented by budgets that adapt to downstream responses. The
+// executor reports the outcome of every attempt to each budget the policy
+// references, except aborts, which say nothing about downstream health.
+type Feedback interface {
+	Observe(ctx context.Context, key policy.PolicyKey, out classify.Outcome)
+}
+
+// adaptiveMinSamples is the number of outcomes an AdaptiveBudget needs in its
+// window before the success rate affects retries.
+const adaptiveMinSamples = 10
+
+// DefaultAdaptiveFloor is the success rate at or below which an AdaptiveBudget
+// suppresses retries entirely.
+const DefaultAdaptiveFloor = 0.5
+
+// AdaptiveBudget is a token bucket that scales with the downstream success
+// rate, so retries stop during sustained outages and come back as the
+// dependency recovers.
+//
+// Outcomes are reported through Observe: successes count as healthy, retryable
+// failures as unhealthy, and other outcomes are ignored. Over a sliding window,
+// the success rate above the floor sets a scale from 0 to 1 that multiplies
+// both the refill rate and the usable capacity. At or below the floor, retries
+// and hedges are denied with ReasonAdaptiveSuppressed. Windows with fewer than
+// ten outcomes are treated as healthy. Primary attempts (attempt 0 of kind
+// KindRetry) are always allowed and consume no tokens, so they keep measuring
+// the dependency while retries are suppressed.
+type AdaptiveBudget struct {
+	mu sync.Mutex
+
+	capacity        float64
+	refillPerSecond float64
+	floor           float64
+
+	tokens   float64
+	last     time.Time
+	outcomes window // successes, failures
+
+	now func() time.Time
+}
+
+// NewAdaptiveBudget creates an AdaptiveBudget holding up to capacity tokens,
+// refilled at up to refillPerSecond, that measures the success rate over
+// window. A non-positive window defaults to 10s.
+func NewAdaptiveBudget(capacity int, refillPerSecond float64, window time.Duration) *AdaptiveBudget {
+	if capacity < 0 {
+		capacity = 0
+	}
+	if math.IsNaN(refillPerSecond) || math.IsInf(refillPerSecond, 0) || refillPerSecond < 0 {
+		refillPerSecond = 0
+	}
+	return &AdaptiveBudget{
+		capacity:        float64(capacity),
+		refillPerSecond: refillPerSecond,
+		floor:           DefaultAdaptiveFloor,
+		tokens:          float64(capacity),
+		outcomes:        newWindow(window),
+		now:             time.Now,
+	}
+}
+
+// SetFloor sets the success rate (0..1) at or below which retries are
+// suppressed. The default is DefaultAdaptiveFloor.
+func (b *AdaptiveBudget) SetFloor(rate float64) {
+	if math.IsNaN(rate) || rate < 0 {
+		rate = 0
+	}
+	if rate > 1 {
+		rate = 1
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.floor = rate
+}
+
+// Observe records an attempt outcome.
+func (b *AdaptiveBudget) Observe(_ context.Context, _ policy.PolicyKey, out classify.Outcome) {
+	if b == nil {
+		return
+	}
+	var i int
+	switch out.Kind {
+	case classify.OutcomeSuccess:
+		i = 0
+	case classify.OutcomeRetryable:
+		i = 1
+	default:
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.outcomes.add(b.now(), i, 1)
+}
+
+func (b *AdaptiveBudget) AllowAttempt(_ context.Context, _ policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	return b.allow(attemptIdx, kind, ref, true)
+}
+
+// PeekAttempt reports whether AllowAttempt would allow the attempt, without
+// taking tokens.
+func (b *AdaptiveBudget) PeekAttempt(_ context.Context, _ policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	return b.allow(attemptIdx, kind, ref, false)
+}
+
+func (b *AdaptiveBudget) allow(attemptIdx int, kind AttemptKind, ref policy.BudgetRef, consume bool) Decision {
+	if b == nil {
+		return Decision{Allowed: false, Reason: ReasonBudgetNil}
+	}
+	if attemptIdx == 0 && kind == KindRetry {
+		return Decision{Allowed: true, Reason: ReasonAllowed}
+	}
+
+	need := tokenCost(ref)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	scale := b.scale(now)
+	tokens := b.refilled(now, scale)
+	if consume {
+		b.tokens, b.last = tokens, now
+	}
+
+	if scale <= 0 {
+		return Decision{Allowed: false, Reason: ReasonAdaptiveSuppressed}
+	}
+	if tokens < need {
+		return Decision{Allowed: false, Reason: ReasonBudgetDenied}
+	}
+	if consume {
+		b.tokens -= need
+	}
+	return Decision{Allowed: true, Reason: ReasonAllowed}
+}
+
+// SuccessRate returns the success rate over the current window and whether
+// the window holds enough outcomes for it to take effect.
+func (b *AdaptiveBudget) SuccessRate() (rate float64, ok bool) {
+	if b == nil {
+		return 1, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.successRate(b.now())
+}
+
+func (b *AdaptiveBudget) successRate(now time.Time) (float64, bool) {
+	successes, failures := b.outcomes.sums(now)
+	total := successes + failures
+	if total < adaptiveMinSamples {
+		return 1, false
+	}
+	return successes / total, true
+}
+
+// scale maps the success rate above the floor to 0..1.
+func (b *AdaptiveBudget) scale(now time.Time) float64 {
+	rate, ok := b.successRate(now)
+	if !ok {
+		return 1
+	}
+	if b.floor >= 1 {
+		if rate >= 1 {
+			return 1
+		}
+		return 0
+	}
+	s := (rate - b.floor) / (1 - b.floor)
+	if s < 0 {
+		return 0
+	}
+	return s
+}
+
+// refilled returns the tokens at now, refilled at the scaled rate since the
+// last refill and capped at the scaled capacity, without updating the bucket.
+func (b *AdaptiveBudget) refilled(now time.Time, scale float64) float64 {
+	tokens := b.tokens
+	if math.IsNaN(tokens) || math.IsInf(tokens, 0) {
+		tokens = 0
+	}
+	if !b.last.IsZero() && now.After(b.last) {
+		tokens += now.Sub(b.last).Seconds() * b.refillPerSecond * scale
+	}
+	if limit := b.capacity * scale; tokens > limit {
+		tokens = limit
+	}
+	return tokens
+}
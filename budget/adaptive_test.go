@@ -0,0 +1,100 @@
+package budget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+func observeN(b *AdaptiveBudget, kind classify.OutcomeKind, n int) {
+	for i := 0; i < n; i++ {
+		b.Observe(context.Background(), policy.PolicyKey{}, classify.Outcome{Kind: kind})
+	}
+}
+
+func TestAdaptiveBudget_SuppressesDuringOutage(t *testing.T) {
+	now := time.Unix(100, 0)
+	b := NewAdaptiveBudget(10, 1, 10*time.Second)
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+	key := policy.PolicyKey{Name: "k"}
+
+	if d := b.AllowAttempt(ctx, key, 1, KindRetry, policy.BudgetRef{}); !d.Allowed {
+		t.Fatalf("retry denied while healthy: %+v", d)
+	}
+
+	observeN(b, classify.OutcomeRetryable, 20)
+	d := b.AllowAttempt(ctx, key, 1, KindRetry, policy.BudgetRef{})
+	if d.Allowed || d.Reason != ReasonAdaptiveSuppressed {
+		t.Fatalf("decision=%+v, want suppressed during outage", d)
+	}
+	if d := b.AllowAttempt(ctx, key, 0, KindRetry, policy.BudgetRef{}); !d.Allowed {
+		t.Fatalf("primary attempt denied during outage: %+v", d)
+	}
+	if rate, ok := b.SuccessRate(); !ok || rate != 0 {
+		t.Fatalf("rate=%v ok=%v, want 0 true", rate, ok)
+	}
+
+	// Recovery: the failures age out and successes restore retries.
+	now = now.Add(11 * time.Second)
+	observeN(b, classify.OutcomeSuccess, 20)
+	if d := b.AllowAttempt(ctx, key, 1, KindHedge, policy.BudgetRef{}); !d.Allowed {
+		t.Fatalf("retry denied after recovery: %+v", d)
+	}
+}
+
+func TestAdaptiveBudget_ScalesCapacityWithSuccessRate(t *testing.T) {
+	now := time.Unix(100, 0)
+	b := NewAdaptiveBudget(10, 0, 10*time.Second)
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// 75% success with floor 0.5 halves the usable capacity.
+	observeN(b, classify.OutcomeSuccess, 15)
+	observeN(b, classify.OutcomeRetryable, 5)
+	observeN(b, classify.OutcomeNonRetryable, 50)
+
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		if b.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}).Allowed {
+			allowed++
+		}
+	}
+	if allowed != 5 {
+		t.Fatalf("allowed=%d, want 5", allowed)
+	}
+}
+
+func TestAdaptiveBudget_FewSamplesAreHealthy(t *testing.T) {
+	b := NewAdaptiveBudget(1, 0, time.Second)
+	observeN(b, classify.OutcomeRetryable, adaptiveMinSamples-1)
+	if rate, ok := b.SuccessRate(); ok || rate != 1 {
+		t.Fatalf("rate=%v ok=%v, want 1 false", rate, ok)
+	}
+	if d := b.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); !d.Allowed {
+		t.Fatalf("retry denied below min samples: %+v", d)
+	}
+}
+
+func TestAdaptiveBudget_SetFloorAndNil(t *testing.T) {
+	b := NewAdaptiveBudget(10, 0, time.Second)
+	b.SetFloor(0)
+	observeN(b, classify.OutcomeSuccess, 1)
+	observeN(b, classify.OutcomeRetryable, 9)
+	if d := b.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); !d.Allowed {
+		t.Fatalf("retry denied at 10%% success with floor 0: %+v", d)
+	}
+	b.SetFloor(2)
+	if b.floor != 1 {
+		t.Fatalf("floor=%v, want 1", b.floor)
+	}
+
+	var nilBudget *AdaptiveBudget
+	nilBudget.Observe(context.Background(), policy.PolicyKey{}, classify.Outcome{})
+	if d := nilBudget.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); d.Allowed || d.Reason != ReasonBudgetNil {
+		t.Fatalf("decision=%+v, want denied with reason %q", d, ReasonBudgetNil)
+	}
+}
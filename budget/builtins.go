@@ -16,21 +16,60 @@ func (UnlimitedBudget) AllowAttempt(_ context.Context, _ policy.PolicyKey, _ int
 	return Decision{Allowed: true, Reason: ReasonAllowed}
 }
 
+// PeekAttempt always allows.
+func (UnlimitedBudget) PeekAttempt(_ context.Context, _ policy.PolicyKey, _ int, _ AttemptKind, _ policy.BudgetRef) Decision {
+	return Decision{Allowed: true, Reason: ReasonAllowed}
+}
+
 // TokenBucketBudget is a simple token-bucket implementation.
 //
 // It starts full (capacity tokens) and refills at refillPerSecond tokens/second.
 // Each attempt consumes ref.Cost tokens (defaulting to 1).
+//
+// With a warmup (see TokenBucketConfig), the bucket starts empty and its
+// capacity ramps up from zero, so a fresh process cannot spend a full retry
+// budget during startup.
+//
+// With a priority reserve configured (see SetPriorityReserve), the bottom of the
+// bucket is held back for higher-priority attempts, so low-priority traffic is
+// shed first as the bucket drains.
 type TokenBucketBudget struct {
 	mu sync.Mutex
 
 	capacity        float64
 	refillPerSecond float64
+	reserve         float64 // fraction of capacity
+	warmup          time.Duration
+	start           time.Time
 
 	tokens float64
 	last   time.Time
+
+	now func() time.Time
+}
+
+// TokenBucketConfig configures a TokenBucketBudget.
+type TokenBucketConfig struct {
+	// Rate is the steady-state refill rate in tokens per second.
+	Rate float64
+
+	// Burst is the bucket capacity: the most tokens that can be spent at once
+	// after a quiet period.
+	Burst int
+
+	// Warmup, when positive, ramps the capacity linearly from zero to Burst
+	// over this period after creation. The bucket starts empty and refills at
+	// Rate, capped by the ramped capacity.
+	Warmup time.Duration
 }
 
 func NewTokenBucketBudget(capacity int, refillPerSecond float64) *TokenBucketBudget {
+	return NewTokenBucketBudgetWithConfig(TokenBucketConfig{Rate: refillPerSecond, Burst: capacity})
+}
+
+// NewTokenBucketBudgetWithConfig creates a TokenBucketBudget from cfg.
+func NewTokenBucketBudgetWithConfig(cfg TokenBucketConfig) *TokenBucketBudget {
+	capacity, refillPerSecond := cfg.Burst, cfg.Rate
 	if capacity < 0 {
 		capacity = 0
 	}
@@ -40,16 +79,43 @@ func NewTokenBucketBudget(capacity int, refillPerSecond float64) *TokenBucketBud
 	if math.IsNaN(refillPerSecond) || math.IsInf(refillPerSecond, 0) {
 		refillPerSecond = 0
 	}
+	warmup := cfg.Warmup
+	if warmup < 0 {
+		warmup = 0
+	}
+	now := time.Now()
 	b := &TokenBucketBudget{
 		capacity:        float64(capacity),
 		refillPerSecond: refillPerSecond,
+		warmup:          warmup,
+		start:           now,
 		tokens:          float64(capacity),
-		last:            time.Now(),
+		last:            now,
+		now:             time.Now,
+	}
+	if warmup > 0 {
+		b.tokens = 0
 	}
 	return b
 }
 
-func (b *TokenBucketBudget) AllowAttempt(_ context.Context, _ policy.PolicyKey, _ int, _ AttemptKind, ref policy.BudgetRef) Decision {
+// SetPriorityReserve holds back fraction (0..1) of the bucket capacity from
+// lower-priority attempts. Low-priority attempts may not drain the bucket below
+// the full reserve, normal-priority attempts below half of it, and high-priority
+// attempts may use every token. Priority is read with policy.PriorityFromContext.
+func (b *TokenBucketBudget) SetPriorityReserve(fraction float64) {
+	if math.IsNaN(fraction) || fraction < 0 {
+		fraction = 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reserve = fraction
+}
+
+func (b *TokenBucketBudget) AllowAttempt(ctx context.Context, _ policy.PolicyKey, _ int, _ AttemptKind, ref policy.BudgetRef) Decision {
 	if b == nil {
 		return Decision{Allowed: false, Reason: ReasonBudgetNil}
 	}
@@ -57,44 +123,103 @@ func (b *TokenBucketBudget) AllowAttempt(_ context.Context, _ policy.PolicyKey,
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	now := time.Now()
-	// Sanity check state
-	if math.IsNaN(b.tokens) || math.IsInf(b.tokens, 0) {
-		b.tokens = 0
+	now := b.now()
+	capacity := b.capacityAt(now)
+	b.tokens = b.refilled(now, capacity)
+	b.last = now
+
+	need := tokenCost(ref)
+	d := b.check(ctx, b.tokens, capacity, need)
+	if d.Allowed {
+		b.tokens -= need
 	}
+	return d
+}
 
+// PeekAttempt reports whether AllowAttempt would allow the attempt, without
+// taking tokens.
+func (b *TokenBucketBudget) PeekAttempt(ctx context.Context, _ policy.PolicyKey, _ int, _ AttemptKind, ref policy.BudgetRef) Decision {
+	if b == nil {
+		return Decision{Allowed: false, Reason: ReasonBudgetNil}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	capacity := b.capacityAt(now)
+	return b.check(ctx, b.refilled(now, capacity), capacity, tokenCost(ref))
+}
+
+// check decides an attempt needing need tokens from a bucket holding tokens.
+func (b *TokenBucketBudget) check(ctx context.Context, tokens, capacity, need float64) Decision {
+	if tokens < need {
+		return Decision{Allowed: false, Reason: ReasonBudgetDenied}
+	}
+	if b.reserve > 0 && tokens-need < b.reserveFor(ctx, capacity) {
+		return Decision{Allowed: false, Reason: ReasonPriorityShed}
+	}
+	return Decision{Allowed: true, Reason: ReasonAllowed}
+}
+
+// refilled returns the tokens in the bucket at now without updating it.
+func (b *TokenBucketBudget) refilled(now time.Time, capacity float64) float64 {
 	if b.last.IsZero() {
-		b.tokens = b.capacity
-		b.last = now
-	} else if b.refillPerSecond > 0 && !now.Before(b.last) {
-		elapsed := now.Sub(b.last).Seconds()
-		added := elapsed * b.refillPerSecond
+		return capacity
+	}
+	tokens := b.tokens
+	// Sanity check state
+	if math.IsNaN(tokens) || math.IsInf(tokens, 0) {
+		tokens = 0
+	}
+	if b.refillPerSecond > 0 && !now.Before(b.last) {
+		added := now.Sub(b.last).Seconds() * b.refillPerSecond
 		if math.IsNaN(added) || math.IsInf(added, 0) || added < 0 {
 			added = 0
 		}
-
-		b.tokens += added
-		if b.tokens > b.capacity {
-			b.tokens = b.capacity
-		}
-		b.last = now
-	} else {
-		// Advance last on skew or no refill.
-		b.last = now
+		tokens += added
+	}
+	if tokens > capacity {
+		tokens = capacity
 	}
+	return tokens
+}
 
-	cost := 1
+// tokenCost returns the tokens an attempt uses: ref.Cost, defaulting to 1.
+func tokenCost(ref policy.BudgetRef) float64 {
 	if ref.Cost > 0 {
-		cost = ref.Cost
+		return float64(ref.Cost)
 	}
-	need := float64(cost)
-	if need <= 0 {
-		need = 1
+	return 1
+}
+
+// capacityAt returns the bucket capacity at now, ramped during warmup.
+func (b *TokenBucketBudget) capacityAt(now time.Time) float64 {
+	if b.warmup <= 0 {
+		return b.capacity
+	}
+	elapsed := now.Sub(b.start)
+	if elapsed >= b.warmup {
+		b.warmup = 0
+		return b.capacity
 	}
+	if elapsed <= 0 {
+		return 0
+	}
+	return b.capacity * float64(elapsed) / float64(b.warmup)
+}
 
-	if b.tokens >= need {
-		b.tokens -= need
-		return Decision{Allowed: true, Reason: ReasonAllowed}
+// reserveFor returns the tokens that must remain after an attempt at the
+// context's priority.
+func (b *TokenBucketBudget) reserveFor(ctx context.Context, capacity float64) float64 {
+	reserve := b.reserve * capacity
+	p, _ := policy.PriorityFromContext(ctx)
+	switch p.Rank() {
+	case -1:
+		return reserve
+	case 0:
+		return reserve / 2
+	default:
+		return 0
 	}
-	return Decision{Allowed: false, Reason: ReasonBudgetDenied}
 }
@@ -97,3 +97,111 @@ func TestTokenBucketBudget_InvalidConfig(t *testing.T) {
 		t.Fatalf("expected denied attempt with zero capacity")
 	}
 }
+
+func TestTokenBucketBudget_PriorityReserve(t *testing.T) {
+	b := NewTokenBucketBudget(10, 0)
+	b.SetPriorityReserve(0.4) // 4 tokens held back from low, 2 from normal.
+
+	low := policy.WithPriority(context.Background(), policy.PriorityLow)
+	high := policy.WithPriority(context.Background(), policy.PriorityHigh)
+	ref := policy.BudgetRef{Cost: 1}
+
+	allowed := 0
+	for b.AllowAttempt(low, policy.PolicyKey{}, 0, KindRetry, ref).Allowed {
+		allowed++
+	}
+	if allowed != 6 {
+		t.Fatalf("low allowed=%d, want 6", allowed)
+	}
+	if d := b.AllowAttempt(low, policy.PolicyKey{}, 0, KindRetry, ref); d.Reason != ReasonPriorityShed {
+		t.Fatalf("reason=%q, want %q", d.Reason, ReasonPriorityShed)
+	}
+
+	allowed = 0
+	for b.AllowAttempt(context.Background(), policy.PolicyKey{}, 0, KindRetry, ref).Allowed {
+		allowed++
+	}
+	if allowed != 2 {
+		t.Fatalf("normal allowed=%d, want 2", allowed)
+	}
+
+	allowed = 0
+	for b.AllowAttempt(high, policy.PolicyKey{}, 0, KindRetry, ref).Allowed {
+		allowed++
+	}
+	if allowed != 2 {
+		t.Fatalf("high allowed=%d, want 2", allowed)
+	}
+	if d := b.AllowAttempt(high, policy.PolicyKey{}, 0, KindRetry, ref); d.Reason != ReasonBudgetDenied {
+		t.Fatalf("reason=%q, want %q", d.Reason, ReasonBudgetDenied)
+	}
+}
+
+func TestTokenBucketBudget_BurstAndRate(t *testing.T) {
+	now := time.Unix(100, 0)
+	b := NewTokenBucketBudgetWithConfig(TokenBucketConfig{Rate: 1, Burst: 3})
+	b.now = func() time.Time { return now }
+	b.last = now
+
+	for i := 0; i < 3; i++ {
+		if d := b.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); !d.Allowed {
+			t.Fatalf("burst attempt %d denied: %+v", i, d)
+		}
+	}
+	if d := b.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); d.Allowed {
+		t.Fatalf("attempt allowed past burst")
+	}
+
+	now = now.Add(time.Hour)
+	b.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{})
+	if b.tokens != 2 {
+		t.Fatalf("tokens=%v, want burst-capped 2", b.tokens)
+	}
+}
+
+func TestTokenBucketBudget_Warmup(t *testing.T) {
+	start := time.Unix(100, 0)
+	now := start
+	b := NewTokenBucketBudgetWithConfig(TokenBucketConfig{Rate: 100, Burst: 10, Warmup: 10 * time.Second})
+	b.now = func() time.Time { return now }
+	b.start, b.last = start, start
+
+	allowN := func() int {
+		n := 0
+		for b.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}).Allowed {
+			n++
+		}
+		return n
+	}
+
+	if n := allowN(); n != 0 {
+		t.Fatalf("allowed=%d at startup, want 0", n)
+	}
+	now = start.Add(3 * time.Second)
+	if n := allowN(); n != 3 {
+		t.Fatalf("allowed=%d after 30%% of warmup, want 3", n)
+	}
+	now = start.Add(20 * time.Second)
+	if n := allowN(); n != 10 {
+		t.Fatalf("allowed=%d after warmup, want 10", n)
+	}
+}
+
+func TestTokenBucketBudget_WarmupScalesPriorityReserve(t *testing.T) {
+	start := time.Unix(100, 0)
+	now := start.Add(5 * time.Second)
+	b := NewTokenBucketBudgetWithConfig(TokenBucketConfig{Rate: 100, Burst: 10, Warmup: 10 * time.Second})
+	b.SetPriorityReserve(0.4)
+	b.now = func() time.Time { return now }
+	b.start, b.last = start, start
+
+	low := policy.WithPriority(context.Background(), policy.PriorityLow)
+	allowed := 0
+	for b.AllowAttempt(low, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}).Allowed {
+		allowed++
+	}
+	// Half-way through warmup: capacity 5, reserve 2.
+	if allowed != 3 {
+		t.Fatalf("low-priority allowed=%d, want 3", allowed)
+	}
+}
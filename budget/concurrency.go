@@ -0,0 +1,79 @@
+package budget
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// ConcurrencyBudget limits the retry and hedge attempts in flight at once,
+// capping extra load in parallelism rather than rate.
+//
+// Each allowed attempt holds ref.Cost slots (defaulting to 1) until the
+// executor releases its decision. Primary attempts (attempt 0 of kind
+// KindRetry) are always allowed and hold no slots.
+type ConcurrencyBudget struct {
+	max      int64
+	inFlight atomic.Int64
+}
+
+// NewConcurrencyBudget creates a ConcurrencyBudget allowing up to maxInFlight
+// retry and hedge slots at once. A negative maxInFlight is treated as 0.
+func NewConcurrencyBudget(maxInFlight int) *ConcurrencyBudget {
+	if maxInFlight < 0 {
+		maxInFlight = 0
+	}
+	return &ConcurrencyBudget{max: int64(maxInFlight)}
+}
+
+func (b *ConcurrencyBudget) AllowAttempt(_ context.Context, _ policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	if b == nil {
+		return Decision{Allowed: false, Reason: ReasonBudgetNil}
+	}
+	if attemptIdx == 0 && kind == KindRetry {
+		return Decision{Allowed: true, Reason: ReasonAllowed}
+	}
+
+	cost := int64(1)
+	if ref.Cost > 0 {
+		cost = int64(ref.Cost)
+	}
+	for {
+		n := b.inFlight.Load()
+		if n+cost > b.max {
+			return Decision{Allowed: false, Reason: ReasonBudgetDenied}
+		}
+		if b.inFlight.CompareAndSwap(n, n+cost) {
+			return Decision{Allowed: true, Reason: ReasonAllowed, Releaser: b, Token: uint64(cost)}
+		}
+	}
+}
+
+// PeekAttempt reports whether AllowAttempt would allow the attempt, without
+// taking slots.
+func (b *ConcurrencyBudget) PeekAttempt(_ context.Context, _ policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	if b == nil {
+		return Decision{Allowed: false, Reason: ReasonBudgetNil}
+	}
+	if attemptIdx == 0 && kind == KindRetry {
+		return Decision{Allowed: true, Reason: ReasonAllowed}
+	}
+	if b.inFlight.Load()+int64(tokenCost(ref)) > b.max {
+		return Decision{Allowed: false, Reason: ReasonBudgetDenied}
+	}
+	return Decision{Allowed: true, Reason: ReasonAllowed}
+}
+
+// ReleaseAttempt returns the slots held by an allowed attempt.
+func (b *ConcurrencyBudget) ReleaseAttempt(token uint64) {
+	b.inFlight.Add(-int64(token))
+}
+
+// InFlight returns the slots currently held.
+func (b *ConcurrencyBudget) InFlight() int {
+	if b == nil {
+		return 0
+	}
+	return int(b.inFlight.Load())
+}
@@ -0,0 +1,79 @@
+package budget
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestConcurrencyBudget_LimitsInFlight(t *testing.T) {
+	b := NewConcurrencyBudget(2)
+	ctx := context.Background()
+	key := policy.PolicyKey{Name: "k"}
+
+	if d := b.AllowAttempt(ctx, key, 0, KindRetry, policy.BudgetRef{}); !d.Allowed || d.Releaser != nil {
+		t.Fatalf("primary decision=%+v, want allowed without a slot", d)
+	}
+
+	first := b.AllowAttempt(ctx, key, 1, KindRetry, policy.BudgetRef{})
+	second := b.AllowAttempt(ctx, key, 0, KindHedge, policy.BudgetRef{})
+	if !first.Allowed || !second.Allowed {
+		t.Fatalf("decisions=%+v %+v, want both allowed", first, second)
+	}
+	if d := b.AllowAttempt(ctx, key, 2, KindRetry, policy.BudgetRef{}); d.Allowed || d.Reason != ReasonBudgetDenied {
+		t.Fatalf("decision=%+v, want denied at the limit", d)
+	}
+
+	first.Done()
+	if b.InFlight() != 1 {
+		t.Fatalf("inFlight=%d, want 1", b.InFlight())
+	}
+	if d := b.AllowAttempt(ctx, key, 2, KindRetry, policy.BudgetRef{Cost: 2}); d.Allowed {
+		t.Fatalf("cost 2 allowed with one free slot")
+	}
+	third := b.AllowAttempt(ctx, key, 2, KindRetry, policy.BudgetRef{})
+	if !third.Allowed {
+		t.Fatalf("decision=%+v, want allowed after release", third)
+	}
+	second.Done()
+	third.Done()
+	if b.InFlight() != 0 {
+		t.Fatalf("inFlight=%d, want 0", b.InFlight())
+	}
+}
+
+func TestConcurrencyBudget_ConcurrentUsage(t *testing.T) {
+	b := NewConcurrencyBudget(4)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				d := b.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{})
+				if n := b.InFlight(); n > 4 {
+					t.Errorf("inFlight=%d exceeds limit", n)
+				}
+				if d.Allowed {
+					d.Done()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if b.InFlight() != 0 {
+		t.Fatalf("inFlight=%d, want 0", b.InFlight())
+	}
+}
+
+func TestConcurrencyBudget_NilAndNegative(t *testing.T) {
+	var nilBudget *ConcurrencyBudget
+	if d := nilBudget.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); d.Allowed || d.Reason != ReasonBudgetNil {
+		t.Fatalf("decision=%+v, want denied with reason %q", d, ReasonBudgetNil)
+	}
+	if d := NewConcurrencyBudget(-1).AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); d.Allowed {
+		t.Fatalf("decision=%+v, want denied with no slots", d)
+	}
+}
@@ -0,0 +1,41 @@
+package budget
+
+import (
+	"context"
+	"time"
+)
+
+// InFlightReporter is implemented by budgets that hold resources for allowed
+// attempts until their decisions are released, such as ConcurrencyBudget.
+type InFlightReporter interface {
+	InFlight() int
+}
+
+// drainPollInterval is how often Drain checks a budget's in-flight count.
+const drainPollInterval = 10 * time.Millisecond
+
+// Drain waits until b holds nothing for allowed attempts, typically after
+// Registry.Replace swapped it out. Budgets that do not implement
+// InFlightReporter hold nothing and drain immediately. Drain returns ctx's
+// error if it is done first.
+func Drain(ctx context.Context, b Budget) error {
+	r, ok := b.(InFlightReporter)
+	if !ok {
+		return nil
+	}
+	if r.InFlight() <= 0 {
+		return nil
+	}
+	t := time.NewTicker(drainPollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if r.InFlight() <= 0 {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package budget
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// FixedWindowBudget allows at most limit retry and hedge units per policy key
+// in each fixed window of interval, such as 100 retries per minute.
+//
+// Windows are aligned to the wall clock (time.Truncate by interval), so every
+// process resets at the same instants and the quota is easy to reason about,
+// unlike a token bucket. Each attempt uses ref.Cost units (defaulting to 1).
+// Primary attempts (attempt 0 of kind KindRetry) are always allowed and use
+// nothing. Counts for all keys are dropped at each window boundary.
+type FixedWindowBudget struct {
+	mu sync.Mutex
+
+	limit    int
+	interval time.Duration
+
+	windowStart time.Time
+	counts      map[policy.PolicyKey]int
+
+	now func() time.Time
+}
+
+// NewFixedWindowBudget creates a FixedWindowBudget allowing limit units per key
+// per interval. A negative limit is treated as 0, and a non-positive interval
+// defaults to one minute.
+func NewFixedWindowBudget(limit int, interval time.Duration) *FixedWindowBudget {
+	if limit < 0 {
+		limit = 0
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &FixedWindowBudget{
+		limit:    limit,
+		interval: interval,
+		counts:   make(map[policy.PolicyKey]int),
+		now:      time.Now,
+	}
+}
+
+func (b *FixedWindowBudget) AllowAttempt(_ context.Context, key policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	return b.allow(key, attemptIdx, kind, ref, true)
+}
+
+// PeekAttempt reports whether AllowAttempt would allow the attempt, without
+// counting it.
+func (b *FixedWindowBudget) PeekAttempt(_ context.Context, key policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	return b.allow(key, attemptIdx, kind, ref, false)
+}
+
+func (b *FixedWindowBudget) allow(key policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef, consume bool) Decision {
+	if b == nil {
+		return Decision{Allowed: false, Reason: ReasonBudgetNil}
+	}
+	if attemptIdx == 0 && kind == KindRetry {
+		return Decision{Allowed: true, Reason: ReasonAllowed}
+	}
+
+	cost := 1
+	if ref.Cost > 0 {
+		cost = ref.Cost
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll()
+
+	used := b.counts[key]
+	if cost > b.limit-used {
+		return Decision{Allowed: false, Reason: ReasonBudgetDenied}
+	}
+	if consume {
+		b.counts[key] = used + cost
+	}
+	return Decision{Allowed: true, Reason: ReasonAllowed}
+}
+
+// Remaining returns the units key may still use in the current window and when
+// the window resets.
+func (b *FixedWindowBudget) Remaining(key policy.PolicyKey) (remaining int, resetAt time.Time) {
+	if b == nil {
+		return 0, time.Time{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll()
+	return b.limit - b.counts[key], b.windowStart.Add(b.interval)
+}
+
+// roll starts a new window, dropping all counts, once the current one ends.
+func (b *FixedWindowBudget) roll() {
+	start := b.now().Truncate(b.interval)
+	if start.Equal(b.windowStart) {
+		return
+	}
+	b.windowStart = start
+	if len(b.counts) > 0 {
+		b.counts = make(map[policy.PolicyKey]int)
+	}
+}
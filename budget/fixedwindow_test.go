@@ -0,0 +1,60 @@
+package budget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestFixedWindowBudget_LimitsPerKeyPerWindow(t *testing.T) {
+	now := time.Unix(999_980, 0) // 20s into a minute
+	b := NewFixedWindowBudget(3, time.Minute)
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+	a := policy.PolicyKey{Name: "a"}
+	other := policy.PolicyKey{Name: "b"}
+
+	if d := b.AllowAttempt(ctx, a, 0, KindRetry, policy.BudgetRef{}); !d.Allowed {
+		t.Fatalf("primary attempt denied: %+v", d)
+	}
+	if d := b.AllowAttempt(ctx, a, 1, KindRetry, policy.BudgetRef{Cost: 2}); !d.Allowed {
+		t.Fatalf("retry denied: %+v", d)
+	}
+	if d := b.AllowAttempt(ctx, a, 2, KindRetry, policy.BudgetRef{Cost: 2}); d.Allowed || d.Reason != ReasonBudgetDenied {
+		t.Fatalf("decision=%+v, want denied past the limit", d)
+	}
+	if d := b.AllowAttempt(ctx, a, 0, KindHedge, policy.BudgetRef{}); !d.Allowed {
+		t.Fatalf("hedge denied with one unit left: %+v", d)
+	}
+	if d := b.AllowAttempt(ctx, other, 1, KindRetry, policy.BudgetRef{}); !d.Allowed {
+		t.Fatalf("other key denied by key a: %+v", d)
+	}
+
+	remaining, resetAt := b.Remaining(a)
+	if remaining != 0 || !resetAt.Equal(time.Unix(1_000_020, 0)) {
+		t.Fatalf("remaining=%d resetAt=%v, want 0 at the next minute", remaining, resetAt)
+	}
+
+	// The window resets on the boundary, not a minute after first use.
+	now = time.Unix(1_000_020, 0)
+	if d := b.AllowAttempt(ctx, a, 1, KindRetry, policy.BudgetRef{Cost: 3}); !d.Allowed {
+		t.Fatalf("retry denied after reset: %+v", d)
+	}
+}
+
+func TestFixedWindowBudget_InvalidConfigAndNil(t *testing.T) {
+	b := NewFixedWindowBudget(-1, 0)
+	if b.limit != 0 || b.interval != time.Minute {
+		t.Fatalf("limit=%d interval=%v, want 0, 1m", b.limit, b.interval)
+	}
+	if d := b.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); d.Allowed {
+		t.Fatalf("decision=%+v, want denied with zero limit", d)
+	}
+
+	var nilBudget *FixedWindowBudget
+	if d := nilBudget.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); d.Allowed || d.Reason != ReasonBudgetNil {
+		t.Fatalf("decision=%+v, want denied with reason %q", d, ReasonBudgetNil)
+	}
+}
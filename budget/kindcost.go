@@ -0,0 +1,104 @@
+package budget
+
+import (
+	"context"
+	"math"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/internal"
+	"github.com/aponysus/recourse/policy"
+)
+
+// KindCostBudget scales attempt costs by AttemptKind before delegating to a
+// budget, so one shared budget can throttle hedges harder than retries for
+// every policy that references it.
+//
+// The scaled cost is ref.Cost (defaulting to 1) times the kind's multiplier,
+// rounded up. It forwards TrackRequest, Observe and InFlight to the wrapped
+// budget when it implements them.
+type KindCostBudget struct {
+	budget Budget
+	retry  float64
+	hedge  float64
+}
+
+// NewKindCostBudget wraps b with cost multipliers for retry and hedge attempts.
+// Non-positive or invalid multipliers are treated as 1.
+func NewKindCostBudget(b Budget, retry, hedge float64) *KindCostBudget {
+	return &KindCostBudget{budget: b, retry: validMultiplier(retry), hedge: validMultiplier(hedge)}
+}
+
+func validMultiplier(m float64) float64 {
+	if math.IsNaN(m) || math.IsInf(m, 0) || m <= 0 {
+		return 1
+	}
+	return m
+}
+
+func (k *KindCostBudget) AllowAttempt(ctx context.Context, key policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	if k == nil || internal.IsTypedNil(k.budget) {
+		return Decision{Allowed: false, Reason: ReasonBudgetNil}
+	}
+	ref.Cost = k.cost(kind, ref.Cost)
+	return k.budget.AllowAttempt(ctx, key, attemptIdx, kind, ref)
+}
+
+// PeekAttempt peeks at the wrapped budget with the scaled cost.
+func (k *KindCostBudget) PeekAttempt(ctx context.Context, key policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	if k == nil || internal.IsTypedNil(k.budget) {
+		return Decision{Allowed: false, Reason: ReasonBudgetNil}
+	}
+	ref.Cost = k.cost(kind, ref.Cost)
+	return Peek(ctx, k.budget, key, attemptIdx, kind, ref)
+}
+
+// cost returns the scaled cost of an attempt of kind.
+func (k *KindCostBudget) cost(kind AttemptKind, base int) int {
+	if base <= 0 {
+		base = 1
+	}
+	m := k.retry
+	if kind == KindHedge {
+		m = k.hedge
+	}
+	scaled := math.Ceil(float64(base) * m)
+	if scaled >= math.MaxInt32 {
+		return math.MaxInt32
+	}
+	if scaled < 1 {
+		return 1
+	}
+	return int(scaled)
+}
+
+// TrackRequest forwards to the wrapped budget when it implements RequestTracker.
+func (k *KindCostBudget) TrackRequest(ctx context.Context, key policy.PolicyKey, ref policy.BudgetRef) {
+	if k == nil {
+		return
+	}
+	if rt, ok := k.budget.(RequestTracker); ok && !internal.IsTypedNil(rt) {
+		rt.TrackRequest(ctx, key, ref)
+	}
+}
+
+// Observe forwards to the wrapped budget when it implements Feedback.
+func (k *KindCostBudget) Observe(ctx context.Context, key policy.PolicyKey, out classify.Outcome) {
+	if k == nil {
+		return
+	}
+	if fb, ok := k.budget.(Feedback); ok && !internal.IsTypedNil(fb) {
+		fb.Observe(ctx, key, out)
+	}
+}
+
+// InFlight forwards to the wrapped budget when it implements InFlightReporter,
+// and returns 0 otherwise.
+func (k *KindCostBudget) InFlight() int {
+	if k == nil {
+		return 0
+	}
+	if r, ok := k.budget.(InFlightReporter); ok && !internal.IsTypedNil(r) {
+		return r.InFlight()
+	}
+	return 0
+}
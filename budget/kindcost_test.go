@@ -0,0 +1,83 @@
+package budget
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+type costRecorder struct {
+	costs []int
+}
+
+func (r *costRecorder) AllowAttempt(_ context.Context, _ policy.PolicyKey, _ int, _ AttemptKind, ref policy.BudgetRef) Decision {
+	r.costs = append(r.costs, ref.Cost)
+	return Decision{Allowed: true, Reason: ReasonAllowed}
+}
+
+func TestKindCostBudget_ScalesCostByKind(t *testing.T) {
+	rec := &costRecorder{}
+	b := NewKindCostBudget(rec, 1, 2.5)
+	ctx := context.Background()
+
+	b.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{})
+	b.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindHedge, policy.BudgetRef{})
+	b.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindHedge, policy.BudgetRef{Cost: 2})
+	b.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{Cost: 3})
+
+	want := []int{1, 3, 5, 3}
+	for i, c := range want {
+		if rec.costs[i] != c {
+			t.Fatalf("costs=%v, want %v", rec.costs, want)
+		}
+	}
+}
+
+func TestKindCostBudget_ThrottlesHedgesHarder(t *testing.T) {
+	b := NewKindCostBudget(NewTokenBucketBudget(4, 0), 1, 2)
+	ctx := context.Background()
+
+	hedges := 0
+	for b.AllowAttempt(ctx, policy.PolicyKey{}, 0, KindHedge, policy.BudgetRef{}).Allowed {
+		hedges++
+	}
+	if hedges != 2 {
+		t.Fatalf("hedges=%d, want 2 from 4 tokens at 2x", hedges)
+	}
+}
+
+func TestKindCostBudget_ForwardsExtensions(t *testing.T) {
+	ratio := NewRatioBudget(1, time.Minute, 0)
+	b := NewKindCostBudget(ratio, math.NaN(), -1)
+	if b.retry != 1 || b.hedge != 1 {
+		t.Fatalf("multipliers=%v,%v, want 1,1", b.retry, b.hedge)
+	}
+
+	b.TrackRequest(context.Background(), policy.PolicyKey{}, policy.BudgetRef{})
+	if requests, _ := ratio.Counts(); requests != 1 {
+		t.Fatalf("requests=%d, want 1", requests)
+	}
+
+	adaptive := NewAdaptiveBudget(1, 0, time.Minute)
+	NewKindCostBudget(adaptive, 1, 2).Observe(context.Background(), policy.PolicyKey{}, classify.Outcome{Kind: classify.OutcomeSuccess})
+	if successes, _ := adaptive.outcomes.sums(time.Now()); successes != 1 {
+		t.Fatalf("successes=%v, want 1", successes)
+	}
+
+	slots := NewConcurrencyBudget(4)
+	wrapped := NewKindCostBudget(slots, 1, 2)
+	d := wrapped.AllowAttempt(context.Background(), policy.PolicyKey{}, 0, KindHedge, policy.BudgetRef{})
+	if !d.Allowed || wrapped.InFlight() != 2 {
+		t.Fatalf("decision=%+v inFlight=%d, want allowed holding 2 slots", d, wrapped.InFlight())
+	}
+	d.Done()
+
+	var nilBudget *KindCostBudget
+	if d := nilBudget.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); d.Allowed || d.Reason != ReasonBudgetNil {
+		t.Fatalf("decision=%+v, want denied with reason %q", d, ReasonBudgetNil)
+	}
+}
@@ -0,0 +1,108 @@
+package budget
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// RequestTracker is implemented by budgets that meter retries against request
+// volume. The executor calls TrackRequest once per call, when the primary
+// attempt starts, for every budget the policy references, before any budget
+// is asked to allow an attempt.
+type RequestTracker interface {
+	TrackRequest(ctx context.Context, key policy.PolicyKey, ref policy.BudgetRef)
+}
+
+// RatioBudget limits retries and hedges to a fraction of recent requests, the
+// retry throttling recommended by gRPC and the Google SRE book: with ratio 0.1,
+// retries may add at most 10% to the load the callers generate.
+//
+// Requests are counted through TrackRequest and retries through AllowAttempt,
+// over a sliding window. A retry of cost c is allowed while
+// retries+c <= ratio*requests + minRetries; minRetries lets low-traffic keys
+// retry at all. Primary attempts (attempt 0 of kind KindRetry) are always
+// allowed and are not counted as retries.
+type RatioBudget struct {
+	mu sync.Mutex
+
+	ratio      float64
+	minRetries float64
+	counts     window // requests, retries
+
+	now func() time.Time
+}
+
+// NewRatioBudget creates a RatioBudget allowing ratio retries per request over
+// window, plus minRetries per window. A non-positive window defaults to 10s.
+func NewRatioBudget(ratio float64, window time.Duration, minRetries int) *RatioBudget {
+	if math.IsNaN(ratio) || math.IsInf(ratio, 0) || ratio < 0 {
+		ratio = 0
+	}
+	if minRetries < 0 {
+		minRetries = 0
+	}
+	return &RatioBudget{
+		ratio:      ratio,
+		minRetries: float64(minRetries),
+		counts:     newWindow(window),
+		now:        time.Now,
+	}
+}
+
+// TrackRequest counts one request toward the window.
+func (b *RatioBudget) TrackRequest(_ context.Context, _ policy.PolicyKey, _ policy.BudgetRef) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counts.add(b.now(), 0, 1)
+}
+
+func (b *RatioBudget) AllowAttempt(_ context.Context, _ policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	return b.allow(attemptIdx, kind, ref, true)
+}
+
+// PeekAttempt reports whether AllowAttempt would allow the attempt, without
+// counting it.
+func (b *RatioBudget) PeekAttempt(_ context.Context, _ policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	return b.allow(attemptIdx, kind, ref, false)
+}
+
+func (b *RatioBudget) allow(attemptIdx int, kind AttemptKind, ref policy.BudgetRef, consume bool) Decision {
+	if b == nil {
+		return Decision{Allowed: false, Reason: ReasonBudgetNil}
+	}
+	if attemptIdx == 0 && kind == KindRetry {
+		return Decision{Allowed: true, Reason: ReasonAllowed}
+	}
+
+	cost := tokenCost(ref)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	requests, retries := b.counts.sums(now)
+	if retries+cost > b.ratio*requests+b.minRetries {
+		return Decision{Allowed: false, Reason: ReasonBudgetDenied}
+	}
+	if consume {
+		b.counts.add(now, 1, cost)
+	}
+	return Decision{Allowed: true, Reason: ReasonAllowed}
+}
+
+// Counts returns the requests and retries recorded in the current window.
+func (b *RatioBudget) Counts() (requests, retries int) {
+	if b == nil {
+		return 0, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r, t := b.counts.sums(b.now())
+	return int(r), int(t)
+}
@@ -0,0 +1,93 @@
+package budget
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestRatioBudget_LimitsRetriesToRatio(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewRatioBudget(0.1, 10*time.Second, 0)
+	b.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	key := policy.PolicyKey{Name: "k"}
+	for i := 0; i < 20; i++ {
+		b.TrackRequest(ctx, key, policy.BudgetRef{})
+		if d := b.AllowAttempt(ctx, key, 0, KindRetry, policy.BudgetRef{}); !d.Allowed {
+			t.Fatalf("primary attempt denied: %+v", d)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if d := b.AllowAttempt(ctx, key, 1, KindRetry, policy.BudgetRef{}); !d.Allowed {
+			t.Fatalf("retry %d denied: %+v", i, d)
+		}
+	}
+	if d := b.AllowAttempt(ctx, key, 1, KindHedge, policy.BudgetRef{}); d.Allowed || d.Reason != ReasonBudgetDenied {
+		t.Fatalf("decision=%+v, want denied past 10%% of requests", d)
+	}
+	if requests, retries := b.Counts(); requests != 20 || retries != 2 {
+		t.Fatalf("counts=%d,%d, want 20,2", requests, retries)
+	}
+}
+
+func TestRatioBudget_MinRetriesAndCost(t *testing.T) {
+	b := NewRatioBudget(0.5, time.Second, 2)
+	b.now = func() time.Time { return time.Unix(0, 0) }
+	ctx := context.Background()
+
+	if d := b.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{Cost: 3}); d.Allowed {
+		t.Fatalf("cost 3 allowed with no requests and minRetries 2")
+	}
+	if d := b.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{Cost: 2}); !d.Allowed {
+		t.Fatalf("cost 2 denied within minRetries: %+v", d)
+	}
+	b.TrackRequest(ctx, policy.PolicyKey{}, policy.BudgetRef{})
+	b.TrackRequest(ctx, policy.PolicyKey{}, policy.BudgetRef{})
+	if d := b.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); !d.Allowed {
+		t.Fatalf("retry denied after requests raised the allowance: %+v", d)
+	}
+}
+
+func TestRatioBudget_WindowSlides(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewRatioBudget(1, 10*time.Second, 0)
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	b.TrackRequest(ctx, policy.PolicyKey{}, policy.BudgetRef{})
+	now = now.Add(5 * time.Second)
+	b.TrackRequest(ctx, policy.PolicyKey{}, policy.BudgetRef{})
+	b.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{})
+
+	now = now.Add(6 * time.Second)
+	if requests, retries := b.Counts(); requests != 1 || retries != 1 {
+		t.Fatalf("counts=%d,%d, want 1,1 after the first bucket expired", requests, retries)
+	}
+	if d := b.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); d.Allowed {
+		t.Fatalf("retry allowed beyond ratio")
+	}
+
+	now = now.Add(time.Minute)
+	if requests, retries := b.Counts(); requests != 0 || retries != 0 {
+		t.Fatalf("counts=%d,%d, want 0,0 after the window passed", requests, retries)
+	}
+}
+
+func TestRatioBudget_InvalidConfigAndNil(t *testing.T) {
+	b := NewRatioBudget(math.NaN(), -time.Second, -1)
+	if b.ratio != 0 || b.minRetries != 0 || b.counts.width != time.Second {
+		t.Fatalf("ratio=%v minRetries=%v width=%v", b.ratio, b.minRetries, b.counts.width)
+	}
+
+	var nilBudget *RatioBudget
+	nilBudget.TrackRequest(context.Background(), policy.PolicyKey{}, policy.BudgetRef{})
+	if d := nilBudget.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); d.Allowed || d.Reason != ReasonBudgetNil {
+		t.Fatalf("decision=%+v, want denied with reason %q", d, ReasonBudgetNil)
+	}
+}
@@ -2,11 +2,14 @@ package budget
 
 // Standard Decision.Reason strings.
 const (
-	ReasonAllowed           = "allowed"
-	ReasonNoBudget          = "no_budget"
-	ReasonBudgetNotFound    = "budget_not_found"
-	ReasonBudgetDenied      = "budget_denied"
-	ReasonPanicInBudget     = "panic_in_budget"
-	ReasonBudgetRegistryNil = "budget_registry_nil"
-	ReasonBudgetNil         = "budget_nil"
+	ReasonAllowed            = "allowed"
+	ReasonNoBudget           = "no_budget"
+	ReasonBudgetNotFound     = "budget_not_found"
+	ReasonBudgetDenied       = "budget_denied"
+	ReasonPanicInBudget      = "panic_in_budget"
+	ReasonBudgetRegistryNil  = "budget_registry_nil"
+	ReasonBudgetNil          = "budget_nil"
+	ReasonPriorityShed       = "budget_priority_shed"
+	ReasonAdaptiveSuppressed = "budget_adaptive_suppressed"
+	ReasonPeekUnsupported    = "budget_peek_unsupported"
 )
@@ -2,20 +2,25 @@ package budget
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/aponysus/recourse/internal"
 )
 
 // Registry is a thread-safe name → Budget map.
+//
+// Lookups are lock-free: registrations copy the map and publish it atomically,
+// since budgets are looked up on every gated attempt but registered rarely.
 type Registry struct {
-	mu sync.RWMutex
-	m  map[string]Budget
+	mu sync.Mutex // serializes writers
+	m  atomic.Pointer[map[string]Budget]
 }
 
 func NewRegistry() *Registry {
-	return &Registry{m: make(map[string]Budget)}
+	return &Registry{}
 }
 
 // Register registers a budget with validation.
@@ -35,10 +40,9 @@ func (r *Registry) Register(name string, b Budget) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if r.m == nil {
-		r.m = make(map[string]Budget)
-	}
-	r.m[name] = b
+	next := internal.CopyMap(r.m.Load(), 1)
+	next[name] = b
+	r.m.Store(&next)
 	return nil
 }
 
@@ -49,6 +53,40 @@ func (r *Registry) MustRegister(name string, b Budget) {
 	}
 }
 
+// Replace atomically swaps the budget registered under name for b and returns
+// the previous budget. It returns an error if name is not registered.
+//
+// Attempts the old budget already allowed keep their decisions: their releases
+// go to the old budget, never to b. Use Drain to wait for them before
+// discarding or inspecting the old budget.
+func (r *Registry) Replace(name string, b Budget) (Budget, error) {
+	if r == nil {
+		return nil, errors.New("registry is nil")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("budget name cannot be empty")
+	}
+	if internal.IsTypedNil(b) {
+		return nil, errors.New("budget cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var old Budget
+	if m := r.m.Load(); m != nil {
+		old = (*m)[name]
+	}
+	if old == nil {
+		return nil, fmt.Errorf("budget %q is not registered", name)
+	}
+	next := internal.CopyMap(r.m.Load(), 0)
+	next[name] = b
+	r.m.Store(&next)
+	return old, nil
+}
+
 func (r *Registry) Get(name string) (Budget, bool) {
 	if r == nil {
 		return nil, false
@@ -58,8 +96,10 @@ func (r *Registry) Get(name string) (Budget, bool) {
 		return nil, false
 	}
 
-	r.mu.RLock()
-	b, ok := r.m[name]
-	r.mu.RUnlock()
+	m := r.m.Load()
+	if m == nil {
+		return nil, false
+	}
+	b, ok := (*m)[name]
 	return b, ok && b != nil
 }
@@ -3,6 +3,7 @@ package budget
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/aponysus/recourse/policy"
 )
@@ -68,3 +69,98 @@ func TestRegistry_GetValidation(t *testing.T) {
 		t.Fatalf("expected nil,false for empty name")
 	}
 }
+
+func TestRegistry_Replace(t *testing.T) {
+	reg := NewRegistry()
+	old := NewConcurrencyBudget(1)
+	reg.MustRegister("slots", old)
+
+	held := old.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{})
+	if !held.Allowed {
+		t.Fatalf("decision=%+v, want allowed", held)
+	}
+
+	resized := NewConcurrencyBudget(4)
+	prev, err := reg.Replace(" slots ", resized)
+	if err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	if prev != old {
+		t.Fatalf("Replace returned %v, want the old budget", prev)
+	}
+	if b, _ := reg.Get("slots"); b != resized {
+		t.Fatalf("Get returned %v, want the new budget", b)
+	}
+
+	// The outstanding decision releases into the old budget only.
+	drained := make(chan error, 1)
+	go func() { drained <- Drain(context.Background(), prev) }()
+	held.Done()
+	if err := <-drained; err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if old.InFlight() != 0 || resized.InFlight() != 0 {
+		t.Fatalf("inFlight old=%d new=%d, want 0,0", old.InFlight(), resized.InFlight())
+	}
+}
+
+func TestRegistry_ReplaceValidation(t *testing.T) {
+	var nilReg *Registry
+	if _, err := nilReg.Replace("x", testBudget{}); err == nil {
+		t.Fatal("expected error for nil registry")
+	}
+
+	reg := NewRegistry()
+	if _, err := reg.Replace("missing", testBudget{}); err == nil {
+		t.Fatal("expected error for unregistered name")
+	}
+	reg.MustRegister("x", testBudget{})
+	if _, err := reg.Replace(" ", testBudget{}); err == nil {
+		t.Fatal("expected error for empty name")
+	}
+	if _, err := reg.Replace("x", nil); err == nil {
+		t.Fatal("expected error for nil budget")
+	}
+}
+
+func TestDrain_HonorsContext(t *testing.T) {
+	b := NewConcurrencyBudget(1)
+	b.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Drain(ctx, b); err != context.Canceled {
+		t.Fatalf("err=%v, want context.Canceled", err)
+	}
+	if err := Drain(context.Background(), testBudget{}); err != nil {
+		t.Fatalf("err=%v, want nil for budgets without in-flight state", err)
+	}
+}
+
+func TestDrain_WaitsForTenantBudgets(t *testing.T) {
+	tb := NewTenantBudget(func(string) Budget { return NewConcurrencyBudget(2) }, 0)
+
+	ctx := policy.WithTenant(context.Background(), "a")
+	held := tb.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{})
+	shared := tb.AllowAttempt(context.Background(), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{})
+	if !held.Allowed || !shared.Allowed {
+		t.Fatalf("decisions=%+v %+v, want allowed", held, shared)
+	}
+	if tb.InFlight() != 2 {
+		t.Fatalf("inFlight=%d, want 2", tb.InFlight())
+	}
+
+	short, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := Drain(short, tb); err != context.DeadlineExceeded {
+		t.Fatalf("err=%v, want DeadlineExceeded while tenant slots are held", err)
+	}
+
+	drained := make(chan error, 1)
+	go func() { drained <- Drain(context.Background(), tb) }()
+	held.Done()
+	shared.Done()
+	if err := <-drained; err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package budget
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/internal"
+	"github.com/aponysus/recourse/policy"
+)
+
+// DefaultMaxTenants bounds how many per-tenant budgets a TenantBudget creates
+// when no explicit limit is given.
+const DefaultMaxTenants = 1024
+
+// TenantBudget scopes a budget per tenant (see policy.WithTenant), so one
+// tenant exhausting its retries does not deny attempts for the others.
+//
+// Each tenant gets its own budget from the factory on first use. Calls without
+// a tenant, and tenants beyond the limit, share the budget created for tenant "".
+// Lookups of existing tenants are lock-free.
+type TenantBudget struct {
+	newBudget  func(tenant string) Budget
+	maxTenants int
+
+	shared Budget
+
+	mu      sync.Mutex // serializes writers
+	tenants atomic.Pointer[map[string]Budget]
+}
+
+// NewTenantBudget creates a TenantBudget that builds per-tenant budgets with
+// newBudget. maxTenants <= 0 uses DefaultMaxTenants.
+func NewTenantBudget(newBudget func(tenant string) Budget, maxTenants int) *TenantBudget {
+	if maxTenants <= 0 {
+		maxTenants = DefaultMaxTenants
+	}
+	return &TenantBudget{
+		newBudget:  newBudget,
+		maxTenants: maxTenants,
+		shared:     newBudget(""),
+	}
+}
+
+// AllowAttempt delegates to the budget of the tenant carried by ctx.
+func (t *TenantBudget) AllowAttempt(ctx context.Context, key policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	if t == nil {
+		return Decision{Allowed: true, Reason: ReasonNoBudget}
+	}
+	tenant, _ := policy.TenantFromContext(ctx)
+	b := t.For(tenant)
+	if internal.IsTypedNil(b) {
+		return Decision{Allowed: false, Reason: ReasonBudgetNil}
+	}
+	return b.AllowAttempt(ctx, key, attemptIdx, kind, ref)
+}
+
+// PeekAttempt peeks at the budget of the tenant carried by ctx.
+func (t *TenantBudget) PeekAttempt(ctx context.Context, key policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	if t == nil {
+		return Decision{Allowed: true, Reason: ReasonNoBudget}
+	}
+	tenant, _ := policy.TenantFromContext(ctx)
+	b := t.For(tenant)
+	if internal.IsTypedNil(b) {
+		return Decision{Allowed: false, Reason: ReasonBudgetNil}
+	}
+	return Peek(ctx, b, key, attemptIdx, kind, ref)
+}
+
+// TrackRequest forwards to the budget of the tenant carried by ctx when it
+// implements RequestTracker.
+func (t *TenantBudget) TrackRequest(ctx context.Context, key policy.PolicyKey, ref policy.BudgetRef) {
+	if t == nil {
+		return
+	}
+	tenant, _ := policy.TenantFromContext(ctx)
+	if rt, ok := t.For(tenant).(RequestTracker); ok && !internal.IsTypedNil(rt) {
+		rt.TrackRequest(ctx, key, ref)
+	}
+}
+
+// Observe forwards to the budget of the tenant carried by ctx when it
+// implements Feedback.
+func (t *TenantBudget) Observe(ctx context.Context, key policy.PolicyKey, out classify.Outcome) {
+	if t == nil {
+		return
+	}
+	tenant, _ := policy.TenantFromContext(ctx)
+	if fb, ok := t.For(tenant).(Feedback); ok && !internal.IsTypedNil(fb) {
+		fb.Observe(ctx, key, out)
+	}
+}
+
+// InFlight sums InFlight over the shared and per-tenant budgets that implement
+// InFlightReporter, so Drain waits for every tenant's outstanding attempts.
+func (t *TenantBudget) InFlight() int {
+	if t == nil {
+		return 0
+	}
+	n := inFlightOf(t.shared)
+	if m := t.tenants.Load(); m != nil {
+		for _, b := range *m {
+			n += inFlightOf(b)
+		}
+	}
+	return n
+}
+
+// inFlightOf returns b's InFlight, or 0 if b does not report one.
+func inFlightOf(b Budget) int {
+	if r, ok := b.(InFlightReporter); ok && !internal.IsTypedNil(r) {
+		return r.InFlight()
+	}
+	return 0
+}
+
+// For returns the budget used for tenant, creating it if needed.
+func (t *TenantBudget) For(tenant string) Budget {
+	if tenant == "" {
+		return t.shared
+	}
+	if b, ok := t.lookup(tenant); ok {
+		return b
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if b, ok := t.lookup(tenant); ok {
+		return b
+	}
+	m := t.tenants.Load()
+	if m != nil && len(*m) >= t.maxTenants {
+		return t.shared
+	}
+
+	b := t.newBudget(tenant)
+	if internal.IsTypedNil(b) {
+		return t.shared
+	}
+	next := internal.CopyMap(m, 1)
+	next[tenant] = b
+	t.tenants.Store(&next)
+	return b
+}
+
+func (t *TenantBudget) lookup(tenant string) (Budget, bool) {
+	m := t.tenants.Load()
+	if m == nil {
+		return nil, false
+	}
+	b, ok := (*m)[tenant]
+	return b, ok
+}
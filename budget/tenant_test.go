@@ -0,0 +1,77 @@
+package budget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestTenantBudget_IsolatesTenants(t *testing.T) {
+	tb := NewTenantBudget(func(string) Budget { return NewTokenBucketBudget(1, 0) }, 0)
+	key := policy.PolicyKey{Name: "op"}
+	ref := policy.BudgetRef{Name: "b", Cost: 1}
+
+	noisy := policy.WithTenant(context.Background(), "noisy")
+	quiet := policy.WithTenant(context.Background(), "quiet")
+
+	if d := tb.AllowAttempt(noisy, key, 1, KindRetry, ref); !d.Allowed {
+		t.Fatalf("first noisy attempt denied: %+v", d)
+	}
+	if d := tb.AllowAttempt(noisy, key, 2, KindRetry, ref); d.Allowed {
+		t.Fatalf("expected noisy tenant to exhaust its budget")
+	}
+	if d := tb.AllowAttempt(quiet, key, 1, KindRetry, ref); !d.Allowed {
+		t.Fatalf("quiet tenant denied by noisy tenant: %+v", d)
+	}
+	if d := tb.AllowAttempt(context.Background(), key, 1, KindRetry, ref); !d.Allowed {
+		t.Fatalf("untenanted call denied: %+v", d)
+	}
+}
+
+func TestTenantBudget_MaxTenantsShareBudget(t *testing.T) {
+	created := 0
+	tb := NewTenantBudget(func(string) Budget {
+		created++
+		return UnlimitedBudget{}
+	}, 2)
+
+	tb.For("a")
+	tb.For("b")
+	if got := tb.For("c"); got != tb.For("") {
+		t.Fatalf("expected tenant beyond the limit to share the untenanted budget")
+	}
+	if tb.For("a") != tb.For("a") {
+		t.Fatalf("expected stable per-tenant budget")
+	}
+	if created != 3 { // shared + a + b
+		t.Fatalf("created=%d, want 3", created)
+	}
+}
+
+func TestTenantBudget_NilFactoryResultDenies(t *testing.T) {
+	tb := NewTenantBudget(func(string) Budget { return nil }, 0)
+	d := tb.AllowAttempt(policy.WithTenant(context.Background(), "a"), policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{})
+	if d.Allowed || d.Reason != ReasonBudgetNil {
+		t.Fatalf("d=%+v, want denied budget_nil", d)
+	}
+}
+
+func TestTenantBudget_TracksRequestsPerTenant(t *testing.T) {
+	ratios := map[string]*RatioBudget{}
+	tb := NewTenantBudget(func(tenant string) Budget {
+		b := NewRatioBudget(1, time.Minute, 0)
+		ratios[tenant] = b
+		return b
+	}, 0)
+
+	ctx := policy.WithTenant(context.Background(), "a")
+	tb.TrackRequest(ctx, policy.PolicyKey{}, policy.BudgetRef{})
+	if d := tb.AllowAttempt(ctx, policy.PolicyKey{}, 1, KindRetry, policy.BudgetRef{}); !d.Allowed {
+		t.Fatalf("retry denied after tracked request: %+v", d)
+	}
+	if requests, _ := ratios[""].Counts(); requests != 0 {
+		t.Fatalf("shared budget tracked %d requests, want 0", requests)
+	}
+}
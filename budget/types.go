@@ -3,6 +3,7 @@ package budget
 import (
 	"context"
 
+	"github.com/aponysus/recourse/internal"
 	"github.com/aponysus/recourse/policy"
 )
 
@@ -21,11 +22,57 @@ type Decision struct {
 	Allowed bool
 	Reason  string
 
+	// Budget names the budget that denied the attempt when a policy references
+	// several. The executor sets it; budgets leave it empty.
+	Budget string
+
 	// Release, when non-nil, is called exactly once after an allowed attempt finishes.
+	//
+	// Budgets on hot paths should prefer Releaser and Token, which avoid
+	// allocating a closure per decision.
 	Release func()
+
+	// Releaser, when non-nil, is called with Token exactly once after an allowed
+	// attempt finishes.
+	Releaser Releaser
+	Token    uint64
+}
+
+// Releaser is implemented by budgets that hold resources for the duration of an
+// allowed attempt. The token is whatever the budget returned in Decision.Token.
+type Releaser interface {
+	ReleaseAttempt(token uint64)
+}
+
+// Done releases any resources held by the decision.
+// It calls Releaser and then Release, whichever are set.
+func (d Decision) Done() {
+	if d.Releaser != nil {
+		d.Releaser.ReleaseAttempt(d.Token)
+	}
+	if d.Release != nil {
+		d.Release()
+	}
 }
 
 // Budget gates attempts to prevent retry/hedge storms.
 type Budget interface {
 	AllowAttempt(ctx context.Context, key policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision
 }
+
+// Peeker is implemented by budgets that can report whether AllowAttempt would
+// allow an attempt without consuming anything. Dry-run calls use it to preview
+// retries. The returned decision holds no resources.
+type Peeker interface {
+	PeekAttempt(ctx context.Context, key policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision
+}
+
+// Peek reports whether b would allow an attempt without consuming anything.
+// Budgets that do not implement Peeker yield an allowed decision with reason
+// ReasonPeekUnsupported.
+func Peek(ctx context.Context, b Budget, key policy.PolicyKey, attemptIdx int, kind AttemptKind, ref policy.BudgetRef) Decision {
+	if p, ok := b.(Peeker); ok && !internal.IsTypedNil(p) {
+		return p.PeekAttempt(ctx, key, attemptIdx, kind, ref)
+	}
+	return Decision{Allowed: true, Reason: ReasonPeekUnsupported}
+}
@@ -0,0 +1,62 @@
+package budget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+type recordingReleaser struct{ tokens []uint64 }
+
+func (r *recordingReleaser) ReleaseAttempt(token uint64) { r.tokens = append(r.tokens, token) }
+
+func TestDecisionDone(t *testing.T) {
+	r := &recordingReleaser{}
+	calls := 0
+	d := Decision{Allowed: true, Releaser: r, Token: 7, Release: func() { calls++ }}
+	d.Done()
+
+	if len(r.tokens) != 1 || r.tokens[0] != 7 {
+		t.Fatalf("tokens=%v, want [7]", r.tokens)
+	}
+	if calls != 1 {
+		t.Fatalf("release calls=%d, want 1", calls)
+	}
+
+	// A decision with nothing to release is a no-op.
+	Decision{Allowed: true}.Done()
+}
+
+func TestPeek_ConsumesNothing(t *testing.T) {
+	ctx := context.Background()
+	key := policy.PolicyKey{Name: "k"}
+	ref := policy.BudgetRef{Cost: 1}
+
+	for name, b := range map[string]Budget{
+		"token_bucket": NewTokenBucketBudget(1, 0),
+		"ratio":        NewRatioBudget(0, time.Minute, 1),
+		"adaptive":     NewAdaptiveBudget(1, 0, time.Minute),
+		"concurrency":  NewConcurrencyBudget(1),
+		"fixed_window": NewFixedWindowBudget(1, time.Hour),
+		"tenant":       NewTenantBudget(func(string) Budget { return NewConcurrencyBudget(1) }, 0),
+		"kind_cost":    NewKindCostBudget(NewTokenBucketBudget(1, 0), 1, 1),
+	} {
+		for i := 0; i < 3; i++ {
+			if d := Peek(ctx, b, key, 1, KindRetry, ref); !d.Allowed || d.Reason == ReasonPeekUnsupported {
+				t.Fatalf("%s: peek %d=%+v, want allowed", name, i, d)
+			}
+		}
+		if d := b.AllowAttempt(ctx, key, 1, KindRetry, ref); !d.Allowed {
+			t.Fatalf("%s: allow after peeks=%+v, want allowed", name, d)
+		}
+		if d := Peek(ctx, b, key, 1, KindRetry, ref); d.Allowed {
+			t.Fatalf("%s: peek after the only unit was used=%+v, want denied", name, d)
+		}
+	}
+
+	if d := Peek(ctx, testBudget{}, key, 1, KindRetry, ref); !d.Allowed || d.Reason != ReasonPeekUnsupported {
+		t.Fatalf("decision=%+v, want %q", d, ReasonPeekUnsupported)
+	}
+}
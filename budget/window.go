@@ -0,0 +1,65 @@
+package budget
+
+import "time"
+
+// windowBuckets is the number of buckets a window is divided into.
+const windowBuckets = 10
+
+// window counts two quantities over a sliding time window divided into
+// windowBuckets buckets. It is not safe for concurrent use.
+type window struct {
+	width   time.Duration
+	buckets [windowBuckets][2]float64
+	head    int
+	start   time.Time // start of the head bucket
+}
+
+func newWindow(d time.Duration) window {
+	if d <= 0 {
+		d = 10 * time.Second
+	}
+	width := d / windowBuckets
+	if width <= 0 {
+		width = 1
+	}
+	return window{width: width}
+}
+
+// add adds n to counter i of the current bucket.
+func (w *window) add(now time.Time, i int, n float64) {
+	w.advance(now)
+	w.buckets[w.head][i] += n
+}
+
+// sums returns both counters summed over the window.
+func (w *window) sums(now time.Time) (a, b float64) {
+	w.advance(now)
+	for _, bucket := range w.buckets {
+		a += bucket[0]
+		b += bucket[1]
+	}
+	return a, b
+}
+
+// advance rotates the window so the head bucket covers now.
+func (w *window) advance(now time.Time) {
+	if w.start.IsZero() || now.Before(w.start) {
+		w.start = now
+		return
+	}
+	steps := int64(now.Sub(w.start) / w.width)
+	if steps <= 0 {
+		return
+	}
+	if steps >= windowBuckets {
+		w.buckets = [windowBuckets][2]float64{}
+		w.head = 0
+		w.start = now
+		return
+	}
+	for i := int64(0); i < steps; i++ {
+		w.head = (w.head + 1) % windowBuckets
+		w.buckets[w.head] = [2]float64{}
+	}
+	w.start = w.start.Add(time.Duration(steps) * w.width)
+}
@@ -0,0 +1,75 @@
+package bulkhead
+
+import (
+	"context"
+	"time"
+)
+
+// Decision is the result of a bulkhead admission check.
+type Decision struct {
+	Allowed bool
+	Reason  string
+
+	// Waited is how long the call queued for a slot.
+	Waited time.Duration
+}
+
+// Bulkhead admits at most MaxInFlight concurrent calls. Every admitted call
+// must call Release exactly once.
+type Bulkhead struct {
+	sem chan struct{}
+}
+
+// New creates a bulkhead admitting at most maxInFlight concurrent calls
+// (minimum 1).
+func New(maxInFlight int) *Bulkhead {
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+	return &Bulkhead{sem: make(chan struct{}, maxInFlight)}
+}
+
+// MaxInFlight returns the concurrency limit.
+func (b *Bulkhead) MaxInFlight() int {
+	return cap(b.sem)
+}
+
+// InFlight returns the number of calls currently admitted.
+func (b *Bulkhead) InFlight() int {
+	return len(b.sem)
+}
+
+// Acquire admits a call, queueing for up to maxWait when the bulkhead is full.
+// With maxWait <= 0 excess calls are rejected immediately.
+func (b *Bulkhead) Acquire(ctx context.Context, maxWait time.Duration) Decision {
+	select {
+	case b.sem <- struct{}{}:
+		return Decision{Allowed: true, Reason: ReasonAllowed}
+	default:
+	}
+	if maxWait <= 0 {
+		return Decision{Reason: ReasonFull}
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+
+	select {
+	case b.sem <- struct{}{}:
+		return Decision{Allowed: true, Reason: ReasonAllowed, Waited: time.Since(start)}
+	case <-timer.C:
+		return Decision{Reason: ReasonWaitTimeout, Waited: time.Since(start)}
+	case <-ctx.Done():
+		return Decision{Reason: ReasonWaitCanceled, Waited: time.Since(start)}
+	}
+}
+
+// Release frees the slot of an admitted call.
+func (b *Bulkhead) Release() {
+	select {
+	case <-b.sem:
+	default:
+		// Unbalanced release; ignore rather than block.
+	}
+}
@@ -0,0 +1,66 @@
+package bulkhead
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBulkhead_RejectsWhenFull(t *testing.T) {
+	b := New(1)
+	if d := b.Acquire(context.Background(), 0); !d.Allowed || d.Reason != ReasonAllowed {
+		t.Fatalf("first acquire=%+v, want allowed", d)
+	}
+	if d := b.Acquire(context.Background(), 0); d.Allowed || d.Reason != ReasonFull {
+		t.Fatalf("second acquire=%+v, want %s", d, ReasonFull)
+	}
+	if b.InFlight() != 1 {
+		t.Fatalf("inFlight=%d, want 1", b.InFlight())
+	}
+
+	b.Release()
+	if d := b.Acquire(context.Background(), 0); !d.Allowed {
+		t.Fatalf("acquire after release=%+v, want allowed", d)
+	}
+}
+
+func TestBulkhead_QueuesUntilRelease(t *testing.T) {
+	b := New(1)
+	b.Acquire(context.Background(), 0)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		b.Release()
+	}()
+
+	d := b.Acquire(context.Background(), time.Second)
+	if !d.Allowed || d.Waited <= 0 {
+		t.Fatalf("queued acquire=%+v, want allowed after waiting", d)
+	}
+}
+
+func TestBulkhead_WaitTimeoutAndCancel(t *testing.T) {
+	b := New(1)
+	b.Acquire(context.Background(), 0)
+
+	if d := b.Acquire(context.Background(), 5*time.Millisecond); d.Allowed || d.Reason != ReasonWaitTimeout {
+		t.Fatalf("acquire=%+v, want %s", d, ReasonWaitTimeout)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if d := b.Acquire(ctx, time.Second); d.Allowed || d.Reason != ReasonWaitCanceled {
+		t.Fatalf("acquire=%+v, want %s", d, ReasonWaitCanceled)
+	}
+}
+
+func TestBulkhead_UnbalancedReleaseIsIgnored(t *testing.T) {
+	b := New(2)
+	b.Release()
+	if b.InFlight() != 0 {
+		t.Fatalf("inFlight=%d, want 0", b.InFlight())
+	}
+	if New(0).MaxInFlight() != 1 {
+		t.Fatalf("New(0) should clamp to 1")
+	}
+}
@@ -0,0 +1,3 @@
+// Package bulkhead limits the number of concurrent calls per policy key, so a
+// slow dependency cannot absorb every goroutine of its caller.
+package bulkhead
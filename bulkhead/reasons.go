@@ -0,0 +1,10 @@
+package bulkhead
+
+// Standard Decision.Reason strings.
+const (
+	ReasonAllowed      = "allowed"
+	ReasonNoBulkhead   = "no_bulkhead"
+	ReasonFull         = "bulkhead_full"
+	ReasonWaitTimeout  = "bulkhead_wait_timeout"
+	ReasonWaitCanceled = "bulkhead_wait_canceled"
+)
@@ -0,0 +1,58 @@
+package bulkhead
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/aponysus/recourse/internal"
+	"github.com/aponysus/recourse/policy"
+)
+
+// Registry manages one bulkhead per policy key.
+//
+// Lookups of existing bulkheads are lock-free; creating or resizing a bulkhead
+// copies the map under a mutex and publishes it atomically.
+type Registry struct {
+	mu        sync.Mutex // serializes writers
+	bulkheads atomic.Pointer[map[policy.PolicyKey]*Bulkhead]
+}
+
+// NewRegistry creates a new bulkhead registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Get returns the bulkhead for key, creating it on first use. It returns nil
+// when config.MaxInFlight is not positive. When the limit changes, a new
+// bulkhead replaces the old one; calls admitted by the old one still release
+// into it.
+func (r *Registry) Get(key policy.PolicyKey, config policy.ConcurrencyPolicy) *Bulkhead {
+	if config.MaxInFlight <= 0 {
+		return nil
+	}
+	if b, ok := r.lookup(key); ok && b.MaxInFlight() == config.MaxInFlight {
+		return b
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.lookup(key); ok && b.MaxInFlight() == config.MaxInFlight {
+		return b
+	}
+
+	b := New(config.MaxInFlight)
+	next := internal.CopyMap(r.bulkheads.Load(), 1)
+	next[key] = b
+	r.bulkheads.Store(&next)
+	return b
+}
+
+func (r *Registry) lookup(key policy.PolicyKey) (*Bulkhead, bool) {
+	m := r.bulkheads.Load()
+	if m == nil {
+		return nil, false
+	}
+	b, ok := (*m)[key]
+	return b, ok
+}
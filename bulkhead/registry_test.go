@@ -0,0 +1,33 @@
+package bulkhead
+
+import (
+	"testing"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestRegistry_DisabledReturnsNil(t *testing.T) {
+	reg := NewRegistry()
+	if b := reg.Get(policy.ParseKey("svc.Method"), policy.ConcurrencyPolicy{}); b != nil {
+		t.Fatal("expected nil bulkhead when MaxInFlight is 0")
+	}
+}
+
+func TestRegistry_ReusesPerKeyAndResizes(t *testing.T) {
+	reg := NewRegistry()
+	key := policy.ParseKey("svc.Method")
+
+	b1 := reg.Get(key, policy.ConcurrencyPolicy{MaxInFlight: 2})
+	b2 := reg.Get(key, policy.ConcurrencyPolicy{MaxInFlight: 2})
+	if b1 == nil || b1 != b2 {
+		t.Fatal("expected the same bulkhead for the same key")
+	}
+	if other := reg.Get(policy.ParseKey("svc.Other"), policy.ConcurrencyPolicy{MaxInFlight: 2}); other == b1 {
+		t.Fatal("expected a distinct bulkhead for a different key")
+	}
+
+	b3 := reg.Get(key, policy.ConcurrencyPolicy{MaxInFlight: 5})
+	if b3 == b1 || b3.MaxInFlight() != 5 {
+		t.Fatalf("expected a resized bulkhead, got max=%d", b3.MaxInFlight())
+	}
+}
@@ -4,27 +4,20 @@ import (
 	"context"
 	"sync"
 	"time"
+
+	"github.com/aponysus/recourse/policy"
 )
 
 // ConsecutiveFailureBreaker implements a circuit breaker that opens after N consecutive failures.
 type ConsecutiveFailureBreaker struct {
 	mu sync.Mutex
-
-	state State
+	machine
 
 	// Config
 	threshold int
-	cooldown  time.Duration
-	maxProbes int // Number of requests allowed in Half-Open state (usually 1)
 
 	// State variables
 	consecutiveFailures int
-	openTime            time.Time
-	probesSent          int
-	probesSuccessful    int
-	probesRequired      int // Number of consecutive successes needed to close
-
-	nowFn func() time.Time
 }
 
 // NewConsecutiveFailureBreaker creates a new breaker.
@@ -34,16 +27,12 @@ func NewConsecutiveFailureBreaker(threshold int, cooldown time.Duration) *Consec
 	if threshold <= 0 {
 		threshold = 5 // Default
 	}
-	if cooldown <= 0 {
-		cooldown = 10 * time.Second // Default
-	}
-	return &ConsecutiveFailureBreaker{
-		state:          StateClosed,
-		threshold:      threshold,
-		cooldown:       cooldown,
-		maxProbes:      1, // Single probe by default
-		probesRequired: 1, // Close after 1 success
+	cb := &ConsecutiveFailureBreaker{
+		machine:   newMachine(cooldown),
+		threshold: threshold,
 	}
+	cb.onClose = func() { cb.consecutiveFailures = 0 }
+	return cb
 }
 
 func (cb *ConsecutiveFailureBreaker) State() State {
@@ -55,23 +44,7 @@ func (cb *ConsecutiveFailureBreaker) State() State {
 func (cb *ConsecutiveFailureBreaker) Allow(ctx context.Context) Decision {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
-
-	state := cb.updateStateLocked()
-
-	if state == StateOpen {
-		// fmt.Printf("BREAKER: Allow=False (Open)\n")
-		return Decision{Allowed: false, State: StateOpen, Reason: ReasonCircuitOpen}
-	}
-
-	if state == StateHalfOpen {
-		if cb.probesSent >= cb.maxProbes {
-			return Decision{Allowed: false, State: StateHalfOpen, Reason: ReasonCircuitHalfOpenProbeLimit}
-		}
-		cb.probesSent++
-		return Decision{Allowed: true, State: StateHalfOpen}
-	}
-
-	return Decision{Allowed: true, State: StateClosed}
+	return cb.allowLocked(ctx)
 }
 
 func (cb *ConsecutiveFailureBreaker) RecordSuccess(ctx context.Context) {
@@ -83,14 +56,7 @@ func (cb *ConsecutiveFailureBreaker) RecordSuccess(ctx context.Context) {
 	if state == StateClosed {
 		cb.consecutiveFailures = 0
 	} else if state == StateHalfOpen {
-		cb.probesSuccessful++
-		// If we met requirements, close it
-		if cb.probesSuccessful >= cb.probesRequired {
-			cb.transitionTo(StateClosed)
-		} else {
-			// Free a probe slot until required successes are met.
-			cb.probesSent--
-		}
+		cb.recordProbeSuccessLocked()
 	}
 	// If Open, ignoring success (technically shouldn't happen unless Allow was bypassed or race)
 }
@@ -104,6 +70,7 @@ func (cb *ConsecutiveFailureBreaker) RecordFailure(ctx context.Context) {
 	if state == StateClosed {
 		cb.consecutiveFailures++
 		if cb.consecutiveFailures >= cb.threshold {
+			cb.consecutiveFailures = 0
 			cb.transitionTo(StateOpen)
 		}
 	} else if state == StateHalfOpen {
@@ -112,41 +79,128 @@ func (cb *ConsecutiveFailureBreaker) RecordFailure(ctx context.Context) {
 	}
 }
 
-func (cb *ConsecutiveFailureBreaker) updateStateLocked() State {
-	if cb.state == StateOpen {
-		if cb.now().Sub(cb.openTime) >= cb.cooldown {
-			cb.transitionTo(StateHalfOpen)
+// Trip opens the breaker immediately.
+func (cb *ConsecutiveFailureBreaker) Trip(ctx context.Context) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.tripLocked()
+}
+
+// SetClock overrides the breaker clock, primarily for tests.
+func (cb *ConsecutiveFailureBreaker) SetClock(f func() time.Time) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.nowFn = f
+}
+
+// machine is the open and half-open state machine shared by the breakers.
+// Breakers decide when to open it and guard it with their own mutex.
+type machine struct {
+	state State
+
+	cooldown  time.Duration
+	maxProbes int // Number of requests allowed in Half-Open state (usually 1)
+
+	openTime         time.Time
+	halfOpenTime     time.Time
+	probesSent       int
+	probesSuccessful int
+	probesRequired   int // Number of consecutive successes needed to close
+
+	onClose func() // Resets the breaker's closed-state counters.
+	nowFn   func() time.Time
+}
+
+func newMachine(cooldown time.Duration) machine {
+	if cooldown <= 0 {
+		cooldown = 10 * time.Second // Default
+	}
+	return machine{
+		state:          StateClosed,
+		cooldown:       cooldown,
+		maxProbes:      1, // Single probe by default
+		probesRequired: 1, // Close after 1 success
+	}
+}
+
+func (m *machine) allowLocked(ctx context.Context) Decision {
+	state := m.updateStateLocked()
+
+	if state == StateOpen {
+		return Decision{Allowed: false, State: StateOpen, Reason: ReasonCircuitOpen}
+	}
+
+	if state == StateHalfOpen {
+		if m.probesSent >= m.maxProbes {
+			return Decision{Allowed: false, State: StateHalfOpen, Reason: ReasonCircuitHalfOpenProbeLimit}
+		}
+		// Probes prefer higher-priority traffic: low-priority requests may only
+		// probe once the breaker has been half-open for a full cooldown.
+		if p, _ := policy.PriorityFromContext(ctx); p.Rank() < 0 && m.now().Sub(m.halfOpenTime) < m.cooldown {
+			return Decision{Allowed: false, State: StateHalfOpen, Reason: ReasonCircuitHalfOpenPriority}
+		}
+		m.probesSent++
+		return Decision{Allowed: true, State: StateHalfOpen}
+	}
+
+	return Decision{Allowed: true, State: StateClosed}
+}
+
+// recordProbeSuccessLocked counts a successful half-open probe and closes the
+// circuit once enough have succeeded.
+func (m *machine) recordProbeSuccessLocked() {
+	m.probesSuccessful++
+	// If we met requirements, close it
+	if m.probesSuccessful >= m.probesRequired {
+		m.transitionTo(StateClosed)
+	} else {
+		// Free a probe slot until required successes are met.
+		m.probesSent--
+	}
+}
+
+// tripLocked opens the circuit unless it is already open. Closed-state
+// counters are reset as when the breaker opens on its own.
+func (m *machine) tripLocked() {
+	if m.updateStateLocked() == StateOpen {
+		return
+	}
+	if m.onClose != nil {
+		m.onClose()
+	}
+	m.transitionTo(StateOpen)
+}
+
+func (m *machine) updateStateLocked() State {
+	if m.state == StateOpen {
+		if m.now().Sub(m.openTime) >= m.cooldown {
+			m.transitionTo(StateHalfOpen)
 		}
 	}
-	return cb.state
+	return m.state
 }
 
-func (cb *ConsecutiveFailureBreaker) transitionTo(newState State) {
-	cb.state = newState
+func (m *machine) transitionTo(newState State) {
+	m.state = newState
 	switch newState {
 	case StateClosed:
-		cb.consecutiveFailures = 0
-		cb.probesSent = 0
-		cb.probesSuccessful = 0
+		if m.onClose != nil {
+			m.onClose()
+		}
+		m.probesSent = 0
+		m.probesSuccessful = 0
 	case StateOpen:
-		cb.openTime = cb.now()
-		cb.consecutiveFailures = 0 // Reset counter so next time we start fresh? Or keep? Usually irrelevant in open.
+		m.openTime = m.now()
 	case StateHalfOpen:
-		cb.probesSent = 0
-		cb.probesSuccessful = 0
+		m.halfOpenTime = m.now()
+		m.probesSent = 0
+		m.probesSuccessful = 0
 	}
 }
 
-func (cb *ConsecutiveFailureBreaker) now() time.Time {
-	if cb.nowFn != nil {
-		return cb.nowFn()
+func (m *machine) now() time.Time {
+	if m.nowFn != nil {
+		return m.nowFn()
 	}
 	return time.Now()
 }
-
-// SetClock overrides the breaker clock, primarily for tests.
-func (cb *ConsecutiveFailureBreaker) SetClock(f func() time.Time) {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-	cb.nowFn = f
-}
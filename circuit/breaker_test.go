@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/aponysus/recourse/policy"
 )
 
 func TestConsecutiveFailureBreaker_Transitions(t *testing.T) {
@@ -107,6 +109,37 @@ func TestConsecutiveFailureBreaker_DefaultClockKeepsOpen(t *testing.T) {
 	}
 }
 
+func TestConsecutiveFailureBreaker_HalfOpenPrefersHigherPriority(t *testing.T) {
+	cooldown := time.Second
+	cb := NewConsecutiveFailureBreaker(1, cooldown)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cb.SetClock(clock.Now)
+
+	low := policy.WithPriority(context.Background(), policy.PriorityLow)
+	cb.RecordFailure(context.Background())
+	clock.Advance(cooldown)
+
+	d := cb.Allow(low)
+	if d.Allowed || d.Reason != ReasonCircuitHalfOpenPriority {
+		t.Fatalf("decision=%+v, want low-priority probe denied", d)
+	}
+	if d := cb.Allow(context.Background()); !d.Allowed {
+		t.Fatalf("expected normal-priority probe to be allowed")
+	}
+
+	// Once the probe fails and the breaker sits half-open for a full cooldown
+	// without other traffic, low-priority requests may probe.
+	cb.RecordFailure(context.Background())
+	clock.Advance(cooldown)
+	if d := cb.Allow(low); d.Allowed {
+		t.Fatalf("expected low-priority probe denied right after reopening")
+	}
+	clock.Advance(cooldown)
+	if d := cb.Allow(low); !d.Allowed {
+		t.Fatalf("decision=%+v, want low-priority probe allowed after grace", d)
+	}
+}
+
 type fakeClock struct {
 	now time.Time
 }
@@ -118,3 +151,41 @@ func (f *fakeClock) Now() time.Time {
 func (f *fakeClock) Advance(d time.Duration) {
 	f.now = f.now.Add(d)
 }
+
+func TestBreakers_Trip(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+	for _, cb := range []interface {
+		CircuitBreaker
+		Tripper
+		SetClock(func() time.Time)
+	}{
+		NewConsecutiveFailureBreaker(5, time.Second),
+		NewFailureRateBreaker(0.5, 20, time.Minute, time.Second),
+	} {
+		cb.SetClock(func() time.Time { return now })
+		cb.RecordFailure(ctx)
+		cb.Trip(ctx)
+		if cb.State() != StateOpen {
+			t.Fatalf("%T: state=%v after Trip, want open", cb, cb.State())
+		}
+
+		// Tripping an open breaker keeps its cooldown.
+		now = now.Add(600 * time.Millisecond)
+		cb.Trip(ctx)
+		now = now.Add(600 * time.Millisecond)
+		if cb.State() != StateHalfOpen {
+			t.Fatalf("%T: state=%v after cooldown, want half-open", cb, cb.State())
+		}
+
+		// A successful probe closes it with no failures left over.
+		if !cb.Allow(ctx).Allowed {
+			t.Fatalf("%T: probe denied", cb)
+		}
+		cb.RecordSuccess(ctx)
+		cb.RecordFailure(ctx)
+		if cb.State() != StateClosed {
+			t.Fatalf("%T: state=%v, want closed", cb, cb.State())
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package circuit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateBuckets is the number of buckets a FailureRateBreaker divides its
+// window into. Outcomes age out of the window one bucket at a time.
+const rateBuckets = 10
+
+// FailureRateBreaker implements a circuit breaker that opens when the fraction
+// of failed calls in a sliding window reaches a threshold, once the window
+// holds a minimum number of calls.
+type FailureRateBreaker struct {
+	mu sync.Mutex
+	machine
+
+	// Config
+	rate        float64
+	minRequests int
+	bucketWidth time.Duration
+
+	// State variables
+	buckets [rateBuckets]rateBucket
+}
+
+type rateBucket struct {
+	start    time.Time
+	requests int
+	failures int
+}
+
+// NewFailureRateBreaker creates a new breaker.
+// rate: Failure fraction (0-1] that opens the circuit.
+// minRequests: Calls in the window required before the rate is considered.
+// window: Duration the failure rate is measured over.
+// cooldown: Duration to stay open.
+func NewFailureRateBreaker(rate float64, minRequests int, window, cooldown time.Duration) *FailureRateBreaker {
+	if rate <= 0 || rate > 1 {
+		rate = 0.5 // Default
+	}
+	if minRequests <= 0 {
+		minRequests = 20 // Default
+	}
+	if window <= 0 {
+		window = 30 * time.Second // Default
+	}
+	cb := &FailureRateBreaker{
+		machine:     newMachine(cooldown),
+		rate:        rate,
+		minRequests: minRequests,
+		bucketWidth: max(window/rateBuckets, time.Nanosecond),
+	}
+	cb.onClose = cb.reset
+	return cb
+}
+
+func (cb *FailureRateBreaker) State() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.updateStateLocked()
+}
+
+func (cb *FailureRateBreaker) Allow(ctx context.Context) Decision {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.allowLocked(ctx)
+}
+
+func (cb *FailureRateBreaker) RecordSuccess(ctx context.Context) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.updateStateLocked() {
+	case StateClosed:
+		cb.record(false)
+	case StateHalfOpen:
+		cb.recordProbeSuccessLocked()
+	}
+}
+
+func (cb *FailureRateBreaker) RecordFailure(ctx context.Context) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.updateStateLocked() {
+	case StateClosed:
+		cb.record(true)
+		if requests, failures := cb.totals(); requests >= cb.minRequests && float64(failures) >= cb.rate*float64(requests) {
+			cb.reset()
+			cb.transitionTo(StateOpen)
+		}
+	case StateHalfOpen:
+		cb.transitionTo(StateOpen)
+	}
+}
+
+// Trip opens the breaker immediately.
+func (cb *FailureRateBreaker) Trip(ctx context.Context) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.tripLocked()
+}
+
+// SetClock overrides the breaker clock, primarily for tests.
+func (cb *FailureRateBreaker) SetClock(f func() time.Time) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.nowFn = f
+}
+
+// record counts one call in the current bucket, clearing the bucket first if
+// it last held calls from an earlier pass over the window.
+func (cb *FailureRateBreaker) record(failed bool) {
+	now := cb.now()
+	start := now.Truncate(cb.bucketWidth)
+	i := (start.UnixNano() / int64(cb.bucketWidth)) % rateBuckets
+	if i < 0 {
+		i += rateBuckets
+	}
+	b := &cb.buckets[i]
+	if !b.start.Equal(start) {
+		*b = rateBucket{start: start}
+	}
+	b.requests++
+	if failed {
+		b.failures++
+	}
+}
+
+// totals sums the buckets still inside the window.
+func (cb *FailureRateBreaker) totals() (requests, failures int) {
+	oldest := cb.now().Truncate(cb.bucketWidth).Add(-cb.bucketWidth * (rateBuckets - 1))
+	for _, b := range cb.buckets {
+		if !b.start.Before(oldest) {
+			requests += b.requests
+			failures += b.failures
+		}
+	}
+	return requests, failures
+}
+
+func (cb *FailureRateBreaker) reset() {
+	cb.buckets = [rateBuckets]rateBucket{}
+}
@@ -0,0 +1,97 @@
+package circuit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestFailureRateBreaker_OpensOnRate(t *testing.T) {
+	cb := NewFailureRateBreaker(0.5, 4, time.Second, 50*time.Millisecond)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cb.SetClock(clock.Now)
+	ctx := context.Background()
+
+	// Below the minimum number of requests the rate is not considered.
+	cb.RecordFailure(ctx)
+	cb.RecordFailure(ctx)
+	cb.RecordSuccess(ctx)
+	if cb.State() != StateClosed {
+		t.Fatalf("expected Closed below minimum requests, got %v", cb.State())
+	}
+
+	// 3 failures of 4 calls reach 50%.
+	cb.RecordFailure(ctx)
+	if cb.State() != StateOpen {
+		t.Fatalf("expected Open at 75%% failures, got %v", cb.State())
+	}
+	if d := cb.Allow(ctx); d.Allowed || d.Reason != ReasonCircuitOpen {
+		t.Fatalf("decision=%+v, want rejected while open", d)
+	}
+
+	// Half-open probe success closes it with an empty window.
+	clock.Advance(50 * time.Millisecond)
+	if d := cb.Allow(ctx); !d.Allowed || d.State != StateHalfOpen {
+		t.Fatalf("decision=%+v, want half-open probe", d)
+	}
+	cb.RecordSuccess(ctx)
+	if cb.State() != StateClosed {
+		t.Fatalf("expected Closed after probe success, got %v", cb.State())
+	}
+	cb.RecordFailure(ctx)
+	if cb.State() != StateClosed {
+		t.Fatalf("expected window reset on close, got %v", cb.State())
+	}
+}
+
+func TestFailureRateBreaker_BelowRate(t *testing.T) {
+	cb := NewFailureRateBreaker(0.5, 4, time.Second, time.Second)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cb.SetClock(clock.Now)
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		cb.RecordSuccess(ctx)
+		cb.RecordSuccess(ctx)
+		cb.RecordFailure(ctx)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("expected Closed at 33%% failures, got %v", cb.State())
+	}
+}
+
+func TestFailureRateBreaker_WindowSlides(t *testing.T) {
+	cb := NewFailureRateBreaker(0.5, 4, time.Second, time.Second)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	cb.SetClock(clock.Now)
+	ctx := context.Background()
+
+	cb.RecordFailure(ctx)
+	cb.RecordFailure(ctx)
+	cb.RecordFailure(ctx)
+
+	// The earlier failures leave the window before the fourth call.
+	clock.Advance(1100 * time.Millisecond)
+	cb.RecordFailure(ctx)
+	if cb.State() != StateClosed {
+		t.Fatalf("expected Closed after old failures aged out, got %v", cb.State())
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	cb.RecordFailure(ctx)
+	cb.RecordSuccess(ctx)
+	cb.RecordFailure(ctx)
+	if cb.State() != StateOpen {
+		t.Fatalf("expected Open with 3 of 4 failures in window, got %v", cb.State())
+	}
+}
+
+func TestRegistry_FailureRateBreaker(t *testing.T) {
+	reg := NewRegistry()
+	cb := reg.Get(policy.ParseKey("svc.Method"), policy.CircuitPolicy{Enabled: true, FailureRateThreshold: 0.5, MinimumRequests: 10, Window: time.Second})
+	if _, ok := cb.(*FailureRateBreaker); !ok {
+		t.Fatalf("breaker=%T, want *FailureRateBreaker", cb)
+	}
+}
@@ -2,34 +2,53 @@ package circuit
 
 import (
 	"sync"
+	"sync/atomic"
 
+	"github.com/aponysus/recourse/internal"
 	"github.com/aponysus/recourse/policy"
 )
 
+// MaxTenantBreakers bounds how many tenant-scoped breakers a Registry creates.
+// Beyond it, new tenants share their key's untenanted breaker.
+const MaxTenantBreakers = 4096
+
+type breakerKey struct {
+	key    policy.PolicyKey
+	tenant string
+}
+
 // Registry manages circuit breakers for different policies.
+//
+// Lookups of existing breakers are lock-free; creating a breaker for a new key
+// copies the map under a mutex and publishes it atomically.
 type Registry struct {
-	mu       sync.RWMutex
-	breakers map[policy.PolicyKey]CircuitBreaker
+	mu       sync.Mutex // serializes writers
+	breakers atomic.Pointer[map[breakerKey]CircuitBreaker]
+	tenanted int // tenant-scoped breakers created; guarded by mu
 }
 
 // NewRegistry creates a new circuit breaker registry.
 func NewRegistry() *Registry {
-	return &Registry{
-		breakers: make(map[policy.PolicyKey]CircuitBreaker),
-	}
+	return &Registry{}
 }
 
 // Get returns an existing breaker or creates a new one for the given policy.
 func (r *Registry) Get(key policy.PolicyKey, config policy.CircuitPolicy) CircuitBreaker {
+	return r.GetForTenant(key, "", config)
+}
+
+// GetForTenant is like Get, but when config.PerTenant is set each tenant gets
+// its own breaker for key. An empty tenant uses the key's shared breaker.
+func (r *Registry) GetForTenant(key policy.PolicyKey, tenant string, config policy.CircuitPolicy) CircuitBreaker {
 	if !config.Enabled {
 		return nil
 	}
+	if !config.PerTenant {
+		tenant = ""
+	}
+	bk := breakerKey{key: key, tenant: tenant}
 
-	r.mu.RLock()
-	cb, ok := r.breakers[key]
-	r.mu.RUnlock()
-
-	if ok {
+	if cb, ok := r.lookup(bk); ok {
 		return cb
 	}
 
@@ -37,12 +56,42 @@ func (r *Registry) Get(key policy.PolicyKey, config policy.CircuitPolicy) Circui
 	defer r.mu.Unlock()
 
 	// Double check
-	if cb, ok := r.breakers[key]; ok {
+	if cb, ok := r.lookup(bk); ok {
 		return cb
 	}
 
+	if tenant != "" && r.tenanted >= MaxTenantBreakers {
+		bk.tenant = ""
+		if cb, ok := r.lookup(bk); ok {
+			return cb
+		}
+	}
+
 	// Create new breaker
-	cb = NewConsecutiveFailureBreaker(config.Threshold, config.Cooldown)
-	r.breakers[key] = cb
+	cb := newBreaker(config)
+	next := internal.CopyMap(r.breakers.Load(), 1)
+	next[bk] = cb
+	r.breakers.Store(&next)
+	if bk.tenant != "" {
+		r.tenanted++
+	}
 	return cb
 }
+
+// newBreaker creates the breaker config describes: failure-rate based when
+// FailureRateThreshold is set, and consecutive-failure based otherwise.
+func newBreaker(config policy.CircuitPolicy) CircuitBreaker {
+	if config.FailureRateThreshold > 0 {
+		return NewFailureRateBreaker(config.FailureRateThreshold, config.MinimumRequests, config.Window, config.Cooldown)
+	}
+	return NewConsecutiveFailureBreaker(config.Threshold, config.Cooldown)
+}
+
+func (r *Registry) lookup(bk breakerKey) (CircuitBreaker, bool) {
+	m := r.breakers.Load()
+	if m == nil {
+		return nil, false
+	}
+	cb, ok := (*m)[bk]
+	return cb, ok
+}
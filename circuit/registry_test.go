@@ -47,3 +47,43 @@ func TestRegistry_ReusesBreakerPerKey(t *testing.T) {
 		t.Fatalf("expected open after 2 failures, got %v", cb1.State())
 	}
 }
+
+func TestRegistry_GetForTenant(t *testing.T) {
+	reg := NewRegistry()
+	key := policy.ParseKey("svc.Method")
+	shared := policy.CircuitPolicy{Enabled: true, Threshold: 1, Cooldown: time.Second}
+	perTenant := shared
+	perTenant.PerTenant = true
+
+	if reg.GetForTenant(key, "a", shared) != reg.Get(key, shared) {
+		t.Fatal("expected tenant to be ignored without PerTenant")
+	}
+
+	a := reg.GetForTenant(key, "a", perTenant)
+	b := reg.GetForTenant(key, "b", perTenant)
+	if a == nil || b == nil || a == b {
+		t.Fatal("expected distinct breakers per tenant")
+	}
+	if reg.GetForTenant(key, "a", perTenant) != a {
+		t.Fatal("expected the same breaker for the same tenant")
+	}
+	if reg.GetForTenant(key, "", perTenant) != reg.Get(key, shared) {
+		t.Fatal("expected empty tenant to use the shared breaker")
+	}
+
+	a.RecordFailure(context.Background())
+	if a.State() != StateOpen || b.State() != StateClosed {
+		t.Fatalf("states a=%v b=%v, want open/closed", a.State(), b.State())
+	}
+}
+
+func TestRegistry_GetForTenant_Cap(t *testing.T) {
+	reg := NewRegistry()
+	reg.tenanted = MaxTenantBreakers
+	key := policy.ParseKey("svc.Method")
+	cfg := policy.CircuitPolicy{Enabled: true, Threshold: 1, Cooldown: time.Second, PerTenant: true}
+
+	if reg.GetForTenant(key, "overflow", cfg) != reg.Get(key, cfg) {
+		t.Fatal("expected tenants beyond the cap to share the key's breaker")
+	}
+}
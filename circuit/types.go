@@ -16,6 +16,7 @@ const (
 const (
 	ReasonCircuitOpen               = "circuit_open"
 	ReasonCircuitHalfOpenProbeLimit = "circuit_half_open_probe_limit"
+	ReasonCircuitHalfOpenPriority   = "circuit_half_open_low_priority"
 )
 
 func (s State) String() string {
@@ -52,3 +53,12 @@ type CircuitBreaker interface {
 	// State returns the current state of the breaker.
 	State() State
 }
+
+// Tripper is implemented by breakers that can be opened directly, for
+// example when a dependency signals that it is shedding load. The built-in
+// breakers implement it.
+type Tripper interface {
+	// Trip opens the breaker regardless of its failure counts. An open
+	// breaker stays open with its cooldown unchanged.
+	Trip(ctx context.Context)
+}
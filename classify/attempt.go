@@ -0,0 +1,34 @@
+package classify
+
+import (
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// AttemptContext describes the attempt an AttemptClassifier classifies.
+type AttemptContext struct {
+	Key     policy.PolicyKey // Policy key of the call.
+	Attempt int              // Zero-based retry index; hedges share their primary's index.
+	IsHedge bool             // Whether the attempt is a hedge.
+	Elapsed time.Duration    // Time since the call started, at the end of the attempt.
+}
+
+// AttemptClassifier is optionally implemented by classifiers whose decision
+// depends on the attempt, for example to retry 429s only once or to stop
+// retrying after some time. The executor calls ClassifyAttempt instead of
+// Classify when a classifier implements it.
+type AttemptClassifier interface {
+	Classifier
+	ClassifyAttempt(value any, err error, ac AttemptContext) Outcome
+}
+
+// ClassifyAttempt classifies with c.ClassifyAttempt when c implements
+// AttemptClassifier, and with c.Classify otherwise. Wrapping classifiers use
+// it to pass the attempt through.
+func ClassifyAttempt(c Classifier, value any, err error, ac AttemptContext) Outcome {
+	if a, ok := c.(AttemptClassifier); ok {
+		return a.ClassifyAttempt(value, err, ac)
+	}
+	return c.Classify(value, err)
+}
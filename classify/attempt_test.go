@@ -0,0 +1,36 @@
+package classify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+type retryOnceClassifier struct{}
+
+func (retryOnceClassifier) Classify(_ any, err error) Outcome {
+	return AlwaysRetryOnError{}.Classify(nil, err)
+}
+
+func (retryOnceClassifier) ClassifyAttempt(_ any, err error, ac AttemptContext) Outcome {
+	if err != nil && ac.Attempt > 0 {
+		return Outcome{Kind: OutcomeNonRetryable, Reason: "retried_once"}
+	}
+	return AlwaysRetryOnError{}.Classify(nil, err)
+}
+
+func TestClassifyAttempt(t *testing.T) {
+	err := errors.New("boom")
+	ac := AttemptContext{Key: policy.PolicyKey{Name: "op"}, Attempt: 1}
+
+	if out := ClassifyAttempt(retryOnceClassifier{}, nil, err, ac); out.Reason != "retried_once" {
+		t.Fatalf("attempt classifier outcome=%+v, want retried_once", out)
+	}
+	if out := ClassifyAttempt(retryOnceClassifier{}, nil, err, AttemptContext{}); out.Kind != OutcomeRetryable {
+		t.Fatalf("first attempt kind=%v, want retryable", out.Kind)
+	}
+	if out := ClassifyAttempt(AlwaysRetryOnError{}, nil, err, ac); out.Kind != OutcomeRetryable {
+		t.Fatalf("plain classifier kind=%v, want retryable", out.Kind)
+	}
+}
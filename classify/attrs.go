@@ -0,0 +1,92 @@
+package classify
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Canonical outcome attribute keys. The built-in classifiers use these keys,
+// and dashboards and retry rules read them; custom classifiers should set
+// them through the Attr constructors so values have the same format.
+const (
+	AttrStatus     = "status"      // HTTP status code, e.g. "503".
+	AttrMethod     = "method"      // Upper-case HTTP method, e.g. "GET".
+	AttrGRPCCode   = "grpc_code"   // gRPC code name, e.g. "Unavailable".
+	AttrRetryAfter = "retry_after" // Server-requested delay, e.g. "2s".
+)
+
+// Attr is an outcome attribute built by StatusAttr, MethodAttr, GRPCCodeAttr,
+// RetryAfterAttr or Attribute.
+type Attr struct {
+	Key   string
+	Value string
+
+	retryAfter time.Duration
+}
+
+// StatusAttr returns the AttrStatus attribute for an HTTP status code.
+func StatusAttr(status int) Attr {
+	return Attr{Key: AttrStatus, Value: strconv.Itoa(status)}
+}
+
+// MethodAttr returns the AttrMethod attribute, upper-casing method.
+func MethodAttr(method string) Attr {
+	return Attr{Key: AttrMethod, Value: strings.ToUpper(strings.TrimSpace(method))}
+}
+
+// GRPCCodeAttr returns the AttrGRPCCode attribute for a gRPC code name, as
+// returned by the code's String method.
+func GRPCCodeAttr(code string) Attr {
+	return Attr{Key: AttrGRPCCode, Value: strings.TrimSpace(code)}
+}
+
+// RetryAfterAttr returns the AttrRetryAfter attribute. Retryable also sets
+// the outcome's BackoffOverride to d, as HTTPClassifier does for Retry-After.
+func RetryAfterAttr(d time.Duration) Attr {
+	return Attr{Key: AttrRetryAfter, Value: d.String(), retryAfter: d}
+}
+
+// Attribute returns a custom attribute. Keys and values must be
+// low-cardinality; never copy error messages or IDs into them.
+func Attribute(key, value string) Attr {
+	return Attr{Key: strings.TrimSpace(key), Value: value}
+}
+
+// Retryable returns a retryable outcome with reason and attrs.
+func Retryable(reason string, attrs ...Attr) Outcome {
+	out := newOutcome(OutcomeRetryable, reason, attrs)
+	for _, a := range attrs {
+		if a.Key == AttrRetryAfter && a.retryAfter > 0 {
+			out.BackoffOverride = a.retryAfter
+		}
+	}
+	return out
+}
+
+// Terminal returns a non-retryable outcome with reason and attrs.
+func Terminal(reason string, attrs ...Attr) Outcome {
+	return newOutcome(OutcomeNonRetryable, reason, attrs)
+}
+
+// Abort returns an outcome that stops the call immediately, with reason and
+// attrs.
+func Abort(reason string, attrs ...Attr) Outcome {
+	return newOutcome(OutcomeAbort, reason, attrs)
+}
+
+// newOutcome builds an outcome, skipping attributes with an empty key or
+// value. Later attributes win over earlier ones with the same key.
+func newOutcome(kind OutcomeKind, reason string, attrs []Attr) Outcome {
+	out := Outcome{Kind: kind, Reason: reason}
+	for _, a := range attrs {
+		if a.Key == "" || a.Value == "" {
+			continue
+		}
+		if out.Attributes == nil {
+			out.Attributes = make(map[string]string, len(attrs))
+		}
+		out.Attributes[a.Key] = a.Value
+	}
+	return out
+}
@@ -0,0 +1,37 @@
+package classify
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutcomeConstructors(t *testing.T) {
+	out := Retryable("throttled", StatusAttr(429), MethodAttr(" get "), RetryAfterAttr(2*time.Second), Attribute("", "dropped"))
+	want := Outcome{
+		Kind:            OutcomeRetryable,
+		Reason:          "throttled",
+		Attributes:      map[string]string{"status": "429", "method": "GET", "retry_after": "2s"},
+		BackoffOverride: 2 * time.Second,
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("Retryable=%+v, want %+v", out, want)
+	}
+
+	out = Terminal("invalid", GRPCCodeAttr("InvalidArgument"), RetryAfterAttr(time.Second))
+	if out.Kind != OutcomeNonRetryable || out.Attributes[AttrGRPCCode] != "InvalidArgument" || out.BackoffOverride != 0 {
+		t.Fatalf("Terminal=%+v", out)
+	}
+
+	if out := Abort("auth"); out.Kind != OutcomeAbort || out.Reason != "auth" || out.Attributes != nil {
+		t.Fatalf("Abort=%+v", out)
+	}
+}
+
+func TestOutcomeConstructors_MatchBuiltins(t *testing.T) {
+	builtin := HTTPClassifier{}.Classify(nil, httpErr{status: 503, method: "get"})
+	custom := Retryable("http_5xx", StatusAttr(503), MethodAttr("get"))
+	if !reflect.DeepEqual(builtin, custom) {
+		t.Fatalf("constructor outcome=%+v, builtin=%+v", custom, builtin)
+	}
+}
@@ -1,16 +1,52 @@
 package classify
 
+import "errors"
+
 // AutoClassifier delegates to a specific classifier based on the error type,
 // or falls back to a generic default.
 //
 // Behavior:
 // - If error implements HTTPError: uses HTTPClassifier.
+// - If a detector registered with RegisterDetector recognizes the error: uses
+// its outcome.
 // - Otherwise: uses AlwaysRetryOnError.
-type AutoClassifier struct{}
+//
+// For retryable non-HTTP errors whose chain holds a RetryAfterProvider with a
+// positive delay, the delay becomes the outcome's BackoffOverride and its
+// "retry_after" attribute.
+type AutoClassifier struct {
+	// HTTP classifies errors implementing HTTPError. The zero value uses the
+	// HTTPClassifier defaults.
+	HTTP HTTPClassifier
+
+	// codes holds the policy's retryable codes for detectors, if any.
+	codes *RetryableCodes
+}
 
-func (AutoClassifier) Classify(val any, err error) Outcome {
+func (c AutoClassifier) Classify(val any, err error) Outcome {
 	if _, ok := err.(HTTPError); ok {
-		return HTTPClassifier{}.Classify(val, err)
+		return c.HTTP.Classify(val, err)
 	}
-	return AlwaysRetryOnError{}.Classify(val, err)
+	if err != nil {
+		if out, ok := detect(val, err, c.codes); ok {
+			return out
+		}
+	}
+	out := AlwaysRetryOnError{}.Classify(val, err)
+	var rap RetryAfterProvider
+	if out.Kind == OutcomeRetryable && errors.As(err, &rap) {
+		if d, ok := rap.RetryAfter(); ok && d > 0 {
+			out.BackoffOverride = d
+			out.Attributes = map[string]string{AttrRetryAfter: d.String()}
+		}
+	}
+	return out
+}
+
+// WithRetryableCodes applies codes.HTTPStatuses to HTTP errors, and codes to
+// detectors implementing CodesDetector.
+func (c AutoClassifier) WithRetryableCodes(codes RetryableCodes) Classifier {
+	c.HTTP = c.HTTP.WithRetryableCodes(codes).(HTTPClassifier)
+	c.codes = &codes
+	return c
 }
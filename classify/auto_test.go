@@ -1,7 +1,9 @@
 package classify
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -29,3 +31,25 @@ func TestAutoClassifier_NonHTTPError(t *testing.T) {
 		t.Fatalf("out=%+v, want retryable_error", out)
 	}
 }
+
+type throttleErr struct{ wait time.Duration }
+
+func (e throttleErr) Error() string                     { return "throttled" }
+func (e throttleErr) RetryAfter() (time.Duration, bool) { return e.wait, e.wait != 0 }
+
+func TestAutoClassifier_RetryAfterProvider(t *testing.T) {
+	out := AutoClassifier{}.Classify(nil, fmt.Errorf("sdk: %w", throttleErr{wait: 3 * time.Second}))
+	if out.Kind != OutcomeRetryable || out.BackoffOverride != 3*time.Second || out.Attributes[AttrRetryAfter] != "3s" {
+		t.Fatalf("out=%+v, want retryable with 3s backoff override", out)
+	}
+
+	out = AutoClassifier{}.Classify(nil, throttleErr{})
+	if out.BackoffOverride != 0 || out.Attributes != nil {
+		t.Fatalf("out=%+v, want no backoff override without a delay", out)
+	}
+
+	out = AutoClassifier{}.Classify(nil, fmt.Errorf("%w: %w", context.Canceled, throttleErr{wait: time.Second}))
+	if out.Kind != OutcomeAbort || out.BackoffOverride != 0 {
+		t.Fatalf("out=%+v, want abort without backoff override", out)
+	}
+}
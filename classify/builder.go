@@ -0,0 +1,113 @@
+package classify
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorClassifier classifies errors by errors.Is targets, the classifier most
+// users would otherwise write by hand:
+//
+//	c := classify.New().
+//		RetryOn(io.ErrUnexpectedEOF, ErrThrottled).
+//		AbortOn(ErrAuth).
+//		Default(classify.OutcomeNonRetryable)
+//
+// Targets are checked in precedence order AbortOn, StopOn, RetryOn, so an
+// error matching several takes the strictest outcome. Errors matching no
+// target get the Default kind (retryable unless set). nil errors are
+// successes and context.Canceled always aborts.
+//
+// The methods return modified copies, so an ErrorClassifier can be shared
+// and extended safely.
+type ErrorClassifier struct {
+	retry []error
+	stop  []error
+	abort []error
+	def   OutcomeKind
+}
+
+// New returns an ErrorClassifier with no targets that retries every error.
+func New() ErrorClassifier {
+	return ErrorClassifier{def: OutcomeRetryable}
+}
+
+// RetryOn returns c retrying errors matching any of targets, with reason
+// "retry_on_match".
+func (c ErrorClassifier) RetryOn(targets ...error) ErrorClassifier {
+	c.retry = appendTargets(c.retry, targets)
+	return c
+}
+
+// StopOn returns c treating errors matching any of targets as non-retryable,
+// with reason "stop_on_match".
+func (c ErrorClassifier) StopOn(targets ...error) ErrorClassifier {
+	c.stop = appendTargets(c.stop, targets)
+	return c
+}
+
+// AbortOn returns c aborting the call on errors matching any of targets,
+// with reason "abort_on_match".
+func (c ErrorClassifier) AbortOn(targets ...error) ErrorClassifier {
+	c.abort = appendTargets(c.abort, targets)
+	return c
+}
+
+// Default returns c classifying unmatched errors as kind. OutcomeUnknown and
+// OutcomeSuccess leave the default unchanged.
+func (c ErrorClassifier) Default(kind OutcomeKind) ErrorClassifier {
+	switch kind {
+	case OutcomeRetryable, OutcomeNonRetryable, OutcomeAbort:
+		c.def = kind
+	}
+	return c
+}
+
+func (c ErrorClassifier) Classify(_ any, err error) Outcome {
+	if err == nil {
+		return Outcome{Kind: OutcomeSuccess, Reason: "success"}
+	}
+	if errors.Is(err, context.Canceled) {
+		return Outcome{Kind: OutcomeAbort, Reason: "context_canceled"}
+	}
+
+	switch {
+	case isAny(err, c.abort):
+		return Outcome{Kind: OutcomeAbort, Reason: "abort_on_match"}
+	case isAny(err, c.stop):
+		return Outcome{Kind: OutcomeNonRetryable, Reason: "stop_on_match"}
+	case isAny(err, c.retry):
+		return Outcome{Kind: OutcomeRetryable, Reason: "retry_on_match"}
+	}
+
+	switch c.def {
+	case OutcomeNonRetryable:
+		return Outcome{Kind: OutcomeNonRetryable, Reason: "non_retryable_error"}
+	case OutcomeAbort:
+		return Outcome{Kind: OutcomeAbort, Reason: "abort"}
+	default:
+		return Outcome{Kind: OutcomeRetryable, Reason: "retryable_error"}
+	}
+}
+
+// appendTargets appends the non-nil targets to a copy of list, so copies of
+// an ErrorClassifier never share appended targets.
+func appendTargets(list, targets []error) []error {
+	out := make([]error, len(list), len(list)+len(targets))
+	copy(out, list)
+	for _, t := range targets {
+		if t != nil {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func isAny(err error, targets []error) bool {
+	for _, t := range targets {
+		if errors.Is(err, t) {
+			return true
+		}
+	}
+	return false
+}
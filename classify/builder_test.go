@@ -0,0 +1,61 @@
+package classify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestErrorClassifier(t *testing.T) {
+	errThrottled := errors.New("throttled")
+	errAuth := errors.New("auth")
+	errInvalid := errors.New("invalid")
+
+	c := New().
+		RetryOn(io.ErrUnexpectedEOF, errThrottled).
+		StopOn(errInvalid).
+		AbortOn(errAuth).
+		Default(OutcomeNonRetryable)
+
+	for _, tc := range []struct {
+		err    error
+		kind   OutcomeKind
+		reason string
+	}{
+		{fmt.Errorf("read: %w", io.ErrUnexpectedEOF), OutcomeRetryable, "retry_on_match"},
+		{errThrottled, OutcomeRetryable, "retry_on_match"},
+		{errInvalid, OutcomeNonRetryable, "stop_on_match"},
+		{errAuth, OutcomeAbort, "abort_on_match"},
+		{errors.Join(errThrottled, errAuth), OutcomeAbort, "abort_on_match"},
+		{errors.New("other"), OutcomeNonRetryable, "non_retryable_error"},
+		{context.Canceled, OutcomeAbort, "context_canceled"},
+		{nil, OutcomeSuccess, "success"},
+	} {
+		out := c.Classify(nil, tc.err)
+		if out.Kind != tc.kind || out.Reason != tc.reason {
+			t.Errorf("Classify(%v)=%+v, want %v %s", tc.err, out, tc.kind, tc.reason)
+		}
+	}
+
+	if out := New().Classify(nil, errors.New("other")); out.Kind != OutcomeRetryable {
+		t.Fatalf("default kind=%v, want retryable", out.Kind)
+	}
+	if out := New().Default(OutcomeUnknown).Classify(nil, errors.New("other")); out.Kind != OutcomeRetryable {
+		t.Fatalf("Default(OutcomeUnknown) changed the default: %v", out.Kind)
+	}
+}
+
+func TestErrorClassifier_CopiesDoNotShareTargets(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	base := New().Default(OutcomeNonRetryable).RetryOn(errA)
+	withB := base.RetryOn(errB)
+
+	if out := base.Classify(nil, errB); out.Kind != OutcomeNonRetryable {
+		t.Fatalf("base retries errB after extension: %+v", out)
+	}
+	if out := withB.Classify(nil, errA); out.Kind != OutcomeRetryable {
+		t.Fatalf("extension lost errA: %+v", out)
+	}
+}
@@ -9,6 +9,8 @@ import (
 const (
 	ClassifierAlwaysRetryOnError = "always"
 	ClassifierHTTP               = "http"
+	ClassifierSQL                = "sql"
+	ClassifierNet                = "net"
 )
 
 // RegisterBuiltins registers core classifiers into reg.
@@ -18,6 +20,8 @@ func RegisterBuiltins(reg *Registry) {
 	}
 	reg.Register(ClassifierAlwaysRetryOnError, AlwaysRetryOnError{})
 	reg.Register(ClassifierHTTP, HTTPClassifier{})
+	reg.Register(ClassifierSQL, SQLClassifier{})
+	reg.Register(ClassifierNet, NetClassifier{})
 	reg.Register("auto", AutoClassifier{})
 }
 
@@ -115,6 +115,26 @@ func TestHTTPClassifier_TransportError_NonIdempotent(t *testing.T) {
 	}
 }
 
+type keyedHTTPError struct {
+	testHTTPError
+	key string
+}
+
+func (e keyedHTTPError) HTTPIdempotencyKey() string { return e.key }
+
+func TestHTTPClassifier_NonIdempotent_WithIdempotencyKey_Retryable(t *testing.T) {
+	c := HTTPClassifier{}
+	out := c.Classify(nil, keyedHTTPError{testHTTPError: testHTTPError{status: 503, method: "POST"}, key: "k1"})
+	if out.Kind != OutcomeRetryable || out.Reason != "http_5xx" {
+		t.Fatalf("out=%+v, want retryable http_5xx", out)
+	}
+
+	out = c.Classify(nil, keyedHTTPError{testHTTPError: testHTTPError{status: 503, method: "POST"}})
+	if out.Kind != OutcomeNonRetryable || out.Reason != "http_non_idempotent" {
+		t.Fatalf("out=%+v, want non-retryable without key", out)
+	}
+}
+
 func TestHTTPClassifier_CustomRetryable4xx(t *testing.T) {
 	c := HTTPClassifier{Retryable4xx: map[int]struct{}{409: {}}}
 	out := c.Classify(nil, testHTTPError{status: 409, method: "GET"})
@@ -123,6 +143,81 @@ func TestHTTPClassifier_CustomRetryable4xx(t *testing.T) {
 	}
 }
 
+func TestHTTPClassifier_Statuses(t *testing.T) {
+	c := HTTPClassifier{Statuses: map[int]OutcomeKind{
+		501: OutcomeNonRetryable,
+		425: OutcomeRetryable,
+		401: OutcomeAbort,
+		204: OutcomeRetryable,
+	}}
+	cases := []struct {
+		status int
+		method string
+		want   OutcomeKind
+		reason string
+	}{
+		{501, "GET", OutcomeNonRetryable, "http_non_retryable_status"},
+		{502, "GET", OutcomeRetryable, "http_5xx"},
+		{425, "GET", OutcomeRetryable, "http_425"},
+		{425, "POST", OutcomeNonRetryable, "http_non_idempotent"},
+		{401, "GET", OutcomeAbort, "http_abort_status"},
+		{204, "GET", OutcomeRetryable, "http_204"},
+	}
+	for _, tc := range cases {
+		out := c.Classify(nil, testHTTPError{status: tc.status, method: tc.method})
+		if out.Kind != tc.want || out.Reason != tc.reason {
+			t.Fatalf("%s %d: out=%+v, want %v %s", tc.method, tc.status, out, tc.want, tc.reason)
+		}
+	}
+
+	out := c.Classify(nil, testHTTPError{status: 425, method: "GET", retryAfter: time.Second, hasRetry: true})
+	if out.BackoffOverride != time.Second {
+		t.Fatalf("425 with Retry-After: out=%+v, want 1s backoff override", out)
+	}
+}
+
+func TestHTTPClassifier_RetryNonIdempotent(t *testing.T) {
+	c := HTTPClassifier{RetryNonIdempotent: true}
+	for _, status := range []int{0, 503, 429} {
+		if out := c.Classify(nil, testHTTPError{status: status, method: "POST"}); out.Kind != OutcomeRetryable {
+			t.Fatalf("POST %d: out=%+v, want retryable", status, out)
+		}
+	}
+	if out := c.Classify(nil, testHTTPError{status: 400, method: "POST"}); out.Kind != OutcomeNonRetryable {
+		t.Fatalf("POST 400: out=%+v, want non-retryable", out)
+	}
+}
+
+func TestHTTPClassifier_WithRetryableCodes(t *testing.T) {
+	if c := (HTTPClassifier{}).WithRetryableCodes(RetryableCodes{GRPCCodes: []string{"UNAVAILABLE"}}); c.(HTTPClassifier).RetryableStatuses != nil {
+		t.Fatal("nil HTTPStatuses should keep the defaults")
+	}
+
+	c := HTTPClassifier{}.WithRetryableCodes(RetryableCodes{HTTPStatuses: []int{409, 503}})
+	cases := []struct {
+		status int
+		want   OutcomeKind
+		reason string
+	}{
+		{409, OutcomeRetryable, "http_409"},
+		{503, OutcomeRetryable, "http_5xx"},
+		{500, OutcomeNonRetryable, "http_non_retryable_status"},
+		{429, OutcomeNonRetryable, "http_non_retryable_status"},
+		{0, OutcomeRetryable, "http_transport_error"},
+	}
+	for _, tc := range cases {
+		out := c.Classify(nil, testHTTPError{status: tc.status, method: "GET"})
+		if out.Kind != tc.want || out.Reason != tc.reason {
+			t.Fatalf("status %d: out=%+v, want %v %s", tc.status, out, tc.want, tc.reason)
+		}
+	}
+
+	auto := AutoClassifier{}.WithRetryableCodes(RetryableCodes{HTTPStatuses: []int{409}})
+	if out := auto.Classify(nil, testHTTPError{status: 409, method: "GET"}); out.Kind != OutcomeRetryable {
+		t.Fatalf("auto out=%+v, want retryable", out)
+	}
+}
+
 func TestRegisterBuiltins(t *testing.T) {
 	reg := NewRegistry()
 	RegisterBuiltins(reg)
@@ -133,6 +228,12 @@ func TestRegisterBuiltins(t *testing.T) {
 	if _, ok := reg.Get(ClassifierHTTP); !ok {
 		t.Fatalf("expected %q to be registered", ClassifierHTTP)
 	}
+	if _, ok := reg.Get(ClassifierSQL); !ok {
+		t.Fatalf("expected %q to be registered", ClassifierSQL)
+	}
+	if _, ok := reg.Get(ClassifierNet); !ok {
+		t.Fatalf("expected %q to be registered", ClassifierNet)
+	}
 	if _, ok := reg.Get("auto"); !ok {
 		t.Fatalf("expected %q to be registered", "auto")
 	}
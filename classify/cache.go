@@ -0,0 +1,192 @@
+package classify
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// CacheKeyFunc returns the memoization key for an attempt result, or false to
+// classify the result without the cache. Results with equal keys must
+// classify identically, and keys must be comparable.
+type CacheKeyFunc func(value any, err error) (any, bool)
+
+// maxCachedOutcomes bounds each cache generation. Once the current
+// generation is full it becomes the previous one, whose keys survive only if
+// they are hit again before the next rotation, so a cache holds at most twice
+// this many outcomes.
+const maxCachedOutcomes = 1024
+
+// Cached returns a classifier that memoizes c's outcomes by key, so hot paths
+// that see the same errors over and over skip parsing them again. A nil key
+// uses HTTPStatusKey.
+//
+// Cached outcomes share their Attributes map, so code that changes an
+// outcome's attributes must copy the map first, as the executor does.
+//
+// Attempt-aware classifiers (AttemptClassifier) are not cached when
+// classified with ClassifyAttempt, since their outcome depends on the
+// attempt. The policy's retryable codes (CodesClassifier) are part of the
+// cache key.
+func Cached(c Classifier, key CacheKeyFunc) Classifier {
+	if key == nil {
+		key = HTTPStatusKey
+	}
+	store := &outcomeCache{}
+	store.gen.Store(&cacheGen{})
+	return cached{inner: c, key: key, store: store}
+}
+
+// outcomeCache is a two-generation cache: lookups are lock-free, and a full
+// generation is retired wholesale rather than evicted key by key.
+type outcomeCache struct {
+	mu  sync.Mutex // serializes rotation
+	gen atomic.Pointer[cacheGen]
+}
+
+type cacheGen struct {
+	m    sync.Map // cacheKey -> Outcome
+	n    atomic.Int64
+	prev atomic.Pointer[cacheGen]
+}
+
+func (s *outcomeCache) load(k cacheKey) (Outcome, bool) {
+	g := s.gen.Load()
+	if v, ok := g.m.Load(k); ok {
+		return v.(Outcome), true
+	}
+	prev := g.prev.Load()
+	if prev == nil {
+		return Outcome{}, false
+	}
+	v, ok := prev.m.Load(k)
+	if !ok {
+		return Outcome{}, false
+	}
+	s.store(k, v.(Outcome))
+	return v.(Outcome), true
+}
+
+func (s *outcomeCache) store(k cacheKey, out Outcome) {
+	g := s.gen.Load()
+	if g.n.Load() >= maxCachedOutcomes {
+		g = s.rotate(g)
+	}
+	if _, loaded := g.m.LoadOrStore(k, out); !loaded {
+		g.n.Add(1)
+	}
+}
+
+// rotate replaces the full generation full with an empty one that keeps full
+// as its previous generation, dropping the one before.
+func (s *outcomeCache) rotate(full *cacheGen) *cacheGen {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g := s.gen.Load(); g != full {
+		return g
+	}
+	full.prev.Store(nil)
+	next := &cacheGen{}
+	next.prev.Store(full)
+	s.gen.Store(next)
+	return next
+}
+
+type cacheKey struct {
+	codes string
+	key   any
+}
+
+type cached struct {
+	inner Classifier
+	key   CacheKeyFunc
+	store *outcomeCache
+	codes string // Fingerprint of the retryable codes applied to inner.
+}
+
+func (c cached) Classify(value any, err error) Outcome {
+	k, ok := c.key(value, err)
+	if !ok {
+		return c.inner.Classify(value, err)
+	}
+	ck := cacheKey{codes: c.codes, key: k}
+	if out, ok := c.store.load(ck); ok {
+		return out
+	}
+	out := c.inner.Classify(value, err)
+	c.store.store(ck, out)
+	return out
+}
+
+func (c cached) ClassifyAttempt(value any, err error, ac AttemptContext) Outcome {
+	if a, ok := c.inner.(AttemptClassifier); ok {
+		return a.ClassifyAttempt(value, err, ac)
+	}
+	return c.Classify(value, err)
+}
+
+// WithRetryableCodes applies codes to the cached classifier when it supports
+// them. The result shares the cache, keyed separately per codes.
+func (c cached) WithRetryableCodes(codes RetryableCodes) Classifier {
+	if cc, ok := c.inner.(CodesClassifier); ok {
+		c.inner = cc.WithRetryableCodes(codes)
+		c.codes = fmt.Sprint(codes.HTTPStatuses, codes.GRPCCodes)
+	}
+	return c
+}
+
+type httpStatusKey struct {
+	typ            reflect.Type // Type of the error as returned.
+	httpTyp        reflect.Type // Type of the HTTPError in its chain.
+	status         int
+	method         string
+	idempotencyKey bool
+}
+
+// HTTPStatusKey keys results whose error chain holds an HTTPError by error
+// type, status, method and whether the request carried an idempotency key.
+// Errors carrying a Retry-After delay (see RetryAfterProvider), other errors
+// and nil errors are not cached.
+func HTTPStatusKey(_ any, err error) (any, bool) {
+	var he HTTPError
+	if !errors.As(err, &he) || hasRetryAfter(err, he) {
+		return nil, false
+	}
+	k := httpStatusKey{typ: reflect.TypeOf(err), httpTyp: reflect.TypeOf(he), status: he.HTTPStatusCode(), method: he.HTTPMethod()}
+	if ik, ok := he.(HTTPIdempotencyKeyer); ok {
+		k.idempotencyKey = ik.HTTPIdempotencyKey() != ""
+	}
+	return k, true
+}
+
+// hasRetryAfter reports whether he or any RetryAfterProvider in err's chain
+// carries a delay, which makes the outcome specific to this error.
+func hasRetryAfter(err error, he HTTPError) bool {
+	if _, ok := he.RetryAfter(); ok {
+		return true
+	}
+	var rap RetryAfterProvider
+	if !errors.As(err, &rap) {
+		return false
+	}
+	_, ok := rap.RetryAfter()
+	return ok
+}
+
+type messageKey struct {
+	typ reflect.Type
+	msg string
+}
+
+// MessageKey keys results by error type and message, for classifiers that
+// match messages such as MessageClassifier. Nil errors are not cached. It
+// suits low-cardinality messages: messages that embed IDs or addresses make
+// every key distinct, so the cache churns without ever hitting.
+func MessageKey(_ any, err error) (any, bool) {
+	if err == nil {
+		return nil, false
+	}
+	return messageKey{typ: reflect.TypeOf(err), msg: err.Error()}, true
+}
@@ -0,0 +1,176 @@
+package classify
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type countingClassifier struct {
+	inner Classifier
+	calls int
+}
+
+func (c *countingClassifier) Classify(value any, err error) Outcome {
+	c.calls++
+	return c.inner.Classify(value, err)
+}
+
+func TestCached(t *testing.T) {
+	inner := &countingClassifier{inner: HTTPClassifier{}}
+	c := Cached(inner, nil)
+
+	for i := 0; i < 3; i++ {
+		out := c.Classify(nil, testHTTPError{status: 503, method: "GET"})
+		if out.Kind != OutcomeRetryable || out.Reason != "http_5xx" || out.Attributes["status"] != "503" {
+			t.Fatalf("out=%+v, want retryable http_5xx", out)
+		}
+	}
+	if inner.calls != 1 {
+		t.Fatalf("inner calls=%d, want 1", inner.calls)
+	}
+
+	c.Classify(nil, testHTTPError{status: 503, method: "POST"})
+	c.Classify(nil, testHTTPError{status: 429, method: "GET", retryAfter: time.Second, hasRetry: true})
+	c.Classify(nil, testHTTPError{status: 429, method: "GET", retryAfter: time.Second, hasRetry: true})
+	c.Classify(nil, errors.New("plain"))
+	if inner.calls != 5 {
+		t.Fatalf("inner calls=%d, want 5 (new method, uncached Retry-After and plain errors)", inner.calls)
+	}
+}
+
+type throttledError struct {
+	error
+	d time.Duration
+}
+
+func (e throttledError) Unwrap() error                     { return e.error }
+func (e throttledError) RetryAfter() (time.Duration, bool) { return e.d, true }
+
+func TestHTTPStatusKey_WrappedAndRetryAfter(t *testing.T) {
+	base := testHTTPError{status: 503, method: "GET"}
+	direct, ok := HTTPStatusKey(nil, base)
+	if !ok {
+		t.Fatal("HTTPError not keyed")
+	}
+	wrapped, ok := HTTPStatusKey(nil, fmt.Errorf("call: %w", base))
+	if !ok {
+		t.Fatal("wrapped HTTPError not keyed")
+	}
+	if wrapped == direct {
+		t.Fatal("wrapped and direct errors share a key, though classifiers may see them differently")
+	}
+	if again, _ := HTTPStatusKey(nil, fmt.Errorf("other call: %w", base)); again != wrapped {
+		t.Fatalf("wrapped keys differ: %v and %v", again, wrapped)
+	}
+
+	if _, ok := HTTPStatusKey(nil, throttledError{error: base, d: time.Second}); ok {
+		t.Fatal("error with a RetryAfterProvider hint was keyed")
+	}
+	if _, ok := HTTPStatusKey(nil, fmt.Errorf("call: %w", throttledError{error: base, d: time.Second})); ok {
+		t.Fatal("wrapped error with a RetryAfterProvider hint was keyed")
+	}
+}
+
+func TestCached_RetryableCodes(t *testing.T) {
+	c := Cached(HTTPClassifier{}, nil)
+	narrowed := c.(CodesClassifier).WithRetryableCodes(RetryableCodes{HTTPStatuses: []int{502}})
+	if out := c.Classify(nil, testHTTPError{status: 503, method: "GET"}); out.Kind != OutcomeRetryable {
+		t.Fatalf("default 503 out=%+v, want retryable", out)
+	}
+	if out := narrowed.Classify(nil, testHTTPError{status: 503, method: "GET"}); out.Kind != OutcomeNonRetryable {
+		t.Fatalf("narrowed 503 out=%+v, want non-retryable", out)
+	}
+}
+
+func TestCached_AttemptClassifierBypassesCache(t *testing.T) {
+	c := Cached(retryOnceClassifier{}, MessageKey)
+	err := errors.New("boom")
+	if out := ClassifyAttempt(c, nil, err, AttemptContext{}); out.Kind != OutcomeRetryable {
+		t.Fatalf("first attempt out=%+v, want retryable", out)
+	}
+	if out := ClassifyAttempt(c, nil, err, AttemptContext{Attempt: 1}); out.Reason != "retried_once" {
+		t.Fatalf("second attempt out=%+v, want retried_once", out)
+	}
+}
+
+func TestCached_BoundedAndEvicting(t *testing.T) {
+	inner := &countingClassifier{inner: AlwaysRetryOnError{}}
+	c := Cached(inner, MessageKey)
+	hot := errors.New("hot")
+	for i := 0; i < 3*maxCachedOutcomes; i++ {
+		c.Classify(nil, fmt.Errorf("error %d", i))
+		c.Classify(nil, hot)
+	}
+
+	g := c.(cached).store.gen.Load()
+	prev := g.prev.Load()
+	if prev == nil || prev.prev.Load() != nil {
+		t.Fatalf("want exactly two generations after rotating")
+	}
+	if n := g.n.Load() + prev.n.Load(); n > 2*maxCachedOutcomes {
+		t.Fatalf("cached entries=%d, want at most %d", n, 2*maxCachedOutcomes)
+	}
+
+	calls := inner.calls
+	c.Classify(nil, hot)
+	c.Classify(nil, fmt.Errorf("error %d", 3*maxCachedOutcomes-1))
+	if inner.calls != calls {
+		t.Fatalf("inner calls=%d, want hot and recent keys still cached", inner.calls-calls)
+	}
+	c.Classify(nil, fmt.Errorf("error %d", 0))
+	if inner.calls != calls+1 {
+		t.Fatalf("oldest key was not evicted")
+	}
+}
+
+var benchOutcome Outcome
+
+func BenchmarkHTTPClassifier(b *testing.B) {
+	err := testHTTPError{status: 503, method: "get"}
+	c := HTTPClassifier{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchOutcome = c.Classify(nil, err)
+	}
+}
+
+func BenchmarkHTTPClassifier_Cached(b *testing.B) {
+	err := testHTTPError{status: 503, method: "get"}
+	c := Cached(HTTPClassifier{}, nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchOutcome = c.Classify(nil, err)
+	}
+}
+
+func benchMessageClassifier(b *testing.B) Classifier {
+	c, err := NewMessageClassifier(
+		MessageRule{Pattern: `(?i)quota .* exceeded`, Kind: OutcomeNonRetryable, Reason: "quota"},
+		MessageRule{Pattern: `^invalid (argument|request)`, Kind: OutcomeNonRetryable, Reason: "invalid"},
+		MessageRule{Contains: "throttl", IgnoreCase: true, Kind: OutcomeRetryable, Reason: "throttled"},
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return c
+}
+
+func BenchmarkMessageClassifier(b *testing.B) {
+	err := errors.New("upstream: request Throttled, slow down")
+	c := benchMessageClassifier(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchOutcome = c.Classify(nil, err)
+	}
+}
+
+func BenchmarkMessageClassifier_Cached(b *testing.B) {
+	err := errors.New("upstream: request Throttled, slow down")
+	c := Cached(benchMessageClassifier(b), MessageKey)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchOutcome = c.Classify(nil, err)
+	}
+}
@@ -0,0 +1,128 @@
+// Package classifytest provides table-driven checks for classify.Classifier
+// implementations.
+package classifytest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/aponysus/recourse/classify"
+)
+
+// Case is one classification to check.
+type Case struct {
+	Name  string
+	Value any
+	Err   error
+
+	// Attempt, when set, classifies with classify.ClassifyAttempt so
+	// attempt-aware classifiers see it.
+	Attempt *classify.AttemptContext
+
+	// Kind is the expected outcome kind.
+	Kind classify.OutcomeKind
+	// Reason is the expected reason; empty skips the check.
+	Reason string
+	// BackoffOverride is the expected backoff override; zero expects none.
+	BackoffOverride time.Duration
+	// Attributes holds expected attribute values; unlisted attributes are not
+	// checked.
+	Attributes map[string]string
+}
+
+// Run classifies each case with c in a subtest, checking the outcome against
+// the case and against Validate.
+func Run(t *testing.T, c classify.Classifier, cases []Case) {
+	t.Helper()
+	for i, tc := range cases {
+		name := tc.Name
+		if name == "" {
+			name = fmt.Sprintf("case_%d", i)
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Helper()
+			var out classify.Outcome
+			if tc.Attempt != nil {
+				out = classify.ClassifyAttempt(c, tc.Value, tc.Err, *tc.Attempt)
+			} else {
+				out = c.Classify(tc.Value, tc.Err)
+			}
+			if err := Validate(out, tc.Err); err != nil {
+				t.Errorf("invalid outcome %+v: %v", out, err)
+			}
+			if out.Kind != tc.Kind {
+				t.Errorf("kind=%s, want %s", kindName(out.Kind), kindName(tc.Kind))
+			}
+			if tc.Reason != "" && out.Reason != tc.Reason {
+				t.Errorf("reason=%q, want %q", out.Reason, tc.Reason)
+			}
+			if out.BackoffOverride != tc.BackoffOverride {
+				t.Errorf("backoff override=%v, want %v", out.BackoffOverride, tc.BackoffOverride)
+			}
+			for k, want := range tc.Attributes {
+				if got, ok := out.Attributes[k]; !ok || got != want {
+					t.Errorf("attribute %q=%q (present=%v), want %q", k, got, ok, want)
+				}
+			}
+		})
+	}
+}
+
+// Validate reports outcomes the executor and timelines do not expect for an
+// attempt that returned err:
+//
+//   - an unknown kind, which the executor turns into an abort;
+//   - an empty reason, or one that is not a low-cardinality code (it contains
+//     whitespace or repeats err's message);
+//   - a negative BackoffOverride, Pushback or MaxAdditionalAttempts, or a
+//     BackoffOverride on an outcome that is not retryable;
+//   - an attribute with an empty key.
+func Validate(out classify.Outcome, err error) error {
+	var errs []error
+	if out.Kind == classify.OutcomeUnknown || kindName(out.Kind) == "" {
+		errs = append(errs, fmt.Errorf("unknown outcome kind %d", out.Kind))
+	}
+	switch {
+	case out.Reason == "":
+		errs = append(errs, errors.New("empty reason"))
+	case strings.IndexFunc(out.Reason, unicode.IsSpace) >= 0:
+		errs = append(errs, fmt.Errorf("reason %q contains whitespace", out.Reason))
+	case err != nil && out.Reason == err.Error():
+		errs = append(errs, fmt.Errorf("reason %q repeats the error message", out.Reason))
+	}
+	if out.BackoffOverride < 0 {
+		errs = append(errs, fmt.Errorf("negative backoff override %v", out.BackoffOverride))
+	} else if out.BackoffOverride > 0 && out.Kind != classify.OutcomeRetryable {
+		errs = append(errs, errors.New("backoff override on an outcome that is not retryable"))
+	}
+	if out.Pushback < 0 {
+		errs = append(errs, fmt.Errorf("negative pushback %v", out.Pushback))
+	}
+	if out.MaxAdditionalAttempts < 0 {
+		errs = append(errs, fmt.Errorf("negative max additional attempts %d", out.MaxAdditionalAttempts))
+	}
+	if _, ok := out.Attributes[""]; ok {
+		errs = append(errs, errors.New("attribute with an empty key"))
+	}
+	return errors.Join(errs...)
+}
+
+func kindName(k classify.OutcomeKind) string {
+	switch k {
+	case classify.OutcomeUnknown:
+		return "unknown"
+	case classify.OutcomeSuccess:
+		return "success"
+	case classify.OutcomeRetryable:
+		return "retryable"
+	case classify.OutcomeNonRetryable:
+		return "non_retryable"
+	case classify.OutcomeAbort:
+		return "abort"
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package classifytest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+)
+
+type httpErr struct {
+	status     int
+	retryAfter time.Duration
+}
+
+func (e httpErr) Error() string                     { return "http error" }
+func (e httpErr) HTTPStatusCode() int               { return e.status }
+func (e httpErr) HTTPMethod() string                { return "GET" }
+func (e httpErr) RetryAfter() (time.Duration, bool) { return e.retryAfter, e.retryAfter > 0 }
+
+func TestRun_Builtins(t *testing.T) {
+	Run(t, classify.HTTPClassifier{}, []Case{
+		{Name: "success", Err: nil, Kind: classify.OutcomeSuccess, Reason: "success"},
+		{Name: "5xx", Err: httpErr{status: 503}, Kind: classify.OutcomeRetryable, Reason: "http_5xx", Attributes: map[string]string{"status": "503", "method": "GET"}},
+		{Name: "429", Err: httpErr{status: 429, retryAfter: time.Second}, Kind: classify.OutcomeRetryable, Reason: "http_429", BackoffOverride: time.Second},
+		{Name: "404", Err: httpErr{status: 404}, Kind: classify.OutcomeNonRetryable},
+		{Name: "canceled", Err: context.Canceled, Kind: classify.OutcomeAbort, Reason: "context_canceled"},
+	})
+}
+
+func TestValidate(t *testing.T) {
+	boom := errors.New("boom")
+	for _, tc := range []struct {
+		out  classify.Outcome
+		want string
+	}{
+		{classify.Outcome{Reason: "x"}, "unknown outcome kind"},
+		{classify.Outcome{Kind: classify.OutcomeRetryable}, "empty reason"},
+		{classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "bad thing"}, "whitespace"},
+		{classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "boom"}, "repeats the error message"},
+		{classify.Outcome{Kind: classify.OutcomeNonRetryable, Reason: "x", BackoffOverride: time.Second}, "not retryable"},
+		{classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "x", BackoffOverride: -time.Second}, "negative backoff"},
+		{classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "x", Pushback: -time.Second}, "negative pushback"},
+		{classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "x", MaxAdditionalAttempts: -1}, "negative max additional attempts"},
+		{classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "x", Attributes: map[string]string{"": "v"}}, "empty key"},
+	} {
+		err := Validate(tc.out, boom)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Validate(%+v)=%v, want %q", tc.out, err, tc.want)
+		}
+	}
+
+	if err := Validate(classify.Retryable("throttled", classify.RetryAfterAttr(time.Second)), boom); err != nil {
+		t.Fatalf("Validate(valid outcome)=%v", err)
+	}
+}
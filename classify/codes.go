@@ -0,0 +1,26 @@
+package classify
+
+// RetryableCodes lists the HTTP statuses and gRPC code names a policy declares
+// retryable (policy.RetryPolicy.RetryableHTTPStatuses and RetryableGRPCCodes).
+// A nil list keeps the classifier's defaults; a non-nil list replaces them.
+// gRPC code names are canonical, such as "UNAVAILABLE".
+type RetryableCodes struct {
+	HTTPStatuses []int
+	GRPCCodes    []string
+}
+
+// CodesClassifier is implemented by classifiers whose retryable HTTP statuses
+// or gRPC codes can be replaced per policy. The executor calls
+// WithRetryableCodes when a policy declares either list.
+type CodesClassifier interface {
+	Classifier
+	WithRetryableCodes(codes RetryableCodes) Classifier
+}
+
+func statusSet(statuses []int) map[int]struct{} {
+	set := make(map[int]struct{}, len(statuses))
+	for _, s := range statuses {
+		set[s] = struct{}{}
+	}
+	return set
+}
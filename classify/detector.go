@@ -0,0 +1,101 @@
+package classify
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Detector recognizes one family of errors, such as an RPC framework's status
+// errors, and classifies them. Detect returns false for errors it does not
+// recognize.
+type Detector interface {
+	Detect(value any, err error) (Outcome, bool)
+}
+
+// DetectorFunc adapts a function to a Detector.
+type DetectorFunc func(value any, err error) (Outcome, bool)
+
+func (f DetectorFunc) Detect(value any, err error) (Outcome, bool) { return f(value, err) }
+
+// CodesDetector is implemented by detectors whose retryable codes can be
+// replaced per policy, like CodesClassifier.
+type CodesDetector interface {
+	Detector
+	WithRetryableCodes(codes RetryableCodes) Detector
+}
+
+type namedDetector struct {
+	name string
+	d    Detector
+}
+
+var (
+	detectorsMu sync.Mutex // serializes writers
+	detectors   atomic.Pointer[[]namedDetector]
+)
+
+// RegisterDetector adds d to the detectors AutoClassifier consults for errors
+// that are not HTTPError, so importing an integration can teach the default
+// classifier about its errors. Integrations typically call it from init.
+//
+// Detectors run in registration order. Registering an existing name replaces
+// that detector in place; empty names and nil detectors are ignored.
+func RegisterDetector(name string, d Detector) {
+	name = strings.TrimSpace(name)
+	if name == "" || d == nil {
+		return
+	}
+
+	detectorsMu.Lock()
+	defer detectorsMu.Unlock()
+	var cur []namedDetector
+	if p := detectors.Load(); p != nil {
+		cur = *p
+	}
+	next := make([]namedDetector, 0, len(cur)+1)
+	replaced := false
+	for _, nd := range cur {
+		if nd.name == name {
+			nd.d, replaced = d, true
+		}
+		next = append(next, nd)
+	}
+	if !replaced {
+		next = append(next, namedDetector{name: name, d: d})
+	}
+	detectors.Store(&next)
+}
+
+// DetectorNames returns the registered detector names in the order
+// AutoClassifier consults them.
+func DetectorNames() []string {
+	p := detectors.Load()
+	if p == nil {
+		return nil
+	}
+	names := make([]string, len(*p))
+	for i, nd := range *p {
+		names[i] = nd.name
+	}
+	return names
+}
+
+// detect classifies err with the first registered detector that recognizes
+// it, applying codes to detectors that support them.
+func detect(value any, err error, codes *RetryableCodes) (Outcome, bool) {
+	p := detectors.Load()
+	if p == nil {
+		return Outcome{}, false
+	}
+	for _, nd := range *p {
+		d := nd.d
+		if cd, ok := d.(CodesDetector); ok && codes != nil {
+			d = cd.WithRetryableCodes(*codes)
+		}
+		if out, ok := d.Detect(value, err); ok {
+			return out, true
+		}
+	}
+	return Outcome{}, false
+}
@@ -0,0 +1,64 @@
+package classify
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+type quotaErr struct{ code string }
+
+func (e quotaErr) Error() string { return "quota: " + e.code }
+
+type quotaDetector struct {
+	retryable []string
+}
+
+func (d quotaDetector) Detect(_ any, err error) (Outcome, bool) {
+	var qe quotaErr
+	if !errors.As(err, &qe) {
+		return Outcome{}, false
+	}
+	if slices.Contains(d.retryable, qe.code) {
+		return Retryable("quota_retryable"), true
+	}
+	return Terminal("quota_exceeded"), true
+}
+
+func (d quotaDetector) WithRetryableCodes(codes RetryableCodes) Detector {
+	d.retryable = codes.GRPCCodes
+	return d
+}
+
+func TestAutoClassifier_Detectors(t *testing.T) {
+	RegisterDetector("test-quota", DetectorFunc(func(any, error) (Outcome, bool) { return Outcome{}, false }))
+	RegisterDetector("test-quota", quotaDetector{})
+	RegisterDetector("", quotaDetector{})
+	RegisterDetector("test-nil", nil)
+
+	names := DetectorNames()
+	if !slices.Contains(names, "test-quota") || slices.Contains(names, "test-nil") || slices.Contains(names, "") {
+		t.Fatalf("DetectorNames()=%v", names)
+	}
+	n := len(names)
+	RegisterDetector("test-quota", quotaDetector{})
+	if len(DetectorNames()) != n {
+		t.Fatalf("re-registering a detector added an entry: %v", DetectorNames())
+	}
+
+	auto := AutoClassifier{}
+	if out := auto.Classify(nil, quotaErr{code: "daily"}); out.Reason != "quota_exceeded" {
+		t.Fatalf("quota out=%+v, want quota_exceeded", out)
+	}
+	if out := auto.Classify(nil, errors.New("other")); out.Reason != "retryable_error" {
+		t.Fatalf("other out=%+v, want retryable_error", out)
+	}
+	if out := auto.Classify(nil, testHTTPError{status: 404, method: "GET"}); out.Reason != "http_non_retryable_status" {
+		t.Fatalf("http out=%+v, want the HTTP classifier", out)
+	}
+
+	narrowed := auto.WithRetryableCodes(RetryableCodes{GRPCCodes: []string{"daily"}})
+	if out := narrowed.Classify(nil, quotaErr{code: "daily"}); out.Reason != "quota_retryable" {
+		t.Fatalf("narrowed quota out=%+v, want quota_retryable", out)
+	}
+}
@@ -16,9 +16,23 @@ import (
 type HTTPError interface {
 	HTTPStatusCode() int
 	HTTPMethod() string
+	RetryAfterProvider
+}
+
+// RetryAfterProvider is implemented by errors that carry a server-directed
+// delay before the next attempt, such as an SDK's throttling error.
+// AutoClassifier honors it for any retryable error, not just HTTPError.
+type RetryAfterProvider interface {
 	RetryAfter() (time.Duration, bool)
 }
 
+// HTTPIdempotencyKeyer is optionally implemented by HTTPError values whose
+// request carried an idempotency key. A non-empty key lets HTTPClassifier retry
+// non-idempotent methods, since the server can deduplicate the attempts.
+type HTTPIdempotencyKeyer interface {
+	HTTPIdempotencyKey() string
+}
+
 // HTTPClassifier classifies outcomes for HTTP-like operations based on an HTTPError.
 //
 // If the provided error does not implement HTTPError, it returns a non-retryable
@@ -27,6 +41,33 @@ type HTTPClassifier struct {
 	// Retryable4xx is an optional set of additional retryable 4xx status codes.
 	// If nil, defaults to {408, 429}.
 	Retryable4xx map[int]struct{}
+
+	// RetryableStatuses, when non-nil, replaces the default retryable statuses
+	// (5xx, 408, 429 and Retryable4xx): only the listed statuses are retried.
+	// Transport errors (status 0) stay retryable.
+	RetryableStatuses map[int]struct{}
+
+	// Statuses maps statuses to the outcome kind to use for them, taking
+	// precedence over every other status rule, for example 501 to
+	// OutcomeNonRetryable or 425 to OutcomeRetryable. Retryable statuses still
+	// require an idempotent request and honor Retry-After; OutcomeAbort uses
+	// reason "http_abort_status".
+	Statuses map[int]OutcomeKind
+
+	// RetryNonIdempotent retries retryable statuses and transport errors for
+	// any method, not just idempotent ones and requests with an idempotency
+	// key. Only set it when the server deduplicates requests some other way.
+	RetryNonIdempotent bool
+}
+
+// WithRetryableCodes returns c retrying exactly codes.HTTPStatuses, or c
+// unchanged when that list is nil.
+func (c HTTPClassifier) WithRetryableCodes(codes RetryableCodes) Classifier {
+	if codes.HTTPStatuses == nil {
+		return c
+	}
+	c.RetryableStatuses = statusSet(codes.HTTPStatuses)
+	return c
 }
 
 func (c HTTPClassifier) Classify(_ any, err error) Outcome {
@@ -54,7 +95,10 @@ func (c HTTPClassifier) Classify(_ any, err error) Outcome {
 
 	status := he.HTTPStatusCode()
 	method := strings.ToUpper(strings.TrimSpace(he.HTTPMethod()))
-	idempotent := isIdempotentMethod(method)
+	idempotent := c.RetryNonIdempotent || isIdempotentMethod(method)
+	if k, ok := err.(HTTPIdempotencyKeyer); ok && k.HTTPIdempotencyKey() != "" {
+		idempotent = true
+	}
 
 	out := Outcome{
 		Kind:   OutcomeNonRetryable,
@@ -65,6 +109,10 @@ func (c HTTPClassifier) Classify(_ any, err error) Outcome {
 		},
 	}
 
+	if kind, ok := c.Statuses[status]; ok && status != 0 && kind != OutcomeUnknown {
+		return statusOutcome(out, he, status, kind, idempotent)
+	}
+
 	if status >= 200 && status < 300 {
 		out.Kind = OutcomeSuccess
 		out.Reason = "success"
@@ -82,6 +130,12 @@ func (c HTTPClassifier) Classify(_ any, err error) Outcome {
 		return out
 	}
 
+	if c.RetryableStatuses != nil {
+		if _, ok := c.RetryableStatuses[status]; !ok {
+			return out
+		}
+	}
+
 	if status >= 500 && status <= 599 {
 		if idempotent {
 			out.Kind = OutcomeRetryable
@@ -93,7 +147,7 @@ func (c HTTPClassifier) Classify(_ any, err error) Outcome {
 		return out
 	}
 
-	if status == 408 || status == 429 || c.retryable4xx(status) {
+	if status == 408 || status == 429 || c.retryable4xx(status) || c.RetryableStatuses != nil {
 		if idempotent {
 			out.Kind = OutcomeRetryable
 			out.Reason = "http_" + strconv.Itoa(status)
@@ -112,6 +166,33 @@ func (c HTTPClassifier) Classify(_ any, err error) Outcome {
 	return out
 }
 
+// statusOutcome completes out for a status with a Statuses override.
+func statusOutcome(out Outcome, he HTTPError, status int, kind OutcomeKind, idempotent bool) Outcome {
+	switch kind {
+	case OutcomeSuccess:
+		out.Kind = OutcomeSuccess
+		out.Reason = "success"
+	case OutcomeAbort:
+		out.Kind = OutcomeAbort
+		out.Reason = "http_abort_status"
+	case OutcomeRetryable:
+		if !idempotent {
+			out.Reason = "http_non_idempotent"
+			return out
+		}
+		out.Kind = OutcomeRetryable
+		out.Reason = "http_" + strconv.Itoa(status)
+		if status >= 500 && status <= 599 {
+			out.Reason = "http_5xx"
+		}
+		if d, ok := he.RetryAfter(); ok && d > 0 {
+			out.BackoffOverride = d
+			out.Attributes["retry_after"] = d.String()
+		}
+	}
+	return out
+}
+
 func (c HTTPClassifier) retryable4xx(status int) bool {
 	if c.Retryable4xx == nil {
 		return false
@@ -0,0 +1,126 @@
+package classify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MessageRule maps errors whose message matches to an outcome. Set Contains,
+// Pattern or both; a rule with both matches when both do.
+type MessageRule struct {
+	// Contains matches messages containing this substring.
+	Contains string
+
+	// Pattern matches messages against this regular expression (RE2 syntax).
+	Pattern string
+
+	// IgnoreCase matches Contains and Pattern case-insensitively.
+	IgnoreCase bool
+
+	// Kind is the outcome for matching errors.
+	Kind OutcomeKind
+
+	// Reason is the outcome reason. Defaults to "message_match".
+	Reason string
+}
+
+// MessageClassifier classifies errors by their message, for SDKs that only
+// expose string errors. Rules are tried in order and the first match wins;
+// the outcome's "message_rule" attribute records its index. Errors no rule
+// matches are classified by Fallback.
+//
+// Like the other built-ins, nil errors are successes and context.Canceled
+// aborts before any rule is tried. Construct it with NewMessageClassifier.
+type MessageClassifier struct {
+	// Fallback classifies errors no rule matches. Defaults to AlwaysRetryOnError.
+	Fallback Classifier
+
+	rules []messageRule
+}
+
+type messageRule struct {
+	contains string
+	pattern  *regexp.Regexp
+	fold     bool
+	kind     OutcomeKind
+	reason   string
+}
+
+// NewMessageClassifier compiles rules into a MessageClassifier. It returns an
+// error if a rule has neither Contains nor Pattern, an invalid Pattern, or
+// the OutcomeUnknown kind.
+func NewMessageClassifier(rules ...MessageRule) (*MessageClassifier, error) {
+	c := &MessageClassifier{rules: make([]messageRule, 0, len(rules))}
+	for i, r := range rules {
+		if r.Contains == "" && r.Pattern == "" {
+			return nil, fmt.Errorf("classify: message rule %d: Contains or Pattern is required", i)
+		}
+		if r.Kind == OutcomeUnknown {
+			return nil, fmt.Errorf("classify: message rule %d: Kind is required", i)
+		}
+		mr := messageRule{contains: r.Contains, fold: r.IgnoreCase, kind: r.Kind, reason: r.Reason}
+		if r.IgnoreCase {
+			mr.contains = strings.ToLower(r.Contains)
+		}
+		if r.Pattern != "" {
+			pattern := r.Pattern
+			if r.IgnoreCase {
+				pattern = "(?i)" + pattern
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("classify: message rule %d: %w", i, err)
+			}
+			mr.pattern = re
+		}
+		c.rules = append(c.rules, mr)
+	}
+	return c, nil
+}
+
+func (c *MessageClassifier) Classify(val any, err error) Outcome {
+	if err == nil {
+		return Outcome{Kind: OutcomeSuccess, Reason: "success"}
+	}
+	if errors.Is(err, context.Canceled) {
+		return Outcome{Kind: OutcomeAbort, Reason: "context_canceled"}
+	}
+
+	msg := err.Error()
+	var lower string
+	for i, r := range c.rules {
+		if r.contains != "" {
+			s := msg
+			if r.fold {
+				if lower == "" {
+					lower = strings.ToLower(msg)
+				}
+				s = lower
+			}
+			if !strings.Contains(s, r.contains) {
+				continue
+			}
+		}
+		if r.pattern != nil && !r.pattern.MatchString(msg) {
+			continue
+		}
+		out := Outcome{
+			Kind:       r.kind,
+			Reason:     r.reason,
+			Attributes: map[string]string{"message_rule": strconv.Itoa(i)},
+		}
+		if out.Reason == "" {
+			out.Reason = "message_match"
+		}
+		return out
+	}
+
+	if c.Fallback != nil {
+		return c.Fallback.Classify(val, err)
+	}
+	return AlwaysRetryOnError{}.Classify(val, err)
+}
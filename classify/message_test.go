@@ -0,0 +1,61 @@
+package classify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMessageClassifier(t *testing.T) {
+	c, err := NewMessageClassifier(
+		MessageRule{Contains: "throttl", IgnoreCase: true, Kind: OutcomeRetryable, Reason: "throttled"},
+		MessageRule{Pattern: `^invalid (argument|request)`, Kind: OutcomeNonRetryable, Reason: "invalid"},
+		MessageRule{Contains: "quota", Pattern: `exceeded for \w+`, Kind: OutcomeAbort},
+	)
+	if err != nil {
+		t.Fatalf("NewMessageClassifier: %v", err)
+	}
+
+	for _, tc := range []struct {
+		err    error
+		kind   OutcomeKind
+		reason string
+		rule   string
+	}{
+		{errors.New("Request THROTTLED by upstream"), OutcomeRetryable, "throttled", "0"},
+		{fmt.Errorf("sdk: %w", errors.New("throttling")), OutcomeRetryable, "throttled", "0"},
+		{errors.New("invalid argument: id"), OutcomeNonRetryable, "invalid", "1"},
+		{errors.New("bad: invalid argument"), OutcomeRetryable, "retryable_error", ""},
+		{errors.New("quota exceeded for project"), OutcomeAbort, "message_match", "2"},
+		{errors.New("quota exceeded"), OutcomeRetryable, "retryable_error", ""},
+		{context.Canceled, OutcomeAbort, "context_canceled", ""},
+		{nil, OutcomeSuccess, "success", ""},
+	} {
+		out := c.Classify(nil, tc.err)
+		if out.Kind != tc.kind || out.Reason != tc.reason || out.Attributes["message_rule"] != tc.rule {
+			t.Errorf("Classify(%v)=%+v, want %v %s rule %q", tc.err, out, tc.kind, tc.reason, tc.rule)
+		}
+	}
+
+	c.Fallback = HTTPClassifier{}
+	if out := c.Classify(nil, errors.New("other")); out.Reason != "classifier_type_mismatch" {
+		t.Fatalf("fallback not used: %+v", out)
+	}
+}
+
+func TestNewMessageClassifier_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		rule MessageRule
+		want string
+	}{
+		{MessageRule{Kind: OutcomeRetryable}, "Contains or Pattern is required"},
+		{MessageRule{Contains: "x"}, "Kind is required"},
+		{MessageRule{Pattern: "(", Kind: OutcomeRetryable}, "missing closing )"},
+	} {
+		if _, err := NewMessageClassifier(tc.rule); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("NewMessageClassifier(%+v) err=%v, want %q", tc.rule, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package classify
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+)
+
+// NetClassifier classifies raw transport failures from the net package:
+//
+//   - Refused, reset, aborted and broken connections, unreachable hosts and
+//     networks, timeouts (net.Error.Timeout), unexpected EOFs and temporary
+//     DNS failures are retryable.
+//   - Hosts that DNS reports do not exist, invalid addresses, certificate
+//     verification failures and use of a closed connection are not.
+//   - Other net.Error values are retryable if they report Temporary.
+//
+// Outcomes of *net.OpError failures carry the operation, such as "dial" or
+// "read", in the "net_op" attribute. Errors that are not network errors are
+// classified by Fallback.
+type NetClassifier struct {
+	// Fallback classifies errors that are not network errors. Nil classifies
+	// them as non-retryable with reason "classifier_type_mismatch".
+	Fallback Classifier
+}
+
+func (c NetClassifier) Classify(val any, err error) Outcome {
+	if err == nil {
+		return Outcome{Kind: OutcomeSuccess, Reason: "success"}
+	}
+	if errors.Is(err, context.Canceled) {
+		return Outcome{Kind: OutcomeAbort, Reason: "context_canceled"}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Outcome{Kind: OutcomeRetryable, Reason: "context_deadline_exceeded"}
+	}
+
+	out, ok := classifyNet(err)
+	if !ok {
+		if c.Fallback != nil {
+			return c.Fallback.Classify(val, err)
+		}
+		return Outcome{
+			Kind:   OutcomeNonRetryable,
+			Reason: "classifier_type_mismatch",
+			Attributes: map[string]string{
+				"expected_type": "net.Error",
+				"got_type":      typeString(err),
+			},
+		}
+	}
+
+	var oe *net.OpError
+	if errors.As(err, &oe) && oe.Op != "" {
+		out.Attributes = map[string]string{"net_op": oe.Op}
+	}
+	return out
+}
+
+// classifyNet classifies err if it is a network error.
+func classifyNet(err error) (Outcome, bool) {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return Outcome{Kind: OutcomeNonRetryable, Reason: "net_dns_not_found"}, true
+		}
+		return Outcome{Kind: OutcomeRetryable, Reason: "net_dns_error"}, true
+	}
+
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalidCert x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &certErr) || errors.As(err, &unknownAuthority) ||
+		errors.As(err, &invalidCert) || errors.As(err, &hostnameErr) {
+		return Outcome{Kind: OutcomeNonRetryable, Reason: "net_tls_verification"}, true
+	}
+
+	var addrErr *net.AddrError
+	var parseErr *net.ParseError
+	var unknownNet net.UnknownNetworkError
+	var invalidAddr net.InvalidAddrError
+	if errors.As(err, &addrErr) || errors.As(err, &parseErr) ||
+		errors.As(err, &unknownNet) || errors.As(err, &invalidAddr) {
+		return Outcome{Kind: OutcomeNonRetryable, Reason: "net_invalid_address"}, true
+	}
+
+	switch {
+	case errors.Is(err, net.ErrClosed):
+		return Outcome{Kind: OutcomeNonRetryable, Reason: "net_closed"}, true
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return Outcome{Kind: OutcomeRetryable, Reason: "net_connection_refused"}, true
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNABORTED), errors.Is(err, syscall.EPIPE):
+		return Outcome{Kind: OutcomeRetryable, Reason: "net_connection_reset"}, true
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return Outcome{Kind: OutcomeRetryable, Reason: "net_unreachable"}, true
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return Outcome{Kind: OutcomeRetryable, Reason: "net_unexpected_eof"}, true
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) {
+		if ne.Timeout() {
+			return Outcome{Kind: OutcomeRetryable, Reason: "net_timeout"}, true
+		}
+		if t, ok := ne.(interface{ Temporary() bool }); ok && t.Temporary() {
+			return Outcome{Kind: OutcomeRetryable, Reason: "net_temporary"}, true
+		}
+		return Outcome{Kind: OutcomeNonRetryable, Reason: "net_error"}, true
+	}
+	return Outcome{}, false
+}
@@ -0,0 +1,59 @@
+package classify
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type tempNetErr struct{ temporary bool }
+
+func (e tempNetErr) Error() string   { return "net" }
+func (e tempNetErr) Timeout() bool   { return false }
+func (e tempNetErr) Temporary() bool { return e.temporary }
+
+func TestNetClassifier(t *testing.T) {
+	dial := func(err error) error {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: &os.SyscallError{Syscall: "connect", Err: err}}
+	}
+
+	for _, tc := range []struct {
+		err    error
+		kind   OutcomeKind
+		reason string
+	}{
+		{dial(syscall.ECONNREFUSED), OutcomeRetryable, "net_connection_refused"},
+		{fmt.Errorf("query: %w", &net.OpError{Op: "read", Err: syscall.ECONNRESET}), OutcomeRetryable, "net_connection_reset"},
+		{dial(syscall.EHOSTUNREACH), OutcomeRetryable, "net_unreachable"},
+		{&net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}, OutcomeRetryable, "net_timeout"},
+		{&net.DNSError{Err: "no such host", Name: "db.invalid", IsNotFound: true}, OutcomeNonRetryable, "net_dns_not_found"},
+		{&net.DNSError{Err: "server misbehaving", Name: "db", IsTemporary: true}, OutcomeRetryable, "net_dns_error"},
+		{&net.AddrError{Err: "missing port", Addr: "db"}, OutcomeNonRetryable, "net_invalid_address"},
+		{fmt.Errorf("tls: %w", x509.UnknownAuthorityError{}), OutcomeNonRetryable, "net_tls_verification"},
+		{&net.OpError{Op: "write", Err: net.ErrClosed}, OutcomeNonRetryable, "net_closed"},
+		{io.ErrUnexpectedEOF, OutcomeRetryable, "net_unexpected_eof"},
+		{tempNetErr{temporary: true}, OutcomeRetryable, "net_temporary"},
+		{tempNetErr{}, OutcomeNonRetryable, "net_error"},
+		{context.Canceled, OutcomeAbort, "context_canceled"},
+		{errors.New("app error"), OutcomeNonRetryable, "classifier_type_mismatch"},
+		{nil, OutcomeSuccess, "success"},
+	} {
+		out := NetClassifier{}.Classify(nil, tc.err)
+		if out.Kind != tc.kind || out.Reason != tc.reason {
+			t.Errorf("Classify(%v)=%+v, want %v %s", tc.err, out, tc.kind, tc.reason)
+		}
+	}
+
+	if out := (NetClassifier{}).Classify(nil, dial(syscall.ECONNREFUSED)); out.Attributes["net_op"] != "dial" {
+		t.Fatalf("net_op=%q, want dial", out.Attributes["net_op"])
+	}
+	if out := (NetClassifier{Fallback: AlwaysRetryOnError{}}).Classify(nil, errors.New("app error")); out.Reason != "retryable_error" {
+		t.Fatalf("fallback not used: %+v", out)
+	}
+}
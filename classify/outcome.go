@@ -26,6 +26,22 @@ type Outcome struct {
 
 	// BackoffOverride, when set, overrides the policy backoff before the next attempt.
 	BackoffOverride time.Duration
+
+	// MaxAdditionalAttempts, when positive, caps the attempts the call may make
+	// after this one, for protocols where the server says how many more tries
+	// it accepts. The cap only lowers the policy's limit, and it persists for
+	// the rest of the call. To allow no more attempts, return a non-retryable
+	// outcome instead.
+	MaxAdditionalAttempts int
+
+	// TripCircuit, when set on a failed attempt, opens the key's circuit
+	// breaker immediately, regardless of its failure counts, and ends the
+	// call. Use it for responses that explicitly signal load shedding.
+	TripCircuit bool
+
+	// Pushback, when set, signals that the dependency asked clients to back off
+	// for this long. See PushbackError for how the executor applies it.
+	Pushback time.Duration
 }
 
 // Classifier determines whether an attempt result is success, retryable, terminal,
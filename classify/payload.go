@@ -0,0 +1,115 @@
+package classify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// PayloadError is implemented by errors that carry the response body of a
+// failed request, such as an API client's error type.
+type PayloadError interface {
+	error
+	Payload() []byte
+}
+
+// PayloadClassifier classifies JSON error payloads by the value of one field,
+// for REST APIs that report machine-readable error codes in the body rather
+// than through the status code.
+//
+// The payload is taken from the first PayloadError in the error's chain or,
+// for attempts without an error, from a []byte, json.RawMessage or string
+// result. The field at Path is looked up in the payload and its value mapped
+// through Codes; a match yields reason "payload_match" with the value in the
+// "payload_code" attribute. Results without a payload, or whose value is
+// missing or unmapped, go to Fallback.
+type PayloadClassifier struct {
+	// Path is the dot-separated path to the field, such as "error.code".
+	// Numeric segments index arrays, as in "errors.0.code".
+	Path string
+
+	// Codes maps field values to outcome kinds. Numbers and booleans match
+	// their JSON text, such as "1042" or "true".
+	Codes map[string]OutcomeKind
+
+	// Fallback classifies results no code matches. Defaults to AutoClassifier.
+	Fallback Classifier
+}
+
+func (c PayloadClassifier) Classify(val any, err error) Outcome {
+	if errors.Is(err, context.Canceled) {
+		return Outcome{Kind: OutcomeAbort, Reason: "context_canceled"}
+	}
+	if code, ok := payloadField(payloadOf(val, err), c.Path); ok {
+		if kind, ok := c.Codes[code]; ok && kind != OutcomeUnknown {
+			return Outcome{
+				Kind:       kind,
+				Reason:     "payload_match",
+				Attributes: map[string]string{"payload_code": code},
+			}
+		}
+	}
+	if c.Fallback != nil {
+		return c.Fallback.Classify(val, err)
+	}
+	return AutoClassifier{}.Classify(val, err)
+}
+
+// payloadOf returns the payload of an attempt result, if any.
+func payloadOf(val any, err error) []byte {
+	if err != nil {
+		var pe PayloadError
+		if errors.As(err, &pe) {
+			return pe.Payload()
+		}
+		return nil
+	}
+	switch v := val.(type) {
+	case []byte:
+		return v
+	case json.RawMessage:
+		return v
+	case string:
+		return []byte(v)
+	}
+	return nil
+}
+
+// payloadField returns the text of the scalar at path in a JSON payload.
+func payloadField(payload []byte, path string) (string, bool) {
+	if len(payload) == 0 || path == "" {
+		return "", false
+	}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var v any
+	if dec.Decode(&v) != nil {
+		return "", false
+	}
+	for _, seg := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			v = node[seg]
+		case []any:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false
+			}
+			v = node[i]
+		default:
+			return "", false
+		}
+	}
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case json.Number:
+		return s.String(), true
+	case bool:
+		return strconv.FormatBool(s), true
+	}
+	return "", false
+}
@@ -0,0 +1,70 @@
+package classify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+type apiError struct {
+	status int
+	body   string
+}
+
+func (e apiError) Error() string   { return fmt.Sprintf("api error %d", e.status) }
+func (e apiError) Payload() []byte { return []byte(e.body) }
+
+func TestPayloadClassifier(t *testing.T) {
+	c := PayloadClassifier{
+		Path: "error.code",
+		Codes: map[string]OutcomeKind{
+			"RATE_LIMITED":  OutcomeRetryable,
+			"INVALID_INPUT": OutcomeNonRetryable,
+			"1042":          OutcomeAbort,
+		},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		val    any
+		err    error
+		kind   OutcomeKind
+		reason string
+	}{
+		{"error payload", nil, fmt.Errorf("call: %w", apiError{400, `{"error":{"code":"RATE_LIMITED"}}`}), OutcomeRetryable, "payload_match"},
+		{"terminal code", nil, apiError{400, `{"error":{"code":"INVALID_INPUT","message":"x"}}`}, OutcomeNonRetryable, "payload_match"},
+		{"numeric code", nil, apiError{400, `{"error":{"code":1042}}`}, OutcomeAbort, "payload_match"},
+		{"200 with error body", []byte(`{"error":{"code":"RATE_LIMITED"}}`), nil, OutcomeRetryable, "payload_match"},
+		{"raw message result", json.RawMessage(`{"error":{"code":"INVALID_INPUT"}}`), nil, OutcomeNonRetryable, "payload_match"},
+		{"unmapped code", nil, apiError{400, `{"error":{"code":"OTHER"}}`}, OutcomeRetryable, "retryable_error"},
+		{"missing field", nil, apiError{400, `{"error":"flat"}`}, OutcomeRetryable, "retryable_error"},
+		{"invalid json", nil, apiError{400, `not json`}, OutcomeRetryable, "retryable_error"},
+		{"success", map[string]string{}, nil, OutcomeSuccess, "success"},
+		{"canceled", nil, context.Canceled, OutcomeAbort, "context_canceled"},
+	} {
+		out := c.Classify(tc.val, tc.err)
+		if out.Kind != tc.kind || out.Reason != tc.reason {
+			t.Errorf("%s: out=%+v, want %v %s", tc.name, out, tc.kind, tc.reason)
+		}
+	}
+
+	out := c.Classify(nil, apiError{400, `{"error":{"code":"RATE_LIMITED"}}`})
+	if out.Attributes["payload_code"] != "RATE_LIMITED" {
+		t.Fatalf("attributes=%v, want payload_code", out.Attributes)
+	}
+}
+
+func TestPayloadClassifier_ArrayPath(t *testing.T) {
+	c := PayloadClassifier{
+		Path:     "errors.0.retryable",
+		Codes:    map[string]OutcomeKind{"false": OutcomeNonRetryable},
+		Fallback: AlwaysRetryOnError{},
+	}
+	if out := c.Classify(nil, apiError{500, `{"errors":[{"retryable":false}]}`}); out.Kind != OutcomeNonRetryable {
+		t.Fatalf("out=%+v, want non-retryable", out)
+	}
+	if out := c.Classify(nil, apiError{500, `{"errors":[]}`}); out.Kind != OutcomeRetryable {
+		t.Fatalf("empty array out=%+v, want fallback", out)
+	}
+}
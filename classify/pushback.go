@@ -0,0 +1,33 @@
+package classify
+
+import (
+	"errors"
+	"time"
+)
+
+// PushbackError is implemented by errors that carry an explicit server request
+// to slow down, such as a gRPC pushback trailer or a load-shedding response.
+//
+// Unlike Outcome.BackoffOverride, which only delays the next attempt of the
+// current call, pushback is applied to the whole policy key: the executor
+// overrides the backoff, stops hedging, and delays retries of concurrent calls
+// on the same key until the pushback elapses.
+type PushbackError interface {
+	error
+	Pushback() time.Duration
+}
+
+// PushbackOf returns the pushback requested by an attempt: Outcome.Pushback
+// if set, otherwise the duration of the first PushbackError in err's chain.
+func PushbackOf(out Outcome, err error) (time.Duration, bool) {
+	if out.Pushback > 0 {
+		return out.Pushback, true
+	}
+	var pe PushbackError
+	if err != nil && errors.As(err, &pe) {
+		if d := pe.Pushback(); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,35 @@
+package classify
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type pushbackErr struct{ d time.Duration }
+
+func (e pushbackErr) Error() string           { return "slow down" }
+func (e pushbackErr) Pushback() time.Duration { return e.d }
+
+func TestPushbackOf(t *testing.T) {
+	if d, ok := PushbackOf(Outcome{Pushback: time.Second}, nil); !ok || d != time.Second {
+		t.Fatalf("d=%v ok=%v, want outcome pushback", d, ok)
+	}
+
+	wrapped := fmt.Errorf("call: %w", pushbackErr{d: 2 * time.Second})
+	if d, ok := PushbackOf(Outcome{}, wrapped); !ok || d != 2*time.Second {
+		t.Fatalf("d=%v ok=%v, want wrapped error pushback", d, ok)
+	}
+
+	if d, ok := PushbackOf(Outcome{Pushback: time.Second}, wrapped); !ok || d != time.Second {
+		t.Fatalf("d=%v ok=%v, outcome pushback should take precedence", d, ok)
+	}
+
+	if _, ok := PushbackOf(Outcome{BackoffOverride: time.Second}, errors.New("x")); ok {
+		t.Fatalf("backoff override alone is not pushback")
+	}
+	if _, ok := PushbackOf(Outcome{}, pushbackErr{}); ok {
+		t.Fatalf("zero pushback should be ignored")
+	}
+}
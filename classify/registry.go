@@ -1,38 +1,156 @@
 package classify
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
+
+	"github.com/aponysus/recourse/internal"
 )
 
 // Registry is a thread-safe name → Classifier map.
+//
+// Lookups are lock-free: registrations copy the map and publish it atomically,
+// which suits the executor's pattern of a lookup per call and rare registration.
+//
+// Register overwrites existing names. Use MustRegister to catch accidental
+// overrides, Replace to override on purpose, and Freeze to reject any change
+// once executors are built.
 type Registry struct {
-	mu sync.RWMutex
-	m  map[string]Classifier
+	mu      sync.Mutex // serializes writers
+	m       atomic.Pointer[map[string]Classifier]
+	version atomic.Uint64
+	frozen  atomic.Bool
 }
 
+// ErrRegistryFrozen is returned by Register and Replace, and panicked with by
+// MustRegister, once the registry is frozen.
+var ErrRegistryFrozen = errors.New("classifier registry is frozen")
+
 func NewRegistry() *Registry {
-	return &Registry{m: make(map[string]Classifier)}
+	return &Registry{}
 }
 
-// Register associates name with c. Empty names and nil classifiers are ignored.
-func (r *Registry) Register(name string, c Classifier) {
+// Register associates name with c, replacing any classifier already
+// registered under name. Empty names and nil classifiers are ignored.
+// Register returns ErrRegistryFrozen, leaving the registry unchanged, if the
+// registry is frozen.
+func (r *Registry) Register(name string, c Classifier) error {
 	if r == nil {
-		return
+		return nil
 	}
 	name = strings.TrimSpace(name)
 	if name == "" || c == nil {
-		return
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.frozen.Load() {
+		return ErrRegistryFrozen
+	}
+	r.store(name, c)
+	return nil
+}
+
+// MustRegister registers c under name and panics if name is empty, c is nil,
+// name is already registered (including the built-ins), or the registry is
+// frozen.
+func (r *Registry) MustRegister(name string, c Classifier) {
+	if err := r.add(name, c, false); err != nil {
+		panic("classify.Registry.MustRegister: " + err.Error())
+	}
+}
+
+// Replace replaces the classifier registered under name. It returns an error
+// if name is not registered, c is nil, or the registry is frozen.
+func (r *Registry) Replace(name string, c Classifier) error {
+	return r.add(name, c, true)
+}
+
+// add registers c under name, requiring name to be registered already when
+// replace is set and to be unregistered otherwise.
+func (r *Registry) add(name string, c Classifier, replace bool) error {
+	if r == nil {
+		return errors.New("registry is nil")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("classifier name cannot be empty")
+	}
+	if internal.IsTypedNil(c) {
+		return errors.New("classifier cannot be nil")
 	}
 
 	r.mu.Lock()
-	if r.m == nil {
-		r.m = make(map[string]Classifier)
+	defer r.mu.Unlock()
+	if r.frozen.Load() {
+		return ErrRegistryFrozen
+	}
+	var exists bool
+	if m := r.m.Load(); m != nil {
+		_, exists = (*m)[name]
+	}
+	switch {
+	case replace && !exists:
+		return fmt.Errorf("classifier %q is not registered", name)
+	case !replace && exists:
+		return fmt.Errorf("classifier %q is already registered", name)
 	}
-	r.m[name] = c
+	r.store(name, c)
+	return nil
+}
+
+// store publishes a copy of the map with name set to c. r.mu must be held.
+func (r *Registry) store(name string, c Classifier) {
+	next := internal.CopyMap(r.m.Load(), 1)
+	next[name] = c
+	r.m.Store(&next)
+	r.version.Add(1)
+}
+
+// Freeze makes the registry immutable: later Register and Replace calls
+// return ErrRegistryFrozen and MustRegister panics. Call it once executors
+// are built, so late or concurrent registrations fail instead of silently
+// changing classification. Freezing cannot be undone; Clone it to extend.
+func (r *Registry) Freeze() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.frozen.Store(true)
 	r.mu.Unlock()
 }
 
+// Clone returns an unfrozen registry holding the same classifiers.
+func (r *Registry) Clone() *Registry {
+	c := NewRegistry()
+	if r == nil {
+		return c
+	}
+	if m := r.m.Load(); m != nil {
+		next := internal.CopyMap(m, 0)
+		c.m.Store(&next)
+	}
+	return c
+}
+
+// Frozen reports whether Freeze has been called.
+func (r *Registry) Frozen() bool {
+	return r != nil && r.frozen.Load()
+}
+
+// Version returns a counter that changes on every registration.
+// Callers caching lookups can compare versions to detect stale entries.
+func (r *Registry) Version() uint64 {
+	if r == nil {
+		return 0
+	}
+	return r.version.Load()
+}
+
 func (r *Registry) Get(name string) (Classifier, bool) {
 	if r == nil {
 		return nil, false
@@ -42,8 +160,10 @@ func (r *Registry) Get(name string) (Classifier, bool) {
 		return nil, false
 	}
 
-	r.mu.RLock()
-	c, ok := r.m[name]
-	r.mu.RUnlock()
+	m := r.m.Load()
+	if m == nil {
+		return nil, false
+	}
+	c, ok := (*m)[name]
 	return c, ok && c != nil
 }
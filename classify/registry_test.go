@@ -1,6 +1,9 @@
 package classify
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 type testClassifier struct{}
 
@@ -39,3 +42,146 @@ func TestRegistry_Validation(t *testing.T) {
 		t.Fatalf("expected nil classifier to be ignored")
 	}
 }
+
+func TestRegistry_VersionChangesOnRegister(t *testing.T) {
+	reg := NewRegistry()
+	v0 := reg.Version()
+	reg.Register("custom", testClassifier{})
+	if reg.Version() == v0 {
+		t.Fatalf("expected version to change after Register")
+	}
+
+	v1 := reg.Version()
+	reg.Register("", testClassifier{})
+	if reg.Version() != v1 {
+		t.Fatalf("expected ignored registration to keep version")
+	}
+
+	var nilReg *Registry
+	if nilReg.Version() != 0 {
+		t.Fatalf("expected zero version for nil registry")
+	}
+}
+
+func TestRegistry_ConcurrentRegisterAndGet(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("base", testClassifier{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			reg.Register("extra", testClassifier{})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if _, ok := reg.Get("base"); !ok {
+			t.Fatal("expected base classifier during concurrent registration")
+		}
+	}
+	<-done
+
+	var zero Registry
+	if _, ok := zero.Get("base"); ok {
+		t.Fatal("expected zero-value registry to be empty")
+	}
+}
+
+func TestRegistry_MustRegisterRejectsDuplicates(t *testing.T) {
+	reg := NewRegistry()
+	RegisterBuiltins(reg)
+	reg.MustRegister("custom", testClassifier{})
+
+	for _, name := range []string{"custom", ClassifierHTTP, ""} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("MustRegister(%q) did not panic", name)
+				}
+			}()
+			reg.MustRegister(name, AlwaysRetryOnError{})
+		}()
+	}
+	if c, _ := reg.Get("custom"); c != (testClassifier{}) {
+		t.Fatalf("custom=%T, want testClassifier", c)
+	}
+}
+
+func TestRegistry_Replace(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Replace("custom", testClassifier{}); err == nil {
+		t.Fatal("Replace of unregistered name succeeded")
+	}
+
+	reg.Register("custom", AlwaysRetryOnError{})
+	v := reg.Version()
+	if err := reg.Replace("custom", testClassifier{}); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	if c, _ := reg.Get("custom"); c != (testClassifier{}) || reg.Version() == v {
+		t.Fatalf("custom=%T version=%d, want replaced classifier and new version", c, reg.Version())
+	}
+	if err := reg.Replace("custom", nil); err == nil {
+		t.Fatal("Replace with nil classifier succeeded")
+	}
+}
+
+func TestRegistry_Freeze(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("custom", testClassifier{})
+	reg.Freeze()
+	if !reg.Frozen() {
+		t.Fatal("Frozen()=false after Freeze")
+	}
+
+	if err := reg.Replace("custom", AlwaysRetryOnError{}); !errors.Is(err, ErrRegistryFrozen) {
+		t.Fatalf("Replace err=%v, want ErrRegistryFrozen", err)
+	}
+	version := reg.Version()
+	if err := reg.Register("other", testClassifier{}); !errors.Is(err, ErrRegistryFrozen) {
+		t.Fatalf("Register err=%v, want ErrRegistryFrozen", err)
+	}
+	if err := reg.Register("custom", AlwaysRetryOnError{}); !errors.Is(err, ErrRegistryFrozen) {
+		t.Fatalf("Register err=%v, want ErrRegistryFrozen", err)
+	}
+	if reg.Version() != version {
+		t.Fatal("Register changed a frozen registry")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("MustRegister on frozen registry did not panic")
+			}
+		}()
+		reg.MustRegister("other", testClassifier{})
+	}()
+	if c, _ := reg.Get("custom"); c != (testClassifier{}) {
+		t.Fatalf("custom=%T, want the classifier registered before Freeze", c)
+	}
+	if _, ok := reg.Get("other"); ok {
+		t.Fatal("frozen registry accepted a registration")
+	}
+	if _, ok := reg.Get("custom"); !ok {
+		t.Fatal("frozen registry lost its classifier")
+	}
+}
+
+func TestRegistry_Clone(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("custom", testClassifier{})
+	reg.Freeze()
+
+	clone := reg.Clone()
+	if clone.Frozen() {
+		t.Fatal("clone of a frozen registry is frozen")
+	}
+	if _, ok := clone.Get("custom"); !ok {
+		t.Fatal("clone lost its classifier")
+	}
+	if err := clone.Register("other", testClassifier{}); err != nil {
+		t.Fatalf("Register on clone: %v", err)
+	}
+	if _, ok := reg.Get("other"); ok {
+		t.Fatal("registering on the clone changed the original")
+	}
+}
@@ -0,0 +1,146 @@
+package classify
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// SQLStateError is implemented by database driver errors that carry a
+// SQLSTATE code, such as pgx's *pgconn.PgError and lib/pq's *pq.Error.
+type SQLStateError interface {
+	SQLState() string
+}
+
+// SQLClassifier classifies database/sql errors by their SQLSTATE code:
+//
+//   - Serialization failures (40001), deadlocks (40P01), lock timeouts
+//     (55P03), connection exceptions (class 08), server shutdowns (57P01,
+//     57P02, 57P03) and too many connections (53300) are retryable.
+//   - Constraint violations (class 23), data exceptions (class 22) and
+//     syntax or access errors (class 42) are not retryable; neither are
+//     other codes.
+//
+// Connection failures without a SQLSTATE are retryable: driver.ErrBadConn,
+// sql.ErrConnDone, net.Error, io.ErrUnexpectedEOF and ECONNRESET.
+// sql.ErrNoRows and sql.ErrTxDone are not retryable. Other errors are not
+// retryable with reason "classifier_type_mismatch".
+//
+// Outcomes carry the code in the "sqlstate" attribute. Retrying writes after
+// a connection failure can repeat them; classify such calls with a stricter
+// classifier unless the statements are idempotent.
+type SQLClassifier struct {
+	// Codes maps SQLSTATE codes or two-character classes (such as "08") to
+	// outcome kinds, overriding the defaults. Full codes take precedence over
+	// classes. Codes from DriverCode are looked up here too, but only as full
+	// codes. Overridden outcomes have reason "sql_retryable_error" or
+	// "sql_error".
+	Codes map[string]OutcomeKind
+
+	// DriverCode extracts a code from errors that do not implement
+	// SQLStateError, such as MySQL error numbers ("1213" for a deadlock). The
+	// code is looked up in Codes, then in the SQLSTATE defaults, by full code
+	// only: driver codes have no classes, so "1062" is not read as class 10.
+	DriverCode func(err error) (code string, ok bool)
+}
+
+// sqlStateOutcomes holds the default outcomes of SQLSTATE codes and
+// two-character classes.
+var sqlStateOutcomes = map[string]Outcome{
+	"40001": {Kind: OutcomeRetryable, Reason: "sql_serialization_failure"},
+	"40P01": {Kind: OutcomeRetryable, Reason: "sql_deadlock"},
+	"55P03": {Kind: OutcomeRetryable, Reason: "sql_lock_not_available"},
+	"53300": {Kind: OutcomeRetryable, Reason: "sql_too_many_connections"},
+	"57P01": {Kind: OutcomeRetryable, Reason: "sql_server_shutdown"},
+	"57P02": {Kind: OutcomeRetryable, Reason: "sql_server_shutdown"},
+	"57P03": {Kind: OutcomeRetryable, Reason: "sql_server_shutdown"},
+	"08":    {Kind: OutcomeRetryable, Reason: "sql_connection_error"},
+	"22":    {Kind: OutcomeNonRetryable, Reason: "sql_data_exception"},
+	"23":    {Kind: OutcomeNonRetryable, Reason: "sql_constraint_violation"},
+	"42":    {Kind: OutcomeNonRetryable, Reason: "sql_syntax_error"},
+}
+
+func (c SQLClassifier) Classify(_ any, err error) Outcome {
+	if err == nil {
+		return Outcome{Kind: OutcomeSuccess, Reason: "success"}
+	}
+	if errors.Is(err, context.Canceled) {
+		return Outcome{Kind: OutcomeAbort, Reason: "context_canceled"}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Outcome{Kind: OutcomeRetryable, Reason: "context_deadline_exceeded"}
+	}
+
+	var se SQLStateError
+	if errors.As(err, &se) {
+		return c.classifyCode(strings.ToUpper(strings.TrimSpace(se.SQLState())), true)
+	}
+	if c.DriverCode != nil {
+		if code, ok := c.DriverCode(err); ok {
+			return c.classifyCode(strings.TrimSpace(code), false)
+		}
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return Outcome{Kind: OutcomeNonRetryable, Reason: "sql_no_rows"}
+	case errors.Is(err, sql.ErrTxDone):
+		return Outcome{Kind: OutcomeNonRetryable, Reason: "sql_tx_done"}
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone),
+		errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, syscall.ECONNRESET):
+		return Outcome{Kind: OutcomeRetryable, Reason: "sql_connection_error"}
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return Outcome{Kind: OutcomeRetryable, Reason: "sql_connection_error"}
+	}
+
+	return Outcome{
+		Kind:   OutcomeNonRetryable,
+		Reason: "classifier_type_mismatch",
+		Attributes: map[string]string{
+			"expected_type": "classify.SQLStateError",
+			"got_type":      typeString(err),
+		},
+	}
+}
+
+// classifyCode classifies a SQLSTATE or driver code. Only five-character
+// SQLSTATE codes fall back to their two-character class.
+func (c SQLClassifier) classifyCode(code string, sqlstate bool) Outcome {
+	out := Outcome{
+		Kind:       OutcomeNonRetryable,
+		Reason:     "sql_error",
+		Attributes: map[string]string{"sqlstate": code},
+	}
+	class := ""
+	if sqlstate && len(code) == 5 {
+		class = code[:2]
+	}
+
+	def, ok := sqlStateOutcomes[code]
+	if !ok && class != "" {
+		def, ok = sqlStateOutcomes[class]
+	}
+	if ok {
+		out.Kind, out.Reason = def.Kind, def.Reason
+	}
+
+	kind, ok := c.Codes[code]
+	if !ok && class != "" {
+		kind, ok = c.Codes[class]
+	}
+	if ok && kind != out.Kind {
+		out.Kind = kind
+		out.Reason = "sql_error"
+		if kind == OutcomeRetryable {
+			out.Reason = "sql_retryable_error"
+		}
+	}
+	return out
+}
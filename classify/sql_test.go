@@ -0,0 +1,92 @@
+package classify
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+type sqlStateErr string
+
+func (e sqlStateErr) Error() string    { return "pq: " + string(e) }
+func (e sqlStateErr) SQLState() string { return string(e) }
+
+type mysqlErr uint16
+
+func (e mysqlErr) Error() string { return "Error " + strconv.Itoa(int(e)) }
+
+func TestSQLClassifier(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		kind   OutcomeKind
+		reason string
+	}{
+		{sqlStateErr("40001"), OutcomeRetryable, "sql_serialization_failure"},
+		{fmt.Errorf("tx: %w", sqlStateErr("40P01")), OutcomeRetryable, "sql_deadlock"},
+		{sqlStateErr("08006"), OutcomeRetryable, "sql_connection_error"},
+		{sqlStateErr("57P01"), OutcomeRetryable, "sql_server_shutdown"},
+		{sqlStateErr("23505"), OutcomeNonRetryable, "sql_constraint_violation"},
+		{sqlStateErr("42P01"), OutcomeNonRetryable, "sql_syntax_error"},
+		{sqlStateErr("XX000"), OutcomeNonRetryable, "sql_error"},
+		{driver.ErrBadConn, OutcomeRetryable, "sql_connection_error"},
+		{fmt.Errorf("read: %w", syscall.ECONNRESET), OutcomeRetryable, "sql_connection_error"},
+		{sql.ErrNoRows, OutcomeNonRetryable, "sql_no_rows"},
+		{sql.ErrTxDone, OutcomeNonRetryable, "sql_tx_done"},
+		{context.Canceled, OutcomeAbort, "context_canceled"},
+		{errors.New("other"), OutcomeNonRetryable, "classifier_type_mismatch"},
+		{nil, OutcomeSuccess, "success"},
+	} {
+		out := SQLClassifier{}.Classify(nil, tc.err)
+		if out.Kind != tc.kind || out.Reason != tc.reason {
+			t.Errorf("Classify(%v)=%+v, want %v %s", tc.err, out, tc.kind, tc.reason)
+		}
+	}
+
+	if out := (SQLClassifier{}).Classify(nil, sqlStateErr("40001")); out.Attributes["sqlstate"] != "40001" {
+		t.Fatalf("sqlstate attribute=%q", out.Attributes["sqlstate"])
+	}
+}
+
+func TestSQLClassifier_DriverCodes(t *testing.T) {
+	c := SQLClassifier{
+		Codes: map[string]OutcomeKind{
+			"1213":  OutcomeRetryable,
+			"23":    OutcomeAbort,
+			"23505": OutcomeNonRetryable,
+		},
+		DriverCode: func(err error) (string, bool) {
+			var me mysqlErr
+			if errors.As(err, &me) {
+				return strconv.Itoa(int(me)), true
+			}
+			return "", false
+		},
+	}
+
+	for _, tc := range []struct {
+		err    error
+		kind   OutcomeKind
+		reason string
+	}{
+		{mysqlErr(1213), OutcomeRetryable, "sql_retryable_error"},
+		{mysqlErr(1062), OutcomeNonRetryable, "sql_error"},
+		// Driver codes are not SQLSTATE classes: 2300 and 23000 match
+		// neither class 23 nor its Codes override.
+		{mysqlErr(2300), OutcomeNonRetryable, "sql_error"},
+		{mysqlErr(23000), OutcomeNonRetryable, "sql_error"},
+		{mysqlErr(2201), OutcomeNonRetryable, "sql_error"},
+		{mysqlErr(4200), OutcomeNonRetryable, "sql_error"},
+		{sqlStateErr("23503"), OutcomeAbort, "sql_error"},
+		{sqlStateErr("23505"), OutcomeNonRetryable, "sql_constraint_violation"},
+	} {
+		out := c.Classify(nil, tc.err)
+		if out.Kind != tc.kind || out.Reason != tc.reason {
+			t.Errorf("Classify(%v)=%+v, want %v %s", tc.err, out, tc.kind, tc.reason)
+		}
+	}
+}
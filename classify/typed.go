@@ -0,0 +1,37 @@
+package classify
+
+import "reflect"
+
+// TypedClassifier classifies with a function of the operation's typed result.
+// Build one with Typed.
+type TypedClassifier[T any] struct {
+	fn func(T, error) Outcome
+}
+
+// Typed returns a classifier that passes the attempt's value to fn as a T, so
+// fn can inspect the result (for example a response with an embedded status
+// field) without type assertions. A nil value is passed as T's zero value.
+//
+// A value of another type is non-retryable with reason
+// "classifier_type_mismatch", like HTTPClassifier given a non-HTTP error.
+func Typed[T any](fn func(value T, err error) Outcome) TypedClassifier[T] {
+	return TypedClassifier[T]{fn: fn}
+}
+
+func (c TypedClassifier[T]) Classify(value any, err error) Outcome {
+	v, ok := value.(T)
+	if !ok && value != nil {
+		return Outcome{
+			Kind:   OutcomeNonRetryable,
+			Reason: "classifier_type_mismatch",
+			Attributes: map[string]string{
+				"expected_type": reflect.TypeFor[T]().String(),
+				"got_type":      reflect.TypeOf(value).String(),
+			},
+		}
+	}
+	if c.fn == nil {
+		return AlwaysRetryOnError{}.Classify(value, err)
+	}
+	return c.fn(v, err)
+}
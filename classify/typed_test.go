@@ -0,0 +1,40 @@
+package classify
+
+import (
+	"errors"
+	"testing"
+)
+
+type apiResponse struct {
+	Status string
+}
+
+func TestTyped(t *testing.T) {
+	c := Typed(func(resp *apiResponse, err error) Outcome {
+		if err != nil {
+			return Outcome{Kind: OutcomeRetryable, Reason: "error"}
+		}
+		if resp != nil && resp.Status == "busy" {
+			return Outcome{Kind: OutcomeRetryable, Reason: "busy"}
+		}
+		return Outcome{Kind: OutcomeSuccess, Reason: "success"}
+	})
+
+	if out := c.Classify(&apiResponse{Status: "busy"}, nil); out.Reason != "busy" {
+		t.Fatalf("busy response outcome=%+v", out)
+	}
+	if out := c.Classify(&apiResponse{Status: "ok"}, nil); out.Kind != OutcomeSuccess {
+		t.Fatalf("ok response outcome=%+v", out)
+	}
+	if out := c.Classify(nil, errors.New("boom")); out.Reason != "error" {
+		t.Fatalf("nil value outcome=%+v", out)
+	}
+
+	out := c.Classify("busy", nil)
+	if out.Kind != OutcomeNonRetryable || out.Reason != "classifier_type_mismatch" {
+		t.Fatalf("mismatch outcome=%+v", out)
+	}
+	if out.Attributes["expected_type"] != "*classify.apiResponse" || out.Attributes["got_type"] != "string" {
+		t.Fatalf("mismatch attributes=%v", out.Attributes)
+	}
+}
@@ -0,0 +1,89 @@
+package classify
+
+import (
+	"context"
+	"log/slog"
+)
+
+// wrapped runs fn on every outcome of inner.
+type wrapped struct {
+	inner Classifier
+	fn    func(value any, err error, out Outcome) Outcome
+}
+
+func (w wrapped) Classify(value any, err error) Outcome {
+	return w.fn(value, err, w.inner.Classify(value, err))
+}
+
+func (w wrapped) ClassifyAttempt(value any, err error, ac AttemptContext) Outcome {
+	return w.fn(value, err, ClassifyAttempt(w.inner, value, err, ac))
+}
+
+// WithRetryableCodes applies codes to the wrapped classifier when it supports
+// them, keeping the decorator.
+func (w wrapped) WithRetryableCodes(codes RetryableCodes) Classifier {
+	if cc, ok := w.inner.(CodesClassifier); ok {
+		w.inner = cc.WithRetryableCodes(codes)
+	}
+	return w
+}
+
+// Wrap returns a classifier that passes every outcome of c through fn, for
+// example to downgrade one retryable reason to non-retryable:
+//
+//	classify.Wrap(classify.HTTPClassifier{}, func(out classify.Outcome) classify.Outcome {
+//		if out.Reason == "http_429" {
+//			out.Kind = classify.OutcomeNonRetryable
+//		}
+//		return out
+//	})
+//
+// The wrapper keeps c's AttemptClassifier and CodesClassifier behavior. A nil
+// fn returns c unchanged.
+func Wrap(c Classifier, fn func(in Outcome) Outcome) Classifier {
+	if fn == nil {
+		return c
+	}
+	return wrapped{inner: c, fn: func(_ any, _ error, out Outcome) Outcome { return fn(out) }}
+}
+
+// Observe returns a classifier that reports every classification of c to fn,
+// for example to count decisions by reason. fn must not modify out's
+// Attributes. A nil fn returns c unchanged.
+func Observe(c Classifier, fn func(value any, err error, out Outcome)) Classifier {
+	if fn == nil {
+		return c
+	}
+	return wrapped{inner: c, fn: func(value any, err error, out Outcome) Outcome {
+		fn(value, err, out)
+		return out
+	}}
+}
+
+var kindNames = map[OutcomeKind]string{
+	OutcomeUnknown:      "unknown",
+	OutcomeSuccess:      "success",
+	OutcomeRetryable:    "retryable",
+	OutcomeNonRetryable: "non_retryable",
+	OutcomeAbort:        "abort",
+}
+
+// Log returns a classifier that logs every classification by c at debug
+// level, with the outcome kind, reason and error. A nil logger uses
+// slog.Default.
+func Log(c Classifier, logger *slog.Logger) Classifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return Observe(c, func(_ any, err error, out Outcome) {
+		ctx := context.Background()
+		if !logger.Enabled(ctx, slog.LevelDebug) {
+			return
+		}
+		attrs := []slog.Attr{slog.String("kind", kindNames[out.Kind]), slog.String("reason", out.Reason)}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		logger.LogAttrs(ctx, slog.LevelDebug, "classified attempt", attrs...)
+	})
+}
@@ -0,0 +1,67 @@
+package classify
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	c := Wrap(HTTPClassifier{}, func(out Outcome) Outcome {
+		if out.Reason == "http_429" {
+			out.Kind = OutcomeNonRetryable
+		}
+		return out
+	})
+
+	if out := c.Classify(nil, httpErr{status: 429, method: "GET"}); out.Kind != OutcomeNonRetryable {
+		t.Fatalf("429 kind=%v, want non-retryable", out.Kind)
+	}
+	if out := c.Classify(nil, httpErr{status: 503, method: "GET"}); out.Kind != OutcomeRetryable {
+		t.Fatalf("503 kind=%v, want retryable", out.Kind)
+	}
+
+	// The decorator keeps the wrapped classifier's retryable codes support.
+	cc, ok := c.(CodesClassifier)
+	if !ok {
+		t.Fatal("wrapped classifier does not implement CodesClassifier")
+	}
+	narrowed := cc.WithRetryableCodes(RetryableCodes{HTTPStatuses: []int{502}})
+	if out := narrowed.Classify(nil, httpErr{status: 503, method: "GET"}); out.Kind != OutcomeNonRetryable {
+		t.Fatalf("narrowed 503 kind=%v, want non-retryable", out.Kind)
+	}
+
+	if got := Wrap(AlwaysRetryOnError{}, nil); got != (AlwaysRetryOnError{}) {
+		t.Fatalf("Wrap with nil fn=%T, want the classifier itself", got)
+	}
+}
+
+func TestWrap_ForwardsAttempt(t *testing.T) {
+	c := Wrap(retryOnceClassifier{}, func(out Outcome) Outcome { return out })
+	out := ClassifyAttempt(c, nil, errors.New("boom"), AttemptContext{Attempt: 1})
+	if out.Reason != "retried_once" {
+		t.Fatalf("outcome=%+v, want the inner attempt classification", out)
+	}
+}
+
+func TestObserveAndLog(t *testing.T) {
+	counts := map[string]int{}
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := Log(Observe(AlwaysRetryOnError{}, func(_ any, _ error, out Outcome) {
+		counts[out.Reason]++
+	}), logger)
+
+	c.Classify(nil, errors.New("boom"))
+	c.Classify(nil, nil)
+
+	if counts["retryable_error"] != 1 || counts["success"] != 1 {
+		t.Fatalf("counts=%v", counts)
+	}
+	logged := buf.String()
+	if !strings.Contains(logged, "kind=retryable reason=retryable_error error=boom") || !strings.Contains(logged, "kind=success") {
+		t.Fatalf("log=%q", logged)
+	}
+}
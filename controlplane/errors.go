@@ -9,4 +9,6 @@ var (
 	ErrPolicyNotFound = errors.New("recourse: policy not found")
 	// ErrPolicyFetchFailed indicates a provider failure other than unavailability.
 	ErrPolicyFetchFailed = errors.New("recourse: policy fetch failed")
+	// ErrPolicyRejected indicates a provider's PolicyValidator rejected a fetched policy.
+	ErrPolicyRejected = errors.New("recourse: policy rejected")
 )
@@ -0,0 +1,172 @@
+package controlplane
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// FakeStep is one scripted behavior of a FakeSource.
+//
+// A step returns Err when set, otherwise Policy. It stays active for For
+// (measured on the source clock from the moment the step became active) or
+// for Times calls, whichever is reached first. A step with neither limit,
+// and the last step of a script, stays active forever.
+type FakeStep struct {
+	Policy  policy.EffectivePolicy // Policy returned while the step is active.
+	Err     error                  // Error returned while the step is active (takes precedence over Policy).
+	For     time.Duration          // How long the step stays active (0 = no time limit).
+	Times   int                    // How many calls the step serves (0 = no call limit).
+	Latency time.Duration          // Simulated fetch latency; honors ctx cancellation.
+}
+
+// FakeSource is a scriptable in-memory Source for tests.
+//
+// It plays back a sequence of FakeSteps, e.g. "return policy X for 5s, then fail,
+// then return Y", against an injectable clock, and exposes hooks to observe or
+// block fetches. It is safe for concurrent use.
+type FakeSource struct {
+	mu        sync.Mutex
+	steps     []FakeStep
+	idx       int
+	served    int
+	stepStart time.Time
+	started   bool
+	calls     int
+
+	nowFn   func() time.Time
+	sleepFn func(context.Context, time.Duration) error
+	onFetch func(ctx context.Context, key policy.PolicyKey, step int)
+}
+
+// NewFakeSource creates a FakeSource that plays back steps in order.
+// With no steps, every call returns ErrPolicyNotFound.
+func NewFakeSource(steps ...FakeStep) *FakeSource {
+	return &FakeSource{steps: append([]FakeStep(nil), steps...)}
+}
+
+// Then appends steps to the script and returns s for chaining.
+func (s *FakeSource) Then(steps ...FakeStep) *FakeSource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.steps = append(s.steps, steps...)
+	return s
+}
+
+// SetClock overrides the clock used to expire timed steps.
+func (s *FakeSource) SetClock(f func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nowFn = f
+}
+
+// SetSleep overrides how step latency is waited out, e.g. to drive virtual time.
+func (s *FakeSource) SetSleep(f func(context.Context, time.Duration) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sleepFn = f
+}
+
+// OnFetch registers a hook invoked on every GetPolicy call with the index of the
+// step serving it. The hook runs before latency is applied, so it may block to
+// hold a fetch in flight.
+func (s *FakeSource) OnFetch(f func(ctx context.Context, key policy.PolicyKey, step int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onFetch = f
+}
+
+// Calls returns the number of GetPolicy calls served so far.
+func (s *FakeSource) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// Step returns the index of the currently active step.
+func (s *FakeSource) Step() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advanceLocked(s.now())
+	return s.idx
+}
+
+func (s *FakeSource) GetPolicy(ctx context.Context, key policy.PolicyKey) (policy.EffectivePolicy, error) {
+	s.mu.Lock()
+	s.calls++
+	if len(s.steps) == 0 {
+		s.mu.Unlock()
+		return policy.EffectivePolicy{}, ErrPolicyNotFound
+	}
+	now := s.now()
+	if !s.started {
+		s.started = true
+		s.stepStart = now
+	}
+	s.advanceLocked(now)
+	idx := s.idx
+	step := s.steps[idx]
+	s.served++
+	hook := s.onFetch
+	sleep := s.sleepFn
+	s.mu.Unlock()
+
+	if hook != nil {
+		hook(ctx, key, idx)
+	}
+	if step.Latency > 0 {
+		if sleep == nil {
+			sleep = sleepContext
+		}
+		if err := sleep(ctx, step.Latency); err != nil {
+			return policy.EffectivePolicy{}, err
+		}
+	}
+
+	if step.Err != nil {
+		return policy.EffectivePolicy{}, step.Err
+	}
+	return step.Policy, nil
+}
+
+// advanceLocked moves past every step whose time or call limit has been reached.
+func (s *FakeSource) advanceLocked(now time.Time) {
+	if !s.started {
+		return
+	}
+	for s.idx < len(s.steps)-1 {
+		step := s.steps[s.idx]
+		expired := step.For > 0 && !now.Before(s.stepStart.Add(step.For))
+		exhausted := step.Times > 0 && s.served >= step.Times
+		if !expired && !exhausted {
+			return
+		}
+		if expired {
+			s.stepStart = s.stepStart.Add(step.For)
+		} else {
+			s.stepStart = now
+		}
+		s.idx++
+		s.served = 0
+	}
+}
+
+func (s *FakeSource) now() time.Time {
+	if s.nowFn != nil {
+		return s.nowFn()
+	}
+	return time.Now()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
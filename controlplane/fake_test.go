@@ -0,0 +1,91 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestFakeSource_ScriptedTimeline(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	outage := errors.New("outage")
+	key := policy.ParseKey("svc.method")
+
+	source := NewFakeSource(
+		FakeStep{Policy: policy.EffectivePolicy{ID: "x"}, For: 5 * time.Second},
+		FakeStep{Err: outage, Times: 2},
+	).Then(FakeStep{Policy: policy.EffectivePolicy{ID: "y"}})
+	source.SetClock(clock.Now)
+
+	pol, err := source.GetPolicy(context.Background(), key)
+	if err != nil || pol.ID != "x" {
+		t.Fatalf("first call: pol=%q err=%v, want x", pol.ID, err)
+	}
+
+	clock.Advance(4 * time.Second)
+	if pol, _ := source.GetPolicy(context.Background(), key); pol.ID != "x" {
+		t.Fatalf("before expiry: pol=%q, want x", pol.ID)
+	}
+
+	clock.Advance(time.Second)
+	for i := 0; i < 2; i++ {
+		if _, err := source.GetPolicy(context.Background(), key); !errors.Is(err, outage) {
+			t.Fatalf("outage call %d: err=%v, want outage", i, err)
+		}
+	}
+
+	pol, err = source.GetPolicy(context.Background(), key)
+	if err != nil || pol.ID != "y" {
+		t.Fatalf("after outage: pol=%q err=%v, want y", pol.ID, err)
+	}
+	if source.Step() != 2 {
+		t.Fatalf("step=%d, want 2", source.Step())
+	}
+	if source.Calls() != 5 {
+		t.Fatalf("calls=%d, want 5", source.Calls())
+	}
+}
+
+func TestFakeSource_EmptyScript(t *testing.T) {
+	_, err := NewFakeSource().GetPolicy(context.Background(), policy.ParseKey("svc.method"))
+	if !errors.Is(err, ErrPolicyNotFound) {
+		t.Fatalf("err=%v, want ErrPolicyNotFound", err)
+	}
+}
+
+func TestFakeSource_HooksAndLatency(t *testing.T) {
+	var slept time.Duration
+	var hookStep = -1
+
+	source := NewFakeSource(FakeStep{Latency: 50 * time.Millisecond})
+	source.SetSleep(func(_ context.Context, d time.Duration) error {
+		slept += d
+		return nil
+	})
+	source.OnFetch(func(_ context.Context, _ policy.PolicyKey, step int) {
+		hookStep = step
+	})
+
+	if _, err := source.GetPolicy(context.Background(), policy.ParseKey("svc.method")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hookStep != 0 {
+		t.Fatalf("hook step=%d, want 0", hookStep)
+	}
+	if slept != 50*time.Millisecond {
+		t.Fatalf("slept=%v, want 50ms", slept)
+	}
+}
+
+func TestFakeSource_LatencyHonorsCancellation(t *testing.T) {
+	source := NewFakeSource(FakeStep{Latency: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := source.GetPolicy(ctx, policy.ParseKey("svc.method")); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err=%v, want context.Canceled", err)
+	}
+}
@@ -17,15 +17,42 @@ type PolicyProvider interface {
 }
 
 // StaticProvider is an in-process PolicyProvider backed by a map and an optional default.
+// Keys without an exact entry use the best-matching selector policy, then the
+// "namespace.*" entry, then the "*" entry, and then Default.
 type StaticProvider struct {
 	Policies map[policy.PolicyKey]policy.EffectivePolicy
 	Default  policy.EffectivePolicy
+
+	// Labels holds each key's labels for Selectors. A key with a variant
+	// without labels of its own uses the labels of the key without it.
+	Labels map[policy.PolicyKey]policy.Labels
+	// Selectors are policies for every key whose labels match, such as all
+	// keys labeled tier=critical. The selector with the most label pairs
+	// wins; ties go to the earliest.
+	Selectors []SelectorPolicy
+}
+
+// SelectorPolicy is a policy for the keys its selector matches. Selectors
+// are resolved by StaticProvider only; RemoteProvider ignores labels.
+type SelectorPolicy struct {
+	Selector policy.Selector
+	Policy   policy.EffectivePolicy
 }
 
 func (p *StaticProvider) GetEffectivePolicy(_ context.Context, key policy.PolicyKey) (policy.EffectivePolicy, error) {
-	if p != nil && p.Policies != nil {
-		if pol, ok := p.Policies[key]; ok {
+	if p != nil && (p.Policies != nil || len(p.Selectors) > 0) {
+		for _, pattern := range key.Patterns() {
+			if pattern.Name == policy.Wildcard {
+				if pol, ok := p.selectorPolicy(key); ok {
+					return pol.Normalize()
+				}
+			}
+			pol, ok := p.Policies[pattern]
+			if !ok {
+				continue
+			}
 			pol.Key = key
+			pol.Meta.MatchedKey = pattern
 			if pol.Meta.Source == "" || pol.Meta.Source == policy.PolicySourceUnknown {
 				pol.Meta.Source = policy.PolicySourceStatic
 			}
@@ -33,7 +60,7 @@ func (p *StaticProvider) GetEffectivePolicy(_ context.Context, key policy.Policy
 		}
 	}
 
-	if p != nil && !isZeroEffectivePolicy(p.Default) {
+	if p != nil && !p.Default.IsZero() {
 		pol := p.Default
 		pol.Key = key
 		if pol.Meta.Source == "" || pol.Meta.Source == policy.PolicySourceUnknown {
@@ -45,9 +72,31 @@ func (p *StaticProvider) GetEffectivePolicy(_ context.Context, key policy.Policy
 	return policy.DefaultPolicyFor(key).Normalize()
 }
 
-func isZeroEffectivePolicy(pol policy.EffectivePolicy) bool {
-	return pol.Key == (policy.PolicyKey{}) &&
-		pol.ID == "" &&
-		pol.Retry == (policy.RetryPolicy{}) &&
-		pol.Hedge == (policy.HedgePolicy{})
+// selectorPolicy returns the policy of the selector that best matches key's
+// labels.
+func (p *StaticProvider) selectorPolicy(key policy.PolicyKey) (policy.EffectivePolicy, bool) {
+	if len(p.Selectors) == 0 {
+		return policy.EffectivePolicy{}, false
+	}
+	labels, ok := p.Labels[key]
+	if !ok && key.Variant != "" {
+		labels = p.Labels[key.Base()]
+	}
+
+	best := -1
+	for i, sp := range p.Selectors {
+		if sp.Selector.Matches(labels) && (best < 0 || len(sp.Selector) > len(p.Selectors[best].Selector)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return policy.EffectivePolicy{}, false
+	}
+	pol := p.Selectors[best].Policy
+	pol.Key = key
+	pol.Meta.Selector = p.Selectors[best].Selector.String()
+	if pol.Meta.Source == "" || pol.Meta.Source == policy.PolicySourceUnknown {
+		pol.Meta.Source = policy.PolicySourceStatic
+	}
+	return pol, true
 }
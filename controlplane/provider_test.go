@@ -84,15 +84,78 @@ func TestStaticProvider_FallbackToDefaultPolicy(t *testing.T) {
 	}
 }
 
-func TestIsZeroEffectivePolicy(t *testing.T) {
-	if !isZeroEffectivePolicy(policy.EffectivePolicy{}) {
-		t.Fatal("expected zero policy")
+func TestStaticProvider_WildcardPrecedence(t *testing.T) {
+	provider := &StaticProvider{
+		Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+			policy.ParseKey("svc.exact"):      {Retry: policy.RetryPolicy{MaxAttempts: 2}},
+			policy.ParseKey("svc.exact@acme"): {Retry: policy.RetryPolicy{MaxAttempts: 5}},
+			policy.ParseKey("svc.*"):          {Retry: policy.RetryPolicy{MaxAttempts: 3}},
+			policy.ParseKey("*"):              {Retry: policy.RetryPolicy{MaxAttempts: 4}},
+		},
 	}
 
-	nonZero := policy.EffectivePolicy{
-		Retry: policy.RetryPolicy{MaxAttempts: 1},
+	cases := []struct {
+		key     string
+		want    int
+		matched string
+	}{
+		{"svc.exact", 2, "svc.exact"},
+		{"svc.exact@acme", 5, "svc.exact@acme"},
+		{"svc.exact@globex", 2, "svc.exact"},
+		{"svc.other@acme", 3, "svc.*"},
+		{"svc.other", 3, "svc.*"},
+		{"other.method", 4, "*"},
+	}
+	for _, tc := range cases {
+		key := policy.ParseKey(tc.key)
+		pol, err := provider.GetEffectivePolicy(context.Background(), key)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.key, err)
+		}
+		if pol.Key != key || pol.Retry.MaxAttempts != tc.want || pol.Meta.MatchedKey.String() != tc.matched {
+			t.Fatalf("%s: key=%v maxAttempts=%d matched=%v, want %d via %s", tc.key, pol.Key, pol.Retry.MaxAttempts, pol.Meta.MatchedKey, tc.want, tc.matched)
+		}
+	}
+}
+
+func TestStaticProvider_Selectors(t *testing.T) {
+	provider := &StaticProvider{
+		Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+			policy.ParseKey("payments.Exact"): {Retry: policy.RetryPolicy{MaxAttempts: 2}},
+			policy.ParseKey("payments.*"):     {Retry: policy.RetryPolicy{MaxAttempts: 3}},
+		},
+		Labels: map[policy.PolicyKey]policy.Labels{
+			policy.ParseKey("payments.Exact"):  {"tier": "critical"},
+			policy.ParseKey("payments.Charge"): {"tier": "critical", "region": "eu"},
+			policy.ParseKey("payments.Refund"): {"tier": "critical"},
+			policy.ParseKey("search.Query"):    {"tier": "batch"},
+		},
+		Selectors: []SelectorPolicy{
+			{Selector: policy.Selector{"tier": "critical"}, Policy: policy.EffectivePolicy{Retry: policy.RetryPolicy{MaxAttempts: 5}}},
+			{Selector: policy.Selector{"tier": "critical", "region": "eu"}, Policy: policy.EffectivePolicy{Retry: policy.RetryPolicy{MaxAttempts: 6}}},
+		},
 	}
-	if isZeroEffectivePolicy(nonZero) {
-		t.Fatal("expected non-zero policy")
+
+	cases := []struct {
+		key      string
+		want     int
+		selector string
+	}{
+		{"payments.Exact", 2, ""},
+		{"payments.Charge", 6, "region=eu,tier=critical"},
+		{"payments.Refund", 5, "tier=critical"},
+		{"payments.Refund@acme", 5, "tier=critical"},
+		{"payments.Other", 3, ""},
+		{"search.Query", 3, ""},
+	}
+	for _, tc := range cases {
+		key := policy.ParseKey(tc.key)
+		pol, err := provider.GetEffectivePolicy(context.Background(), key)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.key, err)
+		}
+		if pol.Key != key || pol.Retry.MaxAttempts != tc.want || pol.Meta.Selector != tc.selector {
+			t.Fatalf("%s: maxAttempts=%d selector=%q, want %d via %q", tc.key, pol.Retry.MaxAttempts, pol.Meta.Selector, tc.want, tc.selector)
+		}
 	}
 }
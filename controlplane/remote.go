@@ -3,6 +3,7 @@ package controlplane
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/aponysus/recourse/policy"
@@ -21,8 +22,15 @@ type RemoteProvider struct {
 	cache            *PolicyCache
 	cacheTTL         time.Duration
 	negativeCacheTTL time.Duration
+	wildcards        bool
+	validators       []PolicyValidator
 }
 
+// PolicyValidator admits or rejects a fetched policy before the provider
+// caches or returns it. pol is already normalized. A non-nil error rejects the
+// policy.
+type PolicyValidator func(key policy.PolicyKey, pol policy.EffectivePolicy) error
+
 // RemoteProviderOption configures a RemoteProvider.
 type RemoteProviderOption func(*RemoteProvider)
 
@@ -40,7 +48,30 @@ func WithNegativeCacheTTL(ttl time.Duration) RemoteProviderOption {
 	}
 }
 
-// NewRemoteProvider creates a new RemoteProvider.
+// WithWildcardMatching makes keys the source has no policy for fall back to
+// their wildcard patterns, "namespace.*" and then "*" (see
+// policy.PolicyKey.Patterns). Each fallback is a separate source lookup; the
+// result is cached under the requested key.
+func WithWildcardMatching() RemoteProviderOption {
+	return func(p *RemoteProvider) {
+		p.wildcards = true
+	}
+}
+
+// WithPolicyValidator adds an admission check for fetched policies, such as an
+// organization-wide attempt limit or a mandatory budget. Validators run in the
+// order added. A rejected policy is not cached, and GetEffectivePolicy returns
+// an error matching ErrPolicyRejected and wrapping the validator's error.
+func WithPolicyValidator(v PolicyValidator) RemoteProviderOption {
+	return func(p *RemoteProvider) {
+		if v != nil {
+			p.validators = append(p.validators, v)
+		}
+	}
+}
+
+// NewRemoteProvider creates a new RemoteProvider. Keys with a variant that the
+// source has no policy for fall back to the key without its variant.
 func NewRemoteProvider(source Source, opts ...RemoteProviderOption) *RemoteProvider {
 	p := &RemoteProvider{
 		source:           source,
@@ -67,8 +98,21 @@ func (p *RemoteProvider) GetEffectivePolicy(ctx context.Context, key policy.Poli
 		return pol, nil
 	}
 
-	// 2. Fetch from Source
-	pol, err := p.source.GetPolicy(ctx, key)
+	// 2. Fetch from Source, falling back to wildcard patterns
+	patterns := []policy.PolicyKey{key}
+	if p.wildcards {
+		patterns = key.Patterns()
+	} else if key.Variant != "" {
+		patterns = append(patterns, key.Base())
+	}
+	var matched policy.PolicyKey
+	var err error
+	for _, matched = range patterns {
+		pol, err = p.source.GetPolicy(ctx, matched)
+		if !errors.Is(err, ErrPolicyNotFound) {
+			break
+		}
+	}
 	if err != nil {
 		if errors.Is(err, ErrPolicyNotFound) {
 			p.cache.SetMissing(key, p.negativeCacheTTL)
@@ -81,6 +125,7 @@ func (p *RemoteProvider) GetEffectivePolicy(ctx context.Context, key policy.Poli
 	// 3. Cache and Return
 	// Ensure metadata is set
 	pol.Key = key
+	pol.Meta.MatchedKey = matched
 	if pol.Meta.Source == "" {
 		pol.Meta.Source = "remote"
 	}
@@ -92,6 +137,12 @@ func (p *RemoteProvider) GetEffectivePolicy(ctx context.Context, key policy.Poli
 		return policy.EffectivePolicy{}, err
 	}
 
+	for _, validate := range p.validators {
+		if err := validate(key, normalized); err != nil {
+			return policy.EffectivePolicy{}, fmt.Errorf("%w: %s: %w", ErrPolicyRejected, key.String(), err)
+		}
+	}
+
 	p.cache.Set(key, normalized, p.cacheTTL)
 	return normalized, nil
 }
@@ -3,38 +3,19 @@ package controlplane
 import (
 	"context"
 	"errors"
-	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/aponysus/recourse/policy"
 )
 
-// MockSource is a Source for testing.
-type MockSource struct {
-	GetPolicyFunc func(ctx context.Context, key policy.PolicyKey) (policy.EffectivePolicy, error)
-	Calls         int32
-}
-
-func (m *MockSource) GetPolicy(ctx context.Context, key policy.PolicyKey) (policy.EffectivePolicy, error) {
-	atomic.AddInt32(&m.Calls, 1)
-	if m.GetPolicyFunc != nil {
-		return m.GetPolicyFunc(ctx, key)
-	}
-	return policy.EffectivePolicy{}, ErrPolicyNotFound
-}
-
 func TestRemoteProvider_CacheHit(t *testing.T) {
 	key := policy.ParseKey("test.key")
 	expected := policy.EffectivePolicy{
 		Retry: policy.RetryPolicy{MaxAttempts: 5},
 	}
 
-	source := &MockSource{
-		GetPolicyFunc: func(ctx context.Context, k policy.PolicyKey) (policy.EffectivePolicy, error) {
-			return expected, nil
-		},
-	}
+	source := NewFakeSource(FakeStep{Policy: expected})
 
 	provider := NewRemoteProvider(source, WithCacheTTL(1*time.Minute))
 
@@ -46,8 +27,8 @@ func TestRemoteProvider_CacheHit(t *testing.T) {
 	if pol.Retry.MaxAttempts != 5 {
 		t.Errorf("got MaxAttempts=%d, want 5", pol.Retry.MaxAttempts)
 	}
-	if atomic.LoadInt32(&source.Calls) != 1 {
-		t.Errorf("expected 1 call to source, got %d", source.Calls)
+	if source.Calls() != 1 {
+		t.Errorf("expected 1 call to source, got %d", source.Calls())
 	}
 
 	// Second call - should hit cache
@@ -55,18 +36,14 @@ func TestRemoteProvider_CacheHit(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if atomic.LoadInt32(&source.Calls) != 1 {
-		t.Errorf("expected 1 call to source (cached), got %d", source.Calls)
+	if source.Calls() != 1 {
+		t.Errorf("expected 1 call to source (cached), got %d", source.Calls())
 	}
 }
 
 func TestRemoteProvider_NegativeCache(t *testing.T) {
 	key := policy.ParseKey("missing.key")
-	source := &MockSource{
-		GetPolicyFunc: func(ctx context.Context, k policy.PolicyKey) (policy.EffectivePolicy, error) {
-			return policy.EffectivePolicy{}, ErrPolicyNotFound
-		},
-	}
+	source := NewFakeSource(FakeStep{Err: ErrPolicyNotFound})
 
 	provider := NewRemoteProvider(source, WithNegativeCacheTTL(10*time.Minute))
 
@@ -75,8 +52,8 @@ func TestRemoteProvider_NegativeCache(t *testing.T) {
 	if !errors.Is(err, ErrPolicyNotFound) {
 		t.Errorf("want ErrPolicyNotFound, got %v", err)
 	}
-	if atomic.LoadInt32(&source.Calls) != 1 {
-		t.Errorf("expected 1 call to source, got %d", source.Calls)
+	if source.Calls() != 1 {
+		t.Errorf("expected 1 call to source, got %d", source.Calls())
 	}
 
 	// Second call - should hit negative cache
@@ -84,8 +61,8 @@ func TestRemoteProvider_NegativeCache(t *testing.T) {
 	if !errors.Is(err, ErrPolicyNotFound) {
 		t.Errorf("want ErrPolicyNotFound (from cache), got %v", err)
 	}
-	if atomic.LoadInt32(&source.Calls) != 1 {
-		t.Errorf("expected 1 call to source (cached negative), got %d", source.Calls)
+	if source.Calls() != 1 {
+		t.Errorf("expected 1 call to source (cached negative), got %d", source.Calls())
 	}
 }
 
@@ -93,19 +70,15 @@ func TestRemoteProvider_Expiration(t *testing.T) {
 	key := policy.ParseKey("expire.key")
 	expected := policy.EffectivePolicy{}
 
-	source := &MockSource{
-		GetPolicyFunc: func(ctx context.Context, k policy.PolicyKey) (policy.EffectivePolicy, error) {
-			return expected, nil
-		},
-	}
+	source := NewFakeSource(FakeStep{Policy: expected})
 
 	// Very short TTL
 	provider := NewRemoteProvider(source, WithCacheTTL(10*time.Millisecond))
 
 	// First call
 	_, _ = provider.GetEffectivePolicy(context.Background(), key)
-	if atomic.LoadInt32(&source.Calls) != 1 {
-		t.Errorf("expected 1 call, got %d", source.Calls)
+	if source.Calls() != 1 {
+		t.Errorf("expected 1 call, got %d", source.Calls())
 	}
 
 	// Wait for expiration
@@ -113,8 +86,8 @@ func TestRemoteProvider_Expiration(t *testing.T) {
 
 	// Second call - should expire and re-fetch
 	_, _ = provider.GetEffectivePolicy(context.Background(), key)
-	if atomic.LoadInt32(&source.Calls) != 2 {
-		t.Errorf("expected 2 calls (expired), got %d", source.Calls)
+	if source.Calls() != 2 {
+		t.Errorf("expected 2 calls (expired), got %d", source.Calls())
 	}
 }
 
@@ -122,11 +95,7 @@ func TestRemoteProvider_FetchError_NotCached(t *testing.T) {
 	key := policy.ParseKey("error.key")
 	networkErr := errors.New("network error")
 
-	source := &MockSource{
-		GetPolicyFunc: func(ctx context.Context, k policy.PolicyKey) (policy.EffectivePolicy, error) {
-			return policy.EffectivePolicy{}, networkErr
-		},
-	}
+	source := NewFakeSource(FakeStep{Err: networkErr})
 
 	provider := NewRemoteProvider(source)
 
@@ -141,7 +110,106 @@ func TestRemoteProvider_FetchError_NotCached(t *testing.T) {
 	if !errors.Is(err, networkErr) {
 		t.Errorf("want network error, got %v", err)
 	}
-	if atomic.LoadInt32(&source.Calls) != 2 {
-		t.Errorf("expected 2 calls (no cache on error), got %d", source.Calls)
+	if source.Calls() != 2 {
+		t.Errorf("expected 2 calls (no cache on error), got %d", source.Calls())
+	}
+}
+
+type mapSource struct {
+	policies map[policy.PolicyKey]policy.EffectivePolicy
+	lookups  []policy.PolicyKey
+}
+
+func (s *mapSource) GetPolicy(_ context.Context, key policy.PolicyKey) (policy.EffectivePolicy, error) {
+	s.lookups = append(s.lookups, key)
+	if pol, ok := s.policies[key]; ok {
+		return pol, nil
+	}
+	return policy.EffectivePolicy{}, ErrPolicyNotFound
+}
+
+func TestRemoteProvider_WildcardMatching(t *testing.T) {
+	source := &mapSource{policies: map[policy.PolicyKey]policy.EffectivePolicy{
+		policy.ParseKey("svc.*"): {Retry: policy.RetryPolicy{MaxAttempts: 3}},
+	}}
+	key := policy.ParseKey("svc.method")
+
+	if _, err := NewRemoteProvider(source).GetEffectivePolicy(context.Background(), key); !errors.Is(err, ErrPolicyNotFound) {
+		t.Fatalf("err=%v, want ErrPolicyNotFound without wildcard matching", err)
+	}
+
+	source.lookups = nil
+	provider := NewRemoteProvider(source, WithWildcardMatching())
+	for i := 0; i < 2; i++ {
+		pol, err := provider.GetEffectivePolicy(context.Background(), key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pol.Key != key || pol.Retry.MaxAttempts != 3 || pol.Meta.MatchedKey != policy.ParseKey("svc.*") {
+			t.Fatalf("policy=%+v", pol)
+		}
+	}
+	if len(source.lookups) != 2 {
+		t.Fatalf("lookups=%v, want exact key then svc.* once", source.lookups)
+	}
+
+	if _, err := provider.GetEffectivePolicy(context.Background(), policy.ParseKey("other.method")); !errors.Is(err, ErrPolicyNotFound) {
+		t.Fatalf("err=%v, want ErrPolicyNotFound", err)
+	}
+}
+
+func TestRemoteProvider_PolicyValidator(t *testing.T) {
+	key := policy.ParseKey("svc.method")
+	source := &mapSource{policies: map[policy.PolicyKey]policy.EffectivePolicy{
+		key: {Retry: policy.RetryPolicy{MaxAttempts: 8}},
+	}}
+	errTooMany := errors.New("at most 5 attempts")
+	var seen []policy.PolicyKey
+	provider := NewRemoteProvider(source,
+		WithPolicyValidator(func(k policy.PolicyKey, pol policy.EffectivePolicy) error {
+			seen = append(seen, k)
+			return nil
+		}),
+		WithPolicyValidator(func(_ policy.PolicyKey, pol policy.EffectivePolicy) error {
+			if pol.Retry.MaxAttempts > 5 {
+				return errTooMany
+			}
+			return nil
+		}),
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := provider.GetEffectivePolicy(context.Background(), key)
+		if !errors.Is(err, ErrPolicyRejected) || !errors.Is(err, errTooMany) {
+			t.Fatalf("err=%v, want ErrPolicyRejected wrapping the validator error", err)
+		}
+	}
+	if len(source.lookups) != 2 || len(seen) != 2 {
+		t.Fatalf("lookups=%d validations=%d, want rejected policy refetched", len(source.lookups), len(seen))
+	}
+
+	source.policies[key] = policy.EffectivePolicy{Retry: policy.RetryPolicy{MaxAttempts: 4}}
+	pol, err := provider.GetEffectivePolicy(context.Background(), key)
+	if err != nil || pol.Retry.MaxAttempts != 4 {
+		t.Fatalf("policy=%+v err=%v", pol.Retry, err)
+	}
+}
+
+func TestRemoteProvider_VariantFallback(t *testing.T) {
+	source := &mapSource{policies: map[policy.PolicyKey]policy.EffectivePolicy{
+		policy.ParseKey("svc.method"):      {Retry: policy.RetryPolicy{MaxAttempts: 3}},
+		policy.ParseKey("svc.method@acme"): {Retry: policy.RetryPolicy{MaxAttempts: 5}},
+	}}
+	provider := NewRemoteProvider(source)
+
+	for variant, want := range map[string]int{"acme": 5, "globex": 3} {
+		key := policy.ParseKey("svc.method").WithVariant(variant)
+		pol, err := provider.GetEffectivePolicy(context.Background(), key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pol.Key != key || pol.Retry.MaxAttempts != want {
+			t.Fatalf("%s: policy=%+v, want %d attempts", variant, pol, want)
+		}
 	}
 }
@@ -0,0 +1,3 @@
+// Package fallback defines named fallback handlers that policies can reference
+// to serve degraded responses when a call fails.
+package fallback
@@ -0,0 +1,70 @@
+package fallback
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aponysus/recourse/internal"
+)
+
+// Registry is a thread-safe name → Handler map.
+//
+// Lookups are lock-free: registrations copy the map and publish it atomically,
+// since handlers are looked up on failing calls but registered rarely.
+type Registry struct {
+	mu sync.Mutex // serializes writers
+	m  atomic.Pointer[map[string]Handler]
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register registers a handler with validation.
+// It returns an error if the registry is nil, the name is empty, or the handler is nil/typed-nil.
+func (r *Registry) Register(name string, h Handler) error {
+	if r == nil {
+		return errors.New("registry is nil")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("fallback name cannot be empty")
+	}
+	if internal.IsTypedNil(h) {
+		return errors.New("handler cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := internal.CopyMap(r.m.Load(), 1)
+	next[name] = h
+	r.m.Store(&next)
+	return nil
+}
+
+// MustRegister registers a handler and panics on error.
+func (r *Registry) MustRegister(name string, h Handler) {
+	if err := r.Register(name, h); err != nil {
+		panic("fallback.Registry.MustRegister: " + err.Error())
+	}
+}
+
+func (r *Registry) Get(name string) (Handler, bool) {
+	if r == nil {
+		return nil, false
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false
+	}
+
+	m := r.m.Load()
+	if m == nil {
+		return nil, false
+	}
+	h, ok := (*m)[name]
+	return h, ok && h != nil
+}
@@ -0,0 +1,57 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestRegistry_RegisterAndGet(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Register(" empty ", Value("")); err != nil {
+		t.Fatalf("unexpected register error: %v", err)
+	}
+	if h, ok := reg.Get("empty"); !ok || h == nil {
+		t.Fatalf("expected handler to be registered")
+	}
+	if _, ok := reg.Get("missing"); ok {
+		t.Fatalf("expected missing handler lookup to fail")
+	}
+}
+
+func TestRegistry_RegisterValidation(t *testing.T) {
+	var nilReg *Registry
+	if err := nilReg.Register("x", Value(1)); err == nil {
+		t.Fatal("expected error for nil registry")
+	}
+
+	reg := NewRegistry()
+	if err := reg.Register("   ", Value(1)); err == nil {
+		t.Fatal("expected error for empty name")
+	}
+	var nilFunc HandlerFunc
+	if err := reg.Register("x", nilFunc); err == nil {
+		t.Fatal("expected error for typed-nil handler")
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	key := policy.PolicyKey{Name: "op"}
+	boom := errors.New("boom")
+
+	v, err := Value(42).Fallback(context.Background(), key, boom)
+	if err != nil || v != 42 {
+		t.Fatalf("v=%v err=%v, want 42", v, err)
+	}
+
+	var gotErr error
+	h := HandlerFunc(func(_ context.Context, _ policy.PolicyKey, err error) (any, error) {
+		gotErr = err
+		return "degraded", nil
+	})
+	if v, _ := h.Fallback(context.Background(), key, boom); v != "degraded" || gotErr != boom {
+		t.Fatalf("v=%v gotErr=%v, want degraded and boom", v, gotErr)
+	}
+}
@@ -0,0 +1,34 @@
+package fallback
+
+import (
+	"context"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// Handler produces a degraded result for a call that failed with err.
+//
+// The returned value must be assignable to the call's result type; otherwise
+// the executor discards it and returns the original error.
+type Handler interface {
+	Fallback(ctx context.Context, key policy.PolicyKey, err error) (any, error)
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(ctx context.Context, key policy.PolicyKey, err error) (any, error)
+
+func (f HandlerFunc) Fallback(ctx context.Context, key policy.PolicyKey, err error) (any, error) {
+	return f(ctx, key, err)
+}
+
+// Value returns a Handler that always serves v. Register it for policies in
+// static fallback mode.
+func Value(v any) Handler {
+	return staticValue{v: v}
+}
+
+type staticValue struct{ v any }
+
+func (s staticValue) Fallback(context.Context, policy.PolicyKey, error) (any, error) {
+	return s.v, nil
+}
@@ -2,20 +2,21 @@ package hedge
 
 import (
 	"sync"
+	"sync/atomic"
+
+	"github.com/aponysus/recourse/internal"
 )
 
 // Registry manages named hedge triggers.
-// It is safe for concurrent use.
+// It is safe for concurrent use; lookups are lock-free.
 type Registry struct {
-	mu       sync.RWMutex
-	triggers map[string]Trigger
+	mu       sync.Mutex // serializes writers
+	triggers atomic.Pointer[map[string]Trigger]
 }
 
 // NewRegistry creates a new, empty registry.
 func NewRegistry() *Registry {
-	return &Registry{
-		triggers: make(map[string]Trigger),
-	}
+	return &Registry{}
 }
 
 // Register adds a trigger to the registry.
@@ -29,13 +30,17 @@ func (r *Registry) Register(name string, t Trigger) {
 	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.triggers[name] = t
+	next := internal.CopyMap(r.triggers.Load(), 1)
+	next[name] = t
+	r.triggers.Store(&next)
 }
 
 // Get returns the trigger with the given name.
 func (r *Registry) Get(name string) (Trigger, bool) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	t, ok := r.triggers[name]
+	m := r.triggers.Load()
+	if m == nil {
+		return nil, false
+	}
+	t, ok := (*m)[name]
 	return t, ok
 }
@@ -12,6 +12,8 @@ type LatencySnapshot struct {
 	P90 time.Duration
 	P95 time.Duration
 	P99 time.Duration
+
+	Count int // Samples the quantiles were computed from.
 }
 
 // LatencyTracker tracks recent latency samples and calculates quantiles.
@@ -87,6 +89,8 @@ func (t *RingBufferTracker) Snapshot() LatencySnapshot {
 		P90: quantile(sorted, 0.90),
 		P95: quantile(sorted, 0.95),
 		P99: quantile(sorted, 0.99),
+
+		Count: count,
 	}
 }
 
@@ -84,3 +84,13 @@ func TestRingBufferTracker_Concurrent(t *testing.T) {
 	<-done
 	<-done
 }
+
+func TestRingBufferTracker_SnapshotCount(t *testing.T) {
+	tr := NewRingBufferTracker(4)
+	for i := 0; i < 6; i++ {
+		tr.Observe(time.Millisecond)
+	}
+	if got := tr.Snapshot().Count; got != 4 {
+		t.Fatalf("count=%d, want 4", got)
+	}
+}
@@ -8,10 +8,19 @@ import (
 // LatencyTrigger spawns a hedge if the elapsed time exceeds a dynamic threshold.
 type LatencyTrigger struct {
 	Percentile string // "p50", "p90", "p95", "p99"
+
+	// MinSamples, when positive, is the number of latency samples required
+	// before the percentile is trusted. With fewer, hedges are spaced by
+	// HedgeState.HedgeDelay as with FixedDelayTrigger.
+	MinSamples int
 }
 
 // ShouldSpawnHedge checks if the hedge should be spawned based on latency stats.
 func (t LatencyTrigger) ShouldSpawnHedge(state HedgeState) (bool, time.Duration) {
+	if t.MinSamples > 0 && state.Snapshot.Count < t.MinSamples {
+		return FixedDelayTrigger{}.ShouldSpawnHedge(state)
+	}
+
 	threshold := time.Duration(0)
 
 	switch strings.ToLower(t.Percentile) {
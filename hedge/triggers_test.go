@@ -109,3 +109,24 @@ func TestLatencyTrigger_ShouldSpawnHedge(t *testing.T) {
 		})
 	}
 }
+
+func TestLatencyTrigger_MinSamplesFallback(t *testing.T) {
+	trig := LatencyTrigger{Percentile: "p95", MinSamples: 20}
+	state := HedgeState{
+		AttemptsLaunched: 1,
+		MaxHedges:        1,
+		Elapsed:          30 * time.Millisecond,
+		HedgeDelay:       25 * time.Millisecond,
+		Snapshot:         LatencySnapshot{P95: 100 * time.Millisecond, Count: 5},
+	}
+
+	if should, _ := trig.ShouldSpawnHedge(state); !should {
+		t.Fatal("expected hedge at the fixed delay with too few samples")
+	}
+
+	state.Snapshot.Count = 20
+	should, wait := trig.ShouldSpawnHedge(state)
+	if should || wait != 70*time.Millisecond {
+		t.Fatalf("should=%v wait=%v, want wait for p95", should, wait)
+	}
+}
@@ -0,0 +1,3 @@
+// Package idempotency generates idempotency keys and carries them through
+// context so every retry and hedge of one logical operation sends the same key.
+package idempotency
@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	mrand "math/rand/v2"
+	"strings"
+)
+
+// Header and metadata names used by the integrations to send the key.
+const (
+	// HeaderName is the HTTP header carrying the key.
+	HeaderName = "Idempotency-Key"
+	// MetadataKey is the gRPC metadata key carrying the key.
+	MetadataKey = "idempotency-key"
+)
+
+type keyCtxKey struct{}
+
+// NewKey returns a random 128-bit key encoded as 32 hex characters.
+func NewKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand failures are practically unreachable; keys only need
+		// to be unique, not unpredictable.
+		binary.LittleEndian.PutUint64(b[:8], mrand.Uint64())
+		binary.LittleEndian.PutUint64(b[8:], mrand.Uint64())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// KeyFor derives a deterministic key from parts, for operations whose inputs
+// already identify them (for example an order ID and an action). Equal parts
+// always produce the same key, across processes and restarts.
+func KeyFor(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		// Length-prefix each part so ("ab", "c") and ("a", "bc") differ.
+		var n [8]byte
+		binary.LittleEndian.PutUint64(n[:], uint64(len(p)))
+		h.Write(n[:])
+		h.Write([]byte(p))
+	}
+	sum := h.Sum(nil)
+	return hex.EncodeToString(sum[:16])
+}
+
+// WithKey returns a context derived from ctx that carries key.
+// An empty (or all-whitespace) key removes any key carried by ctx.
+func WithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, keyCtxKey{}, strings.TrimSpace(key))
+}
+
+// FromContext returns the key carried by ctx, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	key, _ := ctx.Value(keyCtxKey{}).(string)
+	return key, key != ""
+}
+
+// Ensure returns ctx with an idempotency key, generating one with NewKey when
+// ctx does not already carry one, along with the key in effect.
+//
+// Call it once per logical operation, before the executor: attempt contexts
+// derive from the call context, so retries and hedges reuse the key.
+func Ensure(ctx context.Context) (context.Context, string) {
+	if key, ok := FromContext(ctx); ok {
+		return ctx, key
+	}
+	key := NewKey()
+	return WithKey(ctx, key), key
+}
@@ -0,0 +1,62 @@
+package idempotency_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aponysus/recourse/idempotency"
+)
+
+func TestNewKey_Unique(t *testing.T) {
+	seen := make(map[string]struct{})
+	for i := 0; i < 100; i++ {
+		k := idempotency.NewKey()
+		if len(k) != 32 {
+			t.Fatalf("len(%q)=%d, want 32", k, len(k))
+		}
+		if _, dup := seen[k]; dup {
+			t.Fatalf("duplicate key %q", k)
+		}
+		seen[k] = struct{}{}
+	}
+}
+
+func TestKeyFor_Deterministic(t *testing.T) {
+	a := idempotency.KeyFor("order-1", "charge")
+	if b := idempotency.KeyFor("order-1", "charge"); a != b {
+		t.Fatalf("KeyFor not stable: %q vs %q", a, b)
+	}
+	if c := idempotency.KeyFor("order-1", "refund"); a == c {
+		t.Fatalf("different parts produced the same key %q", a)
+	}
+	if idempotency.KeyFor("ab", "c") == idempotency.KeyFor("a", "bc") {
+		t.Fatalf("part boundaries must affect the key")
+	}
+}
+
+func TestEnsure_ReusesExistingKey(t *testing.T) {
+	ctx := idempotency.WithKey(context.Background(), "fixed")
+	ctx2, key := idempotency.Ensure(ctx)
+	if key != "fixed" || ctx2 != ctx {
+		t.Fatalf("key=%q, want fixed and unchanged ctx", key)
+	}
+}
+
+func TestEnsure_GeneratesKey(t *testing.T) {
+	ctx, key := idempotency.Ensure(context.Background())
+	if key == "" {
+		t.Fatalf("expected generated key")
+	}
+	got, ok := idempotency.FromContext(ctx)
+	if !ok || got != key {
+		t.Fatalf("FromContext=%q,%v, want %q", got, ok, key)
+	}
+}
+
+func TestWithKey_EmptyClears(t *testing.T) {
+	ctx := idempotency.WithKey(context.Background(), "k")
+	ctx = idempotency.WithKey(ctx, "  ")
+	if _, ok := idempotency.FromContext(ctx); ok {
+		t.Fatalf("expected empty key to clear")
+	}
+}
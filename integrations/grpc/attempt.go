@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/aponysus/recourse/idempotency"
+	"github.com/aponysus/recourse/observe"
+)
+
+// AttemptMetadataUnaryClientInterceptor returns a gRPC interceptor that appends
+// the attempt metadata carried by the call context to outgoing metadata.
+//
+// Chain it after UnaryClientInterceptor so it runs once per attempt:
+//
+//	grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(exec, nil), AttemptMetadataUnaryClientInterceptor())
+func AttemptMetadataUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(OutgoingAttemptContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// OutgoingAttemptContext returns ctx with the attempt metadata it carries
+// appended to its outgoing gRPC metadata. It returns ctx unchanged outside an attempt.
+func OutgoingAttemptContext(ctx context.Context) context.Context {
+	info, ok := observe.AttemptFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	kv := make([]string, 0, 6)
+	observe.EncodeAttemptInfo(info, func(key, value string) {
+		kv = append(kv, key, value)
+	})
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// AttemptInfoFromIncomingContext extracts attempt metadata sent by an upstream
+// recourse client from a server handler context.
+func AttemptInfoFromIncomingContext(ctx context.Context) (observe.AttemptInfo, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return observe.AttemptInfo{}, false
+	}
+	return observe.DecodeAttemptInfo(func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[len(v)-1]
+		}
+		return ""
+	})
+}
+
+// IdempotencyKeyFromIncomingContext extracts the idempotency key sent by an
+// upstream recourse client from a server handler context.
+func IdempotencyKeyFromIncomingContext(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	v := md.Get(idempotency.MetadataKey)
+	if len(v) == 0 || v[len(v)-1] == "" {
+		return "", false
+	}
+	return v[len(v)-1], true
+}
@@ -0,0 +1,89 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/aponysus/recourse/idempotency"
+	integration "github.com/aponysus/recourse/integrations/grpc"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/retry"
+)
+
+func TestAttemptMetadataUnaryClientInterceptor(t *testing.T) {
+	exec := retry.NewDefaultExecutor(integration.WithClassifier())
+	retrying := integration.UnaryClientInterceptor(exec, nil)
+	attemptMD := integration.AttemptMetadataUnaryClientInterceptor()
+
+	var seen []observe.AttemptInfo
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		// Simulate the server receiving the outgoing metadata.
+		info, ok := integration.AttemptInfoFromIncomingContext(metadata.NewIncomingContext(ctx, md))
+		if !ok {
+			t.Fatalf("missing attempt metadata")
+		}
+		seen = append(seen, info)
+		if len(seen) < 2 {
+			return status.Error(codes.Unavailable, "transient")
+		}
+		return nil
+	}
+	chained := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return attemptMD(ctx, method, req, reply, cc, invoker, opts...)
+	}
+
+	if err := retrying(context.Background(), "/Service/Method", nil, nil, nil, chained); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 2 || seen[0].Attempt != 0 || seen[1].Attempt != 1 {
+		t.Fatalf("seen=%+v, want attempts 0 and 1", seen)
+	}
+}
+
+func TestAttemptInfoFromIncomingContext_Missing(t *testing.T) {
+	if _, ok := integration.AttemptInfoFromIncomingContext(context.Background()); ok {
+		t.Fatalf("expected no attempt info without metadata")
+	}
+	if ctx := integration.OutgoingAttemptContext(context.Background()); ctx != context.Background() {
+		t.Fatalf("expected context unchanged outside an attempt")
+	}
+}
+
+func TestUnaryClientInterceptor_StampsIdempotencyKey(t *testing.T) {
+	exec := retry.NewDefaultExecutor(integration.WithClassifier())
+	interceptor := integration.UnaryClientInterceptor(exec, nil)
+
+	var keys []string
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		key, ok := integration.IdempotencyKeyFromIncomingContext(metadata.NewIncomingContext(ctx, md))
+		if !ok {
+			t.Fatalf("missing idempotency key")
+		}
+		keys = append(keys, key)
+		if len(keys) < 2 {
+			return status.Error(codes.Unavailable, "transient")
+		}
+		return nil
+	}
+
+	ctx := idempotency.WithKey(context.Background(), "op-123")
+	if err := interceptor(ctx, "/Service/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "op-123" || keys[1] != "op-123" {
+		t.Fatalf("keys=%q, want op-123 on both attempts", keys)
+	}
+}
+
+func TestIdempotencyKeyFromIncomingContext_Missing(t *testing.T) {
+	if _, ok := integration.IdempotencyKeyFromIncomingContext(context.Background()); ok {
+		t.Fatalf("expected no key without metadata")
+	}
+}
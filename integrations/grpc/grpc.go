@@ -6,9 +6,11 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/idempotency"
 	"github.com/aponysus/recourse/policy"
 	"github.com/aponysus/recourse/retry"
 )
@@ -26,14 +28,54 @@ func DefaultKeyFunc(method string) policy.PolicyKey {
 }
 
 // UnaryClientInterceptor returns a gRPC interceptor that retries calls using the executor.
+//
+// If the call context carries an idempotency key (see idempotency.Ensure), every
+// attempt sends it as "idempotency-key" metadata. Attempts that fail with a
+// PushbackTrailer trailer return a PushbackError, which Classifier honors.
 func UnaryClientInterceptor(exec *retry.Executor, keyFunc func(method string) policy.PolicyKey) grpc.UnaryClientInterceptor {
 	if keyFunc == nil {
 		keyFunc = DefaultKeyFunc
 	}
-	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return UnaryClientInterceptorWithContext(exec, func(_ context.Context, method string) policy.PolicyKey {
+		return keyFunc(method)
+	})
+}
+
+// TenantKeyFunc returns a key function for UnaryClientInterceptorWithContext
+// that sets the tenant carried by the call context (see policy.WithTenant) as
+// the key's variant, so "Service.Method@acme" policies apply to acme's calls.
+// Calls without a tenant use keyFunc's key unchanged. A nil keyFunc uses
+// DefaultKeyFunc.
+func TenantKeyFunc(keyFunc func(method string) policy.PolicyKey) func(ctx context.Context, method string) policy.PolicyKey {
+	if keyFunc == nil {
+		keyFunc = DefaultKeyFunc
+	}
+	return func(ctx context.Context, method string) policy.PolicyKey {
 		key := keyFunc(method)
+		if tenant, ok := policy.TenantFromContext(ctx); ok {
+			key = key.WithVariant(tenant)
+		}
+		return key
+	}
+}
+
+// UnaryClientInterceptorWithContext is UnaryClientInterceptor with a key
+// function that also receives the call context, such as TenantKeyFunc. A nil
+// keyFunc uses TenantKeyFunc(nil).
+func UnaryClientInterceptorWithContext(exec *retry.Executor, keyFunc func(ctx context.Context, method string) policy.PolicyKey) grpc.UnaryClientInterceptor {
+	if keyFunc == nil {
+		keyFunc = TenantKeyFunc(nil)
+	}
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		key := keyFunc(ctx, method)
+		idemKey, hasIdemKey := idempotency.FromContext(ctx)
 		op := func(ctx context.Context) error {
-			return invoker(ctx, method, req, reply, cc, opts...)
+			if hasIdemKey {
+				ctx = metadata.AppendToOutgoingContext(ctx, idempotency.MetadataKey, idemKey)
+			}
+			var trailer metadata.MD
+			err := invoker(ctx, method, req, reply, cc, append(opts[:len(opts):len(opts)], grpc.Trailer(&trailer))...)
+			return withPushback(err, trailer)
 		}
 		// retry.Do handles the retry loop.
 		return exec.Do(ctx, key, op)
@@ -41,9 +83,60 @@ func UnaryClientInterceptor(exec *retry.Executor, keyFunc func(method string) po
 }
 
 // Classifier implements classify.Classifier for gRPC status codes.
-type Classifier struct{}
+//
+// Errors carrying a PushbackTrailer value follow gRPC's retry throttling
+// contract: a retryable failure waits the pushback delay instead of the
+// policy's backoff (capped at MaxBackoff, like Retry-After), and a negative or malformed value aborts the call with
+// reason "grpc_retry_pushback".
+type Classifier struct {
+	// Retryable, when non-nil, replaces the default retryable codes
+	// (Unavailable, ResourceExhausted and DeadlineExceeded).
+	Retryable []codes.Code
+
+	auto classify.AutoClassifier
+}
+
+// WithRetryableCodes returns c retrying exactly the codes named in
+// rc.GRPCCodes, with rc.HTTPStatuses applied to non-gRPC HTTP errors. Nil
+// lists keep the defaults.
+func (c Classifier) WithRetryableCodes(rc classify.RetryableCodes) classify.Classifier {
+	if rc.GRPCCodes != nil {
+		c.Retryable = make([]codes.Code, 0, len(rc.GRPCCodes))
+		for _, name := range rc.GRPCCodes {
+			if code, ok := codeByName(name); ok {
+				c.Retryable = append(c.Retryable, code)
+			}
+		}
+	}
+	c.auto = c.auto.WithRetryableCodes(rc).(classify.AutoClassifier)
+	return c
+}
+
+// codeByName resolves a canonical code name such as "UNAVAILABLE".
+func codeByName(name string) (codes.Code, bool) {
+	if name == "CANCELED" {
+		return codes.Canceled, true
+	}
+	var code codes.Code
+	if err := code.UnmarshalJSON([]byte(`"` + name + `"`)); err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+func (c Classifier) retryable(code codes.Code) bool {
+	if c.Retryable == nil {
+		return code == codes.Unavailable || code == codes.ResourceExhausted || code == codes.DeadlineExceeded
+	}
+	for _, r := range c.Retryable {
+		if r == code {
+			return true
+		}
+	}
+	return false
+}
 
-func (Classifier) Classify(val any, err error) classify.Outcome {
+func (c Classifier) Classify(val any, err error) classify.Outcome {
 	if err == nil {
 		return classify.Outcome{Kind: classify.OutcomeSuccess, Reason: "success"}
 	}
@@ -54,7 +147,7 @@ func (Classifier) Classify(val any, err error) classify.Outcome {
 	// If status.FromError(err) returns !ok, it means the error is not a gRPC Status error.
 	// In this case, we delegate to the AutoClassifier to handle standard errors.
 	if _, ok := status.FromError(err); !ok {
-		return classify.AutoClassifier{}.Classify(val, err)
+		return c.auto.Classify(val, err)
 	}
 
 	// At this point, we know it's a gRPC error (or wraps one).
@@ -68,22 +161,67 @@ func (Classifier) Classify(val any, err error) classify.Outcome {
 	}
 
 	switch code {
-	case codes.Unavailable, codes.ResourceExhausted:
-		outcome.Kind = classify.OutcomeRetryable
 	case codes.DeadlineExceeded:
-		outcome.Kind = classify.OutcomeRetryable
 		outcome.Reason = "context_deadline_exceeded"
 	case codes.Canceled:
 		outcome.Kind = classify.OutcomeAbort
 		outcome.Reason = "context_canceled"
+		return outcome
 	case codes.Unknown:
 		// Unknown often typically maps to internal application errors, so we treat it as non-retryable
 		// unless a specific policy overrides it.
 	}
+	if c.retryable(code) {
+		outcome.Kind = classify.OutcomeRetryable
+	}
+	if pe, ok := pushbackOf(err); ok {
+		applyPushback(&outcome, pe)
+	}
 
 	return outcome
 }
 
+// applyPushback applies the server's retry pushback to a failed outcome.
+func applyPushback(out *classify.Outcome, pe *PushbackError) {
+	out.Attributes["grpc_retry_pushback_ms"] = pe.Value
+	d, ok := pe.Delay()
+	if !ok {
+		out.Kind = classify.OutcomeAbort
+		out.Reason = "grpc_retry_pushback"
+		return
+	}
+	if out.Kind == classify.OutcomeRetryable {
+		out.BackoffOverride = d
+	}
+}
+
+// DetectorName is the name under which this package registers its gRPC
+// status detector with classify.RegisterDetector. Importing the package makes
+// classify.AutoClassifier, the executor's default classifier, classify gRPC
+// status errors like Classifier does.
+const DetectorName = "grpc"
+
+func init() {
+	classify.RegisterDetector(DetectorName, detector{})
+}
+
+// detector classifies gRPC status errors for classify.AutoClassifier.
+type detector struct {
+	c Classifier
+}
+
+func (d detector) Detect(val any, err error) (classify.Outcome, bool) {
+	if _, ok := status.FromError(err); !ok {
+		return classify.Outcome{}, false
+	}
+	return d.c.Classify(val, err), true
+}
+
+func (d detector) WithRetryableCodes(rc classify.RetryableCodes) classify.Detector {
+	d.c = d.c.WithRetryableCodes(rc).(Classifier)
+	return d
+}
+
 // WithClassifier returns an option to register the gRPC classifier as the default.
 // This ensures that policies without a specific classifier name will use this logic,
 // which handles gRPC codes and delegates non-gRPC errors to AutoClassifier.
@@ -123,6 +123,59 @@ func TestUnaryClientInterceptor_NonRetryable(t *testing.T) {
 	}
 }
 
+func TestUnaryClientInterceptor_PolicyRetryOnCode(t *testing.T) {
+	exec := retry.NewExecutor(
+		retry.WithPolicy("Service.Method", policy.RetryOn(policy.ErrorMatcher{GRPCCodes: []string{"FAILED_PRECONDITION"}})),
+		retry.WithDefaultClassifier(integration.Classifier{}),
+	)
+	interceptor := integration.UnaryClientInterceptor(exec, nil)
+
+	attempts := 0
+	mockInvoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		attempts++
+		if attempts < 2 {
+			return status.Error(codes.FailedPrecondition, "not ready")
+		}
+		return nil
+	}
+
+	if err := interceptor(context.Background(), "/Service/Method", nil, nil, nil, mockInvoker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestUnaryClientInterceptor_PolicyRetryableCodes(t *testing.T) {
+	exec := retry.NewExecutor(
+		retry.WithPolicy("Service.Method", policy.MaxAttempts(3), policy.RetryableGRPCCodes("aborted", "CANCELLED")),
+		retry.WithDefaultClassifier(integration.Classifier{}),
+	)
+	interceptor := integration.UnaryClientInterceptor(exec, nil)
+
+	for _, tc := range []struct {
+		code     codes.Code
+		attempts int
+	}{
+		{codes.Aborted, 3},
+		{codes.Unavailable, 1},
+		{codes.Canceled, 1},
+	} {
+		attempts := 0
+		mockInvoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			attempts++
+			return status.Error(tc.code, "failed")
+		}
+		if err := interceptor(context.Background(), "/Service/Method", nil, nil, nil, mockInvoker); status.Code(err) != tc.code {
+			t.Fatalf("%v: err=%v", tc.code, err)
+		}
+		if attempts != tc.attempts {
+			t.Errorf("%v: expected %d attempts, got %d", tc.code, tc.attempts, attempts)
+		}
+	}
+}
+
 func TestUnaryClientInterceptor_ContextCanceled(t *testing.T) {
 	exec := retry.NewDefaultExecutor(integration.WithClassifier())
 	interceptor := integration.UnaryClientInterceptor(exec, nil)
@@ -144,3 +197,59 @@ func TestUnaryClientInterceptor_ContextCanceled(t *testing.T) {
 		t.Errorf("expected 0 attempts, got %d", attempts)
 	}
 }
+
+func TestUnaryClientInterceptorWithContext_TenantVariant(t *testing.T) {
+	exec := retry.NewExecutor(
+		retry.WithPolicy("Service.Method", policy.MaxAttempts(3)),
+		retry.WithPolicy("Service.Method@acme", policy.MaxAttempts(1)),
+		retry.WithDefaultClassifier(integration.Classifier{}),
+	)
+	interceptor := integration.UnaryClientInterceptorWithContext(exec, integration.TenantKeyFunc(nil))
+
+	for _, tc := range []struct {
+		ctx  context.Context
+		want int
+	}{
+		{context.Background(), 3},
+		{policy.WithTenant(context.Background(), "acme"), 1},
+		{policy.WithTenant(context.Background(), "globex"), 3},
+	} {
+		attempts := 0
+		mockInvoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			attempts++
+			return status.Error(codes.Unavailable, "down")
+		}
+		if err := interceptor(tc.ctx, "/Service/Method", nil, nil, nil, mockInvoker); err == nil {
+			t.Fatal("expected error")
+		}
+		if attempts != tc.want {
+			t.Fatalf("attempts=%d, want %d", attempts, tc.want)
+		}
+	}
+}
+
+func TestAutoClassifier_DetectsGRPCStatus(t *testing.T) {
+	found := false
+	for _, name := range classify.DetectorNames() {
+		found = found || name == integration.DetectorName
+	}
+	if !found {
+		t.Fatalf("detectors=%v, want %q registered", classify.DetectorNames(), integration.DetectorName)
+	}
+
+	auto := classify.AutoClassifier{}
+	if out := auto.Classify(nil, status.Error(codes.InvalidArgument, "bad")); out.Kind != classify.OutcomeNonRetryable || out.Reason != "grpc_InvalidArgument" {
+		t.Fatalf("InvalidArgument out=%+v, want non-retryable grpc_InvalidArgument", out)
+	}
+	if out := auto.Classify(nil, status.Error(codes.Unavailable, "down")); out.Kind != classify.OutcomeRetryable {
+		t.Fatalf("Unavailable out=%+v, want retryable", out)
+	}
+	if out := auto.Classify(nil, errors.New("plain")); out.Reason != "retryable_error" {
+		t.Fatalf("plain error out=%+v, want retryable_error", out)
+	}
+
+	narrowed := auto.WithRetryableCodes(classify.RetryableCodes{GRPCCodes: []string{"ABORTED"}})
+	if out := narrowed.Classify(nil, status.Error(codes.Aborted, "conflict")); out.Kind != classify.OutcomeRetryable {
+		t.Fatalf("Aborted with policy codes out=%+v, want retryable", out)
+	}
+}
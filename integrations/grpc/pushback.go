@@ -0,0 +1,62 @@
+package grpc
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// PushbackTrailer is the trailer servers use to throttle client retries, as
+// defined by gRPC's retry design (A6): a non-negative number of milliseconds
+// to wait before retrying, or a negative or malformed value to stop retrying.
+const PushbackTrailer = "grpc-retry-pushback-ms"
+
+// PushbackError is a failed call's error together with the server's
+// PushbackTrailer value. UnaryClientInterceptor returns attempt errors wrapped
+// in it when the trailer is present, so Classifier can honor it.
+type PushbackError struct {
+	Err   error
+	Value string // Raw trailer value.
+}
+
+func (e *PushbackError) Error() string { return e.Err.Error() }
+
+func (e *PushbackError) Unwrap() error { return e.Err }
+
+// GRPCStatus returns the wrapped error's status, so status.FromError and
+// status.Code see through the wrapper.
+func (e *PushbackError) GRPCStatus() *status.Status { return status.Convert(e.Err) }
+
+// Delay returns the pushback delay, or false when the server asked the client
+// not to retry.
+func (e *PushbackError) Delay() (time.Duration, bool) {
+	ms, err := strconv.ParseInt(strings.TrimSpace(e.Value), 10, 64)
+	if err != nil || ms < 0 {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// withPushback wraps err in a PushbackError when trailer carries a
+// PushbackTrailer value.
+func withPushback(err error, trailer metadata.MD) error {
+	if err == nil {
+		return nil
+	}
+	vals := trailer.Get(PushbackTrailer)
+	if len(vals) == 0 {
+		return err
+	}
+	return &PushbackError{Err: err, Value: vals[0]}
+}
+
+// pushbackOf returns the PushbackError in err's chain, if any.
+func pushbackOf(err error) (*PushbackError, bool) {
+	var pe *PushbackError
+	ok := errors.As(err, &pe)
+	return pe, ok
+}
@@ -0,0 +1,78 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/aponysus/recourse/classify"
+	integration "github.com/aponysus/recourse/integrations/grpc"
+	"github.com/aponysus/recourse/retry"
+)
+
+func TestClassifier_Pushback(t *testing.T) {
+	c := integration.Classifier{}
+	unavailable := status.Error(codes.Unavailable, "busy")
+
+	out := c.Classify(nil, &integration.PushbackError{Err: unavailable, Value: "250"})
+	if out.Kind != classify.OutcomeRetryable || out.BackoffOverride != 250*time.Millisecond {
+		t.Fatalf("pushback 250 outcome=%+v, want retryable with 250ms backoff", out)
+	}
+	if out.Attributes["grpc_retry_pushback_ms"] != "250" {
+		t.Fatalf("attributes=%v", out.Attributes)
+	}
+
+	for _, v := range []string{"-1", "soon"} {
+		out := c.Classify(nil, &integration.PushbackError{Err: unavailable, Value: v})
+		if out.Kind != classify.OutcomeAbort || out.Reason != "grpc_retry_pushback" {
+			t.Fatalf("pushback %q outcome=%+v, want abort", v, out)
+		}
+	}
+
+	out = c.Classify(nil, &integration.PushbackError{Err: status.Error(codes.InvalidArgument, "bad"), Value: "250"})
+	if out.Kind != classify.OutcomeNonRetryable || out.BackoffOverride != 0 {
+		t.Fatalf("non-retryable pushback outcome=%+v", out)
+	}
+}
+
+func TestUnaryClientInterceptor_Pushback(t *testing.T) {
+	var sleeps []time.Duration
+	exec := retry.NewDefaultExecutor(integration.WithClassifier(), retry.WithSleep(func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}))
+	interceptor := integration.UnaryClientInterceptor(exec, nil)
+
+	pushbacks := []string{"200", "-1"}
+	attempts := 0
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		for _, opt := range opts {
+			if t, ok := opt.(grpc.TrailerCallOption); ok {
+				*t.TrailerAddr = metadata.Pairs(integration.PushbackTrailer, pushbacks[attempts])
+			}
+		}
+		attempts++
+		return status.Error(codes.Unavailable, "overloaded")
+	}
+
+	err := interceptor(context.Background(), "/Service/Method", nil, nil, nil, invoker)
+	if attempts != 2 {
+		t.Fatalf("attempts=%d, want 2", attempts)
+	}
+	if len(sleeps) != 1 || sleeps[0] != 200*time.Millisecond {
+		t.Fatalf("sleeps=%v, want [200ms]", sleeps)
+	}
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("code=%v, want Unavailable", status.Code(err))
+	}
+	var pe *integration.PushbackError
+	if !errors.As(err, &pe) || pe.Value != "-1" {
+		t.Fatalf("err=%v, want PushbackError with value -1", err)
+	}
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aponysus/recourse/observe"
+)
+
+// SetAttemptHeaders writes the attempt metadata carried by ctx (see
+// observe.AttemptFromContext) into h. It does nothing outside an attempt.
+func SetAttemptHeaders(ctx context.Context, h http.Header) {
+	info, ok := observe.AttemptFromContext(ctx)
+	if !ok {
+		return
+	}
+	observe.EncodeAttemptInfo(info, h.Set)
+}
+
+// AttemptInfoFromRequest extracts attempt metadata sent by an upstream recourse
+// client, for dedup or hedging-aware admission control on the server.
+func AttemptInfoFromRequest(r *http.Request) (observe.AttemptInfo, bool) {
+	return observe.DecodeAttemptInfo(r.Header.Get)
+}
+
+// AttemptHeaderTransport is an http.RoundTripper that adds attempt metadata
+// headers to every request sent within a recourse attempt.
+type AttemptHeaderTransport struct {
+	// Base is the underlying transport. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+func (t AttemptHeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	info, ok := observe.AttemptFromContext(req.Context())
+	if !ok {
+		return base.RoundTrip(req)
+	}
+	// RoundTrippers must not modify the caller's request.
+	out := req.Clone(req.Context())
+	observe.EncodeAttemptInfo(info, out.Header.Set)
+	return base.RoundTrip(out)
+}
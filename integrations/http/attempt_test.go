@@ -0,0 +1,66 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	integration "github.com/aponysus/recourse/integrations/http"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+	"github.com/aponysus/recourse/retry"
+)
+
+func TestAttemptHeaderTransport_PropagatesAttempts(t *testing.T) {
+	var mu sync.Mutex
+	var seen []observe.AttemptInfo
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, ok := integration.AttemptInfoFromRequest(r)
+		if !ok {
+			t.Errorf("missing attempt headers")
+		}
+		mu.Lock()
+		seen = append(seen, info)
+		n := len(seen)
+		mu.Unlock()
+		if n < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: integration.AttemptHeaderTransport{Base: server.Client().Transport}}
+	exec := retry.NewDefaultExecutor()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, _, err := integration.DoHTTP(context.Background(), exec, policy.PolicyKey{Name: "attempts"}, client, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(seen) != 2 || seen[0].Attempt != 0 || seen[1].Attempt != 1 || seen[1].IsHedge {
+		t.Fatalf("seen=%+v, want attempts 0 and 1", seen)
+	}
+	if req.Header.Get(observe.MetadataAttempt) != "" {
+		t.Fatalf("transport mutated the caller's request headers")
+	}
+}
+
+func TestSetAttemptHeaders(t *testing.T) {
+	h := http.Header{}
+	integration.SetAttemptHeaders(context.Background(), h)
+	if len(h) != 0 {
+		t.Fatalf("headers=%v, want none outside an attempt", h)
+	}
+
+	ctx := observe.WithAttemptInfo(context.Background(), observe.AttemptInfo{Attempt: 2, IsHedge: true, HedgeIndex: 1, PolicyID: "p1"})
+	integration.SetAttemptHeaders(ctx, h)
+	req := &http.Request{Header: h}
+	info, ok := integration.AttemptInfoFromRequest(req)
+	if !ok || info.Attempt != 2 || !info.IsHedge || info.HedgeIndex != 1 || info.PolicyID != "p1" {
+		t.Fatalf("info=%+v ok=%v, want round-tripped attempt info", info, ok)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/aponysus/recourse/idempotency"
 	"github.com/aponysus/recourse/observe"
 	"github.com/aponysus/recourse/policy"
 	"github.com/aponysus/recourse/retry"
@@ -16,14 +17,27 @@ import (
 // DoHTTP executes an HTTP request with retries.
 // It automatically handles request cloning, body draining/closing on retryable errors,
 // and status code classification.
+//
+// If ctx carries an idempotency key (see idempotency.Ensure) and req has no
+// Idempotency-Key header, every attempt is sent with the key, and failures of
+// non-idempotent methods become retryable under HTTPClassifier.
 func DoHTTP(ctx context.Context, exec *retry.Executor, key policy.PolicyKey, client *http.Client, req *http.Request) (*http.Response, observe.Timeline, error) {
 	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
 		return nil, observe.Timeline{}, errors.New("recourse: request body is not replayable (GetBody is nil)")
 	}
 
+	idemKey := req.Header.Get(idempotency.HeaderName)
+	stampKey := false
+	if idemKey == "" {
+		idemKey, stampKey = idempotency.FromContext(ctx)
+	}
+
 	op := func(ctx context.Context) (*http.Response, error) {
 		// Clone request
 		outReq := req.Clone(ctx)
+		if stampKey {
+			outReq.Header.Set(idempotency.HeaderName, idemKey)
+		}
 		if req.GetBody != nil {
 			body, err := req.GetBody()
 			if err != nil {
@@ -36,8 +50,9 @@ func DoHTTP(ctx context.Context, exec *retry.Executor, key policy.PolicyKey, cli
 		if err != nil {
 			// Wrap transport errors so HTTP classification (idempotency) applies.
 			return nil, &StatusError{
-				Err:    err,
-				Method: req.Method,
+				Err:            err,
+				Method:         req.Method,
+				IdempotencyKey: idemKey,
 			}
 		}
 
@@ -52,9 +67,10 @@ func DoHTTP(ctx context.Context, exec *retry.Executor, key policy.PolicyKey, cli
 		resp.Body.Close()
 
 		return nil, &StatusError{
-			Code:   resp.StatusCode,
-			Method: req.Method,
-			Header: resp.Header,
+			Code:           resp.StatusCode,
+			Method:         req.Method,
+			Header:         resp.Header,
+			IdempotencyKey: idemKey,
 		}
 	}
 
@@ -77,6 +93,9 @@ type StatusError struct {
 	Method string
 	Header http.Header
 	Err    error
+
+	// IdempotencyKey is the Idempotency-Key the request was sent with, if any.
+	IdempotencyKey string
 }
 
 func (e *StatusError) Error() string {
@@ -91,6 +110,9 @@ func (e *StatusError) Unwrap() error { return e.Err }
 func (e *StatusError) HTTPStatusCode() int { return e.Code }
 func (e *StatusError) HTTPMethod() string  { return e.Method }
 
+// HTTPIdempotencyKey implements classify.HTTPIdempotencyKeyer.
+func (e *StatusError) HTTPIdempotencyKey() string { return e.IdempotencyKey }
+
 func (e *StatusError) RetryAfter() (time.Duration, bool) {
 	if e.Header == nil {
 		return 0, false
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aponysus/recourse/idempotency"
 	integration "github.com/aponysus/recourse/integrations/http"
 	"github.com/aponysus/recourse/policy"
 	"github.com/aponysus/recourse/retry"
@@ -161,6 +162,75 @@ func TestDoHTTP_ReplaysBodyWithGetBody(t *testing.T) {
 	}
 }
 
+func TestDoHTTP_StampsIdempotencyKeyAndRetriesPost(t *testing.T) {
+	var keys []string
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		keys = append(keys, req.Header.Get(idempotency.HeaderName))
+		status := http.StatusServiceUnavailable
+		if len(keys) > 1 {
+			status = http.StatusOK
+		}
+		return &http.Response{
+			StatusCode: status,
+			Body:       io.NopCloser(strings.NewReader("")),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})
+
+	client := &http.Client{Transport: rt}
+	exec := retry.NewDefaultExecutor(
+		retry.WithPolicy("test",
+			policy.MaxAttempts(2),
+			policy.InitialBackoff(0),
+			policy.MaxBackoff(0),
+			policy.Jitter(policy.JitterNone),
+		),
+	)
+
+	ctx, key := idempotency.Ensure(context.Background())
+	req, _ := http.NewRequest("POST", "http://example.test", nil)
+	resp, _, err := integration.DoHTTP(ctx, exec, policy.PolicyKey{Name: "test"}, client, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(keys) != 2 || keys[0] != key || keys[1] != key {
+		t.Fatalf("keys=%q, want two attempts with %q", keys, key)
+	}
+	if req.Header.Get(idempotency.HeaderName) != "" {
+		t.Fatalf("caller request must not be modified")
+	}
+}
+
+func TestDoHTTP_PostWithoutIdempotencyKeyNotRetried(t *testing.T) {
+	calls := 0
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       io.NopCloser(strings.NewReader("")),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})
+
+	client := &http.Client{Transport: rt}
+	exec := retry.NewDefaultExecutor(
+		retry.WithPolicy("test", policy.MaxAttempts(3), policy.InitialBackoff(0), policy.MaxBackoff(0)),
+	)
+
+	req, _ := http.NewRequest("POST", "http://example.test", nil)
+	_, _, err := integration.DoHTTP(context.Background(), exec, policy.PolicyKey{Name: "test"}, client, req)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("calls=%d, want 1", calls)
+	}
+}
+
 func TestDoHTTP_ContextCanceled(t *testing.T) {
 	called := false
 	rt := roundTripperFunc(func(*http.Request) (*http.Response, error) {
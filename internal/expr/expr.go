@@ -0,0 +1,737 @@
+// Package expr implements the small CEL-like boolean expression language used
+// by policy retry rules.
+//
+// Expressions combine int, string and bool literals, list literals and
+// declared variables with ==, !=, <, <=, >, >=, in, !, && and ||. Map
+// variables are indexed with m["key"], a missing key yielding "". Strings
+// support the methods startsWith, endsWith and contains. String literals may
+// be double- or single-quoted and use Go escapes; \' and \" are accepted in
+// either.
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Type is the static type of an expression value.
+type Type string
+
+// Types of declared variables. Any defers type errors to evaluation.
+const (
+	Any    Type = "any"
+	Int    Type = "int"
+	String Type = "string"
+	Bool   Type = "bool"
+	List   Type = "list"
+	Map    Type = "map"
+)
+
+// Program is a compiled expression. It is safe for concurrent use.
+type Program struct {
+	src  string
+	root node
+}
+
+// Compile parses and type-checks src. Identifiers that are not keys of
+// declared are rejected, as are operands whose types their operators do not
+// accept and expressions that cannot produce a bool.
+func Compile(src string, declared map[string]Type) (*Program, error) {
+	p := &parser{src: src, declared: declared}
+	if err := p.lex(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	if err := expectType(root, declared, Bool, "expression"); err != nil {
+		return nil, fmt.Errorf("expr: %v in %q", err, src)
+	}
+	return &Program{src: src, root: root}, nil
+}
+
+// String returns the source expression.
+func (p *Program) String() string {
+	return p.src
+}
+
+// Eval evaluates the program against vars, whose values must be int, int64,
+// string, bool or map[string]string. The expression must produce a bool.
+func (p *Program) Eval(vars map[string]any) (bool, error) {
+	v, err := p.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expr: %q produced %s, want bool", p.src, typeName(v))
+	}
+	return b, nil
+}
+
+// Lexer
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokInt
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	val  any
+}
+
+type parser struct {
+	src      string
+	declared map[string]Type
+	toks     []token
+	i        int
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("expr: %s at offset %d in %q", fmt.Sprintf(format, args...), t.pos, p.src)
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+func (p *parser) lex() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			n, err := strconv.ParseInt(s[i:j], 10, 64)
+			if err != nil {
+				return p.errorf(token{pos: i}, "invalid number %q", s[i:j])
+			}
+			p.toks = append(p.toks, token{kind: tokInt, text: s[i:j], pos: i, val: n})
+			i = j
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(s) && s[j] != c {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return p.errorf(token{pos: i}, "unterminated string")
+			}
+			str, err := unquote(s[i+1:j], c)
+			if err != nil {
+				return p.errorf(token{pos: i}, "invalid string %s", s[i:j+1])
+			}
+			p.toks = append(p.toks, token{kind: tokString, text: s[i : j+1], pos: i, val: str})
+			i = j + 1
+		case c == '_' || (c|0x20 >= 'a' && c|0x20 <= 'z'):
+			j := i
+			for j < len(s) && (s[j] == '_' || (s[j]|0x20 >= 'a' && s[j]|0x20 <= 'z') || (s[j] >= '0' && s[j] <= '9')) {
+				j++
+			}
+			p.toks = append(p.toks, token{kind: tokIdent, text: s[i:j], pos: i})
+			i = j
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(s[i:], op) {
+					p.toks = append(p.toks, token{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return p.errorf(token{pos: i}, "unexpected %q", string(c))
+			}
+		}
+	}
+	p.toks = append(p.toks, token{kind: tokEOF, text: "end of expression", pos: len(s)})
+	return nil
+}
+
+// unquote decodes the body of a string literal delimited by quote.
+func unquote(body string, quote byte) (string, error) {
+	var b strings.Builder
+	for body != "" {
+		if len(body) >= 2 && body[0] == '\\' && (body[1] == '\'' || body[1] == '"') {
+			b.WriteByte(body[1])
+			body = body[2:]
+			continue
+		}
+		r, multibyte, tail, err := strconv.UnquoteChar(body, quote)
+		if err != nil {
+			return "", err
+		}
+		if r < utf8.RuneSelf || !multibyte {
+			b.WriteByte(byte(r))
+		} else {
+			b.WriteRune(r)
+		}
+		body = tail
+	}
+	return b.String(), nil
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokOp && t.text == op {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		t := p.peek()
+		return p.errorf(t, "expected %q, found %q", op, t.text)
+	}
+	return nil
+}
+
+// Parser
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && (t.text == "==" || t.text == "!=" || t.text == "<" || t.text == "<=" || t.text == ">" || t.text == ">="):
+	case t.kind == tokIdent && t.text == "in":
+	default:
+		return left, nil
+	}
+	p.next()
+	right, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	return compareNode{op: t.text, left: left, right: right}, nil
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("["):
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			n = indexNode{target: n, index: index}
+		case p.accept("."):
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, p.errorf(t, "expected method name, found %q", t.text)
+			}
+			switch t.text {
+			case "startsWith", "endsWith", "contains":
+			default:
+				return nil, p.errorf(t, "unknown method %q", t.text)
+			}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			n = methodNode{name: t.text, target: n, arg: arg}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokInt, tokString:
+		return literal{t.val}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		}
+		if _, ok := p.declared[t.text]; ok {
+			return ident(t.text), nil
+		}
+		return nil, p.errorf(t, "undeclared identifier %q", t.text)
+	case tokOp:
+		switch t.text {
+		case "(":
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		case "[":
+			var items listNode
+			if p.accept("]") {
+				return items, nil
+			}
+			for {
+				item, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+				if p.accept("]") {
+					return items, nil
+				}
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return nil, p.errorf(t, "unexpected %q", t.text)
+}
+
+// Evaluation
+
+type node interface {
+	eval(vars map[string]any) (any, error)
+	check(declared map[string]Type) (Type, error)
+}
+
+type literal struct{ v any }
+
+func (n literal) eval(map[string]any) (any, error) { return n.v, nil }
+
+func (n literal) check(map[string]Type) (Type, error) { return Type(typeName(n.v)), nil }
+
+type ident string
+
+func (n ident) eval(vars map[string]any) (any, error) {
+	v, ok := vars[string(n)]
+	if !ok {
+		return nil, fmt.Errorf("expr: no value for %q", string(n))
+	}
+	switch v := v.(type) {
+	case int:
+		return int64(v), nil
+	case int64, string, bool, map[string]string:
+		return v, nil
+	}
+	return nil, fmt.Errorf("expr: %q has unsupported type %T", string(n), v)
+}
+
+func (n ident) check(declared map[string]Type) (Type, error) {
+	if t := declared[string(n)]; t != "" {
+		return t, nil
+	}
+	return Any, nil
+}
+
+type listNode []node
+
+func (n listNode) eval(vars map[string]any) (any, error) {
+	out := make([]any, len(n))
+	for i, item := range n {
+		v, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (n listNode) check(declared map[string]Type) (Type, error) {
+	if _, err := n.elemType(declared); err != nil {
+		return Any, err
+	}
+	return List, nil
+}
+
+// elemType returns the type shared by the list's items, Any if unknown.
+func (n listNode) elemType(declared map[string]Type) (Type, error) {
+	elem := Any
+	for _, item := range n {
+		t, err := item.check(declared)
+		if err != nil {
+			return Any, err
+		}
+		switch {
+		case t == Any:
+		case elem == Any:
+			elem = t
+		case t != elem:
+			return Any, fmt.Errorf("list mixes %s and %s", elem, t)
+		}
+	}
+	return elem, nil
+}
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(vars map[string]any) (any, error) {
+	b, err := evalBool(n.operand, vars)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+func (n notNode) check(declared map[string]Type) (Type, error) {
+	return Bool, expectType(n.operand, declared, Bool, "!")
+}
+
+type logicalNode struct {
+	or          bool
+	left, right node
+}
+
+func (n logicalNode) eval(vars map[string]any) (any, error) {
+	l, err := evalBool(n.left, vars)
+	if err != nil {
+		return nil, err
+	}
+	if l == n.or {
+		return l, nil
+	}
+	return evalBool(n.right, vars)
+}
+
+func (n logicalNode) check(declared map[string]Type) (Type, error) {
+	op := "&&"
+	if n.or {
+		op = "||"
+	}
+	if err := expectType(n.left, declared, Bool, op); err != nil {
+		return Any, err
+	}
+	return Bool, expectType(n.right, declared, Bool, op)
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n compareNode) eval(vars map[string]any) (any, error) {
+	l, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "in" {
+		list, ok := r.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expr: in requires a list, got %s", typeName(r))
+		}
+		for _, item := range list {
+			if eq, err := equal(l, item); err != nil {
+				return nil, err
+			} else if eq {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	switch n.op {
+	case "==":
+		return equal(l, r)
+	case "!=":
+		eq, err := equal(l, r)
+		return !eq, err
+	}
+
+	var c int
+	switch l := l.(type) {
+	case int64:
+		rv, ok := r.(int64)
+		if !ok {
+			return nil, mismatch(n.op, l, r)
+		}
+		c = cmpInt(l, rv)
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return nil, mismatch(n.op, l, r)
+		}
+		c = strings.Compare(l, rv)
+	default:
+		return nil, mismatch(n.op, l, r)
+	}
+	switch n.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+func (n compareNode) check(declared map[string]Type) (Type, error) {
+	l, err := n.left.check(declared)
+	if err != nil {
+		return Any, err
+	}
+	r, err := n.right.check(declared)
+	if err != nil {
+		return Any, err
+	}
+
+	switch n.op {
+	case "in":
+		if r != Any && r != List {
+			return Any, fmt.Errorf("in requires a list, got %s", r)
+		}
+		elem := Any
+		if list, ok := n.right.(listNode); ok {
+			elem, _ = list.elemType(declared)
+		}
+		return Bool, checkOperands(n.op, l, elem, Int, String, Bool)
+	case "==", "!=":
+		return Bool, checkOperands(n.op, l, r, Int, String, Bool)
+	}
+	return Bool, checkOperands(n.op, l, r, Int, String)
+}
+
+// checkOperands reports whether op accepts operands of types l and r, which
+// must be equal and among allowed once known.
+func checkOperands(op string, l, r Type, allowed ...Type) error {
+	for _, t := range []Type{l, r} {
+		if t == Any {
+			continue
+		}
+		ok := false
+		for _, a := range allowed {
+			ok = ok || t == a
+		}
+		if !ok {
+			return fmt.Errorf("cannot apply %s to %s and %s", op, l, r)
+		}
+	}
+	if l != Any && r != Any && l != r {
+		return fmt.Errorf("cannot apply %s to %s and %s", op, l, r)
+	}
+	return nil
+}
+
+type indexNode struct {
+	target, index node
+}
+
+func (n indexNode) eval(vars map[string]any) (any, error) {
+	t, err := n.target.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := t.(map[string]string)
+	if !ok {
+		return nil, fmt.Errorf("expr: cannot index %s", typeName(t))
+	}
+	k, err := n.index.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := k.(string)
+	if !ok {
+		return nil, fmt.Errorf("expr: map key must be string, got %s", typeName(k))
+	}
+	return m[key], nil
+}
+
+func (n indexNode) check(declared map[string]Type) (Type, error) {
+	if err := expectType(n.target, declared, Map, "indexing"); err != nil {
+		return Any, err
+	}
+	return String, expectType(n.index, declared, String, "map key")
+}
+
+type methodNode struct {
+	name        string
+	target, arg node
+}
+
+func (n methodNode) eval(vars map[string]any) (any, error) {
+	t, err := n.target.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	a, err := n.arg.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	s, ok1 := t.(string)
+	arg, ok2 := a.(string)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("expr: %s requires strings, got %s and %s", n.name, typeName(t), typeName(a))
+	}
+	switch n.name {
+	case "startsWith":
+		return strings.HasPrefix(s, arg), nil
+	case "endsWith":
+		return strings.HasSuffix(s, arg), nil
+	default:
+		return strings.Contains(s, arg), nil
+	}
+}
+
+func (n methodNode) check(declared map[string]Type) (Type, error) {
+	if err := expectType(n.target, declared, String, n.name); err != nil {
+		return Any, err
+	}
+	return Bool, expectType(n.arg, declared, String, n.name)
+}
+
+// expectType checks n and reports an error unless it may produce want.
+func expectType(n node, declared map[string]Type, want Type, what string) error {
+	t, err := n.check(declared)
+	if err != nil {
+		return err
+	}
+	if t != Any && t != want {
+		return fmt.Errorf("%s requires %s, got %s", what, want, t)
+	}
+	return nil
+}
+
+func evalBool(n node, vars map[string]any) (bool, error) {
+	v, err := n.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expr: expected bool, got %s", typeName(v))
+	}
+	return b, nil
+}
+
+func equal(l, r any) (bool, error) {
+	switch l.(type) {
+	case int64, string, bool:
+	default:
+		return false, mismatch("==", l, r)
+	}
+	if typeName(l) != typeName(r) {
+		return false, mismatch("==", l, r)
+	}
+	return l == r, nil
+}
+
+func cmpInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func mismatch(op string, l, r any) error {
+	return fmt.Errorf("expr: cannot apply %s to %s and %s", op, typeName(l), typeName(r))
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case int64:
+		return "int"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case []any:
+		return "list"
+	case map[string]string:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,96 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]any{
+		"status":     503,
+		"grpc_code":  "UNAVAILABLE",
+		"reason":     "http_503",
+		"attributes": map[string]string{"method": "GET"},
+	}
+	declared := map[string]Type{"status": Int, "grpc_code": String, "reason": String, "attributes": Map}
+
+	for _, tc := range []struct {
+		src  string
+		want bool
+	}{
+		{`status == 503`, true},
+		{`status >= 500 && status < 600`, true},
+		{`status != 503 || reason == "http_503"`, true},
+		{`!(status == 503)`, false},
+		{`grpc_code in ["UNAVAILABLE", 'RESOURCE_EXHAUSTED']`, true},
+		{`status in [429, 502]`, false},
+		{`reason.startsWith("http_") && reason.endsWith("3")`, true},
+		{`reason.contains("429")`, false},
+		{`attributes["method"] == "GET"`, true},
+		{`attributes["missing"] == ""`, true},
+		{`true && !false`, true},
+		{`reason != 'it\'s'`, true},
+		{`'it\'s' == "it's"`, true},
+		{`'a\"b' == "a\"b"`, true},
+		{`"tab\t" == 'tab\x09'`, true},
+	} {
+		p, err := Compile(tc.src, declared)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", tc.src, err)
+		}
+		got, err := p.Eval(vars)
+		if err != nil {
+			t.Fatalf("Eval(%q): %v", tc.src, err)
+		}
+		if got != tc.want {
+			t.Errorf("Eval(%q)=%v, want %v", tc.src, got, tc.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src, want string
+	}{
+		{`status ==`, "unexpected"},
+		{`code == 1`, `undeclared identifier "code"`},
+		{`"open`, "unterminated string"},
+		{`status == 1)`, `unexpected ")"`},
+		{`reason.lower()`, `unknown method "lower"`},
+		{`status # 1`, `unexpected "#"`},
+		{`'bad\q'`, "invalid string"},
+		{`status < "a"`, "cannot apply < to int and string"},
+		{`status == reason`, "cannot apply == to int and string"},
+		{`status in [1, "a"]`, "list mixes int and string"},
+		{`reason in [1, 2]`, "cannot apply in to string and int"},
+		{`status in 503`, "in requires a list, got int"},
+		{`reason.startsWith(1)`, "startsWith requires string, got int"},
+		{`status["k"] == ""`, "indexing requires map, got int"},
+		{`!status`, "! requires bool, got int"},
+		{`status`, "expression requires bool, got int"},
+	} {
+		_, err := Compile(tc.src, map[string]Type{"status": Int, "reason": String})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Compile(%q) err=%v, want %q", tc.src, err, tc.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	vars := map[string]any{"status": 503, "reason": "x"}
+	for _, src := range []string{
+		`status == "503"`,
+		`status`,
+		`reason < 1`,
+		`status in reason`,
+		`!status`,
+	} {
+		p, err := Compile(src, map[string]Type{"status": Any, "reason": Any})
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", src, err)
+		}
+		if _, err := p.Eval(vars); err == nil {
+			t.Errorf("Eval(%q) succeeded, want error", src)
+		}
+	}
+}
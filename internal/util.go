@@ -15,3 +15,16 @@ func IsTypedNil(x any) bool {
 		return false
 	}
 }
+
+// CopyMap returns a copy of *m with room for extra additional entries.
+// A nil m yields an empty map. It backs the copy-on-write registries.
+func CopyMap[K comparable, V any](m *map[K]V, extra int) map[K]V {
+	if m == nil {
+		return make(map[K]V, extra)
+	}
+	out := make(map[K]V, len(*m)+extra)
+	for k, v := range *m {
+		out[k] = v
+	}
+	return out
+}
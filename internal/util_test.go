@@ -35,3 +35,16 @@ func TestIsTypedNil(t *testing.T) {
 		})
 	}
 }
+
+func TestCopyMap(t *testing.T) {
+	if got := CopyMap[string, int](nil, 2); got == nil || len(got) != 0 {
+		t.Fatalf("CopyMap(nil)=%v, want empty map", got)
+	}
+
+	src := map[string]int{"a": 1}
+	dst := CopyMap(&src, 1)
+	dst["b"] = 2
+	if len(src) != 1 || len(dst) != 2 || dst["a"] != 1 {
+		t.Fatalf("src=%v dst=%v, want independent copy", src, dst)
+	}
+}
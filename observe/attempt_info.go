@@ -1,6 +1,13 @@
 package observe
 
-import "context"
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+)
 
 type attemptInfoKey struct{}
 
@@ -11,6 +18,13 @@ type AttemptInfo struct {
 	IsHedge    bool
 	HedgeIndex int
 	PolicyID   string
+
+	// PrevErr and PrevOutcome describe how the previous retry attempt failed.
+	// They are nil for the first attempt and are not propagated as metadata.
+	PrevErr     error
+	PrevOutcome *classify.Outcome
+	// Backoff is the total backoff slept by the call before this attempt.
+	Backoff time.Duration
 }
 
 // WithAttemptInfo returns a context derived from ctx that carries info.
@@ -23,3 +37,45 @@ func AttemptFromContext(ctx context.Context) (AttemptInfo, bool) {
 	info, ok := ctx.Value(attemptInfoKey{}).(AttemptInfo)
 	return info, ok
 }
+
+// Metadata keys used to propagate AttemptInfo to downstream services. They are
+// lowercase so they are valid as both HTTP header names and gRPC metadata keys.
+const (
+	MetadataAttempt  = "x-recourse-attempt"
+	MetadataHedge    = "x-recourse-hedge"
+	MetadataPolicyID = "x-recourse-policy-id"
+)
+
+// EncodeAttemptInfo writes info as outbound metadata through set.
+// The attempt index is always written; the hedge index only for hedged
+// attempts and the policy ID only when non-empty.
+func EncodeAttemptInfo(info AttemptInfo, set func(key, value string)) {
+	set(MetadataAttempt, strconv.Itoa(info.Attempt))
+	if info.IsHedge {
+		set(MetadataHedge, strconv.Itoa(info.HedgeIndex))
+	}
+	if info.PolicyID != "" {
+		set(MetadataPolicyID, info.PolicyID)
+	}
+}
+
+// DecodeAttemptInfo reads AttemptInfo written by EncodeAttemptInfo through get.
+// It reports false when the attempt key is missing or malformed.
+func DecodeAttemptInfo(get func(key string) string) (AttemptInfo, bool) {
+	attempt, err := strconv.Atoi(strings.TrimSpace(get(MetadataAttempt)))
+	if err != nil || attempt < 0 {
+		return AttemptInfo{}, false
+	}
+	info := AttemptInfo{
+		RetryIndex: attempt,
+		Attempt:    attempt,
+		PolicyID:   strings.TrimSpace(get(MetadataPolicyID)),
+	}
+	if v := strings.TrimSpace(get(MetadataHedge)); v != "" {
+		if idx, err := strconv.Atoi(v); err == nil && idx >= 0 {
+			info.IsHedge = true
+			info.HedgeIndex = idx
+		}
+	}
+	return info, true
+}
@@ -49,3 +49,29 @@ func TestWithAttemptInfo_Roundtrip(t *testing.T) {
 		t.Fatal("expected base context to be unchanged")
 	}
 }
+
+func TestAttemptInfo_EncodeDecodeRoundTrip(t *testing.T) {
+	md := map[string]string{}
+	set := func(k, v string) { md[k] = v }
+	get := func(k string) string { return md[k] }
+
+	in := observe.AttemptInfo{RetryIndex: 3, Attempt: 3, IsHedge: true, HedgeIndex: 2, PolicyID: "pol"}
+	observe.EncodeAttemptInfo(in, set)
+	out, ok := observe.DecodeAttemptInfo(get)
+	if !ok || out != in {
+		t.Fatalf("out=%+v ok=%v, want %+v", out, ok, in)
+	}
+
+	md = map[string]string{}
+	observe.EncodeAttemptInfo(observe.AttemptInfo{Attempt: 1, RetryIndex: 1}, set)
+	if _, present := md[observe.MetadataHedge]; present {
+		t.Fatalf("hedge key written for a non-hedged attempt")
+	}
+	if _, present := md[observe.MetadataPolicyID]; present {
+		t.Fatalf("policy id key written without a policy id")
+	}
+
+	if _, ok := observe.DecodeAttemptInfo(func(string) string { return "" }); ok {
+		t.Fatalf("expected decode to fail without attempt metadata")
+	}
+}
@@ -0,0 +1,102 @@
+package observe
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Fingerprint returns a stable, low-cardinality identifier for err so failures
+// can be grouped into issues without keeping full error messages.
+//
+// It hashes the chain of error types (following Unwrap, including multi-errors),
+// the message with volatile tokens (numbers, hex IDs, quoted values) replaced by
+// placeholders, and the outcome reason. It returns "" for a nil error.
+func Fingerprint(err error, reason string) string {
+	if err == nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+	writeTypeChain(h, err, 0)
+	h.Write([]byte{0})
+	h.Write([]byte(normalizeErrorMessage(err.Error())))
+	h.Write([]byte{0})
+	h.Write([]byte(reason))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+const maxFingerprintDepth = 16
+
+func writeTypeChain(w interface{ Write([]byte) (int, error) }, err error, depth int) {
+	for err != nil && depth < maxFingerprintDepth {
+		fmt.Fprintf(w, "%T;", err)
+		depth++
+		if multi, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range multi.Unwrap() {
+				writeTypeChain(w, e, depth)
+			}
+			return
+		}
+		err = errors.Unwrap(err)
+	}
+}
+
+// normalizeErrorMessage replaces volatile tokens in msg with placeholders:
+// quoted values become "?", and words containing digits (counts, ports, IDs,
+// addresses, durations) become "#".
+func normalizeErrorMessage(msg string) string {
+	var b strings.Builder
+	b.Grow(len(msg))
+
+	word := make([]rune, 0, 16)
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		hasDigit := false
+		for _, r := range word {
+			if unicode.IsDigit(r) {
+				hasDigit = true
+				break
+			}
+		}
+		if hasDigit {
+			b.WriteByte('#')
+		} else {
+			b.WriteString(string(word))
+		}
+		word = word[:0]
+	}
+
+	var quote rune
+	for _, r := range msg {
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+				b.WriteString("?")
+				b.WriteRune(r)
+			}
+			continue
+		}
+		switch {
+		case r == '"' || r == '\'' || r == '`':
+			flush()
+			quote = r
+			b.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' || r == ':':
+			word = append(word, r)
+		default:
+			flush()
+			b.WriteRune(r)
+		}
+	}
+	flush()
+	if quote != 0 {
+		b.WriteString("?")
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package observe
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type codeError struct{ code int }
+
+func (e codeError) Error() string { return fmt.Sprintf("code %d", e.code) }
+
+func TestFingerprint_StableAcrossVolatileTokens(t *testing.T) {
+	a := fmt.Errorf("dial tcp 10.0.0.1:443: request %q failed after 3 tries: %w", "abc-123", io.ErrUnexpectedEOF)
+	b := fmt.Errorf("dial tcp 10.0.0.7:8443: request %q failed after 12 tries: %w", "zzz-999", io.ErrUnexpectedEOF)
+
+	if fa, fb := Fingerprint(a, "x"), Fingerprint(b, "x"); fa != fb {
+		t.Fatalf("fingerprints differ for equivalent errors: %s vs %s", fa, fb)
+	}
+}
+
+func TestFingerprint_Distinguishes(t *testing.T) {
+	base := Fingerprint(errors.New("boom"), "http_5xx")
+	cases := map[string]string{
+		"reason":  Fingerprint(errors.New("boom"), "http_429"),
+		"message": Fingerprint(errors.New("bang"), "http_5xx"),
+		"type":    Fingerprint(codeError{1}, "http_5xx"),
+		"chain":   Fingerprint(fmt.Errorf("boom: %w", codeError{1}), "http_5xx"),
+	}
+	for name, fp := range cases {
+		if fp == base {
+			t.Fatalf("%s: fingerprint %s should differ from base", name, fp)
+		}
+	}
+	if Fingerprint(codeError{1}, "") != Fingerprint(codeError{2}, "") {
+		t.Fatalf("expected numeric codes in messages to be normalized")
+	}
+}
+
+func TestFingerprint_Nil(t *testing.T) {
+	if fp := Fingerprint(nil, "x"); fp != "" {
+		t.Fatalf("fingerprint=%q, want empty for nil error", fp)
+	}
+}
+
+func TestFingerprint_MultiError(t *testing.T) {
+	a := errors.Join(io.EOF, codeError{1})
+	b := errors.Join(io.EOF, codeError{2})
+	c := errors.Join(io.EOF, io.ErrClosedPipe)
+	if Fingerprint(a, "") != Fingerprint(b, "") {
+		t.Fatalf("expected joined errors of the same shape to match")
+	}
+	if Fingerprint(a, "") == Fingerprint(c, "") {
+		t.Fatalf("expected joined errors of different types to differ")
+	}
+}
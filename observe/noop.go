@@ -14,6 +14,10 @@ func (NoopObserver) OnAttempt(context.Context, policy.PolicyKey, AttemptRecord)
 func (NoopObserver) OnHedgeSpawn(context.Context, policy.PolicyKey, AttemptRecord)     {}
 func (NoopObserver) OnHedgeCancel(context.Context, policy.PolicyKey, AttemptRecord, string) {
 }
-func (NoopObserver) OnBudgetDecision(context.Context, BudgetDecisionEvent) {}
-func (NoopObserver) OnSuccess(context.Context, policy.PolicyKey, Timeline) {}
-func (NoopObserver) OnFailure(context.Context, policy.PolicyKey, Timeline) {}
+func (NoopObserver) OnBudgetDecision(context.Context, BudgetDecisionEvent)       {}
+func (NoopObserver) OnBudgetStateChange(context.Context, BudgetStateEvent)       {}
+func (NoopObserver) OnRateLimitDecision(context.Context, RateLimitDecisionEvent) {}
+func (NoopObserver) OnBulkheadDecision(context.Context, BulkheadDecisionEvent)   {}
+func (NoopObserver) OnHedgeSuppressed(context.Context, HedgeSuppressedEvent)     {}
+func (NoopObserver) OnSuccess(context.Context, policy.PolicyKey, Timeline)       {}
+func (NoopObserver) OnFailure(context.Context, policy.PolicyKey, Timeline)       {}
@@ -2,6 +2,7 @@ package observe
 
 import (
 	"context"
+	"errors"
 
 	"github.com/aponysus/recourse/policy"
 )
@@ -17,9 +18,13 @@ func (BaseObserver) OnHedgeSpawn(context.Context, policy.PolicyKey, AttemptRecor
 func (BaseObserver) OnHedgeCancel(context.Context, policy.PolicyKey, AttemptRecord, string) {
 }
 
-func (BaseObserver) OnBudgetDecision(context.Context, BudgetDecisionEvent) {}
-func (BaseObserver) OnSuccess(context.Context, policy.PolicyKey, Timeline) {}
-func (BaseObserver) OnFailure(context.Context, policy.PolicyKey, Timeline) {}
+func (BaseObserver) OnBudgetDecision(context.Context, BudgetDecisionEvent)       {}
+func (BaseObserver) OnBudgetStateChange(context.Context, BudgetStateEvent)       {}
+func (BaseObserver) OnRateLimitDecision(context.Context, RateLimitDecisionEvent) {}
+func (BaseObserver) OnBulkheadDecision(context.Context, BulkheadDecisionEvent)   {}
+func (BaseObserver) OnHedgeSuppressed(context.Context, HedgeSuppressedEvent)     {}
+func (BaseObserver) OnSuccess(context.Context, policy.PolicyKey, Timeline)       {}
+func (BaseObserver) OnFailure(context.Context, policy.PolicyKey, Timeline)       {}
 
 // MultiObserver fans out events to multiple observers.
 type MultiObserver struct {
@@ -66,6 +71,55 @@ func (m MultiObserver) OnBudgetDecision(ctx context.Context, ev BudgetDecisionEv
 	}
 }
 
+// OnBudgetStateChange forwards to observers that implement BudgetStateObserver.
+func (m MultiObserver) OnBudgetStateChange(ctx context.Context, ev BudgetStateEvent) {
+	for _, o := range m.Observers {
+		if so, ok := o.(BudgetStateObserver); ok {
+			so.OnBudgetStateChange(ctx, ev)
+		}
+	}
+}
+
+// OnRateLimitDecision forwards to observers that implement RateLimitObserver.
+func (m MultiObserver) OnRateLimitDecision(ctx context.Context, ev RateLimitDecisionEvent) {
+	for _, o := range m.Observers {
+		if ro, ok := o.(RateLimitObserver); ok {
+			ro.OnRateLimitDecision(ctx, ev)
+		}
+	}
+}
+
+// OnBulkheadDecision forwards to observers that implement BulkheadObserver.
+func (m MultiObserver) OnBulkheadDecision(ctx context.Context, ev BulkheadDecisionEvent) {
+	for _, o := range m.Observers {
+		if bo, ok := o.(BulkheadObserver); ok {
+			bo.OnBulkheadDecision(ctx, ev)
+		}
+	}
+}
+
+// OnHedgeSuppressed forwards to observers that implement HedgeObserver.
+func (m MultiObserver) OnHedgeSuppressed(ctx context.Context, ev HedgeSuppressedEvent) {
+	for _, o := range m.Observers {
+		if ho, ok := o.(HedgeObserver); ok {
+			ho.OnHedgeSuppressed(ctx, ev)
+		}
+	}
+}
+
+// Flush flushes observers that implement Flusher and joins their errors.
+func (m MultiObserver) Flush(ctx context.Context) error {
+	var errs []error
+	for _, o := range m.Observers {
+		if f, ok := o.(Flusher); ok {
+			if err := f.Flush(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (m MultiObserver) OnSuccess(ctx context.Context, key policy.PolicyKey, tl Timeline) {
 	for _, o := range m.Observers {
 		if o != nil {
@@ -2,6 +2,7 @@ package observe_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/aponysus/recourse/observe"
@@ -94,3 +95,108 @@ func requireCounts(t *testing.T, obs *countingObserver, name string) {
 		t.Fatalf("%s failures: expected 1, got %d", name, obs.failures)
 	}
 }
+
+type rateLimitCounter struct {
+	countingObserver
+	rateLimits int
+}
+
+func (c *rateLimitCounter) OnRateLimitDecision(context.Context, observe.RateLimitDecisionEvent) {
+	c.rateLimits++
+}
+
+func TestMultiObserver_ForwardsRateLimitDecisions(t *testing.T) {
+	withExt := &rateLimitCounter{}
+	multi := observe.MultiObserver{Observers: []observe.Observer{&countingObserver{}, nil, withExt}}
+
+	multi.OnRateLimitDecision(context.Background(), observe.RateLimitDecisionEvent{LimiterName: "api"})
+
+	if withExt.rateLimits != 1 {
+		t.Fatalf("rateLimits=%d, want 1", withExt.rateLimits)
+	}
+}
+
+type bulkheadCounter struct {
+	countingObserver
+	bulkheads int
+}
+
+func (c *bulkheadCounter) OnBulkheadDecision(context.Context, observe.BulkheadDecisionEvent) {
+	c.bulkheads++
+}
+
+func TestMultiObserver_ForwardsBulkheadDecisions(t *testing.T) {
+	withExt := &bulkheadCounter{}
+	multi := observe.MultiObserver{Observers: []observe.Observer{&countingObserver{}, nil, withExt}}
+
+	multi.OnBulkheadDecision(context.Background(), observe.BulkheadDecisionEvent{MaxInFlight: 1})
+
+	if withExt.bulkheads != 1 {
+		t.Fatalf("bulkheads=%d, want 1", withExt.bulkheads)
+	}
+}
+
+type flushCounter struct {
+	countingObserver
+	flushes int
+	err     error
+}
+
+func (c *flushCounter) Flush(context.Context) error {
+	c.flushes++
+	return c.err
+}
+
+func TestMultiObserver_FlushesFlushers(t *testing.T) {
+	errFlush := errors.New("flush failed")
+	ok := &flushCounter{}
+	failing := &flushCounter{err: errFlush}
+	multi := observe.MultiObserver{Observers: []observe.Observer{&countingObserver{}, nil, ok, failing}}
+
+	if err := multi.Flush(context.Background()); !errors.Is(err, errFlush) {
+		t.Fatalf("err=%v, want %v", err, errFlush)
+	}
+	if ok.flushes != 1 || failing.flushes != 1 {
+		t.Fatalf("flushes=%d,%d, want 1,1", ok.flushes, failing.flushes)
+	}
+}
+
+type hedgeSuppressionCounter struct {
+	countingObserver
+	suppressed int
+}
+
+func (c *hedgeSuppressionCounter) OnHedgeSuppressed(context.Context, observe.HedgeSuppressedEvent) {
+	c.suppressed++
+}
+
+func TestMultiObserver_ForwardsHedgeSuppressions(t *testing.T) {
+	withExt := &hedgeSuppressionCounter{}
+	multi := observe.MultiObserver{Observers: []observe.Observer{&countingObserver{}, nil, withExt}}
+
+	multi.OnHedgeSuppressed(context.Background(), observe.HedgeSuppressedEvent{Limit: 1})
+
+	if withExt.suppressed != 1 {
+		t.Fatalf("suppressed=%d, want 1", withExt.suppressed)
+	}
+}
+
+type budgetStateCounter struct {
+	countingObserver
+	changes int
+}
+
+func (c *budgetStateCounter) OnBudgetStateChange(context.Context, observe.BudgetStateEvent) {
+	c.changes++
+}
+
+func TestMultiObserver_ForwardsBudgetStateChanges(t *testing.T) {
+	withExt := &budgetStateCounter{}
+	multi := observe.MultiObserver{Observers: []observe.Observer{&countingObserver{}, nil, withExt}}
+
+	multi.OnBudgetStateChange(context.Background(), observe.BudgetStateEvent{BudgetName: "b", Exhausted: true})
+
+	if withExt.changes != 1 {
+		t.Fatalf("changes=%d, want 1", withExt.changes)
+	}
+}
@@ -0,0 +1,63 @@
+package observe
+
+import "sync"
+
+var (
+	attemptsPool = sync.Pool{
+		New: func() any {
+			s := make([]AttemptRecord, 0, 4)
+			return &s
+		},
+	}
+	attributesPool = sync.Pool{
+		New: func() any {
+			return make(map[string]string, 4)
+		},
+	}
+)
+
+// PooledAttempts returns an empty attempt slice with capacity of at least n,
+// reusing a buffer released by Timeline.Release when one is available.
+func PooledAttempts(n int) []AttemptRecord {
+	p := attemptsPool.Get().(*[]AttemptRecord)
+	s := (*p)[:0]
+	if cap(s) < n {
+		s = make([]AttemptRecord, 0, n)
+	}
+	return s
+}
+
+// PooledAttributes returns an empty attributes map, reusing a map released by
+// Timeline.Release when one is available.
+func PooledAttributes() map[string]string {
+	return attributesPool.Get().(map[string]string)
+}
+
+// Release returns the timeline's Attempts slice and Attributes map to the shared
+// pools and clears both fields. It releases a Chained timeline too.
+//
+// Release must only be called once nothing retains the timeline's attempts or
+// attributes: observers that keep a Timeline beyond OnSuccess/OnFailure must copy
+// what they need. The retry executor calls Release itself only when timeline
+// pooling is enabled and the timeline is not returned to the caller.
+func (tl *Timeline) Release() {
+	if tl == nil {
+		return
+	}
+	if tl.Attempts != nil {
+		attempts := tl.Attempts[:cap(tl.Attempts)]
+		clear(attempts)
+		attempts = attempts[:0]
+		attemptsPool.Put(&attempts)
+		tl.Attempts = nil
+	}
+	if tl.Attributes != nil {
+		clear(tl.Attributes)
+		attributesPool.Put(tl.Attributes)
+		tl.Attributes = nil
+	}
+	if tl.Chained != nil {
+		tl.Chained.Release()
+		tl.Chained = nil
+	}
+}
@@ -0,0 +1,37 @@
+package observe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPooledAttempts_Capacity(t *testing.T) {
+	s := PooledAttempts(8)
+	if len(s) != 0 || cap(s) < 8 {
+		t.Fatalf("len=%d cap=%d, want 0/>=8", len(s), cap(s))
+	}
+}
+
+func TestTimelineRelease_ClearsFields(t *testing.T) {
+	tl := &Timeline{
+		Attempts:   append(PooledAttempts(1), AttemptRecord{Err: errors.New("boom")}),
+		Attributes: PooledAttributes(),
+	}
+	tl.Attributes["k"] = "v"
+	attrs := tl.Attributes
+
+	tl.Release()
+	if tl.Attempts != nil || tl.Attributes != nil {
+		t.Fatalf("expected fields to be cleared, got %+v", tl)
+	}
+	if len(attrs) != 0 {
+		t.Fatalf("expected released attributes to be emptied, got %v", attrs)
+	}
+
+	if got := PooledAttributes(); len(got) != 0 {
+		t.Fatalf("pooled attributes not empty: %v", got)
+	}
+
+	var nilTL *Timeline
+	nilTL.Release()
+}
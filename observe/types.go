@@ -21,6 +21,45 @@ type BudgetDecisionEvent struct {
 	Reason     string             // Decision reason (see budget reasons).
 }
 
+// BudgetStateEvent describes a budget becoming exhausted or recovering.
+type BudgetStateEvent struct {
+	Key        policy.PolicyKey // Policy key of the call whose attempt changed the state.
+	BudgetName string           // Budget registry name.
+	Exhausted  bool             // True when the budget started denying, false when it allowed a retry or hedge again.
+	Reason     string           // Denial reason that exhausted the budget; empty on recovery.
+	Since      time.Time        // When the budget became exhausted.
+	Duration   time.Duration    // How long the budget was exhausted (recovery only).
+}
+
+// RateLimitDecisionEvent describes a rate limiter admission decision.
+type RateLimitDecisionEvent struct {
+	Key         policy.PolicyKey // Policy key for the call.
+	LimiterName string           // Rate limiter registry name.
+	Mode        string           // "standard", "allow", "deny", "fallback", "allow_unsafe", "unknown"
+	Allowed     bool             // Whether the call was admitted.
+	Reason      string           // Decision reason (see ratelimit reasons).
+	RetryAfter  time.Duration    // Limiter-suggested wait when denied (0 if unknown).
+}
+
+// BulkheadDecisionEvent describes a bulkhead admission decision.
+type BulkheadDecisionEvent struct {
+	Key         policy.PolicyKey // Policy key for the call.
+	MaxInFlight int              // Concurrency limit of the key's bulkhead.
+	Allowed     bool             // Whether the call was admitted.
+	Reason      string           // Decision reason (see bulkhead reasons).
+	Waited      time.Duration    // Time the call queued for a slot.
+}
+
+// HedgeSuppressedEvent describes a hedge the executor declined to launch.
+type HedgeSuppressedEvent struct {
+	Key        policy.PolicyKey // Policy key for the call.
+	Attempt    int              // Attempt index (0-based) of the attempt group.
+	HedgeIndex int              // Index the hedge would have had within the group.
+	Reason     string           // "max_concurrent_hedges"
+	InFlight   int              // Hedges outstanding across the executor.
+	Limit      int              // Executor-wide concurrent hedge limit.
+}
+
 // AttemptRecord describes a single attempt (or hedge) execution.
 type AttemptRecord struct {
 	Attempt   int       // Attempt index (0-based).
@@ -32,12 +71,16 @@ type AttemptRecord struct {
 
 	Outcome classify.Outcome // Classification outcome for this attempt.
 
-	Err error // Error returned by the attempt (if any).
+	Err            error  // Error returned by the attempt (if any).
+	ErrFingerprint string // Stable fingerprint of Err (see Fingerprint); empty when Err is nil.
 
 	Backoff time.Duration // Backoff delay before this attempt.
 
 	BudgetAllowed bool   // Whether budget gating allowed this attempt.
 	BudgetReason  string // Budget decision reason (see budget reasons).
+	BudgetName    string // Budget that denied the attempt; empty when allowed.
+
+	Attributes map[string]string // Annotations added by attempt middleware; nil when none.
 }
 
 // Timeline is the structured record of a single call and all of its attempts.
@@ -48,10 +91,19 @@ type Timeline struct {
 	End      time.Time        // Call end time.
 
 	// Attributes holds call-level metadata (policy source, fallbacks, normalization notes, etc.).
+	// It is nil when the call recorded no attributes.
 	Attributes map[string]string
 
-	Attempts []AttemptRecord // Per-attempt records in execution order.
-	FinalErr error           // Final error returned to the caller.
+	Attempts            []AttemptRecord // Per-attempt records in execution order.
+	FinalErr            error           // Final error returned to the caller.
+	FinalErrFingerprint string          // Stable fingerprint of FinalErr (see Fingerprint); empty on success.
+
+	Fallback    string // Fallback that produced the result ("static", "cached", "handler", "key"); empty if none.
+	FallbackErr error  // Error the fallback replaced; FinalErr is nil when a fallback served the call.
+
+	// Chained is the timeline of the call a key fallback ran under
+	// FallbackPolicy.Key, whether or not it succeeded; nil otherwise.
+	Chained *Timeline
 }
 
 // Observer receives lifecycle callbacks for a single call.
@@ -67,3 +119,34 @@ type Observer interface {
 	OnSuccess(ctx context.Context, key policy.PolicyKey, tl Timeline)
 	OnFailure(ctx context.Context, key policy.PolicyKey, tl Timeline)
 }
+
+// BudgetStateObserver is an optional Observer extension that is told when a
+// budget becomes exhausted and when it recovers, rather than about each
+// decision. The executor checks for it with a type assertion.
+type BudgetStateObserver interface {
+	OnBudgetStateChange(ctx context.Context, ev BudgetStateEvent)
+}
+
+// RateLimitObserver is an optional Observer extension that receives rate
+// limiter admission decisions. The executor checks for it with a type assertion.
+type RateLimitObserver interface {
+	OnRateLimitDecision(ctx context.Context, ev RateLimitDecisionEvent)
+}
+
+// BulkheadObserver is an optional Observer extension that receives bulkhead
+// admission decisions. The executor checks for it with a type assertion.
+type BulkheadObserver interface {
+	OnBulkheadDecision(ctx context.Context, ev BulkheadDecisionEvent)
+}
+
+// HedgeObserver is an optional Observer extension that is told about hedges
+// the executor suppressed. The executor checks for it with a type assertion.
+type HedgeObserver interface {
+	OnHedgeSuppressed(ctx context.Context, ev HedgeSuppressedEvent)
+}
+
+// Flusher is an optional Observer extension for observers that deliver events
+// asynchronously. Executor.Close calls Flush once in-flight calls have drained.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
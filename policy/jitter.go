@@ -0,0 +1,87 @@
+package policy
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// JitterFunc computes the sleep before a retry from the unjittered
+// exponential backoff. rnd returns uniform random numbers in [0, 1) from the
+// executor's jitter source, so executors seeded with WithJitterSeed or
+// WithRand sleep reproducibly. Results are capped at RetryPolicy.MaxBackoff.
+type JitterFunc func(backoff time.Duration, rnd func() float64) time.Duration
+
+// JitterRegistry is a thread-safe name → JitterFunc map for custom jitter
+// strategies that RetryPolicy.Jitter can refer to by name.
+type JitterRegistry struct {
+	mu sync.RWMutex
+	m  map[JitterKind]JitterFunc
+}
+
+// NewJitterRegistry returns an empty registry.
+func NewJitterRegistry() *JitterRegistry {
+	return &JitterRegistry{}
+}
+
+// DefaultJitters is the registry consulted by Normalize and Validate for
+// jitter names beyond the built-in kinds, and by executors at call time.
+var DefaultJitters = NewJitterRegistry()
+
+// RegisterJitter registers a custom jitter in DefaultJitters.
+func RegisterJitter(name JitterKind, fn JitterFunc) {
+	DefaultJitters.Register(name, fn)
+}
+
+// Register associates name with fn, replacing any jitter of that name. Empty
+// names, nil functions and the built-in kinds are ignored.
+func (r *JitterRegistry) Register(name JitterKind, fn JitterFunc) {
+	if r == nil || fn == nil {
+		return
+	}
+	name = JitterKind(strings.TrimSpace(string(name)))
+	if name == "" || builtinJitter(name) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.m == nil {
+		r.m = make(map[JitterKind]JitterFunc)
+	}
+	r.m[name] = fn
+}
+
+// Get returns the jitter registered under name.
+func (r *JitterRegistry) Get(name JitterKind) (JitterFunc, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	fn, ok := r.m[name]
+	r.mu.RUnlock()
+	return fn, ok
+}
+
+// Names returns the registered jitter names in no particular order.
+func (r *JitterRegistry) Names() []JitterKind {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]JitterKind, 0, len(r.m))
+	for name := range r.m {
+		names = append(names, name)
+	}
+	return names
+}
+
+// builtinJitter reports whether j is one of the built-in jitter kinds.
+func builtinJitter(j JitterKind) bool {
+	switch j {
+	case JitterNone, JitterFull, JitterEqual, JitterDecorrelated:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitterRegistry_Register(t *testing.T) {
+	r := NewJitterRegistry()
+	half := func(b time.Duration, _ func() float64) time.Duration { return b / 2 }
+
+	r.Register(" half ", half)
+	r.Register("", half)
+	r.Register(JitterFull, half)
+	r.Register("nil", nil)
+
+	if fn, ok := r.Get("half"); !ok || fn(time.Second, nil) != 500*time.Millisecond {
+		t.Fatal("half not registered")
+	}
+	if names := r.Names(); len(names) != 1 {
+		t.Fatalf("names=%v, want [half]", names)
+	}
+
+	var nilReg *JitterRegistry
+	nilReg.Register("x", half)
+	if _, ok := nilReg.Get("x"); ok {
+		t.Fatal("nil registry returned a jitter")
+	}
+}
+
+func TestNormalize_RegisteredJitter(t *testing.T) {
+	pol := EffectivePolicy{Key: ParseKey("svc.jitter"), Retry: RetryPolicy{Jitter: "policy_test_quarter"}}
+	if _, err := pol.Normalize(); err == nil {
+		t.Fatal("unregistered jitter normalized")
+	}
+	if err := pol.Validate(); err == nil {
+		t.Fatal("unregistered jitter validated")
+	}
+
+	RegisterJitter("policy_test_quarter", func(b time.Duration, _ func() float64) time.Duration { return b / 4 })
+	normalized, err := pol.Normalize()
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	if normalized.Retry.Jitter != "policy_test_quarter" {
+		t.Fatalf("jitter=%q", normalized.Retry.Jitter)
+	}
+	if err := pol.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+}
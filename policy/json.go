@@ -0,0 +1,243 @@
+package policy
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// effectivePolicyJSON has EffectivePolicy's fields without its methods, so
+// UnmarshalJSON can decode into it without recursing.
+type effectivePolicyJSON EffectivePolicy
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// MarshalJSON encodes p with durations as Go duration strings such as "250ms"
+// and resolution metadata under "meta". Fields appear in declaration order,
+// and empty omitempty fields, including zero structs, are left out. The
+// encoding round-trips through UnmarshalJSON unchanged.
+func (p EffectivePolicy) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeValue(&buf, reflect.ValueOf(effectivePolicyJSON(p))); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes data into p, accepting durations as Go duration
+// strings or as integer nanoseconds. Like encoding/json, it keeps p's values
+// for fields data does not mention.
+func (p *EffectivePolicy) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return err
+	}
+	tree, err := parseDurations(reflect.TypeOf(effectivePolicyJSON{}), tree, "")
+	if err != nil {
+		return err
+	}
+	data, err = json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	out := effectivePolicyJSON(*p)
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	*p = EffectivePolicy(out)
+	return nil
+}
+
+// encodeValue writes v as JSON, writing durations as strings and recursing
+// into structs, maps and slices so nested durations are converted too. Types
+// with their own JSON or text encoding are left to encoding/json.
+func encodeValue(buf *bytes.Buffer, v reflect.Value) error {
+	switch t := v.Type(); {
+	case t == durationType:
+		buf.WriteString(strconv.Quote(time.Duration(v.Int()).String()))
+		return nil
+	case t.Implements(jsonMarshalerType), t.Implements(textMarshalerType):
+		return marshalValue(buf, v)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		buf.WriteByte('{')
+		first := true
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, omitEmpty, ok := jsonField(f)
+			if !ok {
+				continue
+			}
+			fv := v.Field(i)
+			if omitEmpty && fv.IsZero() {
+				continue
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			buf.WriteString(strconv.Quote(name))
+			buf.WriteByte(':')
+			if err := encodeValue(buf, fv); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(strconv.Quote(k.String()))
+			buf.WriteByte(':')
+			if err := encodeValue(buf, v.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeValue(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+
+	return marshalValue(buf, v)
+}
+
+func marshalValue(buf *bytes.Buffer, v reflect.Value) error {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// parseDurations walks v, a decoded JSON value destined for a value of type
+// t, and rewrites duration strings as nanoseconds. path is v's JSON path.
+func parseDurations(t reflect.Type, v any, path string) (any, error) {
+	switch {
+	case t == durationType:
+		s, ok := v.(string)
+		if !ok {
+			return v, nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("policy: %s: invalid duration %q", path, s)
+		}
+		return json.Number(strconv.FormatInt(int64(d), 10)), nil
+
+	case t.Kind() == reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+		for i := 0; i < t.NumField(); i++ {
+			name, _, ok := jsonField(t.Field(i))
+			if !ok {
+				continue
+			}
+			fv, ok := obj[name]
+			if !ok {
+				continue
+			}
+			converted, err := parseDurations(t.Field(i).Type, fv, joinJSONPath(path, name))
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = converted
+		}
+		return obj, nil
+
+	case t.Kind() == reflect.Map:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+		for name, ev := range obj {
+			converted, err := parseDurations(t.Elem(), ev, joinJSONPath(path, name))
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = converted
+		}
+		return obj, nil
+
+	case t.Kind() == reflect.Slice:
+		arr, ok := v.([]any)
+		if !ok {
+			return v, nil
+		}
+		for i, ev := range arr {
+			converted, err := parseDurations(t.Elem(), ev, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = converted
+		}
+		return arr, nil
+	}
+	return v, nil
+}
+
+// jsonField returns f's JSON name and whether it is omitempty. ok is false
+// for fields encoding/json skips.
+func jsonField(f reflect.StructField) (name string, omitEmpty, ok bool) {
+	if !f.IsExported() {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false, false
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, strings.Contains(opts, "omitempty"), true
+}
+
+func joinJSONPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
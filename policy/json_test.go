@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEffectivePolicyJSONRoundTrip(t *testing.T) {
+	p, err := New("payments.Charge@gold",
+		HTTPDefaults(),
+		PerAttemptTimeout(250*time.Millisecond),
+		EnableHedging(),
+		HedgeDelay(20*time.Millisecond),
+		OnReason("http_429", ReasonOverride{MinBackoff: 2 * time.Second}),
+		Window(PolicyWindow{
+			Name:  "nightly",
+			Start: "22:00",
+			End:   "06:00",
+			Retry: RetryPolicy{MaxAttempts: 2, InitialBackoff: 50 * time.Millisecond},
+		}),
+	).Normalize()
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	p.Meta.Source = PolicySourceRemote
+	p.Meta.MatchedKey = ParseKey("payments.*")
+	p.Meta.Version = 7
+	p.Meta.Revision = "abc123"
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for _, want := range []string{
+		`"timeout_per_attempt":"250ms"`,
+		`"hedge_delay":"20ms"`,
+		`"min_backoff":"2s"`,
+		`"initial_backoff":"50ms"`,
+		`"key":"payments.Charge@gold"`,
+		`"meta":{"source":"remote","matched_key":"payments.*","version":7,"revision":"abc123"`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("encoding missing %s:\n%s", want, data)
+		}
+	}
+
+	var got EffectivePolicy
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", got, p)
+	}
+
+	again, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(again) != string(data) {
+		t.Fatalf("encoding not stable:\n%s\n%s", data, again)
+	}
+}
+
+func TestEffectivePolicyJSONOmitsEmptyMeta(t *testing.T) {
+	data, err := json.Marshal(EffectivePolicy{Key: ParseKey("svc.Op")})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(data), `"meta"`) {
+		t.Fatalf("zero metadata encoded: %s", data)
+	}
+}
+
+func TestEffectivePolicyUnmarshalJSONDurations(t *testing.T) {
+	var p EffectivePolicy
+	err := json.Unmarshal([]byte(`{
+		"retry": {"initial_backoff": "100ms", "max_backoff": 2000000000},
+		"reason_overrides": {"http_503": {"min_backoff": "1s"}}
+	}`), &p)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if p.Retry.InitialBackoff != 100*time.Millisecond {
+		t.Errorf("InitialBackoff=%v, want 100ms", p.Retry.InitialBackoff)
+	}
+	if p.Retry.MaxBackoff != 2*time.Second {
+		t.Errorf("MaxBackoff=%v, want 2s", p.Retry.MaxBackoff)
+	}
+	if got := p.ReasonOverrides["http_503"].MinBackoff; got != time.Second {
+		t.Errorf("reason MinBackoff=%v, want 1s", got)
+	}
+
+	err = json.Unmarshal([]byte(`{"hedge": {"hedge_delay": "soon"}}`), &p)
+	if err == nil || !strings.Contains(err.Error(), "hedge.hedge_delay") {
+		t.Fatalf("Unmarshal error=%v, want hedge.hedge_delay", err)
+	}
+}
@@ -1,21 +1,40 @@
 package policy
 
-import "strings"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
 
 // PolicyKey identifies a low-cardinality call site (e.g. "svc.Method").
 type PolicyKey struct {
-	Namespace string `json:"namespace"` // Optional logical namespace (e.g. service name).
-	Name      string `json:"name"`      // Operation name within the namespace.
+	Namespace string `json:"namespace"`         // Optional logical namespace (e.g. service name).
+	Name      string `json:"name"`              // Operation name within the namespace.
+	Variant   string `json:"variant,omitempty"` // Optional variant of the call site, such as a tenant or tier.
 }
 
+// VariantSeparator separates a key's variant from the rest of the key in
+// its string form ("payments.Charge@acme").
+const VariantSeparator = "@"
+
 // ParseKey parses "namespace.name" into a PolicyKey.
-// If no dot is present, the entire string is treated as Name.
+// If no dot is present, the entire string is treated as Name. A trailing
+// "@variant" sets Variant.
 func ParseKey(s string) PolicyKey {
 	s = strings.TrimSpace(s)
 	if s == "" {
 		return PolicyKey{}
 	}
 
+	if i := strings.LastIndex(s, VariantSeparator); i > 0 {
+		base := ParseKey(s[:i])
+		if base != (PolicyKey{}) {
+			base.Variant = strings.TrimSpace(s[i+len(VariantSeparator):])
+			return base
+		}
+	}
+
 	i := strings.IndexByte(s, '.')
 	if i < 0 {
 		return PolicyKey{Name: s}
@@ -29,12 +48,162 @@ func ParseKey(s string) PolicyKey {
 	return PolicyKey{Namespace: ns, Name: name}
 }
 
+// KeyError reports a key rejected by ParseKeyStrict.
+type KeyError struct {
+	Key    string
+	Reason string
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("policy: invalid key %q: %s", e.Key, e.Reason)
+}
+
+// ParseKeyStrict parses a key like ParseKey but rejects malformed keys with a
+// *KeyError instead of guessing: empty keys, empty namespace, name or
+// variant segments ("svc.", ".method", "svc..method", "svc.method@"), more
+// than one variant separator, and whitespace within the key. Surrounding
+// whitespace is ignored.
+func ParseKeyStrict(s string) (PolicyKey, error) {
+	raw := s
+	s = strings.TrimSpace(s)
+	fail := func(reason string) (PolicyKey, error) {
+		return PolicyKey{}, &KeyError{Key: raw, Reason: reason}
+	}
+	if s == "" {
+		return fail("empty key")
+	}
+	if strings.IndexFunc(s, unicode.IsSpace) >= 0 {
+		return fail("contains whitespace")
+	}
+
+	base, variant, hasVariant := strings.Cut(s, VariantSeparator)
+	if hasVariant {
+		if variant == "" {
+			return fail("empty variant")
+		}
+		if strings.Contains(variant, VariantSeparator) {
+			return fail("more than one " + VariantSeparator)
+		}
+	}
+	if base == "" {
+		return fail("empty name")
+	}
+	for _, seg := range strings.Split(base, ".") {
+		if seg == "" {
+			return fail("empty segment")
+		}
+	}
+
+	k := ParseKey(base)
+	k.Variant = variant
+	return k, nil
+}
+
+// MarshalText encodes k in its string form, so keys appear as
+// "payments.Charge@acme" in JSON, logs and map keys. It returns a *KeyError
+// for a key whose string form UnmarshalText would reject or decode to a
+// different key, such as one with whitespace, an empty segment or only a
+// variant.
+func (k PolicyKey) MarshalText() ([]byte, error) {
+	if k == (PolicyKey{}) {
+		return []byte{}, nil
+	}
+	s := k.String()
+	parsed, err := ParseKeyStrict(s)
+	if err != nil {
+		return nil, err
+	}
+	if parsed != k {
+		return nil, &KeyError{Key: s, Reason: fmt.Sprintf("decodes as %+v", parsed)}
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseKeyStrict. Empty text decodes to the
+// zero key.
+func (k *PolicyKey) UnmarshalText(text []byte) error {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		*k = PolicyKey{}
+		return nil
+	}
+	parsed, err := ParseKeyStrict(string(text))
+	if err != nil {
+		return err
+	}
+	*k = parsed
+	return nil
+}
+
+// UnmarshalJSON decodes a key from its string form or, for documents written
+// before keys encoded as strings, from a {"namespace", "name", "variant"}
+// object.
+func (k *PolicyKey) UnmarshalJSON(data []byte) error {
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "{") {
+		type fields PolicyKey
+		var f fields
+		if err := json.Unmarshal(data, &f); err != nil {
+			return err
+		}
+		*k = PolicyKey(f)
+		return nil
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return k.UnmarshalText([]byte(s))
+}
+
+// Wildcard as a key name matches every key in its namespace ("payments.*");
+// as a whole key ("*") it matches every key.
+const Wildcard = "*"
+
+// WithVariant returns k with Variant set to variant.
+func (k PolicyKey) WithVariant(variant string) PolicyKey {
+	k.Variant = variant
+	return k
+}
+
+// Base returns k without its variant.
+func (k PolicyKey) Base() PolicyKey {
+	k.Variant = ""
+	return k
+}
+
+// Patterns returns the keys providers look up for k, most specific first: k
+// itself, k without its variant, "namespace.*" when k has a namespace, and
+// "*".
+func (k PolicyKey) Patterns() []PolicyKey {
+	if k.Variant != "" {
+		return append([]PolicyKey{k}, k.Base().Patterns()...)
+	}
+	global := PolicyKey{Name: Wildcard}
+	if k == global {
+		return []PolicyKey{k}
+	}
+	patterns := make([]PolicyKey, 0, 3)
+	patterns = append(patterns, k)
+	if k.Namespace != "" && k.Name != Wildcard {
+		patterns = append(patterns, PolicyKey{Namespace: k.Namespace, Name: Wildcard})
+	}
+	return append(patterns, global)
+}
+
 func (k PolicyKey) String() string {
-	if k.Namespace == "" {
-		return k.Name
+	var s string
+	switch {
+	case k.Namespace == "":
+		s = k.Name
+	case k.Name == "":
+		s = k.Namespace
+	default:
+		s = k.Namespace + "." + k.Name
 	}
-	if k.Name == "" {
-		return k.Namespace
+	if k.Variant == "" {
+		return s
 	}
-	return k.Namespace + "." + k.Name
+	return s + VariantSeparator + k.Variant
 }
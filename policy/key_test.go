@@ -1,6 +1,11 @@
 package policy
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestParseKey_Cases(t *testing.T) {
 	cases := []struct {
@@ -15,6 +20,10 @@ func TestParseKey_Cases(t *testing.T) {
 		{input: ".method", want: PolicyKey{Name: "method"}},
 		{input: "service . method", want: PolicyKey{Namespace: "service", Name: "method"}},
 		{input: "svc.method.extra", want: PolicyKey{Namespace: "svc", Name: "method.extra"}},
+		{input: "svc.method@acme", want: PolicyKey{Namespace: "svc", Name: "method", Variant: "acme"}},
+		{input: "method @ acme", want: PolicyKey{Name: "method", Variant: "acme"}},
+		{input: "svc.method@", want: PolicyKey{Namespace: "svc", Name: "method"}},
+		{input: "@acme", want: PolicyKey{Name: "@acme"}},
 	}
 
 	for _, tc := range cases {
@@ -33,6 +42,7 @@ func TestPolicyKey_String(t *testing.T) {
 		{key: PolicyKey{Name: "method"}, want: "method"},
 		{key: PolicyKey{Namespace: "svc"}, want: "svc"},
 		{key: PolicyKey{Namespace: "svc", Name: "method"}, want: "svc.method"},
+		{key: PolicyKey{Namespace: "svc", Name: "method", Variant: "acme"}, want: "svc.method@acme"},
 	}
 
 	for _, tc := range cases {
@@ -41,3 +51,140 @@ func TestPolicyKey_String(t *testing.T) {
 		}
 	}
 }
+
+func TestPolicyKey_Patterns(t *testing.T) {
+	cases := []struct {
+		key  string
+		want []string
+	}{
+		{key: "svc.method", want: []string{"svc.method", "svc.*", "*"}},
+		{key: "method", want: []string{"method", "*"}},
+		{key: "svc.*", want: []string{"svc.*", "*"}},
+		{key: "*", want: []string{"*"}},
+		{key: "svc.method@acme", want: []string{"svc.method@acme", "svc.method", "svc.*", "*"}},
+	}
+
+	for _, tc := range cases {
+		var got []string
+		for _, p := range ParseKey(tc.key).Patterns() {
+			got = append(got, p.String())
+		}
+		if strings.Join(got, " ") != strings.Join(tc.want, " ") {
+			t.Fatalf("Patterns(%q) = %v, want %v", tc.key, got, tc.want)
+		}
+	}
+}
+
+func TestPolicyKey_Variant(t *testing.T) {
+	key := ParseKey("svc.method").WithVariant("acme")
+	if key.String() != "svc.method@acme" || ParseKey(key.String()) != key {
+		t.Fatalf("key=%+v string=%q", key, key.String())
+	}
+	if base := key.Base(); base != ParseKey("svc.method") {
+		t.Fatalf("base=%+v", base)
+	}
+}
+
+func TestParseKeyStrict(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  PolicyKey
+	}{
+		{input: "method", want: PolicyKey{Name: "method"}},
+		{input: " svc.method ", want: PolicyKey{Namespace: "svc", Name: "method"}},
+		{input: "svc.method.v2", want: PolicyKey{Namespace: "svc", Name: "method.v2"}},
+		{input: "svc.method@acme", want: PolicyKey{Namespace: "svc", Name: "method", Variant: "acme"}},
+		{input: "svc.*", want: PolicyKey{Namespace: "svc", Name: "*"}},
+	} {
+		got, err := ParseKeyStrict(tc.input)
+		if err != nil || got != tc.want {
+			t.Fatalf("ParseKeyStrict(%q) = %+v, %v; want %+v", tc.input, got, err, tc.want)
+		}
+	}
+
+	for input, reason := range map[string]string{
+		"":              "empty key",
+		"   ":           "empty key",
+		"svc.":          "empty segment",
+		".method":       "empty segment",
+		"svc..method":   "empty segment",
+		"svc.method@":   "empty variant",
+		"@acme":         "empty name",
+		"svc.m@a@b":     "more than one @",
+		"service . one": "contains whitespace",
+	} {
+		_, err := ParseKeyStrict(input)
+		var ke *KeyError
+		if !errors.As(err, &ke) || ke.Reason != reason {
+			t.Errorf("ParseKeyStrict(%q) err=%v, want %q", input, err, reason)
+		}
+	}
+}
+
+func TestPolicyKey_TextMarshaling(t *testing.T) {
+	key := PolicyKey{Namespace: "svc", Name: "method", Variant: "acme"}
+	data, err := json.Marshal(map[PolicyKey]PolicyKey{key: key.Base()})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"svc.method@acme":"svc.method"}` {
+		t.Fatalf("encoding=%s", data)
+	}
+
+	var got map[PolicyKey]PolicyKey
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got[key] != key.Base() {
+		t.Fatalf("decoded=%+v", got)
+	}
+
+	var legacy PolicyKey
+	if err := json.Unmarshal([]byte(`{"namespace":"svc","name":"method"}`), &legacy); err != nil || legacy != key.Base() {
+		t.Fatalf("legacy object: key=%+v err=%v", legacy, err)
+	}
+	var empty PolicyKey
+	if err := json.Unmarshal([]byte(`""`), &empty); err != nil || empty != (PolicyKey{}) {
+		t.Fatalf("empty: key=%+v err=%v", empty, err)
+	}
+	if err := json.Unmarshal([]byte(`"svc."`), &empty); err == nil {
+		t.Fatal("malformed key decoded")
+	}
+}
+
+func TestPolicyKey_TextRoundTrip(t *testing.T) {
+	for _, key := range []PolicyKey{
+		{},
+		{Name: "method"},
+		{Name: "method", Variant: "acme"},
+		{Namespace: "svc", Name: "method"},
+		{Namespace: "svc", Name: "method", Variant: "acme"},
+		{Namespace: "svc", Name: "a.b"},
+		{Namespace: "svc", Name: Wildcard},
+		{Name: Wildcard},
+	} {
+		data, err := key.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%+v): %v", key, err)
+		}
+		var got PolicyKey
+		if err := got.UnmarshalText(data); err != nil || got != key {
+			t.Fatalf("round trip of %+v via %q = %+v, %v", key, data, got, err)
+		}
+	}
+
+	for _, key := range []PolicyKey{
+		{Variant: "acme"},
+		{Namespace: "svc"},
+		{Name: "a.b"},
+		{Namespace: "svc", Name: "my method"},
+		{Name: " method"},
+		{Namespace: "svc", Name: ".method"},
+		{Name: "method", Variant: "a@b"},
+	} {
+		var ke *KeyError
+		if _, err := key.MarshalText(); !errors.As(err, &ke) {
+			t.Errorf("MarshalText(%+v) err=%v, want *KeyError", key, err)
+		}
+	}
+}
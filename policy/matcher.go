@@ -0,0 +1,85 @@
+package policy
+
+import (
+	"strconv"
+	"strings"
+)
+
+// grpcCodeNames are the canonical gRPC status code names accepted in
+// ErrorMatcher.GRPCCodes.
+var grpcCodeNames = map[string]struct{}{
+	"OK":                  {},
+	"CANCELED":            {},
+	"UNKNOWN":             {},
+	"INVALID_ARGUMENT":    {},
+	"DEADLINE_EXCEEDED":   {},
+	"NOT_FOUND":           {},
+	"ALREADY_EXISTS":      {},
+	"PERMISSION_DENIED":   {},
+	"RESOURCE_EXHAUSTED":  {},
+	"FAILED_PRECONDITION": {},
+	"ABORTED":             {},
+	"OUT_OF_RANGE":        {},
+	"UNIMPLEMENTED":       {},
+	"INTERNAL":            {},
+	"UNAVAILABLE":         {},
+	"DATA_LOSS":           {},
+	"UNAUTHENTICATED":     {},
+}
+
+// normalizeErrorMatcher trims and upper-cases gRPC code names and rejects
+// unknown codes, out-of-range HTTP statuses, and empty error names. It never
+// modifies m's slices in place.
+func normalizeErrorMatcher(field string, m ErrorMatcher) (ErrorMatcher, error) {
+	if m.IsZero() {
+		return m, nil
+	}
+
+	out := ErrorMatcher{HTTPStatuses: m.HTTPStatuses}
+	if err := checkHTTPStatuses(field+".http_statuses", m.HTTPStatuses); err != nil {
+		return ErrorMatcher{}, err
+	}
+	for _, name := range m.Errors {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return ErrorMatcher{}, &NormalizeError{Field: field + ".errors", Value: name}
+		}
+		out.Errors = append(out.Errors, name)
+	}
+	codes, err := normalizeGRPCCodes(field+".grpc_codes", m.GRPCCodes)
+	if err != nil {
+		return ErrorMatcher{}, err
+	}
+	out.GRPCCodes = codes
+	return out, nil
+}
+
+// checkHTTPStatuses rejects statuses outside 100-599.
+func checkHTTPStatuses(field string, statuses []int) error {
+	for _, status := range statuses {
+		if status < 100 || status > 599 {
+			return &NormalizeError{Field: field, Value: strconv.Itoa(status)}
+		}
+	}
+	return nil
+}
+
+// normalizeGRPCCodes trims and upper-cases gRPC code names, accepting the
+// "CANCELLED" spelling, and rejects unknown codes. A nil input stays nil.
+func normalizeGRPCCodes(field string, codes []string) ([]string, error) {
+	if codes == nil {
+		return nil, nil
+	}
+	out := make([]string, 0, len(codes))
+	for _, code := range codes {
+		canonical := strings.ToUpper(strings.TrimSpace(code))
+		if canonical == "CANCELLED" {
+			canonical = "CANCELED"
+		}
+		if _, ok := grpcCodeNames[canonical]; !ok {
+			return nil, &NormalizeError{Field: field, Value: code}
+		}
+		out = append(out, canonical)
+	}
+	return out, nil
+}
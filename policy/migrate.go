@@ -0,0 +1,132 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// SchemaVersion is the current layout of JSON policy documents.
+// ParseVersioned migrates documents written for older versions to it.
+//
+// Version 1 documents differ from version 2 as follows:
+//
+//   - "retry.timeout" is "retry.timeout_per_attempt".
+//   - "retry.classifier" is "retry.classifier_name".
+//   - "retry.jitter" may be a boolean: true for "full", false for "none".
+//   - "retry.budget" and "hedge.budget" may be a budget name instead of a
+//     BudgetRef object.
+//   - "hedge.delay" is "hedge.hedge_delay" and "hedge.trigger" is
+//     "hedge.trigger_name".
+const SchemaVersion = 2
+
+// schemaVersionField is the document field holding its schema version.
+const schemaVersionField = "schema_version"
+
+// schemaMigrations[i] upgrades a decoded version i+1 document to version i+2
+// in place and returns a warning for each field it rewrote.
+var schemaMigrations = []func(doc map[string]any) []string{
+	migrateV1,
+}
+
+// ParseVersioned decodes a JSON policy document written for any supported
+// schema version. The document's "schema_version" field, 1 when absent,
+// selects the migrations applied before the document is decoded as by
+// UnmarshalJSON. Each rewritten field is described in
+// Meta.Normalization.Warnings, which Normalize preserves, so operators can
+// find documents still using old layouts. Documents newer than SchemaVersion
+// are rejected.
+func ParseVersioned(data []byte) (EffectivePolicy, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return EffectivePolicy{}, err
+	}
+	doc, ok := tree.(map[string]any)
+	if !ok {
+		return EffectivePolicy{}, errors.New("policy: document is not a JSON object")
+	}
+
+	version := 1
+	if raw, ok := doc[schemaVersionField]; ok {
+		n, isNum := raw.(json.Number)
+		v, err := strconv.Atoi(n.String())
+		if !isNum || err != nil || v < 1 {
+			return EffectivePolicy{}, fmt.Errorf("policy: %s: invalid version %v", schemaVersionField, raw)
+		}
+		version = v
+		delete(doc, schemaVersionField)
+	}
+	if version > SchemaVersion {
+		return EffectivePolicy{}, fmt.Errorf("policy: %s %d is newer than supported version %d", schemaVersionField, version, SchemaVersion)
+	}
+
+	var warnings []string
+	for v := version; v < SchemaVersion; v++ {
+		warnings = append(warnings, schemaMigrations[v-1](doc)...)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return EffectivePolicy{}, err
+	}
+	var p EffectivePolicy
+	if err := p.UnmarshalJSON(data); err != nil {
+		return EffectivePolicy{}, err
+	}
+	p.Meta.Normalization.Warnings = append(p.Meta.Normalization.Warnings, warnings...)
+	return p, nil
+}
+
+// migrateV1 upgrades a version 1 document to version 2.
+func migrateV1(doc map[string]any) []string {
+	var warnings []string
+	retry, _ := doc["retry"].(map[string]any)
+	hedge, _ := doc["hedge"].(map[string]any)
+
+	warnings = renameField(warnings, retry, "retry", "timeout", "timeout_per_attempt")
+	warnings = renameField(warnings, retry, "retry", "classifier", "classifier_name")
+	warnings = renameField(warnings, hedge, "hedge", "delay", "hedge_delay")
+	warnings = renameField(warnings, hedge, "hedge", "trigger", "trigger_name")
+
+	if on, ok := retry["jitter"].(bool); ok {
+		kind := JitterNone
+		if on {
+			kind = JitterFull
+		}
+		retry["jitter"] = string(kind)
+		warnings = append(warnings, fmt.Sprintf("retry.jitter: boolean %t converted to %q", on, kind))
+	}
+
+	warnings = budgetObject(warnings, retry, "retry")
+	warnings = budgetObject(warnings, hedge, "hedge")
+	return warnings
+}
+
+// renameField moves obj[from] to obj[to], where obj is the object at path.
+// A value already at to wins and the old field is dropped.
+func renameField(warnings []string, obj map[string]any, path, from, to string) []string {
+	v, ok := obj[from]
+	if !ok {
+		return warnings
+	}
+	delete(obj, from)
+	if _, exists := obj[to]; exists {
+		return append(warnings, fmt.Sprintf("%s.%s: ignored, %s.%s is set", path, from, path, to))
+	}
+	obj[to] = v
+	return append(warnings, fmt.Sprintf("%s.%s: renamed to %s.%s", path, from, path, to))
+}
+
+// budgetObject converts a budget given by name to a BudgetRef object.
+func budgetObject(warnings []string, obj map[string]any, path string) []string {
+	name, ok := obj["budget"].(string)
+	if !ok {
+		return warnings
+	}
+	obj["budget"] = map[string]any{"name": name}
+	return append(warnings, fmt.Sprintf("%s.budget: name %q converted to {\"name\": %q}", path, name, name))
+}
@@ -0,0 +1,83 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseVersioned_MigratesV1(t *testing.T) {
+	p, err := ParseVersioned([]byte(`{
+		"retry": {
+			"max_attempts": 4,
+			"timeout": "200ms",
+			"classifier": "http",
+			"jitter": true,
+			"budget": "payments"
+		},
+		"hedge": {"enabled": true, "delay": "50ms", "trigger": "p90", "hedge_delay": "30ms"}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseVersioned: %v", err)
+	}
+
+	if p.Retry.TimeoutPerAttempt != 200*time.Millisecond || p.Retry.ClassifierName != "http" {
+		t.Fatalf("retry=%+v", p.Retry)
+	}
+	if p.Retry.Jitter != JitterFull || p.Retry.Budget != (BudgetRef{Name: "payments"}) {
+		t.Fatalf("jitter=%q budget=%+v", p.Retry.Jitter, p.Retry.Budget)
+	}
+	if p.Hedge.HedgeDelay != 30*time.Millisecond || p.Hedge.TriggerName != "p90" {
+		t.Fatalf("hedge=%+v", p.Hedge)
+	}
+
+	want := []string{
+		"retry.timeout: renamed to retry.timeout_per_attempt",
+		"retry.classifier: renamed to retry.classifier_name",
+		"hedge.delay: ignored, hedge.hedge_delay is set",
+		"hedge.trigger: renamed to hedge.trigger_name",
+		`retry.jitter: boolean true converted to "full"`,
+		`retry.budget: name "payments" converted to {"name": "payments"}`,
+	}
+	if got := p.Meta.Normalization.Warnings; !reflect.DeepEqual(got, want) {
+		t.Fatalf("warnings=%q\nwant %q", got, want)
+	}
+
+	normalized, err := p.Normalize()
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	if len(normalized.Meta.Normalization.Warnings) != len(want) {
+		t.Fatalf("Normalize dropped warnings: %q", normalized.Meta.Normalization.Warnings)
+	}
+}
+
+func TestParseVersioned_Current(t *testing.T) {
+	p, err := ParseVersioned([]byte(`{"schema_version": 2, "retry": {"timeout_per_attempt": "1s", "jitter": "equal"}}`))
+	if err != nil {
+		t.Fatalf("ParseVersioned: %v", err)
+	}
+	if p.Retry.TimeoutPerAttempt != time.Second || p.Retry.Jitter != JitterEqual {
+		t.Fatalf("retry=%+v", p.Retry)
+	}
+	if w := p.Meta.Normalization.Warnings; len(w) != 0 {
+		t.Fatalf("warnings=%q, want none", w)
+	}
+}
+
+func TestParseVersioned_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		doc, want string
+	}{
+		{`[]`, "not a JSON object"},
+		{`{"schema_version": 3}`, "newer than supported version 2"},
+		{`{"schema_version": "two"}`, "invalid version"},
+		{`{"schema_version": 0}`, "invalid version"},
+		{`{"retry": {"timeout": "soon"}}`, "retry.timeout_per_attempt: invalid duration"},
+	} {
+		if _, err := ParseVersioned([]byte(tc.doc)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("ParseVersioned(%s) err=%v, want %q", tc.doc, err, tc.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"errors"
 	"time"
 )
 
@@ -36,7 +37,23 @@ func NewFromKey(key PolicyKey, opts ...Option) EffectivePolicy {
 	return normalized
 }
 
-// MaxAttempts sets the maximum number of retry attempts.
+// Build is like New but reports invalid options instead of falling back to
+// the default policy: it returns the policy's Validate errors joined, or the
+// Normalize error.
+func Build(key string, opts ...Option) (EffectivePolicy, error) {
+	p := DefaultPolicyFor(ParseKey(key))
+	for _, opt := range opts {
+		opt(&p)
+	}
+	if errs := p.Validate(); len(errs) > 0 {
+		return EffectivePolicy{}, errors.Join(errs...)
+	}
+	return p.Normalize()
+}
+
+// MaxAttempts sets the maximum number of retry attempts. Pass
+// UnlimitedAttempts to retry until the overall timeout or context deadline;
+// it requires an OverallTimeout.
 func MaxAttempts(n int) Option {
 	return func(p *EffectivePolicy) {
 		p.Retry.MaxAttempts = n
@@ -80,6 +97,15 @@ func Jitter(j JitterKind) Option {
 	}
 }
 
+// AdaptiveBackoff makes backoff adapt to sustained failures on the key: each
+// retryable failure multiplies a per-key backoff floor by BackoffMultiplier,
+// and each success decays it by InitialBackoff.
+func AdaptiveBackoff() Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.AdaptiveBackoff = true
+	}
+}
+
 // PerAttemptTimeout sets the timeout for each individual attempt.
 func PerAttemptTimeout(d time.Duration) Option {
 	return func(p *EffectivePolicy) {
@@ -87,6 +113,57 @@ func PerAttemptTimeout(d time.Duration) Option {
 	}
 }
 
+// SoftPerAttemptTimeout sets a per-attempt timeout that hedges instead of
+// cancelling: an attempt still running after d keeps running while a hedge
+// races it.
+func SoftPerAttemptTimeout(d time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.TimeoutPerAttempt = d
+		p.Retry.SoftTimeoutPerAttempt = true
+	}
+}
+
+// AutoPerAttemptTimeout derives each attempt's timeout from the remaining call
+// deadline, split across the remaining attempts after expected backoff, never
+// below floor. A TimeoutPerAttempt, if also set, caps the derived value.
+func AutoPerAttemptTimeout(floor time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.AutoTimeoutPerAttempt = true
+		p.Retry.MinTimeoutPerAttempt = floor
+	}
+}
+
+// SplitDeadline derives each attempt's timeout from the remaining call
+// deadline using split, never below floor. Use DeadlineFraction for the
+// fraction split.
+func SplitDeadline(split DeadlineSplit, floor time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.AutoTimeoutPerAttempt = true
+		p.Retry.MinTimeoutPerAttempt = floor
+		p.Retry.DeadlineSplit = split
+	}
+}
+
+// DeadlineFraction derives each attempt's timeout as fraction of the
+// remaining call deadline, never below floor.
+func DeadlineFraction(fraction float64, floor time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.AutoTimeoutPerAttempt = true
+		p.Retry.MinTimeoutPerAttempt = floor
+		p.Retry.DeadlineSplit = DeadlineSplitFraction
+		p.Retry.DeadlineFraction = fraction
+	}
+}
+
+// SkipInsufficientDeadline stops a call instead of starting an attempt that is
+// expected to outlast the context deadline, judged by the key's median attempt
+// latency or, before any is observed, by the per-attempt timeout.
+func SkipInsufficientDeadline() Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.SkipInsufficientDeadline = true
+	}
+}
+
 // OverallTimeout sets the total timeout across all attempts.
 func OverallTimeout(d time.Duration) Option {
 	return func(p *EffectivePolicy) {
@@ -94,6 +171,14 @@ func OverallTimeout(d time.Duration) Option {
 	}
 }
 
+// MaxCumulativeBackoff caps the total backoff one call sleeps across all
+// retries. A retry whose backoff would exceed the cap is not attempted.
+func MaxCumulativeBackoff(d time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.MaxCumulativeBackoff = d
+	}
+}
+
 // Classifier sets the classifier name for this policy.
 func Classifier(name string) Option {
 	return func(p *EffectivePolicy) {
@@ -115,6 +200,80 @@ func BudgetWithCost(name string, cost int) Option {
 	}
 }
 
+// AlsoBudget adds a budget that must allow retry attempts in addition to the
+// one set by Budget, for example a global budget alongside a per-key one.
+func AlsoBudget(name string) Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.Budgets = append(p.Retry.Budgets[:len(p.Retry.Budgets):len(p.Retry.Budgets)], BudgetRef{Name: name, Cost: 1})
+	}
+}
+
+// RateLimit sets the rate limiter that gates each call before its first attempt.
+func RateLimit(name string) Option {
+	return func(p *EffectivePolicy) {
+		p.RateLimit = RateLimitRef{Name: name}
+	}
+}
+
+// InjectFaults enables game-day fault injection: each attempt is delayed by
+// latency and fails with probability errorRate. It has no effect unless the
+// executor also enables fault injection.
+func InjectFaults(errorRate float64, latency time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.FaultInjection.Enabled = true
+		p.FaultInjection.ErrorRate = errorRate
+		p.FaultInjection.Latency = latency
+	}
+}
+
+// Bulkhead limits the key to maxInFlight concurrent calls. Excess calls queue
+// for up to maxWait, or are rejected immediately when maxWait is 0.
+func Bulkhead(maxInFlight int, maxWait time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Concurrency.MaxInFlight = maxInFlight
+		p.Concurrency.MaxWait = maxWait
+	}
+}
+
+// CacheResults serves the key's last successful result for ttl instead of
+// executing the operation again. Combine with CachedFallback to also serve a
+// stale result when attempts are exhausted.
+func CacheResults(ttl time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Cache.TTL = ttl
+	}
+}
+
+// StaticFallback serves the fallback value registered under name when the call fails.
+func StaticFallback(name string) Option {
+	return func(p *EffectivePolicy) {
+		p.Fallback = FallbackPolicy{Mode: FallbackStatic, Name: name}
+	}
+}
+
+// CachedFallback serves the key's last successful result, if no older than
+// maxAge (0 means no limit), when the call fails.
+func CachedFallback(maxAge time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Fallback = FallbackPolicy{Mode: FallbackCached, MaxAge: maxAge}
+	}
+}
+
+// HandlerFallback calls the fallback handler registered under name when the call fails.
+func HandlerFallback(name string) Option {
+	return func(p *EffectivePolicy) {
+		p.Fallback = FallbackPolicy{Mode: FallbackHandler, Name: name}
+	}
+}
+
+// KeyFallback runs the operation under the policy for key when the call
+// fails, such as a cache read standing in for a primary database.
+func KeyFallback(key string) Option {
+	return func(p *EffectivePolicy) {
+		p.Fallback = FallbackPolicy{Mode: FallbackKey, Key: key}
+	}
+}
+
 // PolicyID sets an identifier for this policy (useful for observability).
 func PolicyID(id string) Option {
 	return func(p *EffectivePolicy) {
@@ -122,6 +281,13 @@ func PolicyID(id string) Option {
 	}
 }
 
+// DefaultPriority sets the load-shedding priority used when the call context carries none.
+func DefaultPriority(pr Priority) Option {
+	return func(p *EffectivePolicy) {
+		p.Priority = pr
+	}
+}
+
 // EnableHedging enables hedging with default settings.
 // Note: Hedging logic might not be fully functional if hedge execution is unimplemented.
 func EnableHedging() Option {
@@ -136,6 +302,18 @@ func EnableHedging() Option {
 	}
 }
 
+// Hedging enables hedging with up to maxHedges additional attempts, each
+// spawned after delay or when the named trigger fires. An empty trigger
+// hedges on delay alone.
+func Hedging(maxHedges int, delay time.Duration, trigger string) Option {
+	return func(p *EffectivePolicy) {
+		p.Hedge.Enabled = true
+		p.Hedge.MaxHedges = maxHedges
+		p.Hedge.HedgeDelay = delay
+		p.Hedge.TriggerName = trigger
+	}
+}
+
 // HedgeMaxAttempts sets the maximum parallel attempts per retry group.
 func HedgeMaxAttempts(n int) Option {
 	return func(p *EffectivePolicy) {
@@ -152,6 +330,16 @@ func HedgeDelay(d time.Duration) Option {
 	}
 }
 
+// HedgeDelayPercentile hedges once an attempt has run longer than the key's
+// observed latency percentile, such as "p95". HedgeDelay applies until enough
+// latencies have been observed.
+func HedgeDelayPercentile(percentile string) Option {
+	return func(p *EffectivePolicy) {
+		p.Hedge.Enabled = true
+		p.Hedge.HedgeDelayPercentile = percentile
+	}
+}
+
 // HedgeTrigger sets a named trigger for hedge decisions.
 func HedgeTrigger(name string) Option {
 	return func(p *EffectivePolicy) {
@@ -167,6 +355,22 @@ func HedgeBudget(name string) Option {
 	}
 }
 
+// AlsoHedgeBudget adds a budget that must allow hedge attempts in addition to
+// the one set by HedgeBudget.
+func AlsoHedgeBudget(name string) Option {
+	return func(p *EffectivePolicy) {
+		p.Hedge.Budgets = append(p.Hedge.Budgets[:len(p.Hedge.Budgets):len(p.Hedge.Budgets)], BudgetRef{Name: name, Cost: 1})
+	}
+}
+
+// HedgeBudgetWithCost sets the budget reference for hedge attempts with a
+// custom cost.
+func HedgeBudgetWithCost(name string, cost int) Option {
+	return func(p *EffectivePolicy) {
+		p.Hedge.Budget = BudgetRef{Name: name, Cost: cost}
+	}
+}
+
 // HedgeCancelOnTerminal configures fail-fast behavior for hedges.
 func HedgeCancelOnTerminal(cancel bool) Option {
 	return func(p *EffectivePolicy) {
@@ -174,6 +378,121 @@ func HedgeCancelOnTerminal(cancel bool) Option {
 	}
 }
 
+// CircuitBreaker enables a circuit breaker that opens after threshold
+// consecutive failures and probes again after cooldown.
+func CircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Circuit.Enabled = true
+		p.Circuit.Threshold = threshold
+		p.Circuit.Cooldown = cooldown
+	}
+}
+
+// CircuitFailureRate opens the circuit when more than rate (0-1] of the calls
+// in window fail, once at least minRequests calls have been seen, instead of
+// on consecutive failures. Combine it with CircuitBreaker to set the cooldown.
+func CircuitFailureRate(rate float64, minRequests int, window time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Circuit.Enabled = true
+		p.Circuit.FailureRateThreshold = rate
+		p.Circuit.MinimumRequests = minRequests
+		p.Circuit.Window = window
+	}
+}
+
+// CircuitPerTenant keeps a separate circuit breaker per tenant (see WithTenant).
+func CircuitPerTenant() Option {
+	return func(p *EffectivePolicy) {
+		p.Circuit.Enabled = true
+		p.Circuit.PerTenant = true
+	}
+}
+
+// RetryOn retries errors matched by m regardless of the classifier.
+func RetryOn(m ErrorMatcher) Option {
+	return func(p *EffectivePolicy) {
+		p.RetryOn = m
+	}
+}
+
+// AbortOn aborts the call on errors matched by m regardless of the classifier.
+// It takes precedence over RetryOn.
+func AbortOn(m ErrorMatcher) Option {
+	return func(p *EffectivePolicy) {
+		p.AbortOn = m
+	}
+}
+
+// RetryableHTTPStatuses makes classifiers that support it (the HTTP and
+// automatic classifiers) retry exactly these statuses.
+func RetryableHTTPStatuses(statuses ...int) Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.RetryableHTTPStatuses = append([]int{}, statuses...)
+	}
+}
+
+// RetryableGRPCCodes makes classifiers that support it (the gRPC integration's
+// classifier) retry exactly these codes, given by name such as "UNAVAILABLE".
+func RetryableGRPCCodes(codes ...string) Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.RetryableGRPCCodes = append([]string{}, codes...)
+	}
+}
+
+// OnReason overrides retry behavior after retryable failures classified with
+// reason, such as "http_429". It copies the policy's existing overrides.
+func OnReason(reason string, ov ReasonOverride) Option {
+	return func(p *EffectivePolicy) {
+		overrides := make(map[string]ReasonOverride, len(p.ReasonOverrides)+1)
+		for r, o := range p.ReasonOverrides {
+			overrides[r] = o
+		}
+		overrides[reason] = ov
+		p.ReasonOverrides = overrides
+	}
+}
+
+// Rule appends a retry rule that changes failed attempts matching when, such
+// as Rule(`status == 503`, RuleRetry, 2*time.Second).
+func Rule(when string, action RuleAction, backoff time.Duration) Option {
+	return func(p *EffectivePolicy) {
+		p.Retry.Rules = append(p.Retry.Rules, RetryRule{When: when, Action: action, Backoff: backoff})
+	}
+}
+
+// Window adds a time window whose retry or hedge settings replace the policy's
+// while active. Windows are checked in the order added.
+func Window(w PolicyWindow) Option {
+	return func(p *EffectivePolicy) {
+		p.Windows = append(p.Windows[:len(p.Windows):len(p.Windows)], w)
+	}
+}
+
+// HedgeRequireIdempotent refuses to hedge calls unless the operation is marked
+// idempotent, by Idempotent or per call.
+func HedgeRequireIdempotent() Option {
+	return func(p *EffectivePolicy) {
+		p.Hedge.RequireIdempotent = true
+	}
+}
+
+// DryRun previews the policy: each call runs one attempt and records on the
+// timeline what the executor would have done next, without sleeping, retrying,
+// hedging or tripping the circuit breaker.
+func DryRun() Option {
+	return func(p *EffectivePolicy) {
+		p.DryRun = true
+	}
+}
+
+// Idempotent marks operations under the key as safe to run more than once
+// concurrently.
+func Idempotent() Option {
+	return func(p *EffectivePolicy) {
+		p.Idempotent = true
+	}
+}
+
 // --- Presets ---
 
 // ExponentialBackoff returns options for exponential backoff with equal jitter.
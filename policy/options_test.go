@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -89,6 +90,7 @@ func TestOptionSetters(t *testing.T) {
 		MaxBackoff(2*time.Second),
 		BackoffMultiplier(3),
 		Jitter(JitterFull),
+		AdaptiveBackoff(),
 		PerAttemptTimeout(5*time.Second),
 		OverallTimeout(20*time.Second),
 		Classifier("custom"),
@@ -110,6 +112,9 @@ func TestOptionSetters(t *testing.T) {
 	if p.Retry.Jitter != JitterFull {
 		t.Fatalf("jitter=%v, want %v", p.Retry.Jitter, JitterFull)
 	}
+	if !p.Retry.AdaptiveBackoff {
+		t.Fatalf("expected adaptive backoff enabled")
+	}
 	if p.Retry.TimeoutPerAttempt != 5*time.Second || p.Retry.OverallTimeout != 20*time.Second {
 		t.Fatalf("unexpected timeouts: %+v", p.Retry)
 	}
@@ -196,3 +201,108 @@ func TestBudgetOption(t *testing.T) {
 		t.Fatalf("budget=%+v, want name=budget cost=1", p.Retry.Budget)
 	}
 }
+
+func TestAutoPerAttemptTimeoutOption(t *testing.T) {
+	p := New("test.auto", AutoPerAttemptTimeout(0))
+	if !p.Retry.AutoTimeoutPerAttempt {
+		t.Fatalf("expected auto per-attempt timeout to be enabled")
+	}
+
+	n, err := p.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n.Retry.MinTimeoutPerAttempt != defaultMinAutoTimeout {
+		t.Fatalf("floor=%v, want default %v", n.Retry.MinTimeoutPerAttempt, defaultMinAutoTimeout)
+	}
+}
+
+func TestIdempotencyOptions(t *testing.T) {
+	p := New("test.idempotent", EnableHedging(), HedgeRequireIdempotent(), Idempotent())
+	if !p.Hedge.RequireIdempotent {
+		t.Fatalf("expected hedges to require idempotency")
+	}
+	if !p.Idempotent {
+		t.Fatalf("expected policy to be marked idempotent")
+	}
+}
+
+func TestPresetRegistry(t *testing.T) {
+	r := NewPresetRegistry()
+	r.Register("internal-critical", MaxAttempts(6), ExponentialBackoff(20*time.Millisecond, time.Second))
+	r.Register(" ", MaxAttempts(9))
+
+	opt, ok := r.Get("internal-critical")
+	if !ok {
+		t.Fatal("expected preset")
+	}
+	p := New("svc.Op", opt, MaxAttempts(2))
+	if p.Retry.MaxAttempts != 2 || p.Retry.InitialBackoff != 20*time.Millisecond {
+		t.Fatalf("retry=%+v", p.Retry)
+	}
+	if _, ok := r.Get("missing"); ok {
+		t.Fatal("unexpected preset")
+	}
+	if names := r.Names(); len(names) != 1 {
+		t.Fatalf("names=%v", names)
+	}
+}
+
+func TestPreset_Default(t *testing.T) {
+	if p := New("svc.Op", Preset("http")); p.Retry.ClassifierName != "http" {
+		t.Fatalf("classifier=%q, want http", p.Retry.ClassifierName)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for unknown preset")
+		}
+	}()
+	Preset("no-such-preset")
+}
+
+func TestHedgeAndCircuitOptions(t *testing.T) {
+	p := New("test.fluent",
+		Hedging(1, 50*time.Millisecond, "p90"),
+		HedgeBudgetWithCost("hedges", 2),
+		CircuitBreaker(5, 30*time.Second),
+		CircuitFailureRate(0.5, 20, time.Minute),
+		CircuitPerTenant(),
+	)
+
+	h := p.Hedge
+	if !h.Enabled || h.MaxHedges != 1 || h.HedgeDelay != 50*time.Millisecond || h.TriggerName != "p90" {
+		t.Fatalf("hedge=%+v", h)
+	}
+	if h.Budget != (BudgetRef{Name: "hedges", Cost: 2}) {
+		t.Fatalf("hedge budget=%+v", h.Budget)
+	}
+
+	c := p.Circuit
+	if !c.Enabled || c.Threshold != 5 || c.Cooldown != 30*time.Second || !c.PerTenant {
+		t.Fatalf("circuit=%+v", c)
+	}
+	if c.FailureRateThreshold != 0.5 || c.MinimumRequests != 20 || c.Window != time.Minute {
+		t.Fatalf("circuit failure rate=%+v", c)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	p, err := Build("test.build", MaxAttempts(4), CircuitBreaker(3, time.Second))
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if p.Retry.MaxAttempts != 4 || !p.Circuit.Enabled || p.Key.String() != "test.build" {
+		t.Fatalf("policy=%+v", p)
+	}
+
+	_, err = Build("test.build", Hedging(-1, time.Millisecond, ""), CircuitBreaker(-1, time.Second))
+	if err == nil {
+		t.Fatal("Build accepted invalid options")
+	}
+	for _, field := range []string{"hedge.max_hedges", "circuit.threshold"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("err=%v, want %s", err, field)
+		}
+	}
+}
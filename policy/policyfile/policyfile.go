@@ -0,0 +1,316 @@
+// Package policyfile loads keyed policies from JSON policy files.
+//
+// A policy file maps policy keys to policies:
+//
+//	{
+//	  "policies": {
+//	    "payments.Charge": {
+//	      "retry": {"max_attempts": 4, "initial_backoff": "50ms", "overall_timeout": "2s"},
+//	      "circuit": {"enabled": true, "threshold": 5, "cooldown": "30s"}
+//	    }
+//	  }
+//	}
+//
+// Policy fields use the JSON names of policy.EffectivePolicy. Durations are
+// Go duration strings such as "250ms"; plain integers are read as
+// nanoseconds. Unknown fields are rejected, and every policy must pass
+// EffectivePolicy.Normalize.
+//
+// A policy may name a preset registered in policy.DefaultPresets. The preset
+// is applied first and the policy's own fields override it:
+//
+//	"checkout.Submit": {"preset": "internal-critical", "retry": {"max_attempts": 2}}
+//
+// LoadProvider also reads key labels and selector policies (see
+// controlplane.StaticProvider.Selectors):
+//
+//	{
+//	  "labels": {"payments.Charge": {"tier": "critical"}},
+//	  "selectors": [{"selector": {"tier": "critical"}, "policy": {"retry": {"max_attempts": 5}}}],
+//	  "policies": {}
+//	}
+package policyfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/policy"
+)
+
+// FieldError reports an invalid field of one policy in a policy file. Field
+// is the dot-delimited JSON path within the policy, such as
+// "retry.initial_backoff", or empty when the policy as a whole is invalid.
+type FieldError struct {
+	Key   string
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("policyfile: policy %q: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("policyfile: policy %q: field %s: %v", e.Key, e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+type document struct {
+	Policies  map[string]json.RawMessage   `json:"policies"`
+	Labels    map[string]map[string]string `json:"labels,omitempty"`
+	Selectors []selectorEntry              `json:"selectors,omitempty"`
+}
+
+type selectorEntry struct {
+	Selector map[string]string `json:"selector"`
+	Policy   json.RawMessage   `json:"policy"`
+}
+
+// Parse parses a policy file. The returned policies are validated but not
+// normalized; providers normalize them on resolution.
+func Parse(data []byte) (map[policy.PolicyKey]policy.EffectivePolicy, error) {
+	provider, err := parseProvider(data)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Policies, nil
+}
+
+// parseProvider parses a policy file into a StaticProvider, including its
+// labels and selector policies.
+func parseProvider(data []byte) (*controlplane.StaticProvider, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var doc document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("policyfile: %w", err)
+	}
+
+	policies := make(map[policy.PolicyKey]policy.EffectivePolicy, len(doc.Policies))
+	for name, raw := range doc.Policies {
+		if strings.TrimSpace(name) == "" {
+			return nil, &FieldError{Key: name, Err: errors.New("empty policy key")}
+		}
+		key := policy.ParseKey(name)
+		pol, err := parseKeyedPolicy(name, key, raw)
+		if err != nil {
+			return nil, err
+		}
+		policies[key] = pol
+	}
+	provider := &controlplane.StaticProvider{Policies: policies}
+
+	if len(doc.Labels) > 0 {
+		provider.Labels = make(map[policy.PolicyKey]policy.Labels, len(doc.Labels))
+		for name, labels := range doc.Labels {
+			if strings.TrimSpace(name) == "" {
+				return nil, &FieldError{Key: name, Field: "labels", Err: errors.New("empty policy key")}
+			}
+			provider.Labels[policy.ParseKey(name)] = labels
+		}
+	}
+	for i, entry := range doc.Selectors {
+		name := fmt.Sprintf("selectors[%d]", i)
+		if len(entry.Selector) == 0 {
+			return nil, &FieldError{Key: name, Field: "selector", Err: errors.New("empty selector")}
+		}
+		pol, err := parseKeyedPolicy(name, policy.PolicyKey{}, entry.Policy)
+		if err != nil {
+			return nil, err
+		}
+		provider.Selectors = append(provider.Selectors, controlplane.SelectorPolicy{Selector: entry.Selector, Policy: pol})
+	}
+	return provider, nil
+}
+
+// parseKeyedPolicy parses and validates the policy stored under name.
+func parseKeyedPolicy(name string, key policy.PolicyKey, raw json.RawMessage) (policy.EffectivePolicy, error) {
+	pol, err := parsePolicy(raw)
+	if err != nil {
+		var fe *FieldError
+		if errors.As(err, &fe) {
+			fe.Key = name
+			return policy.EffectivePolicy{}, fe
+		}
+		return policy.EffectivePolicy{}, &FieldError{Key: name, Err: err}
+	}
+	pol.Key = key
+	if _, err := pol.Normalize(); err != nil {
+		var ne *policy.NormalizeError
+		if errors.As(err, &ne) {
+			return policy.EffectivePolicy{}, &FieldError{Key: name, Field: ne.Field, Err: err}
+		}
+		return policy.EffectivePolicy{}, &FieldError{Key: name, Err: err}
+	}
+	return pol, nil
+}
+
+// Load reads and parses the policy file at path.
+func Load(path string) (map[policy.PolicyKey]policy.EffectivePolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("policyfile: %w", err)
+	}
+	return Parse(data)
+}
+
+// LoadProvider loads the policy file at path, including its labels and
+// selector policies, into a StaticProvider. Keys not in the file resolve to
+// policy.DefaultPolicyFor.
+func LoadProvider(path string) (*controlplane.StaticProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("policyfile: %w", err)
+	}
+	return parseProvider(data)
+}
+
+// presetField names the preset a policy starts from.
+const presetField = "preset"
+
+// parsePolicy decodes one policy, converting duration strings and rejecting
+// unknown fields along the way.
+func parsePolicy(raw json.RawMessage) (policy.EffectivePolicy, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return policy.EffectivePolicy{}, err
+	}
+
+	var pol policy.EffectivePolicy
+	if obj, ok := tree.(map[string]any); ok {
+		if name, ok := obj[presetField]; ok {
+			delete(obj, presetField)
+			s, _ := name.(string)
+			preset, ok := policy.DefaultPresets.Get(s)
+			if !ok {
+				return policy.EffectivePolicy{}, &FieldError{Field: presetField, Err: fmt.Errorf("unknown preset %q", s)}
+			}
+			preset(&pol)
+		}
+	}
+
+	tree, err := convert(reflect.TypeOf(policy.EffectivePolicy{}), tree, "")
+	if err != nil {
+		return policy.EffectivePolicy{}, err
+	}
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return policy.EffectivePolicy{}, err
+	}
+	if err := json.Unmarshal(data, &pol); err != nil {
+		var te *json.UnmarshalTypeError
+		if errors.As(err, &te) {
+			return policy.EffectivePolicy{}, &FieldError{Field: te.Field, Err: fmt.Errorf("cannot use %s as %s", te.Value, te.Type)}
+		}
+		return policy.EffectivePolicy{}, err
+	}
+	return pol, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// convert walks v, a decoded JSON value destined for a value of type t, and
+// rewrites duration strings as nanoseconds. path is v's JSON path.
+func convert(t reflect.Type, v any, path string) (any, error) {
+	switch {
+	case t == durationType:
+		s, ok := v.(string)
+		if !ok {
+			return v, nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, &FieldError{Field: path, Err: fmt.Errorf("invalid duration %q", s)}
+		}
+		return json.Number(strconv.FormatInt(int64(d), 10)), nil
+
+	case t.Kind() == reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+		fields := jsonFields(t)
+		for name, fv := range obj {
+			field := joinPath(path, name)
+			ft, ok := fields[name]
+			if !ok {
+				return nil, &FieldError{Field: field, Err: errors.New("unknown field")}
+			}
+			converted, err := convert(ft, fv, field)
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = converted
+		}
+		return obj, nil
+
+	case t.Kind() == reflect.Map:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+		for name, ev := range obj {
+			converted, err := convert(t.Elem(), ev, joinPath(path, name))
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = converted
+		}
+		return obj, nil
+
+	case t.Kind() == reflect.Slice:
+		arr, ok := v.([]any)
+		if !ok {
+			return v, nil
+		}
+		for i, ev := range arr {
+			converted, err := convert(t.Elem(), ev, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = converted
+		}
+		return arr, nil
+	}
+	return v, nil
+}
+
+// jsonFields maps the JSON names of t's encoded fields to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,172 @@
+package policyfile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+const validFile = `{
+  "policies": {
+    "payments.Charge": {
+      "retry": {
+        "max_attempts": 4,
+        "initial_backoff": "50ms",
+        "max_backoff": "1s",
+        "overall_timeout": 2000000000,
+        "budgets": [{"name": "shared", "cost": 2}]
+      },
+      "circuit": {"enabled": true, "threshold": 5, "cooldown": "30s"},
+      "reason_overrides": {"http_429": {"min_backoff": "2s"}}
+    },
+    "search": {
+      "hedge": {"enabled": true, "hedge_delay": "20ms", "max_hedges": 1}
+    }
+  }
+}`
+
+func TestParse(t *testing.T) {
+	policies, err := Parse([]byte(validFile))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("policies=%d, want 2", len(policies))
+	}
+
+	charge := policies[policy.ParseKey("payments.Charge")]
+	if charge.Key != policy.ParseKey("payments.Charge") {
+		t.Fatalf("key=%v", charge.Key)
+	}
+	r := charge.Retry
+	if r.MaxAttempts != 4 || r.InitialBackoff != 50*time.Millisecond || r.MaxBackoff != time.Second || r.OverallTimeout != 2*time.Second {
+		t.Fatalf("retry=%+v", r)
+	}
+	if len(r.Budgets) != 1 || r.Budgets[0] != (policy.BudgetRef{Name: "shared", Cost: 2}) {
+		t.Fatalf("budgets=%+v", r.Budgets)
+	}
+	if c := charge.Circuit; !c.Enabled || c.Threshold != 5 || c.Cooldown != 30*time.Second {
+		t.Fatalf("circuit=%+v", c)
+	}
+
+	if ov := charge.ReasonOverrides["http_429"]; ov.MinBackoff != 2*time.Second {
+		t.Fatalf("reason overrides=%+v", charge.ReasonOverrides)
+	}
+
+	search := policies[policy.ParseKey("search")]
+	if h := search.Hedge; !h.Enabled || h.HedgeDelay != 20*time.Millisecond || h.MaxHedges != 1 {
+		t.Fatalf("hedge=%+v", h)
+	}
+}
+
+func TestParse_FieldErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		data  string
+		field string
+	}{
+		{"unknown field", `{"policies": {"svc.Op": {"retry": {"max_backof": "1s"}}}}`, "retry.max_backof"},
+		{"bad duration", `{"policies": {"svc.Op": {"retry": {"max_backoff": "soon"}}}}`, "retry.max_backoff"},
+		{"wrong type", `{"policies": {"svc.Op": {"retry": {"max_attempts": "four"}}}}`, "retry.max_attempts"},
+		{"nested slice", `{"policies": {"svc.Op": {"retry_on": {"grpc_codes": ["NOPE"]}}}}`, "retry_on.grpc_codes"},
+		{"normalization", `{"policies": {"svc.Op": {"priority": "urgent"}}}`, "priority"},
+		{"unknown preset", `{"policies": {"svc.Op": {"preset": "nope"}}}`, "preset"},
+		{"map value", `{"policies": {"svc.Op": {"reason_overrides": {"http_429": {"backoff": "1s"}}}}}`, "reason_overrides.http_429.backoff"},
+		{"slice element", `{"policies": {"svc.Op": {"retry": {"budgets": [{"nme": "x"}]}}}}`, "retry.budgets[0].nme"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data))
+			var fe *FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("err=%v, want *FieldError", err)
+			}
+			if fe.Key != "svc.Op" || fe.Field != tc.field {
+				t.Fatalf("key=%q field=%q, want svc.Op/%s (%v)", fe.Key, fe.Field, tc.field, err)
+			}
+		})
+	}
+}
+
+func TestParse_Preset(t *testing.T) {
+	policy.RegisterPreset("policyfile-test", policy.MaxAttempts(7), policy.Classifier("http"))
+
+	policies, err := Parse([]byte(`{"policies": {"svc.Op": {"preset": "policyfile-test", "retry": {"max_attempts": 2}}}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := policies[policy.ParseKey("svc.Op")].Retry
+	if r.MaxAttempts != 2 || r.ClassifierName != "http" {
+		t.Fatalf("retry=%+v, want explicit max_attempts over preset classifier", r)
+	}
+}
+
+func TestParse_DocumentErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"policies": `,
+		`{"polices": {}}`,
+		`{"policies": {" ": {}}}`,
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Fatalf("Parse(%s): expected error", data)
+		}
+	}
+}
+
+func TestLoadProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policies.json")
+	if err := os.WriteFile(path, []byte(validFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := LoadProvider(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pol, err := provider.GetEffectivePolicy(context.Background(), policy.ParseKey("payments.Charge"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pol.Retry.MaxAttempts != 4 || pol.Meta.Source != policy.PolicySourceStatic {
+		t.Fatalf("policy=%+v", pol)
+	}
+
+	if _, err := LoadProvider(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("err=%v, want os.ErrNotExist", err)
+	}
+}
+
+func TestLoadProvider_Selectors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policies.json")
+	data := `{
+	  "labels": {"payments.Charge": {"tier": "critical"}},
+	  "selectors": [{"selector": {"tier": "critical"}, "policy": {"retry": {"max_attempts": 5, "initial_backoff": "20ms"}}}],
+	  "policies": {}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := LoadProvider(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pol, err := provider.GetEffectivePolicy(context.Background(), policy.ParseKey("payments.Charge"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pol.Retry.MaxAttempts != 5 || pol.Retry.InitialBackoff != 20*time.Millisecond || pol.Meta.Selector != "tier=critical" {
+		t.Fatalf("policy=%+v", pol)
+	}
+
+	_, err = Parse([]byte(`{"selectors": [{"selector": {}, "policy": {}}], "policies": {}}`))
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Key != "selectors[0]" || fe.Field != "selector" {
+		t.Fatalf("err=%v, want empty selector error", err)
+	}
+}
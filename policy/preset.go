@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PresetRegistry is a thread-safe name → preset map. A preset is a list of
+// options applied together, such as a platform team's "internal-critical"
+// settings, so policies and policy files can refer to it by name.
+type PresetRegistry struct {
+	mu sync.RWMutex
+	m  map[string][]Option
+}
+
+// NewPresetRegistry returns an empty registry.
+func NewPresetRegistry() *PresetRegistry {
+	return &PresetRegistry{}
+}
+
+// DefaultPresets is the registry used by Preset and by policy files. It starts
+// with the built-in presets "http", "database", "background_job" and
+// "low_latency".
+var DefaultPresets = newBuiltinPresets()
+
+func newBuiltinPresets() *PresetRegistry {
+	r := NewPresetRegistry()
+	r.Register("http", HTTPDefaults())
+	r.Register("database", DatabaseDefaults())
+	r.Register("background_job", BackgroundJobDefaults())
+	r.Register("low_latency", LowLatencyDefaults())
+	return r
+}
+
+// RegisterPreset registers a preset in DefaultPresets.
+func RegisterPreset(name string, opts ...Option) {
+	DefaultPresets.Register(name, opts...)
+}
+
+// Register associates name with opts, replacing any preset of that name.
+// Empty names are ignored.
+func (r *PresetRegistry) Register(name string, opts ...Option) {
+	if r == nil {
+		return
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.m == nil {
+		r.m = make(map[string][]Option)
+	}
+	r.m[name] = append([]Option(nil), opts...)
+}
+
+// Get returns the named preset as a single Option.
+func (r *PresetRegistry) Get(name string) (Option, bool) {
+	if r == nil {
+		return nil, false
+	}
+	name = strings.TrimSpace(name)
+
+	r.mu.RLock()
+	opts, ok := r.m[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return func(p *EffectivePolicy) {
+		for _, opt := range opts {
+			if opt != nil {
+				opt(p)
+			}
+		}
+	}, true
+}
+
+// Names returns the registered preset names in no particular order.
+func (r *PresetRegistry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.m))
+	for name := range r.m {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Preset returns the named preset from DefaultPresets. Pass it before other
+// options so explicit fields override the preset. Preset panics if no preset
+// of that name is registered; register presets during initialization.
+func Preset(name string) Option {
+	opt, ok := DefaultPresets.Get(name)
+	if !ok {
+		panic(fmt.Sprintf("policy: unknown preset %q", name))
+	}
+	return opt
+}
@@ -0,0 +1,45 @@
+package policy
+
+import "context"
+
+// Priority ranks a call for load shedding. Under pressure, budgets and circuit
+// breakers shed lower-priority attempts first so interactive traffic keeps
+// retrying while batch traffic backs off.
+//
+// The zero value is treated as PriorityNormal.
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityNormal Priority = "normal"
+	PriorityHigh   Priority = "high"
+)
+
+// Rank orders priorities: -1 for low, 0 for normal (and unset), 1 for high.
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityLow:
+		return -1
+	case PriorityHigh:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a context derived from ctx that carries p.
+// A priority on the context takes precedence over EffectivePolicy.Priority.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the Priority from ctx, if present.
+func PriorityFromContext(ctx context.Context) (Priority, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	p, ok := ctx.Value(priorityKey{}).(Priority)
+	return p, ok
+}
@@ -0,0 +1,40 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPriorityContextRoundTrip(t *testing.T) {
+	if _, ok := PriorityFromContext(context.Background()); ok {
+		t.Fatalf("expected no priority on background context")
+	}
+	ctx := WithPriority(context.Background(), PriorityHigh)
+	if p, ok := PriorityFromContext(ctx); !ok || p != PriorityHigh {
+		t.Fatalf("priority=%q ok=%v, want high", p, ok)
+	}
+}
+
+func TestPriorityRank(t *testing.T) {
+	if PriorityLow.Rank() >= Priority("").Rank() || Priority("").Rank() != PriorityNormal.Rank() || PriorityNormal.Rank() >= PriorityHigh.Rank() {
+		t.Fatalf("unexpected ranks: low=%d unset=%d normal=%d high=%d",
+			PriorityLow.Rank(), Priority("").Rank(), PriorityNormal.Rank(), PriorityHigh.Rank())
+	}
+}
+
+func TestEffectivePolicyNormalize_Priority(t *testing.T) {
+	pol, err := New("svc.op", DefaultPriority(PriorityLow)).Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pol.Priority != PriorityLow {
+		t.Fatalf("priority=%q, want low", pol.Priority)
+	}
+
+	_, err = EffectivePolicy{Priority: "urgent"}.Normalize()
+	var nerr *NormalizeError
+	if !errors.As(err, &nerr) || nerr.Field != "priority" {
+		t.Fatalf("err=%v, want NormalizeError for priority", err)
+	}
+}
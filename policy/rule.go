@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aponysus/recourse/internal/expr"
+)
+
+// RuleVariables returns the variables RetryRule expressions may use:
+//
+//	status      int     HTTP status code of the attempt error, 0 if none
+//	grpc_code   string  gRPC code such as "UNAVAILABLE", "" if none
+//	reason      string  outcome reason, such as "http_503"
+//	kind        string  outcome kind: "retryable", "non_retryable", "abort" or "unknown"
+//	attributes  map     outcome attributes; missing keys read as ""
+func RuleVariables() []string {
+	return []string{"status", "grpc_code", "reason", "kind", "attributes"}
+}
+
+// ruleVariableTypes declares the types of the RuleVariables.
+var ruleVariableTypes = map[string]expr.Type{
+	"status":     expr.Int,
+	"grpc_code":  expr.String,
+	"reason":     expr.String,
+	"kind":       expr.String,
+	"attributes": expr.Map,
+}
+
+// checkRuleExpression reports whether when compiles and type-checks against
+// the RuleVariables.
+func checkRuleExpression(when string) error {
+	_, err := expr.Compile(when, ruleVariableTypes)
+	return err
+}
+
+// normalizeRetryRules trims and lower-cases rule actions, rejects empty or
+// malformed expressions and unknown actions, and caps backoffs at the backoff
+// ceiling. It never modifies rules in place.
+func normalizeRetryRules(field string, rules []RetryRule, markChanged func(string)) ([]RetryRule, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	out := make([]RetryRule, len(rules))
+	for i, r := range rules {
+		prefix := fmt.Sprintf("%s[%d]", field, i)
+		r.When = strings.TrimSpace(r.When)
+		if r.When == "" || checkRuleExpression(r.When) != nil {
+			return nil, &NormalizeError{Field: prefix + ".when", Value: rules[i].When}
+		}
+		r.Action = RuleAction(strings.ToLower(strings.TrimSpace(string(r.Action))))
+		switch r.Action {
+		case "", RuleRetry, RuleAbort:
+		default:
+			return nil, &NormalizeError{Field: prefix + ".action", Value: string(rules[i].Action)}
+		}
+		if r.Backoff < 0 {
+			r.Backoff = 0
+			markChanged(prefix + ".backoff")
+		} else if r.Backoff > maxBackoffCeiling {
+			r.Backoff = maxBackoffCeiling
+			markChanged(prefix + ".backoff")
+		}
+		out[i] = r
+	}
+	return out, nil
+}
@@ -0,0 +1,46 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizeRetryRules(t *testing.T) {
+	p, err := New("svc.Op",
+		Rule(`  status == 503 `, " Retry ", -time.Second),
+		Rule(`grpc_code == "UNAVAILABLE"`, RuleAbort, time.Hour),
+	).Normalize()
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	got := p.Retry.Rules
+	if got[0].When != "status == 503" || got[0].Action != RuleRetry || got[0].Backoff != 0 {
+		t.Fatalf("rule 0 = %+v, want trimmed retry rule without backoff", got[0])
+	}
+	if got[1].Backoff != maxBackoffCeiling {
+		t.Fatalf("rule 1 backoff = %v, want %v", got[1].Backoff, maxBackoffCeiling)
+	}
+
+	for _, tc := range []struct {
+		rule  RetryRule
+		field string
+	}{
+		{RetryRule{When: ""}, "retry.rules[0].when"},
+		{RetryRule{When: "code == 1"}, "retry.rules[0].when"},
+		{RetryRule{When: "status ==="}, "retry.rules[0].when"},
+		{RetryRule{When: `status < "a"`}, "retry.rules[0].when"},
+		{RetryRule{When: `status in [1, "a"]`}, "retry.rules[0].when"},
+		{RetryRule{When: "status == 1", Action: "skip"}, "retry.rules[0].action"},
+	} {
+		p := EffectivePolicy{Retry: RetryPolicy{Rules: []RetryRule{tc.rule}}}
+		_, err := p.Normalize()
+		var ne *NormalizeError
+		if !errors.As(err, &ne) || ne.Field != tc.field {
+			t.Errorf("rule %+v: err=%v, want NormalizeError on %s", tc.rule, err, tc.field)
+		}
+		if errs := p.Validate(); len(errs) != 1 {
+			t.Errorf("rule %+v: Validate=%v, want one error", tc.rule, errs)
+		}
+	}
+}
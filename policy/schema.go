@@ -1,6 +1,8 @@
 package policy
 
 import (
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,6 +12,27 @@ const (
 	JitterNone  JitterKind = "none"
 	JitterFull  JitterKind = "full"
 	JitterEqual JitterKind = "equal"
+
+	// JitterDecorrelated sleeps a random duration between InitialBackoff and
+	// three times the previous sleep, capped at MaxBackoff.
+	JitterDecorrelated JitterKind = "decorrelated"
+)
+
+// DeadlineSplit selects how AutoTimeoutPerAttempt derives each attempt's
+// timeout from the remaining call deadline.
+type DeadlineSplit string
+
+const (
+	// DeadlineSplitEven splits the remaining deadline, less the backoff still
+	// expected, evenly across the remaining attempts. It is the default.
+	DeadlineSplitEven DeadlineSplit = "even"
+
+	// DeadlineSplitRemaining gives each attempt the whole remaining deadline.
+	DeadlineSplitRemaining DeadlineSplit = "remaining"
+
+	// DeadlineSplitFraction gives each attempt DeadlineFraction of the
+	// remaining deadline.
+	DeadlineSplitFraction DeadlineSplit = "fraction"
 )
 
 type BudgetRef struct {
@@ -17,18 +40,120 @@ type BudgetRef struct {
 	Cost int    `json:"cost,omitempty"` // Units consumed per attempt (min 1).
 }
 
+type RateLimitRef struct {
+	Name string `json:"name"` // Rate limiter registry name.
+}
+
+// UnlimitedAttempts as RetryPolicy.MaxAttempts retries until the call's
+// OverallTimeout or context deadline ends it, for example while waiting for a
+// dependency at startup. Normalize rejects it without an OverallTimeout.
+const UnlimitedAttempts = -1
+
 type RetryPolicy struct {
-	MaxAttempts       int           `json:"max_attempts"`        // Maximum attempts per call.
+	MaxAttempts       int           `json:"max_attempts"`        // Maximum attempts per call (UnlimitedAttempts for no limit).
 	InitialBackoff    time.Duration `json:"initial_backoff"`     // Starting backoff before retries.
 	MaxBackoff        time.Duration `json:"max_backoff"`         // Upper bound for backoff delays.
 	BackoffMultiplier float64       `json:"backoff_multiplier"`  // Exponential backoff multiplier.
 	Jitter            JitterKind    `json:"jitter"`              // Backoff jitter strategy.
 
+	AdaptiveBackoff bool `json:"adaptive_backoff,omitempty"` // Grow backoff per key on retryable failures and decay it on success.
+
 	TimeoutPerAttempt time.Duration `json:"timeout_per_attempt"` // Per-attempt timeout (0 disables).
+
+	AutoTimeoutPerAttempt bool          `json:"auto_timeout_per_attempt,omitempty"` // Derive per-attempt timeouts from the remaining deadline.
+	MinTimeoutPerAttempt  time.Duration `json:"min_timeout_per_attempt,omitempty"`  // Floor for derived per-attempt timeouts.
+	DeadlineSplit         DeadlineSplit `json:"deadline_split,omitempty"`           // How derived timeouts divide the remaining deadline (default even).
+	DeadlineFraction      float64       `json:"deadline_fraction,omitempty"`        // Share of the remaining deadline per attempt under the fraction split (default 0.5).
+	SoftTimeoutPerAttempt bool          `json:"soft_timeout_per_attempt,omitempty"` // Hedge slow attempts at the per-attempt timeout instead of cancelling them.
+
+	SkipInsufficientDeadline bool `json:"skip_insufficient_deadline,omitempty"` // Skip attempts expected to outlast the context deadline.
+
 	OverallTimeout    time.Duration `json:"overall_timeout"`     // Total timeout for all attempts (0 disables).
 
-	ClassifierName string    `json:"classifier_name,omitempty"` // Classifier registry name.
-	Budget         BudgetRef `json:"budget,omitempty"`          // Budget gating for retry attempts.
+	MaxCumulativeBackoff time.Duration `json:"max_cumulative_backoff,omitempty"` // Total backoff one call may sleep across retries (0 disables).
+
+	ClassifierName string      `json:"classifier_name,omitempty"` // Classifier registry name.
+
+	RetryableHTTPStatuses []int    `json:"retryable_http_statuses,omitempty"` // Replaces the HTTP classifier's retryable statuses.
+	RetryableGRPCCodes    []string `json:"retryable_grpc_codes,omitempty"`    // Replaces the gRPC classifier's retryable codes (e.g. "UNAVAILABLE").
+
+	Rules []RetryRule `json:"rules,omitempty"` // Expression rules applied to failed attempts; the first match wins.
+
+	Budget         BudgetRef   `json:"budget,omitempty"`          // Budget gating for retry attempts.
+	Budgets        []BudgetRef `json:"budgets,omitempty"`         // Additional budgets that must all allow each retry attempt.
+}
+
+// IsZero reports whether p is the zero RetryPolicy.
+func (p RetryPolicy) IsZero() bool {
+	return p.MaxAttempts == 0 &&
+		p.InitialBackoff == 0 &&
+		p.MaxBackoff == 0 &&
+		p.BackoffMultiplier == 0 &&
+		p.Jitter == "" &&
+		!p.AdaptiveBackoff &&
+		p.TimeoutPerAttempt == 0 &&
+		!p.AutoTimeoutPerAttempt &&
+		p.MinTimeoutPerAttempt == 0 &&
+		p.DeadlineSplit == "" &&
+		p.DeadlineFraction == 0 &&
+		!p.SoftTimeoutPerAttempt &&
+		!p.SkipInsufficientDeadline &&
+		p.OverallTimeout == 0 &&
+		p.MaxCumulativeBackoff == 0 &&
+		p.ClassifierName == "" &&
+		p.RetryableHTTPStatuses == nil &&
+		p.RetryableGRPCCodes == nil &&
+		len(p.Rules) == 0 &&
+		p.Budget == (BudgetRef{}) &&
+		len(p.Budgets) == 0
+}
+
+// RuleAction is what a RetryRule does to a matching outcome.
+type RuleAction string
+
+const (
+	RuleRetry RuleAction = "retry" // Retry the failure.
+	RuleAbort RuleAction = "abort" // End the call.
+)
+
+// RetryRule changes the outcome of a failed attempt when its When expression
+// holds. Expressions use a CEL-like syntax over the variables listed by
+// RuleVariables, such as `status == 503 || grpc_code == "UNAVAILABLE"`.
+type RetryRule struct {
+	When    string        `json:"when"`              // Boolean expression over the attempt's outcome.
+	Action  RuleAction    `json:"action,omitempty"`  // "retry" or "abort"; empty keeps the classified outcome.
+	Backoff time.Duration `json:"backoff,omitempty"` // Backoff before the next attempt after a match (0 keeps the policy backoff).
+}
+
+// ErrorMatcher selects attempt errors for EffectivePolicy.RetryOn and AbortOn.
+type ErrorMatcher struct {
+	Errors       []string `json:"errors,omitempty"`        // Named sentinel errors registered with the executor, matched with errors.Is.
+	HTTPStatuses []int    `json:"http_statuses,omitempty"` // HTTP status codes of errors implementing classify.HTTPError.
+	GRPCCodes    []string `json:"grpc_codes,omitempty"`    // gRPC code names such as "UNAVAILABLE".
+}
+
+// IsZero reports whether m matches nothing.
+func (m ErrorMatcher) IsZero() bool {
+	return len(m.Errors) == 0 && len(m.HTTPStatuses) == 0 && len(m.GRPCCodes) == 0
+}
+
+// ReasonOverride adjusts retries after a retryable failure with a given
+// outcome reason, applied after classification and RetryOn/AbortOn.
+type ReasonOverride struct {
+	Abort       bool          `json:"abort,omitempty"`        // End the call instead of retrying.
+	MaxAttempts int           `json:"max_attempts,omitempty"` // Stop once this many attempts have run (0 keeps Retry.MaxAttempts).
+	MinBackoff  time.Duration `json:"min_backoff,omitempty"`  // Floor for the backoff before the next attempt.
+}
+
+// PolicyWindow replaces a policy's retry or hedge settings during a recurring
+// daily time window, such as stricter retries during business hours.
+type PolicyWindow struct {
+	Name  string      `json:"name"`            // Window name recorded in the timeline while active.
+	Days  []string    `json:"days,omitempty"`  // Weekdays the window starts on ("mon" to "sun"); empty means every day.
+	Start string      `json:"start"`           // Start time of day ("15:04") in the executor clock's time zone.
+	End   string      `json:"end"`             // End time of day, exclusive; earlier than Start for windows spanning midnight.
+	Retry RetryPolicy `json:"retry,omitempty"` // Replaces Retry while active (zero keeps the policy's).
+	Hedge HedgePolicy `json:"hedge,omitempty"` // Replaces Hedge while active (zero keeps the policy's).
 }
 
 type HedgePolicy struct {
@@ -36,14 +161,67 @@ type HedgePolicy struct {
 	MaxHedges             int           `json:"max_hedges"`                  // Maximum additional hedged attempts.
 	HedgeDelay            time.Duration `json:"hedge_delay"`                 // Delay before spawning a hedge.
 	TriggerName           string        `json:"trigger_name,omitempty"`      // Optional dynamic trigger name.
+	HedgeDelayPercentile  string        `json:"hedge_delay_percentile,omitempty"` // Hedge at this latency percentile of the key ("p50", "p90", "p95", "p99"); HedgeDelay applies until enough samples exist.
 	CancelOnFirstTerminal bool          `json:"cancel_on_first_terminal"`    // Cancel on any terminal outcome.
 	Budget                BudgetRef     `json:"budget,omitempty"`            // Budget gating for hedged attempts.
+	Budgets               []BudgetRef   `json:"budgets,omitempty"`           // Additional budgets that must all allow each hedged attempt.
+	RequireIdempotent     bool          `json:"require_idempotent,omitempty"` // Only hedge operations marked idempotent.
+}
+
+// IsZero reports whether p is the zero HedgePolicy.
+func (p HedgePolicy) IsZero() bool {
+	return !p.Enabled &&
+		p.MaxHedges == 0 &&
+		p.HedgeDelay == 0 &&
+		p.TriggerName == "" &&
+		p.HedgeDelayPercentile == "" &&
+		!p.CancelOnFirstTerminal &&
+		p.Budget == (BudgetRef{}) &&
+		len(p.Budgets) == 0 &&
+		!p.RequireIdempotent
 }
 
 type CircuitPolicy struct {
-	Enabled   bool          `json:"enabled"`   // Enable circuit breaking for this key.
-	Threshold int           `json:"threshold"` // Consecutive failures to open the circuit.
-	Cooldown  time.Duration `json:"cooldown"`  // Cooldown before a half-open probe.
+	Enabled   bool          `json:"enabled"`              // Enable circuit breaking for this key.
+	Threshold int           `json:"threshold"`            // Consecutive failures to open the circuit.
+	Cooldown  time.Duration `json:"cooldown"`             // Cooldown before a half-open probe.
+	PerTenant bool          `json:"per_tenant,omitempty"` // Keep a separate breaker per tenant (see policy.WithTenant).
+
+	FailureRateThreshold float64       `json:"failure_rate_threshold,omitempty"` // Open when this fraction (0-1] of calls in Window fail, instead of on consecutive failures.
+	MinimumRequests      int           `json:"minimum_requests,omitempty"`       // Calls in Window required before the failure rate can open the circuit.
+	Window               time.Duration `json:"window,omitempty"`                 // Sliding window the failure rate is measured over.
+}
+
+type ConcurrencyPolicy struct {
+	MaxInFlight int           `json:"max_in_flight,omitempty"` // Maximum concurrent calls per key (0 disables the bulkhead).
+	MaxWait     time.Duration `json:"max_wait,omitempty"`      // How long excess calls queue for a slot (0 rejects immediately).
+}
+
+type CachePolicy struct {
+	TTL time.Duration `json:"ttl,omitempty"` // Serve the key's last successful result for this long without executing (0 disables).
+}
+
+type FaultInjectionPolicy struct {
+	Enabled   bool          `json:"enabled"`              // Enable fault injection; the executor must also opt in.
+	ErrorRate float64       `json:"error_rate,omitempty"` // Fraction of attempts (0-1) failed without calling the operation.
+	Latency   time.Duration `json:"latency,omitempty"`    // Delay added before each attempt.
+	Reason    string        `json:"reason,omitempty"`     // Outcome reason recorded for injected failures.
+}
+
+type FallbackMode string
+
+const (
+	FallbackStatic  FallbackMode = "static"  // Serve a registered static value.
+	FallbackCached  FallbackMode = "cached"  // Serve the key's last successful result.
+	FallbackHandler FallbackMode = "handler" // Call a registered fallback handler.
+	FallbackKey     FallbackMode = "key"     // Run the operation again under another policy key.
+)
+
+type FallbackPolicy struct {
+	Mode   FallbackMode  `json:"mode,omitempty"`    // Fallback source (empty disables fallback).
+	Name   string        `json:"name,omitempty"`    // Fallback registry name (static and handler modes).
+	MaxAge time.Duration `json:"max_age,omitempty"` // Maximum age of a cached result (0 means no limit).
+	Key    string        `json:"key,omitempty"`     // Policy key the operation runs under (key mode), such as "cache.Read".
 }
 
 type PolicySource string
@@ -57,13 +235,18 @@ const (
 )
 
 type NormalizationInfo struct {
-	Changed       bool     `json:"-"` // Whether normalization changed any field.
-	ChangedFields []string `json:"-"` // Dot-delimited field paths that were changed.
+	Changed       bool     `json:"changed,omitempty"`        // Whether normalization changed any field.
+	ChangedFields []string `json:"changed_fields,omitempty"` // Dot-delimited field paths that were changed.
+	Warnings      []string `json:"warnings,omitempty"`       // Schema migration notes from ParseVersioned, such as renamed fields.
 }
 
 type Metadata struct {
-	Source        PolicySource      `json:"-"` // Policy resolution source.
-	Normalization NormalizationInfo `json:"-"` // Normalization metadata.
+	Source        PolicySource      `json:"source,omitempty"`        // Policy resolution source.
+	MatchedKey    PolicyKey         `json:"matched_key,omitempty"`   // Key or wildcard pattern the provider found the policy under.
+	Selector      string            `json:"selector,omitempty"`      // Label selector the policy matched by, if any (see Selector.String).
+	Version       uint64            `json:"version,omitempty"`       // Monotonic policy version set by the policy's author (0 if unversioned).
+	Revision      string            `json:"revision,omitempty"`      // Opaque revision identifier, such as a commit or rollout ID.
+	Normalization NormalizationInfo `json:"normalization,omitempty"` // Normalization metadata.
 }
 
 type EffectivePolicy struct {
@@ -73,7 +256,37 @@ type EffectivePolicy struct {
 	Hedge   HedgePolicy   `json:"hedge"`         // Hedging configuration.
 	Circuit CircuitPolicy `json:"circuit"`       // Circuit breaker configuration.
 
-	Meta Metadata `json:"-"` // Resolution metadata (source, normalization).
+	RateLimit RateLimitRef `json:"rate_limit,omitempty"` // Client-side rate limiter gating each call before its first attempt.
+	Priority  Priority     `json:"priority,omitempty"`   // Default load-shedding priority (low, normal, high); a context priority overrides it.
+
+	FaultInjection FaultInjectionPolicy `json:"fault_injection,omitempty"` // Game-day failure injection applied before each attempt.
+	Fallback       FallbackPolicy       `json:"fallback,omitempty"`        // Degraded response when attempts are exhausted or the circuit is open.
+
+	Concurrency ConcurrencyPolicy `json:"concurrency,omitempty"` // Per-key bulkhead limiting concurrent calls.
+	Cache       CachePolicy       `json:"cache,omitempty"`       // Per-key memoization of successful results.
+
+	Idempotent bool `json:"idempotent,omitempty"` // Operations under this key are safe to run concurrently or repeat.
+
+	DryRun bool `json:"dry_run,omitempty"` // Run only the first attempt and record the retry, hedge and circuit decisions that would follow.
+
+	RetryOn ErrorMatcher `json:"retry_on,omitempty"` // Errors retried regardless of the classifier.
+	AbortOn ErrorMatcher `json:"abort_on,omitempty"` // Errors that abort the call regardless of the classifier; takes precedence over RetryOn.
+
+	ReasonOverrides map[string]ReasonOverride `json:"reason_overrides,omitempty"` // Retry behavior by outcome reason (e.g. "http_429").
+
+	Windows []PolicyWindow `json:"windows,omitempty"` // Time windows that replace Retry or Hedge while active; the first match wins.
+
+	Meta Metadata `json:"meta,omitempty"` // Resolution metadata (source, normalization).
+}
+
+// IsZero reports whether p sets no key, ID, retry or hedge configuration.
+// Providers and executors treat such a policy as unset and use
+// DefaultPolicyFor instead.
+func (p EffectivePolicy) IsZero() bool {
+	return p.Key == (PolicyKey{}) &&
+		p.ID == "" &&
+		p.Retry.IsZero() &&
+		p.Hedge.IsZero()
 }
 
 func DefaultPolicyFor(key PolicyKey) EffectivePolicy {
@@ -122,6 +335,17 @@ const (
 	maxBackoffMultiplier = 10.0
 	minCircuitThreshold  = 1
 	minCircuitCooldown   = 100 * time.Millisecond
+	minCircuitWindow     = 100 * time.Millisecond
+
+	defaultCircuitMinimumRequests = 20
+	defaultCircuitWindow          = 30 * time.Second
+
+	defaultMinAutoTimeout    = 10 * time.Millisecond
+	defaultDeadlineFraction = 0.5
+
+	maxFaultLatency    = 30 * time.Second
+	maxConcurrencyWait = 30 * time.Second
+	defaultFaultReason = "fault_injected"
 )
 
 func (p EffectivePolicy) Normalize() (EffectivePolicy, error) {
@@ -142,7 +366,7 @@ func (p EffectivePolicy) Normalize() (EffectivePolicy, error) {
 		normalized.Retry.MaxAttempts = 3
 		markChanged("retry.max_attempts")
 	}
-	if normalized.Retry.MaxAttempts < 1 {
+	if normalized.Retry.MaxAttempts < 1 && normalized.Retry.MaxAttempts != UnlimitedAttempts {
 		normalized.Retry.MaxAttempts = 1
 		markChanged("retry.max_attempts")
 	} else if normalized.Retry.MaxAttempts > maxRetryAttempts {
@@ -188,9 +412,11 @@ func (p EffectivePolicy) Normalize() (EffectivePolicy, error) {
 	case "":
 		normalized.Retry.Jitter = JitterNone
 		markChanged("retry.jitter")
-	case JitterNone, JitterFull, JitterEqual:
+	case JitterNone, JitterFull, JitterEqual, JitterDecorrelated:
 	default:
-		return EffectivePolicy{}, &NormalizeError{Field: "retry.jitter", Value: string(normalized.Retry.Jitter)}
+		if _, ok := DefaultJitters.Get(normalized.Retry.Jitter); !ok {
+			return EffectivePolicy{}, &NormalizeError{Field: "retry.jitter", Value: string(normalized.Retry.Jitter)}
+		}
 	}
 
 	if normalized.Retry.TimeoutPerAttempt < 0 {
@@ -202,6 +428,32 @@ func (p EffectivePolicy) Normalize() (EffectivePolicy, error) {
 		markChanged("retry.timeout_per_attempt")
 	}
 
+	if normalized.Retry.AutoTimeoutPerAttempt && normalized.Retry.MinTimeoutPerAttempt <= 0 {
+		normalized.Retry.MinTimeoutPerAttempt = defaultMinAutoTimeout
+		markChanged("retry.min_timeout_per_attempt")
+	}
+	if normalized.Retry.MinTimeoutPerAttempt > 0 && normalized.Retry.MinTimeoutPerAttempt < minTimeoutFloor {
+		normalized.Retry.MinTimeoutPerAttempt = minTimeoutFloor
+		markChanged("retry.min_timeout_per_attempt")
+	}
+	normalized.Retry.DeadlineSplit = DeadlineSplit(strings.ToLower(strings.TrimSpace(string(normalized.Retry.DeadlineSplit))))
+	switch normalized.Retry.DeadlineSplit {
+	case "", DeadlineSplitEven, DeadlineSplitRemaining, DeadlineSplitFraction:
+	default:
+		return EffectivePolicy{}, &NormalizeError{Field: "retry.deadline_split", Value: string(normalized.Retry.DeadlineSplit)}
+	}
+	if normalized.Retry.DeadlineFraction < 0 || normalized.Retry.DeadlineFraction > 1 {
+		return EffectivePolicy{}, &NormalizeError{Field: "retry.deadline_fraction", Value: strconv.FormatFloat(normalized.Retry.DeadlineFraction, 'g', -1, 64)}
+	}
+	if normalized.Retry.DeadlineSplit == DeadlineSplitFraction && normalized.Retry.DeadlineFraction == 0 {
+		normalized.Retry.DeadlineFraction = defaultDeadlineFraction
+		markChanged("retry.deadline_fraction")
+	}
+	if normalized.Retry.SoftTimeoutPerAttempt && normalized.Retry.TimeoutPerAttempt == 0 && !normalized.Retry.AutoTimeoutPerAttempt {
+		normalized.Retry.SoftTimeoutPerAttempt = false
+		markChanged("retry.soft_timeout_per_attempt")
+	}
+
 	if normalized.Retry.OverallTimeout < 0 {
 		normalized.Retry.OverallTimeout = 0
 		markChanged("retry.overall_timeout")
@@ -210,6 +462,13 @@ func (p EffectivePolicy) Normalize() (EffectivePolicy, error) {
 		normalized.Retry.OverallTimeout = minTimeoutFloor
 		markChanged("retry.overall_timeout")
 	}
+	if normalized.Retry.MaxAttempts == UnlimitedAttempts && normalized.Retry.OverallTimeout == 0 {
+		return EffectivePolicy{}, &NormalizeError{Field: "retry.max_attempts", Value: strconv.Itoa(UnlimitedAttempts)}
+	}
+	if normalized.Retry.MaxCumulativeBackoff < 0 {
+		normalized.Retry.MaxCumulativeBackoff = 0
+		markChanged("retry.max_cumulative_backoff")
+	}
 
 	if normalized.Retry.Budget.Cost == 0 {
 		normalized.Retry.Budget.Cost = 1
@@ -229,52 +488,224 @@ func (p EffectivePolicy) Normalize() (EffectivePolicy, error) {
 		markChanged("hedge.budget.cost")
 	}
 
-	if !normalized.Hedge.Enabled {
-		return normalized, nil
+	if normalized.FaultInjection.ErrorRate < 0 {
+		normalized.FaultInjection.ErrorRate = 0
+		markChanged("fault_injection.error_rate")
+	} else if normalized.FaultInjection.ErrorRate > 1 {
+		normalized.FaultInjection.ErrorRate = 1
+		markChanged("fault_injection.error_rate")
 	}
-
-	if normalized.Hedge.MaxHedges == 0 {
-		normalized.Hedge.MaxHedges = 2
-		markChanged("hedge.max_hedges")
+	if normalized.FaultInjection.Latency < 0 {
+		normalized.FaultInjection.Latency = 0
+		markChanged("fault_injection.latency")
+	} else if normalized.FaultInjection.Latency > maxFaultLatency {
+		normalized.FaultInjection.Latency = maxFaultLatency
+		markChanged("fault_injection.latency")
 	}
-	if normalized.Hedge.MaxHedges < 1 {
-		normalized.Hedge.MaxHedges = 1
-		markChanged("hedge.max_hedges")
-	} else if normalized.Hedge.MaxHedges > maxHedges {
-		normalized.Hedge.MaxHedges = maxHedges
-		markChanged("hedge.max_hedges")
+	if normalized.FaultInjection.Enabled && normalized.FaultInjection.Reason == "" {
+		normalized.FaultInjection.Reason = defaultFaultReason
+		markChanged("fault_injection.reason")
 	}
 
-	if normalized.Hedge.HedgeDelay <= 0 {
-		normalized.Hedge.HedgeDelay = 200 * time.Millisecond
-		markChanged("hedge.hedge_delay")
+	switch normalized.Fallback.Mode {
+	case "", FallbackCached:
+	case FallbackStatic, FallbackHandler:
+		if normalized.Fallback.Name == "" {
+			return EffectivePolicy{}, &NormalizeError{Field: "fallback.name", Value: ""}
+		}
+	case FallbackKey:
+		normalized.Fallback.Key = strings.TrimSpace(normalized.Fallback.Key)
+		if normalized.Fallback.Key == "" || ParseKey(normalized.Fallback.Key) == normalized.Key {
+			return EffectivePolicy{}, &NormalizeError{Field: "fallback.key", Value: normalized.Fallback.Key}
+		}
+	default:
+		return EffectivePolicy{}, &NormalizeError{Field: "fallback.mode", Value: string(normalized.Fallback.Mode)}
+	}
+	if normalized.Fallback.MaxAge < 0 {
+		normalized.Fallback.MaxAge = 0
+		markChanged("fallback.max_age")
 	}
-	if normalized.Hedge.HedgeDelay < minHedgeDelayFloor {
-		normalized.Hedge.HedgeDelay = minHedgeDelayFloor
-		markChanged("hedge.hedge_delay")
+
+	if normalized.Cache.TTL < 0 {
+		normalized.Cache.TTL = 0
+		markChanged("cache.ttl")
 	}
 
-	if !normalized.Circuit.Enabled {
-		return normalized, nil
+	if normalized.Concurrency.MaxInFlight < 0 {
+		normalized.Concurrency.MaxInFlight = 0
+		markChanged("concurrency.max_in_flight")
+	}
+	if normalized.Concurrency.MaxWait < 0 {
+		normalized.Concurrency.MaxWait = 0
+		markChanged("concurrency.max_wait")
+	} else if normalized.Concurrency.MaxWait > maxConcurrencyWait {
+		normalized.Concurrency.MaxWait = maxConcurrencyWait
+		markChanged("concurrency.max_wait")
 	}
 
-	if normalized.Circuit.Threshold <= 0 {
-		normalized.Circuit.Threshold = 5
-		markChanged("circuit.threshold")
+	var err error
+	if normalized.RetryOn, err = normalizeErrorMatcher("retry_on", normalized.RetryOn); err != nil {
+		return EffectivePolicy{}, err
 	}
-	if normalized.Circuit.Threshold < minCircuitThreshold {
-		normalized.Circuit.Threshold = minCircuitThreshold
-		markChanged("circuit.threshold")
+	if normalized.AbortOn, err = normalizeErrorMatcher("abort_on", normalized.AbortOn); err != nil {
+		return EffectivePolicy{}, err
 	}
+	if err = checkHTTPStatuses("retry.retryable_http_statuses", normalized.Retry.RetryableHTTPStatuses); err != nil {
+		return EffectivePolicy{}, err
+	}
+	if normalized.Retry.RetryableGRPCCodes, err = normalizeGRPCCodes("retry.retryable_grpc_codes", normalized.Retry.RetryableGRPCCodes); err != nil {
+		return EffectivePolicy{}, err
+	}
+	if normalized.Retry.Rules, err = normalizeRetryRules("retry.rules", normalized.Retry.Rules, markChanged); err != nil {
+		return EffectivePolicy{}, err
+	}
+	if normalized.Retry.Budgets, err = normalizeBudgetRefs("retry.budgets", normalized.Retry.Budgets, markChanged); err != nil {
+		return EffectivePolicy{}, err
+	}
+	if normalized.Hedge.Budgets, err = normalizeBudgetRefs("hedge.budgets", normalized.Hedge.Budgets, markChanged); err != nil {
+		return EffectivePolicy{}, err
+	}
+	if normalized.ReasonOverrides, err = normalizeReasonOverrides("reason_overrides", normalized.ReasonOverrides, markChanged); err != nil {
+		return EffectivePolicy{}, err
+	}
+	if normalized.Windows, err = normalizeWindows("windows", normalized.Windows, markChanged); err != nil {
+		return EffectivePolicy{}, err
+	}
+
+	switch normalized.Priority {
+	case "", PriorityLow, PriorityNormal, PriorityHigh:
+	default:
+		return EffectivePolicy{}, &NormalizeError{Field: "priority", Value: string(normalized.Priority)}
+	}
+
+	if normalized.Hedge.Enabled {
+		if normalized.Hedge.MaxHedges == 0 {
+			normalized.Hedge.MaxHedges = 2
+			markChanged("hedge.max_hedges")
+		}
+		if normalized.Hedge.MaxHedges < 1 {
+			normalized.Hedge.MaxHedges = 1
+			markChanged("hedge.max_hedges")
+		} else if normalized.Hedge.MaxHedges > maxHedges {
+			normalized.Hedge.MaxHedges = maxHedges
+			markChanged("hedge.max_hedges")
+		}
+
+		if normalized.Hedge.HedgeDelay <= 0 {
+			normalized.Hedge.HedgeDelay = 200 * time.Millisecond
+			markChanged("hedge.hedge_delay")
+		}
+		if normalized.Hedge.HedgeDelay < minHedgeDelayFloor {
+			normalized.Hedge.HedgeDelay = minHedgeDelayFloor
+			markChanged("hedge.hedge_delay")
+		}
 
-	if normalized.Circuit.Cooldown <= 0 {
-		normalized.Circuit.Cooldown = 10 * time.Second
-		markChanged("circuit.cooldown")
+		if pct := normalized.Hedge.HedgeDelayPercentile; pct != "" {
+			normalized.Hedge.HedgeDelayPercentile = strings.ToLower(strings.TrimSpace(pct))
+			if !validPercentile(normalized.Hedge.HedgeDelayPercentile) {
+				return EffectivePolicy{}, &NormalizeError{Field: "hedge.hedge_delay_percentile", Value: pct}
+			}
+		}
 	}
-	if normalized.Circuit.Cooldown < minCircuitCooldown {
-		normalized.Circuit.Cooldown = minCircuitCooldown
-		markChanged("circuit.cooldown")
+
+	if normalized.Circuit.Enabled {
+		if normalized.Circuit.Threshold <= 0 {
+			normalized.Circuit.Threshold = 5
+			markChanged("circuit.threshold")
+		}
+		if normalized.Circuit.Threshold < minCircuitThreshold {
+			normalized.Circuit.Threshold = minCircuitThreshold
+			markChanged("circuit.threshold")
+		}
+
+		if normalized.Circuit.Cooldown <= 0 {
+			normalized.Circuit.Cooldown = 10 * time.Second
+			markChanged("circuit.cooldown")
+		}
+		if normalized.Circuit.Cooldown < minCircuitCooldown {
+			normalized.Circuit.Cooldown = minCircuitCooldown
+			markChanged("circuit.cooldown")
+		}
+
+		if normalized.Circuit.FailureRateThreshold < 0 {
+			normalized.Circuit.FailureRateThreshold = 0
+			markChanged("circuit.failure_rate_threshold")
+		} else if normalized.Circuit.FailureRateThreshold > 1 {
+			normalized.Circuit.FailureRateThreshold = 1
+			markChanged("circuit.failure_rate_threshold")
+		}
+		if normalized.Circuit.FailureRateThreshold > 0 {
+			if normalized.Circuit.MinimumRequests <= 0 {
+				normalized.Circuit.MinimumRequests = defaultCircuitMinimumRequests
+				markChanged("circuit.minimum_requests")
+			}
+			if normalized.Circuit.Window <= 0 {
+				normalized.Circuit.Window = defaultCircuitWindow
+				markChanged("circuit.window")
+			}
+			if normalized.Circuit.Window < minCircuitWindow {
+				normalized.Circuit.Window = minCircuitWindow
+				markChanged("circuit.window")
+			}
+		}
 	}
 
 	return normalized, nil
 }
+
+// normalizeBudgetRefs trims budget names, rejects empty ones, and raises costs
+// below 1 to 1. It never modifies refs in place.
+func normalizeBudgetRefs(field string, refs []BudgetRef, markChanged func(string)) ([]BudgetRef, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	out := make([]BudgetRef, len(refs))
+	for i, ref := range refs {
+		ref.Name = strings.TrimSpace(ref.Name)
+		if ref.Name == "" {
+			return nil, &NormalizeError{Field: field + ".name", Value: ""}
+		}
+		if ref.Cost < 1 {
+			ref.Cost = 1
+			markChanged(field + ".cost")
+		}
+		out[i] = ref
+	}
+	return out, nil
+}
+
+// normalizeReasonOverrides rejects empty reasons and negative attempt limits
+// and caps backoff floors at the backoff ceiling. It never modifies overrides
+// in place.
+func normalizeReasonOverrides(field string, overrides map[string]ReasonOverride, markChanged func(string)) (map[string]ReasonOverride, error) {
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]ReasonOverride, len(overrides))
+	for reason, ov := range overrides {
+		if strings.TrimSpace(reason) == "" {
+			return nil, &NormalizeError{Field: field, Value: reason}
+		}
+		if ov.MaxAttempts < 0 {
+			return nil, &NormalizeError{Field: field + "." + reason + ".max_attempts", Value: strconv.Itoa(ov.MaxAttempts)}
+		}
+		if ov.MinBackoff < 0 {
+			ov.MinBackoff = 0
+			markChanged(field + "." + reason + ".min_backoff")
+		} else if ov.MinBackoff > maxBackoffCeiling {
+			ov.MinBackoff = maxBackoffCeiling
+			markChanged(field + "." + reason + ".min_backoff")
+		}
+		out[reason] = ov
+	}
+	return out, nil
+}
+
+// validPercentile reports whether pct is a percentile latency trackers report.
+func validPercentile(pct string) bool {
+	switch pct {
+	case "p50", "p90", "p95", "p99":
+		return true
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -76,8 +77,8 @@ func TestEffectivePolicyNormalize_InvalidJitter(t *testing.T) {
 	}
 	if normalized.Key != (PolicyKey{}) ||
 		normalized.ID != "" ||
-		normalized.Retry != (RetryPolicy{}) ||
-		normalized.Hedge != (HedgePolicy{}) ||
+		!normalized.Retry.IsZero() ||
+		!normalized.Hedge.IsZero() ||
 		normalized.Circuit != (CircuitPolicy{}) ||
 		normalized.Meta.Source != "" ||
 		normalized.Meta.Normalization.Changed ||
@@ -226,3 +227,324 @@ func TestEffectivePolicyNormalize_CapsMaxAttemptsAndBackoff(t *testing.T) {
 		t.Fatalf("multiplier=%v, want 10", normalized.Retry.BackoffMultiplier)
 	}
 }
+
+func TestEffectivePolicyNormalize_FaultInjection(t *testing.T) {
+	p := EffectivePolicy{
+		FaultInjection: FaultInjectionPolicy{
+			Enabled:   true,
+			ErrorRate: 1.5,
+			Latency:   time.Hour,
+		},
+	}
+
+	normalized, err := p.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fi := normalized.FaultInjection
+	if fi.ErrorRate != 1 {
+		t.Fatalf("errorRate=%v, want 1", fi.ErrorRate)
+	}
+	if fi.Latency != maxFaultLatency {
+		t.Fatalf("latency=%v, want %v", fi.Latency, maxFaultLatency)
+	}
+	if fi.Reason != defaultFaultReason {
+		t.Fatalf("reason=%q, want %q", fi.Reason, defaultFaultReason)
+	}
+
+	p.FaultInjection = FaultInjectionPolicy{ErrorRate: -1, Latency: -time.Second}
+	normalized, err = p.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if normalized.FaultInjection != (FaultInjectionPolicy{}) {
+		t.Fatalf("faultInjection=%+v, want zero value", normalized.FaultInjection)
+	}
+}
+
+func TestEffectivePolicyNormalize_Fallback(t *testing.T) {
+	cases := []struct {
+		fb      FallbackPolicy
+		wantErr string
+	}{
+		{fb: FallbackPolicy{}},
+		{fb: FallbackPolicy{Mode: FallbackCached}},
+		{fb: FallbackPolicy{Mode: FallbackStatic, Name: "empty"}},
+		{fb: FallbackPolicy{Mode: FallbackHandler}, wantErr: "fallback.name"},
+		{fb: FallbackPolicy{Mode: "bogus"}, wantErr: "fallback.mode"},
+	}
+	for _, tc := range cases {
+		_, err := EffectivePolicy{Fallback: tc.fb}.Normalize()
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Fatalf("fb=%+v: unexpected error %v", tc.fb, err)
+			}
+			continue
+		}
+		var nerr *NormalizeError
+		if !errors.As(err, &nerr) || nerr.Field != tc.wantErr {
+			t.Fatalf("fb=%+v: err=%v, want NormalizeError on %s", tc.fb, err, tc.wantErr)
+		}
+	}
+
+	normalized, err := EffectivePolicy{Fallback: FallbackPolicy{Mode: FallbackCached, MaxAge: -time.Second}}.Normalize()
+	if err != nil || normalized.Fallback.MaxAge != 0 {
+		t.Fatalf("maxAge=%v err=%v, want 0", normalized.Fallback.MaxAge, err)
+	}
+}
+
+func TestEffectivePolicyNormalize_Concurrency(t *testing.T) {
+	normalized, err := EffectivePolicy{Concurrency: ConcurrencyPolicy{MaxInFlight: -1, MaxWait: time.Hour}}.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if normalized.Concurrency.MaxInFlight != 0 || normalized.Concurrency.MaxWait != maxConcurrencyWait {
+		t.Fatalf("concurrency=%+v, want max_in_flight=0 max_wait=%v", normalized.Concurrency, maxConcurrencyWait)
+	}
+}
+
+func TestEffectivePolicyNormalize_CacheTTL(t *testing.T) {
+	normalized, err := EffectivePolicy{Cache: CachePolicy{TTL: -time.Second}}.Normalize()
+	if err != nil || normalized.Cache.TTL != 0 {
+		t.Fatalf("ttl=%v err=%v, want 0", normalized.Cache.TTL, err)
+	}
+}
+
+func TestNormalize_UnlimitedAttempts(t *testing.T) {
+	p := DefaultPolicyFor(ParseKey("svc.Unlimited"))
+	p.Retry.MaxAttempts = UnlimitedAttempts
+
+	_, err := p.Normalize()
+	var ne *NormalizeError
+	if !errors.As(err, &ne) || ne.Field != "retry.max_attempts" {
+		t.Fatalf("err=%v, want NormalizeError on retry.max_attempts without an overall timeout", err)
+	}
+	if errs := p.Validate(); len(errs) != 1 {
+		t.Fatalf("Validate=%v, want one error", errs)
+	}
+
+	p.Retry.OverallTimeout = time.Minute
+	normalized, err := p.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if normalized.Retry.MaxAttempts != UnlimitedAttempts {
+		t.Fatalf("maxAttempts=%d, want %d", normalized.Retry.MaxAttempts, UnlimitedAttempts)
+	}
+}
+
+func TestNormalize_ReasonOverrides(t *testing.T) {
+	p := DefaultPolicyFor(ParseKey("svc.Reasons"))
+	overrides := map[string]ReasonOverride{
+		"http_429": {MinBackoff: time.Hour},
+		"http_503": {MaxAttempts: 2, MinBackoff: -time.Second},
+	}
+	p.ReasonOverrides = overrides
+
+	normalized, err := p.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := normalized.ReasonOverrides["http_429"].MinBackoff; got != maxBackoffCeiling {
+		t.Fatalf("http_429 min_backoff=%v, want %v", got, maxBackoffCeiling)
+	}
+	if got := normalized.ReasonOverrides["http_503"]; got.MaxAttempts != 2 || got.MinBackoff != 0 {
+		t.Fatalf("http_503=%+v", got)
+	}
+	if overrides["http_429"].MinBackoff != time.Hour {
+		t.Fatal("normalization modified the input map")
+	}
+
+	for _, bad := range []map[string]ReasonOverride{
+		{" ": {Abort: true}},
+		{"http_503": {MaxAttempts: -1}},
+	} {
+		p.ReasonOverrides = bad
+		if _, err := p.Normalize(); err == nil {
+			t.Fatalf("ReasonOverrides=%v: expected error", bad)
+		}
+	}
+}
+
+func TestNormalize_RetryableCodes(t *testing.T) {
+	p := DefaultPolicyFor(ParseKey("svc.Codes"))
+	p.Retry.RetryableHTTPStatuses = []int{409, 503}
+	p.Retry.RetryableGRPCCodes = []string{" unavailable", "Cancelled"}
+
+	normalized, err := p.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := normalized.Retry.RetryableGRPCCodes; len(got) != 2 || got[0] != "UNAVAILABLE" || got[1] != "CANCELED" {
+		t.Fatalf("grpc codes=%v", got)
+	}
+
+	p.Retry.RetryableHTTPStatuses = []int{42}
+	var ne *NormalizeError
+	if _, err := p.Normalize(); !errors.As(err, &ne) || ne.Field != "retry.retryable_http_statuses" {
+		t.Fatalf("err=%v, want retry.retryable_http_statuses error", err)
+	}
+	p.Retry.RetryableHTTPStatuses = nil
+	p.Retry.RetryableGRPCCodes = []string{"NOPE"}
+	if _, err := p.Normalize(); !errors.As(err, &ne) || ne.Field != "retry.retryable_grpc_codes" {
+		t.Fatalf("err=%v, want retry.retryable_grpc_codes error", err)
+	}
+}
+
+func TestNormalize_AcceptsDecorrelatedJitter(t *testing.T) {
+	p := DefaultPolicyFor(ParseKey("svc.Decorrelated"))
+	p.Retry.Jitter = JitterDecorrelated
+
+	normalized, err := p.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if normalized.Retry.Jitter != JitterDecorrelated {
+		t.Fatalf("jitter=%v, want %v", normalized.Retry.Jitter, JitterDecorrelated)
+	}
+}
+
+func TestNormalize_ErrorMatchers(t *testing.T) {
+	p := DefaultPolicyFor(ParseKey("svc.Matchers"))
+	codes := []string{" unavailable ", "Cancelled"}
+	p.RetryOn = ErrorMatcher{Errors: []string{" db.ErrLocked "}, HTTPStatuses: []int{503}, GRPCCodes: codes}
+
+	normalized, err := p.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := normalized.RetryOn.GRPCCodes; len(got) != 2 || got[0] != "UNAVAILABLE" || got[1] != "CANCELED" {
+		t.Fatalf("grpc codes = %q, want [UNAVAILABLE CANCELED]", got)
+	}
+	if got := normalized.RetryOn.Errors; len(got) != 1 || got[0] != "db.ErrLocked" {
+		t.Fatalf("errors = %q, want [db.ErrLocked]", got)
+	}
+	if codes[0] != " unavailable " {
+		t.Fatalf("normalize modified the input slice: %q", codes)
+	}
+
+	for _, m := range []ErrorMatcher{
+		{HTTPStatuses: []int{42}},
+		{GRPCCodes: []string{"NOPE"}},
+		{Errors: []string{" "}},
+	} {
+		p.AbortOn = m
+		if _, err := p.Normalize(); err == nil {
+			t.Fatalf("expected error for abort_on %+v", m)
+		}
+	}
+}
+
+func TestEffectivePolicyNormalize_Budgets(t *testing.T) {
+	p := New("svc.Budgets", AlsoHedgeBudget("hedges"))
+	p.Retry.Budgets = []BudgetRef{{Name: " global "}}
+
+	normalized, err := p.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := normalized.Retry.Budgets; len(got) != 1 || got[0] != (BudgetRef{Name: "global", Cost: 1}) {
+		t.Fatalf("retry budgets=%+v", got)
+	}
+	if p.Retry.Budgets[0].Name != " global " {
+		t.Fatal("Normalize modified the input slice")
+	}
+	if got := normalized.Hedge.Budgets; len(got) != 1 || got[0].Name != "hedges" {
+		t.Fatalf("hedge budgets=%+v", got)
+	}
+
+	p.Retry.Budgets = []BudgetRef{{Name: " "}}
+	if _, err := p.Normalize(); err == nil {
+		t.Fatal("expected error for empty budget name")
+	}
+}
+
+func TestEffectivePolicyNormalize_SoftTimeoutNeedsTimeout(t *testing.T) {
+	p := New("svc.Soft", SoftPerAttemptTimeout(0))
+	if p.Retry.SoftTimeoutPerAttempt {
+		t.Fatal("soft timeout kept without a per-attempt timeout")
+	}
+	if p = New("svc.Soft", SoftPerAttemptTimeout(time.Second)); !p.Retry.SoftTimeoutPerAttempt || p.Retry.TimeoutPerAttempt != time.Second {
+		t.Fatalf("retry=%+v, want a 1s soft timeout", p.Retry)
+	}
+}
+
+func TestEffectivePolicyNormalize_CircuitFailureRate(t *testing.T) {
+	normalized, err := EffectivePolicy{Circuit: CircuitPolicy{Enabled: true, FailureRateThreshold: 1.5}}.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := normalized.Circuit
+	if c.FailureRateThreshold != 1 || c.MinimumRequests != defaultCircuitMinimumRequests || c.Window != defaultCircuitWindow {
+		t.Fatalf("circuit=%+v", c)
+	}
+	if c.Threshold != 5 || c.Cooldown != 10*time.Second {
+		t.Fatalf("circuit=%+v, want defaults without hedging", c)
+	}
+
+	normalized, err = EffectivePolicy{Circuit: CircuitPolicy{Enabled: true, FailureRateThreshold: 0.5, MinimumRequests: 50, Window: time.Millisecond}}.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := normalized.Circuit; c.MinimumRequests != 50 || c.Window != minCircuitWindow {
+		t.Fatalf("circuit=%+v", c)
+	}
+}
+
+func TestNormalize_DeadlineSplit(t *testing.T) {
+	p, err := New("svc.Op", SplitDeadline(" Fraction ", 0)).Normalize()
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	if p.Retry.DeadlineSplit != DeadlineSplitFraction || p.Retry.DeadlineFraction != defaultDeadlineFraction || p.Retry.MinTimeoutPerAttempt != defaultMinAutoTimeout {
+		t.Fatalf("retry = %+v, want fraction split with defaults", p.Retry)
+	}
+
+	for _, r := range []RetryPolicy{
+		{DeadlineSplit: "halves"},
+		{DeadlineSplit: DeadlineSplitFraction, DeadlineFraction: 1.5},
+	} {
+		if _, err := (EffectivePolicy{Retry: r}).Normalize(); err == nil {
+			t.Errorf("Normalize(%+v) succeeded, want error", r)
+		}
+		if errs := (EffectivePolicy{Retry: r}).Validate(); len(errs) != 1 {
+			t.Errorf("Validate(%+v) = %v, want one error", r, errs)
+		}
+	}
+}
+
+func TestNormalize_KeyFallback(t *testing.T) {
+	p, err := New("db.Read", KeyFallback(" cache.Read ")).Normalize()
+	if err != nil || p.Fallback.Key != "cache.Read" {
+		t.Fatalf("fallback=%+v err=%v, want trimmed key", p.Fallback, err)
+	}
+	for _, key := range []string{"", "db.Read"} {
+		p := EffectivePolicy{Key: ParseKey("db.Read"), Fallback: FallbackPolicy{Mode: FallbackKey, Key: key}}
+		if _, err := p.Normalize(); err == nil {
+			t.Errorf("Normalize with key %q succeeded, want error", key)
+		}
+		if errs := p.Validate(); len(errs) != 1 {
+			t.Errorf("Validate with key %q = %v, want one error", key, errs)
+		}
+	}
+}
+
+func TestEffectivePolicy_IsZero(t *testing.T) {
+	if !(EffectivePolicy{}).IsZero() {
+		t.Fatal("expected zero policy")
+	}
+	if !(EffectivePolicy{Meta: Metadata{Source: PolicySourceStatic}}).IsZero() {
+		t.Fatal("expected metadata alone to leave the policy zero")
+	}
+	for _, pol := range []EffectivePolicy{
+		{Key: PolicyKey{Name: "a"}},
+		{ID: "p1"},
+		{Retry: RetryPolicy{MaxAttempts: 1}},
+		{Hedge: HedgePolicy{Enabled: true}},
+	} {
+		if pol.IsZero() {
+			t.Fatalf("IsZero(%+v)=true, want false", pol)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package policy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Labels describe a policy key for selector matching, such as
+// {"tier": "critical"}. Labels are attached to keys by providers (see
+// controlplane.StaticProvider.Labels), not carried in PolicyKey.
+//
+// Only controlplane.StaticProvider resolves selectors. RemoteProvider and
+// other providers look policies up by key and wildcard pattern alone.
+type Labels map[string]string
+
+// Selector matches keys whose labels include every one of its label pairs.
+type Selector map[string]string
+
+// Matches reports whether labels include every pair in s. An empty selector
+// matches nothing.
+func (s Selector) Matches(labels Labels) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for k, v := range s {
+		if lv, ok := labels[k]; !ok || lv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns s as comma-separated "label=value" pairs in label order,
+// such as "region=eu,tier=critical".
+func (s Selector) String() string {
+	pairs := make([]string, 0, len(s))
+	for k, v := range s {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,28 @@
+package policy
+
+import "testing"
+
+func TestSelector_Matches(t *testing.T) {
+	labels := Labels{"tier": "critical", "region": "eu"}
+	cases := []struct {
+		selector Selector
+		want     bool
+	}{
+		{Selector{"tier": "critical"}, true},
+		{Selector{"tier": "critical", "region": "eu"}, true},
+		{Selector{"tier": "critical", "region": "us"}, false},
+		{Selector{"owner": "payments"}, false},
+		{Selector{}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.selector.Matches(labels); got != tc.want {
+			t.Fatalf("%v.Matches(%v)=%v, want %v", tc.selector, labels, got, tc.want)
+		}
+	}
+}
+
+func TestSelector_String(t *testing.T) {
+	if got := (Selector{"tier": "critical", "region": "eu"}).String(); got != "region=eu,tier=critical" {
+		t.Fatalf("String()=%q", got)
+	}
+}
@@ -0,0 +1,24 @@
+package policy
+
+import "context"
+
+type tenantKey struct{}
+
+// WithTenant returns a context derived from ctx that carries tenant.
+//
+// Tenant-scoped budgets (budget.TenantBudget) and circuits
+// (CircuitPolicy.PerTenant) keep separate state per tenant, so one noisy
+// tenant cannot exhaust retries or trip circuits for the others. Tenants should
+// be bounded in number (customer tiers or account IDs of a known set, not user IDs).
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant from ctx, if present and non-empty.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	t, _ := ctx.Value(tenantKey{}).(string)
+	return t, t != ""
+}
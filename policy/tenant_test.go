@@ -0,0 +1,19 @@
+package policy
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTenantContextRoundTrip(t *testing.T) {
+	if _, ok := TenantFromContext(context.Background()); ok {
+		t.Fatalf("expected no tenant on background context")
+	}
+	ctx := WithTenant(context.Background(), "acme")
+	if tenant, ok := TenantFromContext(ctx); !ok || tenant != "acme" {
+		t.Fatalf("tenant=%q ok=%v, want acme", tenant, ok)
+	}
+	if _, ok := TenantFromContext(WithTenant(ctx, "")); ok {
+		t.Fatalf("expected empty tenant to be treated as absent")
+	}
+}
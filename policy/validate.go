@@ -0,0 +1,265 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError reports a policy field that Normalize would reject or
+// silently adjust.
+type ValidationError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("recourse: invalid policy: %s=%q: %s", e.Field, e.Value, e.Reason)
+}
+
+// Validate reports every field of p that is out of bounds or inconsistent
+// with another field, without modifying p. It returns nil for a valid policy.
+//
+// Normalize fills zero fields with defaults and clamps out-of-range values;
+// Validate is the strict counterpart for CI checks and admin tooling that
+// should reject such policies instead. Zero values are valid because they
+// select defaults.
+func (p EffectivePolicy) Validate() []error {
+	var v validator
+
+	r := p.Retry
+	switch {
+	case r.MaxAttempts < 0 && r.MaxAttempts != UnlimitedAttempts:
+		v.add("retry.max_attempts", strconv.Itoa(r.MaxAttempts), "must be at least 1 or UnlimitedAttempts")
+	case r.MaxAttempts > maxRetryAttempts:
+		v.add("retry.max_attempts", strconv.Itoa(r.MaxAttempts), fmt.Sprintf("exceeds maximum %d", maxRetryAttempts))
+	}
+	v.duration("retry.initial_backoff", r.InitialBackoff, minBackoffFloor, 0)
+	v.duration("retry.max_backoff", r.MaxBackoff, 0, maxBackoffCeiling)
+	if r.InitialBackoff > 0 && r.MaxBackoff > 0 && r.MaxBackoff < r.InitialBackoff {
+		v.add("retry.max_backoff", r.MaxBackoff.String(), "less than retry.initial_backoff")
+	}
+	switch {
+	case r.BackoffMultiplier < 0 || (r.BackoffMultiplier > 0 && r.BackoffMultiplier < 1):
+		v.add("retry.backoff_multiplier", formatFloat(r.BackoffMultiplier), "must be at least 1")
+	case r.BackoffMultiplier > maxBackoffMultiplier:
+		v.add("retry.backoff_multiplier", formatFloat(r.BackoffMultiplier), fmt.Sprintf("exceeds maximum %g", maxBackoffMultiplier))
+	}
+	switch r.Jitter {
+	case "", JitterNone, JitterFull, JitterEqual, JitterDecorrelated:
+	default:
+		if _, ok := DefaultJitters.Get(r.Jitter); !ok {
+			v.add("retry.jitter", string(r.Jitter), "unknown jitter")
+		}
+	}
+	v.duration("retry.timeout_per_attempt", r.TimeoutPerAttempt, minTimeoutFloor, 0)
+	v.duration("retry.min_timeout_per_attempt", r.MinTimeoutPerAttempt, minTimeoutFloor, 0)
+	switch DeadlineSplit(strings.ToLower(strings.TrimSpace(string(r.DeadlineSplit)))) {
+	case "", DeadlineSplitEven, DeadlineSplitRemaining, DeadlineSplitFraction:
+	default:
+		v.add("retry.deadline_split", string(r.DeadlineSplit), "must be even, remaining or fraction")
+	}
+	if r.DeadlineFraction < 0 || r.DeadlineFraction > 1 {
+		v.add("retry.deadline_fraction", formatFloat(r.DeadlineFraction), "must be between 0 and 1")
+	}
+	if r.SoftTimeoutPerAttempt && r.TimeoutPerAttempt == 0 && !r.AutoTimeoutPerAttempt {
+		v.add("retry.soft_timeout_per_attempt", "true", "requires retry.timeout_per_attempt or retry.auto_timeout_per_attempt")
+	}
+	v.duration("retry.overall_timeout", r.OverallTimeout, minTimeoutFloor, 0)
+	if r.MaxAttempts == UnlimitedAttempts && r.OverallTimeout <= 0 {
+		v.add("retry.max_attempts", strconv.Itoa(r.MaxAttempts), "UnlimitedAttempts requires retry.overall_timeout")
+	}
+	if r.TimeoutPerAttempt > 0 && r.OverallTimeout > 0 && r.TimeoutPerAttempt > r.OverallTimeout {
+		v.add("retry.timeout_per_attempt", r.TimeoutPerAttempt.String(), "exceeds retry.overall_timeout")
+	}
+	v.duration("retry.max_cumulative_backoff", r.MaxCumulativeBackoff, 0, 0)
+	v.budgetRef("retry.budget", r.Budget, false)
+	for i, ref := range r.Budgets {
+		v.budgetRef(fmt.Sprintf("retry.budgets[%d]", i), ref, true)
+	}
+	for i, rule := range r.Rules {
+		prefix := fmt.Sprintf("retry.rules[%d]", i)
+		if strings.TrimSpace(rule.When) == "" {
+			v.add(prefix+".when", rule.When, "empty expression")
+		} else if err := checkRuleExpression(rule.When); err != nil {
+			v.add(prefix+".when", rule.When, strings.TrimPrefix(err.Error(), "expr: "))
+		}
+		switch RuleAction(strings.ToLower(strings.TrimSpace(string(rule.Action)))) {
+		case "", RuleRetry, RuleAbort:
+		default:
+			v.add(prefix+".action", string(rule.Action), "must be retry or abort")
+		}
+		v.duration(prefix+".backoff", rule.Backoff, 0, maxBackoffCeiling)
+	}
+	v.err(checkHTTPStatuses("retry.retryable_http_statuses", r.RetryableHTTPStatuses))
+	_, err := normalizeGRPCCodes("retry.retryable_grpc_codes", r.RetryableGRPCCodes)
+	v.err(err)
+
+	h := p.Hedge
+	if h.MaxHedges < 0 {
+		v.add("hedge.max_hedges", strconv.Itoa(h.MaxHedges), "must not be negative")
+	} else if h.MaxHedges > maxHedges {
+		v.add("hedge.max_hedges", strconv.Itoa(h.MaxHedges), fmt.Sprintf("exceeds maximum %d", maxHedges))
+	}
+	v.duration("hedge.hedge_delay", h.HedgeDelay, minHedgeDelayFloor, 0)
+	if h.HedgeDelayPercentile != "" && !validPercentile(strings.ToLower(strings.TrimSpace(h.HedgeDelayPercentile))) {
+		v.add("hedge.hedge_delay_percentile", h.HedgeDelayPercentile, "must be p50, p90, p95 or p99")
+	}
+	v.budgetRef("hedge.budget", h.Budget, false)
+	for i, ref := range h.Budgets {
+		v.budgetRef(fmt.Sprintf("hedge.budgets[%d]", i), ref, true)
+	}
+
+	c := p.Circuit
+	if c.Threshold < 0 {
+		v.add("circuit.threshold", strconv.Itoa(c.Threshold), "must not be negative")
+	}
+	v.duration("circuit.cooldown", c.Cooldown, minCircuitCooldown, 0)
+	if c.FailureRateThreshold < 0 || c.FailureRateThreshold > 1 {
+		v.add("circuit.failure_rate_threshold", formatFloat(c.FailureRateThreshold), "must be between 0 and 1")
+	}
+	if c.MinimumRequests < 0 {
+		v.add("circuit.minimum_requests", strconv.Itoa(c.MinimumRequests), "must not be negative")
+	}
+	v.duration("circuit.window", c.Window, minCircuitWindow, 0)
+
+	f := p.FaultInjection
+	if f.ErrorRate < 0 || f.ErrorRate > 1 {
+		v.add("fault_injection.error_rate", formatFloat(f.ErrorRate), "must be between 0 and 1")
+	}
+	v.duration("fault_injection.latency", f.Latency, 0, maxFaultLatency)
+
+	switch p.Fallback.Mode {
+	case "", FallbackCached:
+	case FallbackStatic, FallbackHandler:
+		if p.Fallback.Name == "" {
+			v.add("fallback.name", "", "required for "+string(p.Fallback.Mode)+" fallbacks")
+		}
+	case FallbackKey:
+		switch k := strings.TrimSpace(p.Fallback.Key); {
+		case k == "":
+			v.add("fallback.key", "", "required for key fallbacks")
+		case ParseKey(k) == p.Key:
+			v.add("fallback.key", p.Fallback.Key, "names the policy's own key")
+		}
+	default:
+		v.add("fallback.mode", string(p.Fallback.Mode), "unknown fallback mode")
+	}
+	v.duration("fallback.max_age", p.Fallback.MaxAge, 0, 0)
+	v.duration("cache.ttl", p.Cache.TTL, 0, 0)
+
+	if p.Concurrency.MaxInFlight < 0 {
+		v.add("concurrency.max_in_flight", strconv.Itoa(p.Concurrency.MaxInFlight), "must not be negative")
+	}
+	v.duration("concurrency.max_wait", p.Concurrency.MaxWait, 0, maxConcurrencyWait)
+
+	switch p.Priority {
+	case "", PriorityLow, PriorityNormal, PriorityHigh:
+	default:
+		v.add("priority", string(p.Priority), "unknown priority")
+	}
+
+	_, err = normalizeErrorMatcher("retry_on", p.RetryOn)
+	v.err(err)
+	_, err = normalizeErrorMatcher("abort_on", p.AbortOn)
+	v.err(err)
+
+	reasons := make([]string, 0, len(p.ReasonOverrides))
+	for reason := range p.ReasonOverrides {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		ov := p.ReasonOverrides[reason]
+		field := "reason_overrides." + reason
+		if strings.TrimSpace(reason) == "" {
+			v.add("reason_overrides", reason, "empty reason")
+		}
+		if ov.MaxAttempts < 0 {
+			v.add(field+".max_attempts", strconv.Itoa(ov.MaxAttempts), "must not be negative")
+		}
+		v.duration(field+".min_backoff", ov.MinBackoff, 0, maxBackoffCeiling)
+	}
+
+	for i, w := range p.Windows {
+		prefix := fmt.Sprintf("windows[%d]", i)
+		if _, err := parseTimeOfDay(w.Start); err != nil {
+			v.add(prefix+".start", w.Start, "must be a time of day like 09:00")
+		}
+		if _, err := parseTimeOfDay(w.End); err != nil {
+			v.add(prefix+".end", w.End, "must be a time of day like 17:00")
+		} else if w.End == w.Start {
+			v.add(prefix+".end", w.End, "equals start")
+		}
+		for _, d := range w.Days {
+			if _, ok := weekdays[strings.ToLower(strings.TrimSpace(d))]; !ok {
+				v.add(prefix+".days", d, "unknown weekday")
+			}
+		}
+		for _, err := range (EffectivePolicy{Retry: w.Retry, Hedge: w.Hedge}).Validate() {
+			var ve *ValidationError
+			if errors.As(err, &ve) {
+				v.add(prefix+"."+ve.Field, ve.Value, ve.Reason)
+			}
+		}
+	}
+
+	return v.errs
+}
+
+// validator collects Validate's violations.
+type validator struct {
+	errs []error
+}
+
+func (v *validator) add(field, value, reason string) {
+	v.errs = append(v.errs, &ValidationError{Field: field, Value: value, Reason: reason})
+}
+
+// err records a NormalizeError from a shared normalization helper.
+func (v *validator) err(err error) {
+	if err == nil {
+		return
+	}
+	var ne *NormalizeError
+	if errors.As(err, &ne) {
+		v.add(ne.Field, ne.Value, "invalid value")
+		return
+	}
+	v.errs = append(v.errs, err)
+}
+
+// duration checks that a non-zero d is positive and within [min, max]; a zero
+// bound is not checked.
+func (v *validator) duration(field string, d, min, max time.Duration) {
+	switch {
+	case d < 0:
+		v.add(field, d.String(), "must not be negative")
+	case d > 0 && min > 0 && d < min:
+		v.add(field, d.String(), "below minimum "+min.String())
+	case max > 0 && d > max:
+		v.add(field, d.String(), "exceeds maximum "+max.String())
+	}
+}
+
+// budgetRef checks a budget reference. Named references are required in
+// budget lists, where every entry must name a budget.
+func (v *validator) budgetRef(field string, ref BudgetRef, named bool) {
+	if named && strings.TrimSpace(ref.Name) == "" {
+		v.add(field+".name", "", "empty budget name")
+	}
+	if ref.Cost < 0 {
+		v.add(field+".cost", strconv.Itoa(ref.Cost), "must not be negative")
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,96 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidate_Valid(t *testing.T) {
+	for name, p := range map[string]EffectivePolicy{
+		"zero":      {},
+		"default":   DefaultPolicyFor(ParseKey("svc.Op")),
+		"unlimited": New("svc.Op", MaxAttempts(UnlimitedAttempts), OverallTimeout(time.Minute)),
+		"http":      New("svc.Op", HTTPDefaults()),
+	} {
+		if errs := p.Validate(); errs != nil {
+			t.Errorf("%s: unexpected errors: %v", name, errs)
+		}
+	}
+}
+
+func TestValidate_CollectsAllViolations(t *testing.T) {
+	p := EffectivePolicy{
+		Retry: RetryPolicy{
+			MaxAttempts:       50,
+			InitialBackoff:    time.Second,
+			MaxBackoff:        100 * time.Millisecond,
+			BackoffMultiplier: 0.5,
+			Jitter:            "wobbly",
+			TimeoutPerAttempt: 5 * time.Second,
+			OverallTimeout:    time.Second,
+			Budgets:           []BudgetRef{{Name: " "}},
+		},
+		Hedge:          HedgePolicy{MaxHedges: 9, HedgeDelay: time.Millisecond},
+		FaultInjection: FaultInjectionPolicy{ErrorRate: 1.5},
+		Fallback:       FallbackPolicy{Mode: FallbackStatic},
+		Priority:       "urgent",
+		RetryOn:        ErrorMatcher{HTTPStatuses: []int{42}},
+	}
+	before := p.Retry
+
+	want := []string{
+		"retry.max_attempts",
+		"retry.max_backoff",
+		"retry.backoff_multiplier",
+		"retry.jitter",
+		"retry.timeout_per_attempt",
+		"retry.budgets[0].name",
+		"hedge.max_hedges",
+		"hedge.hedge_delay",
+		"fault_injection.error_rate",
+		"fallback.name",
+		"priority",
+		"retry_on.http_statuses",
+	}
+	errs := p.Validate()
+	if len(errs) != len(want) {
+		t.Fatalf("errors=%v, want %d", errs, len(want))
+	}
+	for i, err := range errs {
+		var ve *ValidationError
+		if !errors.As(err, &ve) {
+			t.Fatalf("err=%T, want *ValidationError", err)
+		}
+		if ve.Field != want[i] {
+			t.Errorf("errs[%d].Field=%q, want %q (%v)", i, ve.Field, want[i], err)
+		}
+	}
+
+	if p.Retry.MaxAttempts != before.MaxAttempts || p.Retry.Jitter != before.Jitter {
+		t.Fatalf("Validate modified the policy: %+v", p.Retry)
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	var err *ValidationError
+	if got := err.Error(); got != "<nil>" {
+		t.Fatalf("nil error string=%q", got)
+	}
+	err = &ValidationError{Field: "retry.max_attempts", Value: "50", Reason: "exceeds maximum 10"}
+	if got, want := err.Error(), `recourse: invalid policy: retry.max_attempts="50": exceeds maximum 10`; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestValidate_Windows(t *testing.T) {
+	p := EffectivePolicy{Windows: []PolicyWindow{{Start: "25:00", End: "17:00", Retry: RetryPolicy{MaxAttempts: 40}}}}
+	errs := p.Validate()
+	if len(errs) != 2 {
+		t.Fatalf("errors=%v, want start and retry.max_attempts", errs)
+	}
+	var ve *ValidationError
+	if !errors.As(errs[1], &ve) || ve.Field != "windows[0].retry.max_attempts" {
+		t.Fatalf("errs[1]=%v", errs[1])
+	}
+}
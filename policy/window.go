@@ -0,0 +1,142 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// AtTime returns p with the first window active at t applied, and the
+// window's name. When no window is active it returns p and "".
+//
+// Windows are evaluated in t's time zone; the executor passes its clock's
+// current time.
+func (p EffectivePolicy) AtTime(t time.Time) (EffectivePolicy, string) {
+	for _, w := range p.Windows {
+		if !w.activeAt(t) {
+			continue
+		}
+		if !w.Retry.IsZero() {
+			p.Retry = w.Retry
+		}
+		if !w.Hedge.IsZero() {
+			p.Hedge = w.Hedge
+		}
+		return p, w.Name
+	}
+	return p, ""
+}
+
+// activeAt reports whether t falls in the window. A window spanning midnight
+// belongs to the day it starts on.
+func (w PolicyWindow) activeAt(t time.Time) bool {
+	start, err := parseTimeOfDay(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseTimeOfDay(w.End)
+	if err != nil {
+		return false
+	}
+
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	day := t.Weekday()
+	switch {
+	case start < end:
+		if now < start || now >= end {
+			return false
+		}
+	case now >= start:
+	case now < end:
+		day = (day + 6) % 7
+	default:
+		return false
+	}
+
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if wd, ok := weekdays[d]; ok && wd == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTimeOfDay parses "15:04" into the offset from midnight.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok || len(hh) != 2 || len(mm) != 2 {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	h, err := strconv.Atoi(hh)
+	if err != nil || h < 0 || h > 23 {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	m, err := strconv.Atoi(mm)
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// normalizeWindows lower-cases day names, names unnamed windows after their
+// times, and rejects malformed times, unknown days, empty windows and window
+// settings that would not normalize. It never modifies windows in place.
+func normalizeWindows(field string, windows []PolicyWindow, markChanged func(string)) ([]PolicyWindow, error) {
+	if len(windows) == 0 {
+		return nil, nil
+	}
+	out := make([]PolicyWindow, len(windows))
+	for i, w := range windows {
+		prefix := fmt.Sprintf("%s[%d]", field, i)
+
+		start, err := parseTimeOfDay(w.Start)
+		if err != nil {
+			return nil, &NormalizeError{Field: prefix + ".start", Value: w.Start}
+		}
+		end, err := parseTimeOfDay(w.End)
+		if err != nil || end == start {
+			return nil, &NormalizeError{Field: prefix + ".end", Value: w.End}
+		}
+
+		if len(w.Days) > 0 {
+			days := make([]string, len(w.Days))
+			for j, d := range w.Days {
+				days[j] = strings.ToLower(strings.TrimSpace(d))
+				if _, ok := weekdays[days[j]]; !ok {
+					return nil, &NormalizeError{Field: prefix + ".days", Value: d}
+				}
+			}
+			w.Days = days
+		}
+
+		if w.Name = strings.TrimSpace(w.Name); w.Name == "" {
+			w.Name = w.Start + "-" + w.End
+			markChanged(prefix + ".name")
+		}
+
+		if _, err := (EffectivePolicy{Retry: w.Retry, Hedge: w.Hedge}).Normalize(); err != nil {
+			var ne *NormalizeError
+			if errors.As(err, &ne) {
+				return nil, &NormalizeError{Field: prefix + "." + ne.Field, Value: ne.Value}
+			}
+			return nil, err
+		}
+		out[i] = w
+	}
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAtTime(t *testing.T) {
+	p := New("svc.Op",
+		MaxAttempts(5),
+		Window(PolicyWindow{
+			Name:  "business-hours",
+			Days:  []string{"mon", "tue", "wed", "thu", "fri"},
+			Start: "09:00",
+			End:   "17:00",
+			Retry: RetryPolicy{MaxAttempts: 2},
+		}),
+		Window(PolicyWindow{
+			Name:  "overnight",
+			Start: "22:00",
+			End:   "06:00",
+			Hedge: HedgePolicy{Enabled: true, MaxHedges: 2, HedgeDelay: 20 * time.Millisecond},
+		}),
+	)
+
+	// 2024-01-01 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	cases := []struct {
+		name     string
+		t        time.Time
+		window   string
+		attempts int
+		hedge    bool
+	}{
+		{"weekday business hours", at(1, 9, 0), "business-hours", 2, false},
+		{"end is exclusive", at(1, 17, 0), "", 5, false},
+		{"weekend", at(6, 12, 0), "", 5, false},
+		{"late evening", at(1, 23, 30), "overnight", 5, true},
+		{"after midnight", at(2, 5, 59), "overnight", 5, true},
+		{"morning", at(2, 6, 0), "", 5, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, window := p.AtTime(tc.t)
+			if window != tc.window || got.Retry.MaxAttempts != tc.attempts || got.Hedge.Enabled != tc.hedge {
+				t.Fatalf("window=%q attempts=%d hedge=%v", window, got.Retry.MaxAttempts, got.Hedge.Enabled)
+			}
+		})
+	}
+}
+
+func TestAtTime_OvernightDays(t *testing.T) {
+	p := EffectivePolicy{Windows: []PolicyWindow{{Name: "fri-night", Days: []string{"fri"}, Start: "22:00", End: "02:00"}}}
+
+	// 2024-01-06 01:00 is Saturday, within the window that started Friday.
+	if _, window := p.AtTime(time.Date(2024, 1, 6, 1, 0, 0, 0, time.UTC)); window != "fri-night" {
+		t.Fatalf("window=%q, want fri-night", window)
+	}
+	if _, window := p.AtTime(time.Date(2024, 1, 5, 1, 0, 0, 0, time.UTC)); window != "" {
+		t.Fatalf("window=%q, want none on Friday morning", window)
+	}
+}
+
+func TestNormalize_Windows(t *testing.T) {
+	p, err := EffectivePolicy{Windows: []PolicyWindow{{Days: []string{" Mon "}, Start: "08:30", End: "12:00"}}}.Normalize()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w := p.Windows[0]; w.Name != "08:30-12:00" || w.Days[0] != "mon" {
+		t.Fatalf("window=%+v", w)
+	}
+
+	for field, w := range map[string]PolicyWindow{
+		"windows[0].start":        {Start: "9:00", End: "17:00"},
+		"windows[0].end":          {Start: "09:00", End: "09:00"},
+		"windows[0].days":         {Days: []string{"someday"}, Start: "09:00", End: "17:00"},
+		"windows[0].retry.jitter": {Start: "09:00", End: "17:00", Retry: RetryPolicy{Jitter: "bogus"}},
+	} {
+		_, err := EffectivePolicy{Windows: []PolicyWindow{w}}.Normalize()
+		var ne *NormalizeError
+		if !errors.As(err, &ne) || ne.Field != field {
+			t.Errorf("err=%v, want NormalizeError for %s", err, field)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+// UnlimitedLimiter admits every call.
+type UnlimitedLimiter struct{}
+
+func (UnlimitedLimiter) Allow(context.Context, policy.PolicyKey) Decision {
+	return Decision{Allowed: true, Reason: ReasonAllowed}
+}
+
+// TokenBucketLimiter admits calls at ratePerSecond with bursts up to burst.
+//
+// It starts full and each admitted call consumes one token.
+type TokenBucketLimiter struct {
+	mu sync.Mutex
+
+	rate  float64
+	burst float64
+
+	tokens float64
+	last   time.Time
+
+	nowFn func() time.Time
+}
+
+// NewTokenBucketLimiter creates a limiter admitting ratePerSecond calls per
+// second with bursts of up to burst calls. A burst below 1 is treated as 1.
+func NewTokenBucketLimiter(ratePerSecond float64, burst int) *TokenBucketLimiter {
+	if math.IsNaN(ratePerSecond) || math.IsInf(ratePerSecond, 0) || ratePerSecond < 0 {
+		ratePerSecond = 0
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucketLimiter{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+func (l *TokenBucketLimiter) Allow(context.Context, policy.PolicyKey) Decision {
+	if l == nil {
+		return Decision{Allowed: false, Reason: ReasonLimiterNil}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refillLocked()
+	if l.tokens >= 1 {
+		l.tokens--
+		return Decision{Allowed: true, Reason: ReasonAllowed}
+	}
+
+	d := Decision{Allowed: false, Reason: ReasonRateLimited}
+	if l.rate > 0 {
+		d.RetryAfter = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	}
+	return d
+}
+
+// SetRate changes the refill rate, keeping accumulated tokens.
+func (l *TokenBucketLimiter) SetRate(ratePerSecond float64) {
+	if math.IsNaN(ratePerSecond) || math.IsInf(ratePerSecond, 0) || ratePerSecond < 0 {
+		ratePerSecond = 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refillLocked()
+	l.rate = ratePerSecond
+}
+
+// Rate returns the current refill rate in calls per second.
+func (l *TokenBucketLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// SetClock overrides the limiter clock, primarily for tests.
+func (l *TokenBucketLimiter) SetClock(f func() time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nowFn = f
+	l.last = time.Time{}
+}
+
+func (l *TokenBucketLimiter) refillLocked() {
+	now := l.now()
+	if l.last.IsZero() || now.Before(l.last) {
+		l.last = now
+		return
+	}
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+func (l *TokenBucketLimiter) now() time.Time {
+	if l.nowFn != nil {
+		return l.nowFn()
+	}
+	return time.Now()
+}
+
+// AdaptiveLimiter is a token bucket whose rate follows downstream pushback
+// (additive increase, multiplicative decrease).
+//
+// The rate starts at maxRate. Each throttled outcome (see IsThrottled) halves it,
+// down to minRate; every other outcome raises it by 1% of maxRate, up to maxRate.
+type AdaptiveLimiter struct {
+	bucket *TokenBucketLimiter
+
+	minRate float64
+	maxRate float64
+}
+
+// NewAdaptiveLimiter creates an adaptive limiter between minRate and maxRate
+// calls per second with bursts of up to burst calls.
+func NewAdaptiveLimiter(minRate, maxRate float64, burst int) *AdaptiveLimiter {
+	if math.IsNaN(minRate) || minRate < 0 {
+		minRate = 0
+	}
+	if math.IsNaN(maxRate) || math.IsInf(maxRate, 0) || maxRate < minRate {
+		maxRate = minRate
+	}
+	return &AdaptiveLimiter{
+		bucket:  NewTokenBucketLimiter(maxRate, burst),
+		minRate: minRate,
+		maxRate: maxRate,
+	}
+}
+
+func (l *AdaptiveLimiter) Allow(ctx context.Context, key policy.PolicyKey) Decision {
+	if l == nil {
+		return Decision{Allowed: false, Reason: ReasonLimiterNil}
+	}
+	return l.bucket.Allow(ctx, key)
+}
+
+func (l *AdaptiveLimiter) Observe(_ context.Context, _ policy.PolicyKey, out classify.Outcome) {
+	if l == nil {
+		return
+	}
+	b := l.bucket
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked()
+	if IsThrottled(out) {
+		b.rate = math.Max(l.minRate, b.rate/2)
+		return
+	}
+	b.rate = math.Min(l.maxRate, b.rate+l.maxRate/100)
+}
+
+// Rate returns the current admitted rate in calls per second.
+func (l *AdaptiveLimiter) Rate() float64 {
+	return l.bucket.Rate()
+}
+
+// SetClock overrides the limiter clock, primarily for tests.
+func (l *AdaptiveLimiter) SetClock(f func() time.Time) {
+	l.bucket.SetClock(f)
+}
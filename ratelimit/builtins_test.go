@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time          { return f.now }
+func (f *fakeClock) Advance(d time.Duration) { f.now = f.now.Add(d) }
+
+func TestTokenBucketLimiter_BurstAndRefill(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewTokenBucketLimiter(2, 3)
+	l.SetClock(clock.Now)
+	ctx := context.Background()
+	key := policy.PolicyKey{Name: "op"}
+
+	for i := 0; i < 3; i++ {
+		if d := l.Allow(ctx, key); !d.Allowed {
+			t.Fatalf("call %d denied, want burst of 3", i)
+		}
+	}
+	d := l.Allow(ctx, key)
+	if d.Allowed || d.Reason != ReasonRateLimited {
+		t.Fatalf("decision=%+v, want rate limited", d)
+	}
+	if d.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("RetryAfter=%v, want 500ms", d.RetryAfter)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if d := l.Allow(ctx, key); !d.Allowed {
+		t.Fatalf("expected a token after refill")
+	}
+	if d := l.Allow(ctx, key); d.Allowed {
+		t.Fatalf("expected bucket to be empty again")
+	}
+}
+
+func TestTokenBucketLimiter_NilReceiver(t *testing.T) {
+	var l *TokenBucketLimiter
+	if d := l.Allow(context.Background(), policy.PolicyKey{}); d.Allowed || d.Reason != ReasonLimiterNil {
+		t.Fatalf("decision=%+v, want denied with reason %q", d, ReasonLimiterNil)
+	}
+}
+
+func TestAdaptiveLimiter_AIMD(t *testing.T) {
+	l := NewAdaptiveLimiter(10, 100, 1)
+	ctx := context.Background()
+	key := policy.PolicyKey{Name: "op"}
+
+	l.Observe(ctx, key, classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "http_429"})
+	if got := l.Rate(); got != 50 {
+		t.Fatalf("rate=%v, want 50 after throttle", got)
+	}
+	l.Observe(ctx, key, classify.Outcome{Kind: classify.OutcomeSuccess})
+	if got := l.Rate(); got != 51 {
+		t.Fatalf("rate=%v, want 51 after success", got)
+	}
+
+	for i := 0; i < 10; i++ {
+		l.Observe(ctx, key, classify.Outcome{Kind: classify.OutcomeRetryable, BackoffOverride: time.Second})
+	}
+	if got := l.Rate(); got != 10 {
+		t.Fatalf("rate=%v, want floor of 10", got)
+	}
+	for i := 0; i < 200; i++ {
+		l.Observe(ctx, key, classify.Outcome{Kind: classify.OutcomeSuccess})
+	}
+	if got := l.Rate(); got != 100 {
+		t.Fatalf("rate=%v, want ceiling of 100", got)
+	}
+}
+
+func TestIsThrottled(t *testing.T) {
+	cases := []struct {
+		out  classify.Outcome
+		want bool
+	}{
+		{classify.Outcome{Reason: "http_429"}, true},
+		{classify.Outcome{Reason: "grpc_ResourceExhausted"}, true},
+		{classify.Outcome{Reason: "http_5xx", BackoffOverride: time.Second}, true},
+		{classify.Outcome{Reason: "http_5xx"}, false},
+	}
+	for _, tc := range cases {
+		if got := IsThrottled(tc.out); got != tc.want {
+			t.Fatalf("IsThrottled(%+v)=%v, want %v", tc.out, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package ratelimit defines client-side rate limiters that bound the total
+// request rate to a dependency, not just the retry rate.
+package ratelimit
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/internal"
+	"github.com/aponysus/recourse/policy"
+)
+
+// DefaultMaxKeys bounds how many per-key limiters a PerKeyLimiter creates when
+// no explicit limit is given.
+const DefaultMaxKeys = 1024
+
+// PerKeyLimiter gives every policy key its own limiter, so one registered
+// name can throttle many keys independently (e.g. a token bucket per key).
+//
+// Each key gets its own limiter from the factory on first use. Keys beyond the
+// limit share one overflow limiter. Lookups of existing keys are lock-free.
+// Outcomes are fed back to the key's limiter when it implements Feedback.
+type PerKeyLimiter struct {
+	newLimiter func(key policy.PolicyKey) Limiter
+	maxKeys    int
+
+	overflowOnce sync.Once
+	overflow     Limiter
+
+	mu   sync.Mutex // serializes writers
+	keys atomic.Pointer[map[policy.PolicyKey]Limiter]
+}
+
+// NewPerKeyLimiter creates a PerKeyLimiter that builds per-key limiters with
+// newLimiter. maxKeys <= 0 uses DefaultMaxKeys.
+func NewPerKeyLimiter(newLimiter func(key policy.PolicyKey) Limiter, maxKeys int) *PerKeyLimiter {
+	if maxKeys <= 0 {
+		maxKeys = DefaultMaxKeys
+	}
+	return &PerKeyLimiter{newLimiter: newLimiter, maxKeys: maxKeys}
+}
+
+// NewPerKeyTokenBucketLimiter creates a PerKeyLimiter with an independent
+// token bucket of ratePerSecond and burst for every key.
+func NewPerKeyTokenBucketLimiter(ratePerSecond float64, burst int) *PerKeyLimiter {
+	return NewPerKeyLimiter(func(policy.PolicyKey) Limiter {
+		return NewTokenBucketLimiter(ratePerSecond, burst)
+	}, 0)
+}
+
+func (p *PerKeyLimiter) Allow(ctx context.Context, key policy.PolicyKey) Decision {
+	if p == nil {
+		return Decision{Allowed: false, Reason: ReasonLimiterNil}
+	}
+	l := p.For(key)
+	if internal.IsTypedNil(l) {
+		return Decision{Allowed: false, Reason: ReasonLimiterNil}
+	}
+	return l.Allow(ctx, key)
+}
+
+func (p *PerKeyLimiter) Observe(ctx context.Context, key policy.PolicyKey, out classify.Outcome) {
+	if p == nil {
+		return
+	}
+	if fb, ok := p.For(key).(Feedback); ok {
+		fb.Observe(ctx, key, out)
+	}
+}
+
+// For returns the limiter used for key, creating it if needed.
+func (p *PerKeyLimiter) For(key policy.PolicyKey) Limiter {
+	if l, ok := p.lookup(key); ok {
+		return l
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if l, ok := p.lookup(key); ok {
+		return l
+	}
+	m := p.keys.Load()
+	if m != nil && len(*m) >= p.maxKeys {
+		return p.overflowLimiter()
+	}
+
+	l := p.newLimiter(key)
+	if internal.IsTypedNil(l) {
+		return p.overflowLimiter()
+	}
+	next := internal.CopyMap(m, 1)
+	next[key] = l
+	p.keys.Store(&next)
+	return l
+}
+
+func (p *PerKeyLimiter) overflowLimiter() Limiter {
+	p.overflowOnce.Do(func() {
+		p.overflow = p.newLimiter(policy.PolicyKey{})
+	})
+	return p.overflow
+}
+
+func (p *PerKeyLimiter) lookup(key policy.PolicyKey) (Limiter, bool) {
+	m := p.keys.Load()
+	if m == nil {
+		return nil, false
+	}
+	l, ok := (*m)[key]
+	return l, ok
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestPerKeyLimiter_IndependentBuckets(t *testing.T) {
+	l := NewPerKeyTokenBucketLimiter(0, 1)
+	ctx := context.Background()
+	a := policy.PolicyKey{Name: "a"}
+	b := policy.PolicyKey{Name: "b"}
+
+	if d := l.Allow(ctx, a); !d.Allowed {
+		t.Fatalf("first call on a denied")
+	}
+	if d := l.Allow(ctx, a); d.Allowed || d.Reason != ReasonRateLimited {
+		t.Fatalf("decision=%+v, want a rate limited", d)
+	}
+	if d := l.Allow(ctx, b); !d.Allowed {
+		t.Fatalf("b should have its own bucket")
+	}
+}
+
+func TestPerKeyLimiter_OverflowKeysShareLimiter(t *testing.T) {
+	created := 0
+	l := NewPerKeyLimiter(func(policy.PolicyKey) Limiter {
+		created++
+		return NewTokenBucketLimiter(0, 1)
+	}, 1)
+
+	first := l.For(policy.PolicyKey{Name: "a"})
+	over1 := l.For(policy.PolicyKey{Name: "b"})
+	over2 := l.For(policy.PolicyKey{Name: "c"})
+	if first == over1 || over1 != over2 {
+		t.Fatalf("keys beyond the limit should share the overflow limiter")
+	}
+	if created != 2 {
+		t.Fatalf("created=%d, want 2 (one key, one overflow)", created)
+	}
+}
+
+func TestPerKeyLimiter_ForwardsFeedback(t *testing.T) {
+	l := NewPerKeyLimiter(func(policy.PolicyKey) Limiter {
+		return NewAdaptiveLimiter(1, 100, 1)
+	}, 0)
+	key := policy.PolicyKey{Name: "a"}
+
+	l.Observe(context.Background(), key, classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "http_429"})
+	if rate := l.For(key).(*AdaptiveLimiter).Rate(); rate != 50 {
+		t.Fatalf("rate=%v, want 50 after throttling", rate)
+	}
+	if rate := l.For(policy.PolicyKey{Name: "b"}).(*AdaptiveLimiter).Rate(); rate != 100 {
+		t.Fatalf("other key rate=%v, want 100", rate)
+	}
+}
+
+func TestPerKeyLimiter_NilReceiver(t *testing.T) {
+	var l *PerKeyLimiter
+	if d := l.Allow(context.Background(), policy.PolicyKey{}); d.Allowed || d.Reason != ReasonLimiterNil {
+		t.Fatalf("decision=%+v, want denied with reason %q", d, ReasonLimiterNil)
+	}
+}
@@ -0,0 +1,12 @@
+package ratelimit
+
+// Standard Decision.Reason strings.
+const (
+	ReasonAllowed            = "allowed"
+	ReasonNoLimiter          = "no_rate_limit"
+	ReasonLimiterNotFound    = "rate_limiter_not_found"
+	ReasonRateLimited        = "rate_limited"
+	ReasonPanicInLimiter     = "panic_in_rate_limiter"
+	ReasonLimiterRegistryNil = "rate_limiter_registry_nil"
+	ReasonLimiterNil         = "rate_limiter_nil"
+)
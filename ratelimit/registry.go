@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aponysus/recourse/internal"
+)
+
+// Registry is a thread-safe name → Limiter map.
+//
+// Lookups are lock-free: registrations copy the map and publish it atomically,
+// since limiters are looked up on every gated call but registered rarely.
+type Registry struct {
+	mu sync.Mutex // serializes writers
+	m  atomic.Pointer[map[string]Limiter]
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register registers a limiter with validation.
+// It returns an error if the registry is nil, the name is empty, or the limiter is nil/typed-nil.
+func (r *Registry) Register(name string, l Limiter) error {
+	if r == nil {
+		return errors.New("registry is nil")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("limiter name cannot be empty")
+	}
+	if internal.IsTypedNil(l) {
+		return errors.New("limiter cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := internal.CopyMap(r.m.Load(), 1)
+	next[name] = l
+	r.m.Store(&next)
+	return nil
+}
+
+// MustRegister registers a limiter and panics on error.
+func (r *Registry) MustRegister(name string, l Limiter) {
+	if err := r.Register(name, l); err != nil {
+		panic("ratelimit.Registry.MustRegister: " + err.Error())
+	}
+}
+
+func (r *Registry) Get(name string) (Limiter, bool) {
+	if r == nil {
+		return nil, false
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false
+	}
+
+	m := r.m.Load()
+	if m == nil {
+		return nil, false
+	}
+	l, ok := (*m)[name]
+	return l, ok && l != nil
+}
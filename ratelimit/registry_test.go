@@ -0,0 +1,32 @@
+package ratelimit
+
+import "testing"
+
+func TestRegistry_RegisterAndGet(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Register(" primary ", UnlimitedLimiter{}); err != nil {
+		t.Fatalf("unexpected register error: %v", err)
+	}
+	if l, ok := reg.Get("primary"); !ok || l == nil {
+		t.Fatalf("expected limiter to be registered")
+	}
+	if _, ok := reg.Get("missing"); ok {
+		t.Fatalf("expected missing limiter lookup to fail")
+	}
+}
+
+func TestRegistry_RegisterValidation(t *testing.T) {
+	var nilReg *Registry
+	if err := nilReg.Register("x", UnlimitedLimiter{}); err == nil {
+		t.Fatal("expected error for nil registry")
+	}
+
+	reg := NewRegistry()
+	if err := reg.Register("   ", UnlimitedLimiter{}); err == nil {
+		t.Fatal("expected error for empty name")
+	}
+	var nilLimiter *TokenBucketLimiter
+	if err := reg.Register("x", nilLimiter); err == nil {
+		t.Fatal("expected error for typed-nil limiter")
+	}
+}
@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+// Standard Decision.Reason strings are defined in reasons.go.
+
+// Decision is the result of a rate limit check.
+type Decision struct {
+	Allowed bool
+	Reason  string
+
+	// RetryAfter, when set on a denial, is how long until the limiter expects
+	// to admit another request.
+	RetryAfter time.Duration
+}
+
+// Limiter admits or rejects calls before their first attempt.
+type Limiter interface {
+	Allow(ctx context.Context, key policy.PolicyKey) Decision
+}
+
+// Feedback is implemented by limiters that adapt to downstream responses.
+// The executor reports every attempt outcome for calls the limiter admitted.
+type Feedback interface {
+	Observe(ctx context.Context, key policy.PolicyKey, out classify.Outcome)
+}
+
+// IsThrottled reports whether out signals server-side throttling: an explicit
+// backoff hint (such as Retry-After) or pushback, HTTP 429, or gRPC RESOURCE_EXHAUSTED.
+func IsThrottled(out classify.Outcome) bool {
+	if out.BackoffOverride > 0 || out.Pushback > 0 {
+		return true
+	}
+	switch out.Reason {
+	case "http_429", "grpc_ResourceExhausted":
+		return true
+	}
+	return false
+}
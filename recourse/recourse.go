@@ -20,11 +20,11 @@ func Init(exec *retry.Executor) {
 }
 
 // Do executes op using the default executor and the policy for key.
-func Do(ctx context.Context, key string, op retry.Operation) error {
-	return retry.DefaultExecutor().Do(ctx, policy.ParseKey(key), op)
+func Do(ctx context.Context, key string, op retry.Operation, opts ...retry.CallOption) error {
+	return retry.DefaultExecutor().Do(ctx, policy.ParseKey(key), op, opts...)
 }
 
 // DoValue executes op using the default executor and the policy for key.
-func DoValue[T any](ctx context.Context, key string, op retry.OperationValue[T]) (T, error) {
-	return retry.DoValue(ctx, retry.DefaultExecutor(), policy.ParseKey(key), op)
+func DoValue[T any](ctx context.Context, key string, op retry.OperationValue[T], opts ...retry.CallOption) (T, error) {
+	return retry.DoValue(ctx, retry.DefaultExecutor(), policy.ParseKey(key), op, opts...)
 }
@@ -0,0 +1,93 @@
+package retry
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+// adaptBackoff updates the adaptive backoff floor of key from an attempt
+// outcome. Retryable failures grow the floor multiplicatively, starting from
+// InitialBackoff and capped by MaxBackoff; successes decay it additively by
+// InitialBackoff. Other outcomes leave it unchanged.
+func (e *Executor) adaptBackoff(key policy.PolicyKey, pol policy.RetryPolicy, out classify.Outcome) {
+	if !pol.AdaptiveBackoff {
+		return
+	}
+	switch out.Kind {
+	case classify.OutcomeRetryable:
+		f := e.adaptiveFloor(key, true)
+		for {
+			cur := f.Load()
+			next := time.Duration(cur)
+			if next < pol.InitialBackoff {
+				next = pol.InitialBackoff
+			} else {
+				next = nextBackoff(next, pol.BackoffMultiplier, pol.MaxBackoff)
+			}
+			if next > pol.MaxBackoff && pol.MaxBackoff > 0 {
+				next = pol.MaxBackoff
+			}
+			if f.CompareAndSwap(cur, int64(next)) {
+				return
+			}
+		}
+	case classify.OutcomeSuccess:
+		f := e.adaptiveFloor(key, false)
+		if f == nil {
+			return
+		}
+		for {
+			cur := f.Load()
+			if cur <= 0 {
+				return
+			}
+			next := cur - int64(pol.InitialBackoff)
+			if next < 0 || pol.InitialBackoff <= 0 {
+				next = 0
+			}
+			if f.CompareAndSwap(cur, next) {
+				return
+			}
+		}
+	}
+}
+
+// adaptiveBackoff raises backoff to the adaptive floor of key for policies
+// with AdaptiveBackoff enabled.
+func (e *Executor) adaptiveBackoff(key policy.PolicyKey, pol policy.RetryPolicy, backoff time.Duration) time.Duration {
+	if !pol.AdaptiveBackoff {
+		return backoff
+	}
+	f := e.adaptiveFloor(key, false)
+	if f == nil {
+		return backoff
+	}
+	if floor := time.Duration(f.Load()); floor > backoff {
+		return floor
+	}
+	return backoff
+}
+
+// adaptiveFloor returns the floor for key, creating it when create is set.
+func (e *Executor) adaptiveFloor(key policy.PolicyKey, create bool) *atomic.Int64 {
+	e.adaptiveMu.RLock()
+	f, ok := e.adaptiveFloors[key]
+	e.adaptiveMu.RUnlock()
+	if ok || !create {
+		return f
+	}
+
+	e.adaptiveMu.Lock()
+	defer e.adaptiveMu.Unlock()
+	if e.adaptiveFloors == nil {
+		e.adaptiveFloors = make(map[policy.PolicyKey]*atomic.Int64)
+	}
+	if f, ok = e.adaptiveFloors[key]; !ok {
+		f = new(atomic.Int64)
+		e.adaptiveFloors[key] = f
+	}
+	return f
+}
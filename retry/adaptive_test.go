@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+var retryableOutcome = classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "test"}
+
+func TestExecutor_AdaptiveBackoff_GrowsAcrossCallsAndDecays(t *testing.T) {
+	key := policy.PolicyKey{Name: "adaptive"}
+	rp := policy.RetryPolicy{
+		MaxAttempts:       2,
+		InitialBackoff:    10 * time.Millisecond,
+		MaxBackoff:        time.Second,
+		BackoffMultiplier: 2,
+		AdaptiveBackoff:   true,
+	}
+	exec, sleeps := newRecordingExecutor(t, policy.EffectivePolicy{Key: key, Retry: rp}, WithClock((&fakeClock{now: time.Unix(100, 0)}).Now))
+
+	fail := func(context.Context) error { return errors.New("boom") }
+	_ = exec.Do(context.Background(), key, fail)
+	_ = exec.Do(context.Background(), key, fail)
+
+	want := []time.Duration{10 * time.Millisecond, 40 * time.Millisecond}
+	if len(*sleeps) != len(want) {
+		t.Fatalf("sleeps=%v, want %v", *sleeps, want)
+	}
+	for i := range want {
+		if (*sleeps)[i] != want[i] {
+			t.Fatalf("sleeps=%v, want %v", *sleeps, want)
+		}
+	}
+
+	if err := exec.Do(context.Background(), key, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := exec.adaptiveBackoff(key, rp, 0); got != 70*time.Millisecond {
+		t.Fatalf("floor after success=%v, want 70ms", got)
+	}
+}
+
+func TestExecutor_AdaptiveBackoff_CappedByMaxBackoff(t *testing.T) {
+	key := policy.PolicyKey{Name: "adaptive"}
+	pol := policy.RetryPolicy{
+		InitialBackoff:    10 * time.Millisecond,
+		MaxBackoff:        25 * time.Millisecond,
+		BackoffMultiplier: 2,
+		AdaptiveBackoff:   true,
+	}
+	exec := NewExecutor()
+	for i := 0; i < 5; i++ {
+		exec.adaptBackoff(key, pol, retryableOutcome)
+	}
+	if got := exec.adaptiveBackoff(key, pol, 0); got != 25*time.Millisecond {
+		t.Fatalf("floor=%v, want 25ms", got)
+	}
+}
+
+func TestExecutor_AdaptiveBackoff_DisabledIgnoresOutcomes(t *testing.T) {
+	key := policy.PolicyKey{Name: "adaptive"}
+	pol := policy.RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: time.Second, BackoffMultiplier: 2}
+	exec := NewExecutor()
+	exec.adaptBackoff(key, pol, retryableOutcome)
+	if got := exec.adaptiveBackoff(key, pol, 5*time.Millisecond); got != 5*time.Millisecond {
+		t.Fatalf("backoff=%v, want unchanged 5ms", got)
+	}
+}
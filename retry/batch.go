@@ -0,0 +1,145 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+// BatchResult is the outcome of one operation in a DoBatch call.
+type BatchResult[T any] struct {
+	Value    T
+	Err      error
+	Attempts int // Rounds in which the operation ran.
+}
+
+// BatchError is returned by DoBatch when at least one operation failed.
+// Errs holds the error of each failed operation, in input order.
+type BatchError struct {
+	Failed int
+	Total  int
+	Errs   []error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("recourse: %d of %d batch operations failed: %v", e.Failed, e.Total, e.Errs[0])
+}
+
+func (e *BatchError) Unwrap() []error {
+	return e.Errs
+}
+
+// DoBatch runs independent operations under the policy for key as one call.
+// Each attempt is a round that runs the still-pending operations
+// concurrently, so budget, circuit breaker and rate limiter decisions are made
+// once per round rather than once per operation.
+//
+// Operations that succeed, or fail with a non-retryable outcome under the
+// policy's classifier, are final; only retryable failures run again in the
+// next round. The returned timeline has one attempt record per round. The
+// error is nil when every operation succeeded and a *BatchError otherwise.
+// Hedging is disabled for batches. With RecoverPanics, an operation that
+// panics fails with a *PanicError and, unless RetryPanics is set, is final.
+func DoBatch[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, ops []OperationValue[T], opts ...CallOption) ([]BatchResult[T], observe.Timeline, error) {
+	results := make([]BatchResult[T], len(ops))
+	if len(ops) == 0 {
+		return results, observe.Timeline{Key: key}, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if exec == nil {
+		exec = NewExecutor()
+	}
+	exec.ensureInitialized()
+
+	classifier := batchClassifier(ctx, exec, key, opts)
+	final := make([]bool, len(ops))
+
+	var mu sync.Mutex
+	callStart := exec.clock()
+	_, tl, callErr := doValueInternal(ctx, exec, key, func(attemptCtx context.Context) (struct{}, error) {
+		var wg sync.WaitGroup
+		var retryErr error
+		for i, op := range ops {
+			mu.Lock()
+			skip, attempt := final[i], results[i].Attempts
+			mu.Unlock()
+			if skip {
+				continue
+			}
+
+			wg.Add(1)
+			go func(i int, op OperationValue[T]) {
+				defer wg.Done()
+				val, err := callOperation(exec, attemptCtx, key, op)
+				ac := classify.AttemptContext{Key: key, Attempt: attempt, Elapsed: exec.clock().Sub(callStart)}
+				out, panicErr := classifyWithRecovery(exec.recoverPanics, classifier, val, err, ac)
+				if panicErr != nil {
+					err = panicErr
+				} else {
+					exec.classifyOperationPanic(&out, err)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				results[i].Value, results[i].Err = val, err
+				results[i].Attempts++
+				if out.Kind == classify.OutcomeRetryable {
+					if retryErr == nil {
+						retryErr = err
+					}
+					return
+				}
+				final[i] = true
+			}(i, op)
+		}
+		wg.Wait()
+
+		// The round fails with a retryable operation's own error, so the
+		// executor classifies it like the operation and retries the round.
+		return struct{}{}, retryErr
+	}, true, append(opts[:len(opts):len(opts)], OverrideHedging(false))...)
+
+	var errs []error
+	for i := range results {
+		if results[i].Attempts == 0 && callErr != nil {
+			results[i].Err = callErr
+		}
+		if results[i].Err != nil {
+			errs = append(errs, results[i].Err)
+		}
+	}
+	if len(errs) == 0 {
+		return results, tl, nil
+	}
+	return results, tl, &BatchError{Failed: len(errs), Total: len(ops), Errs: errs}
+}
+
+// batchClassifier resolves the classifier DoBatch uses to decide which
+// operations to retry. When the policy cannot be resolved it falls back to the
+// executor's default; the call itself then fails the same way.
+func batchClassifier(ctx context.Context, exec *Executor, key policy.PolicyKey, opts []CallOption) classify.Classifier {
+	pol, err := resolvePolicyFast(ctx, exec, key)
+	if err != nil {
+		return exec.defaultClassifier
+	}
+	cfg := newCallConfig(opts)
+	if cfg.hasOverrides() {
+		if pol, _, err = cfg.applyOverrides(pol); err != nil {
+			return exec.defaultClassifier
+		}
+	}
+	classifier, _, err := resolveCallClassifier(exec, pol, &cfg)
+	if err != nil {
+		return exec.defaultClassifier
+	}
+	if !pol.RetryOn.IsZero() || !pol.AbortOn.IsZero() || len(pol.ReasonOverrides) > 0 {
+		return matcherClassifier{exec: exec, pol: pol, inner: classifier}
+	}
+	return classifier
+}
@@ -0,0 +1,150 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestDoBatch_RetriesOnlyFailedSubset(t *testing.T) {
+	key := policy.PolicyKey{Name: "batch"}
+	exec, _ := newRecordingExecutor(t, policy.New("batch", policy.MaxAttempts(3)))
+
+	var calls [3]atomic.Int32
+	ops := []OperationValue[int]{
+		func(context.Context) (int, error) { calls[0].Add(1); return 10, nil },
+		func(context.Context) (int, error) {
+			if calls[1].Add(1) == 1 {
+				return 0, errors.New("flaky")
+			}
+			return 20, nil
+		},
+		func(context.Context) (int, error) { calls[2].Add(1); return 30, nil },
+	}
+
+	results, tl, err := DoBatch(context.Background(), exec, key, ops)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []int{10, 20, 30} {
+		if results[i].Value != want || results[i].Err != nil {
+			t.Fatalf("results[%d]=%+v, want %d", i, results[i], want)
+		}
+	}
+	if calls[0].Load() != 1 || calls[1].Load() != 2 || calls[2].Load() != 1 {
+		t.Fatalf("calls=[%d %d %d], want [1 2 1]", calls[0].Load(), calls[1].Load(), calls[2].Load())
+	}
+	if results[1].Attempts != 2 || len(tl.Attempts) != 2 {
+		t.Fatalf("item attempts=%d rounds=%d, want 2 and 2", results[1].Attempts, len(tl.Attempts))
+	}
+}
+
+// fatalClassifier treats fatal as non-retryable and any other error as retryable.
+type fatalClassifier struct{ fatal error }
+
+func (c fatalClassifier) Classify(_ any, err error) classify.Outcome {
+	switch {
+	case err == nil:
+		return classify.Outcome{Kind: classify.OutcomeSuccess, Reason: "success"}
+	case errors.Is(err, c.fatal):
+		return classify.Outcome{Kind: classify.OutcomeNonRetryable, Reason: "fatal"}
+	default:
+		return classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "flaky"}
+	}
+}
+
+func TestDoBatch_NonRetryableItemIsFinal(t *testing.T) {
+	key := policy.PolicyKey{Name: "batch"}
+	exec, _ := newRecordingExecutor(t, policy.New("batch", policy.MaxAttempts(3), policy.Classifier("batch")))
+	fatal := errors.New("fatal")
+	exec.classifiers.Register("batch", fatalClassifier{fatal: fatal})
+
+	var fatalCalls, flakyCalls atomic.Int32
+	flaky := errors.New("flaky")
+	ops := []OperationValue[int]{
+		func(context.Context) (int, error) { fatalCalls.Add(1); return 0, fatal },
+		func(context.Context) (int, error) { flakyCalls.Add(1); return 0, flaky },
+	}
+
+	results, tl, err := DoBatch(context.Background(), exec, key, ops)
+	var berr *BatchError
+	if !errors.As(err, &berr) || berr.Failed != 2 || berr.Total != 2 {
+		t.Fatalf("err=%v, want BatchError with 2 failures", err)
+	}
+	if !errors.Is(err, fatal) || !errors.Is(err, flaky) {
+		t.Fatalf("err=%v, want both item errors", err)
+	}
+	if fatalCalls.Load() != 1 || flakyCalls.Load() != 3 {
+		t.Fatalf("fatal=%d flaky=%d, want 1 and 3", fatalCalls.Load(), flakyCalls.Load())
+	}
+	if results[0].Attempts != 1 || results[1].Attempts != 3 || len(tl.Attempts) != 3 {
+		t.Fatalf("results=%+v rounds=%d", results, len(tl.Attempts))
+	}
+}
+
+func TestDoBatch_CallFailureAppliesToEveryItem(t *testing.T) {
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Provider:          stubProvider{err: controlplane.ErrProviderUnavailable},
+		MissingPolicyMode: FailureDeny,
+	})
+	ops := []OperationValue[int]{
+		func(context.Context) (int, error) { t.Fatal("should not run"); return 0, nil },
+		func(context.Context) (int, error) { t.Fatal("should not run"); return 0, nil },
+	}
+
+	results, _, err := DoBatch(context.Background(), exec, policy.PolicyKey{Name: "missing"}, ops)
+	if !errors.Is(err, ErrNoPolicy) {
+		t.Fatalf("err=%v, want ErrNoPolicy", err)
+	}
+	for i, r := range results {
+		if !errors.Is(r.Err, ErrNoPolicy) || r.Attempts != 0 {
+			t.Fatalf("results[%d]=%+v, want ErrNoPolicy without attempts", i, r)
+		}
+	}
+}
+
+func TestDoBatch_Empty(t *testing.T) {
+	results, _, err := DoBatch[int](context.Background(), nil, policy.PolicyKey{Name: "x"}, nil)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("results=%v err=%v, want empty success", results, err)
+	}
+}
+
+func TestDoBatch_RecoversItemPanic(t *testing.T) {
+	key := policy.PolicyKey{Name: "batch"}
+	for _, retryPanics := range []bool{false, true} {
+		exec, _ := newRecordingExecutor(t, policy.New("batch", policy.MaxAttempts(3)),
+			WithRecoverPanics(true), WithRetryPanics(retryPanics))
+
+		var panics atomic.Int32
+		ops := []OperationValue[int]{
+			func(context.Context) (int, error) { return 10, nil },
+			func(context.Context) (int, error) {
+				if panics.Add(1) == 1 {
+					panic("boom")
+				}
+				return 20, nil
+			},
+		}
+
+		results, _, err := DoBatch(context.Background(), exec, key, ops)
+		if results[0].Value != 10 || results[0].Err != nil {
+			t.Fatalf("retryPanics=%v: results[0]=%+v, want 10", retryPanics, results[0])
+		}
+		if retryPanics {
+			if err != nil || results[1].Value != 20 || results[1].Attempts != 2 {
+				t.Fatalf("results[1]=%+v err=%v, want 20 after a retried panic", results[1], err)
+			}
+			continue
+		}
+		var pe *PanicError
+		if !errors.As(err, &pe) || !errors.As(results[1].Err, &pe) || pe.Component != "operation" || results[1].Attempts != 1 {
+			t.Fatalf("results[1]=%+v err=%v, want one attempt failing with an operation PanicError", results[1], err)
+		}
+	}
+}
@@ -6,11 +6,137 @@ import (
 	"sync"
 
 	"github.com/aponysus/recourse/budget"
+	"github.com/aponysus/recourse/classify"
 	"github.com/aponysus/recourse/internal"
 	"github.com/aponysus/recourse/observe"
 	"github.com/aponysus/recourse/policy"
 )
 
+// allowAttempts checks ref and then each of extra; all must allow the attempt.
+// When one denies, the decisions already granted are released and the denying
+// decision is returned with Budget set to its name. The allowed decision
+// releases every budget when done.
+func (e *Executor) allowAttempts(ctx context.Context, key policy.PolicyKey, ref policy.BudgetRef, extra []policy.BudgetRef, attemptIdx int, kind budget.AttemptKind) (budget.Decision, bool) {
+	if len(extra) == 0 {
+		decision, ok := e.allowAttempt(ctx, key, ref, attemptIdx, kind)
+		if !ok {
+			decision.Budget = strings.TrimSpace(ref.Name)
+		}
+		return decision, ok
+	}
+
+	held := make([]budget.Decision, 0, 1+len(extra))
+	check := func(ref policy.BudgetRef) (budget.Decision, bool) {
+		decision, ok := e.allowAttempt(ctx, key, ref, attemptIdx, kind)
+		if !ok {
+			for _, d := range held {
+				d.Done()
+			}
+			decision.Budget = strings.TrimSpace(ref.Name)
+			return decision, false
+		}
+		held = append(held, decision)
+		return decision, true
+	}
+
+	if strings.TrimSpace(ref.Name) != "" {
+		if d, ok := check(ref); !ok {
+			return d, false
+		}
+	}
+	for _, r := range extra {
+		if d, ok := check(r); !ok {
+			return d, false
+		}
+	}
+
+	return budget.Decision{
+		Allowed: true,
+		Reason:  held[0].Reason,
+		Release: func() {
+			for _, d := range held {
+				d.Done()
+			}
+		},
+	}, true
+}
+
+// peekAttempts reports whether ref and each of extra would allow an attempt,
+// like allowAttempts but without consuming budget or notifying the observer.
+// It returns the first denying decision with Budget set to its name, and the
+// names of budgets that cannot be peeked (see budget.Peeker), which are
+// treated as allowing.
+func (e *Executor) peekAttempts(ctx context.Context, key policy.PolicyKey, ref policy.BudgetRef, extra []policy.BudgetRef, attemptIdx int, kind budget.AttemptKind) (decision budget.Decision, allowed bool, unchecked []string) {
+	decision = budget.Decision{Allowed: true, Reason: budget.ReasonNoBudget}
+	check := func(ref policy.BudgetRef) bool {
+		name := strings.TrimSpace(ref.Name)
+		if name == "" {
+			return true
+		}
+		d := e.peekAttempt(ctx, key, name, ref, attemptIdx, kind)
+		if d.Reason == budget.ReasonPeekUnsupported {
+			unchecked = append(unchecked, name)
+			return true
+		}
+		if !d.Allowed {
+			d.Budget = name
+			decision = d
+			return false
+		}
+		if decision.Reason == budget.ReasonNoBudget {
+			decision = d
+		}
+		return true
+	}
+
+	if !check(ref) {
+		return decision, false, unchecked
+	}
+	for _, r := range extra {
+		if !check(r) {
+			return decision, false, unchecked
+		}
+	}
+	return decision, true, unchecked
+}
+
+func (e *Executor) peekAttempt(ctx context.Context, key policy.PolicyKey, name string, ref policy.BudgetRef, attemptIdx int, kind budget.AttemptKind) (decision budget.Decision) {
+	if e == nil {
+		return budget.Decision{Allowed: true, Reason: budget.ReasonNoBudget}
+	}
+	if e.budgets == nil {
+		d, _ := e.handleMissingBudget(ctx, budget.ReasonBudgetRegistryNil)
+		return d
+	}
+	b, ok := e.budgets.Get(name)
+	if !ok {
+		d, _ := e.handleMissingBudget(ctx, budget.ReasonBudgetNotFound)
+		return d
+	}
+	if internal.IsTypedNil(b) {
+		d, _ := e.handleMissingBudget(ctx, budget.ReasonBudgetNil)
+		return d
+	}
+
+	if e.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				decision = budget.Decision{Allowed: false, Reason: budget.ReasonPanicInBudget}
+			}
+		}()
+	}
+	ref.Name = name
+	decision = budget.Peek(ctx, b, key, attemptIdx, kind, ref)
+	if decision.Reason == "" {
+		if decision.Allowed {
+			decision.Reason = budget.ReasonAllowed
+		} else {
+			decision.Reason = budget.ReasonBudgetDenied
+		}
+	}
+	return decision
+}
+
 func (e *Executor) allowAttempt(ctx context.Context, key policy.PolicyKey, ref policy.BudgetRef, attemptIdx int, kind budget.AttemptKind) (decision budget.Decision, allowed bool) {
 	if e == nil {
 		return budget.Decision{Allowed: true, Reason: budget.ReasonNoBudget}, true
@@ -99,9 +225,83 @@ func (e *Executor) allowAttempt(ctx context.Context, key policy.PolicyKey, ref p
 	}
 
 	emit(decision, decision.Allowed)
+	e.updateBudgetState(ctx, key, ref.Name, attemptIdx, kind, decision)
 	return decision, decision.Allowed
 }
 
+// trackRequest reports a call's primary attempt to every budget pol references
+// that implements budget.RequestTracker.
+func (e *Executor) trackRequest(ctx context.Context, key policy.PolicyKey, pol policy.EffectivePolicy) {
+	e.forEachBudget(pol, func(b budget.Budget, ref policy.BudgetRef) {
+		if rt, ok := b.(budget.RequestTracker); ok {
+			rt.TrackRequest(ctx, key, ref)
+		}
+	})
+}
+
+// observeBudgets reports an attempt outcome to every budget pol references that
+// implements budget.Feedback. Aborts (cancellation, budget denials) say nothing
+// about downstream health and are skipped.
+func (e *Executor) observeBudgets(ctx context.Context, key policy.PolicyKey, pol policy.EffectivePolicy, out classify.Outcome) {
+	if out.Kind == classify.OutcomeAbort {
+		return
+	}
+	e.forEachBudget(pol, func(b budget.Budget, _ policy.BudgetRef) {
+		if fb, ok := b.(budget.Feedback); ok {
+			fb.Observe(ctx, key, out)
+		}
+	})
+}
+
+// forEachBudget calls fn once for every registered budget pol references,
+// including hedge budgets when hedging is enabled. Missing budgets are left to
+// the attempt checks to report, and panics in fn are recovered when the
+// executor recovers panics.
+func (e *Executor) forEachBudget(pol policy.EffectivePolicy, fn func(budget.Budget, policy.BudgetRef)) {
+	if e == nil || e.budgets == nil {
+		return
+	}
+	if pol.Retry.Budget.Name == "" && len(pol.Retry.Budgets) == 0 && (!pol.Hedge.Enabled || pol.Hedge.Budget.Name == "" && len(pol.Hedge.Budgets) == 0) {
+		return
+	}
+
+	var buf [4]string
+	seen := buf[:0]
+	visit := func(ref policy.BudgetRef) {
+		name := strings.TrimSpace(ref.Name)
+		if name == "" {
+			return
+		}
+		for _, s := range seen {
+			if s == name {
+				return
+			}
+		}
+		seen = append(seen, name)
+		if b, ok := e.budgets.Get(name); ok && !internal.IsTypedNil(b) {
+			e.visitBudget(fn, b, ref)
+		}
+	}
+
+	visit(pol.Retry.Budget)
+	for _, ref := range pol.Retry.Budgets {
+		visit(ref)
+	}
+	if pol.Hedge.Enabled {
+		visit(pol.Hedge.Budget)
+		for _, ref := range pol.Hedge.Budgets {
+			visit(ref)
+		}
+	}
+}
+
+func (e *Executor) visitBudget(fn func(budget.Budget, policy.BudgetRef), b budget.Budget, ref policy.BudgetRef) {
+	if e.recoverPanics {
+		defer func() { _ = recover() }()
+	}
+	fn(b, ref)
+}
+
 func (e *Executor) handleMissingBudget(ctx context.Context, reason string) (budget.Decision, bool) {
 	switch e.missingBudgetMode {
 	case FailureAllow, FailureAllowUnsafe:
@@ -0,0 +1,71 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/aponysus/recourse/budget"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+// budgetStateKey identifies an exhaustion state: budgets such as
+// FixedWindowBudget and TenantBudget can be exhausted for one key or tenant
+// and not for another.
+type budgetStateKey struct {
+	name   string
+	key    policy.PolicyKey
+	tenant string
+}
+
+// updateBudgetState reports budget name becoming exhausted or recovering to an
+// observe.BudgetStateObserver, per policy key and tenant. A budget is exhausted
+// from the first attempt it denies until the next retry or hedge it allows.
+// Primary attempts are allowed by many budgets regardless of their state and
+// do not count as recovery. Priority shedding denies only some attempts and
+// does not count as exhaustion.
+func (e *Executor) updateBudgetState(ctx context.Context, key policy.PolicyKey, name string, attemptIdx int, kind budget.AttemptKind, d budget.Decision) {
+	so, ok := e.observer.(observe.BudgetStateObserver)
+	if !ok {
+		return
+	}
+	tenant, _ := policy.TenantFromContext(ctx)
+	sk := budgetStateKey{name: name, key: key, tenant: tenant}
+
+	if d.Allowed {
+		if attemptIdx == 0 && kind == budget.KindRetry {
+			return
+		}
+		v, ok := e.budgetStates.Load(sk)
+		if !ok || !e.budgetStates.CompareAndDelete(sk, v) {
+			return
+		}
+		start := time.Unix(0, v.(int64))
+		so.OnBudgetStateChange(ctx, observe.BudgetStateEvent{
+			Key:        key,
+			BudgetName: name,
+			Exhausted:  false,
+			Since:      start,
+			Duration:   e.clock().Sub(start),
+		})
+		return
+	}
+
+	if d.Reason == budget.ReasonPriorityShed {
+		return
+	}
+	if _, ok := e.budgetStates.Load(sk); ok {
+		return
+	}
+	now := e.clock()
+	if _, loaded := e.budgetStates.LoadOrStore(sk, now.UnixNano()); loaded {
+		return
+	}
+	so.OnBudgetStateChange(ctx, observe.BudgetStateEvent{
+		Key:        key,
+		BudgetName: name,
+		Exhausted:  true,
+		Reason:     d.Reason,
+		Since:      now,
+	})
+}
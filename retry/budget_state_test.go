@@ -0,0 +1,122 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/budget"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+// switchBudget always allows primary attempts, like RatioBudget, and denies
+// retries and hedges while deny is set.
+type switchBudget struct {
+	deny   atomic.Bool
+	reason string
+}
+
+func (b *switchBudget) AllowAttempt(_ context.Context, _ policy.PolicyKey, attemptIdx int, kind budget.AttemptKind, _ policy.BudgetRef) budget.Decision {
+	if (attemptIdx > 0 || kind == budget.KindHedge) && b.deny.Load() {
+		return budget.Decision{Allowed: false, Reason: b.reason}
+	}
+	return budget.Decision{Allowed: true, Reason: budget.ReasonAllowed}
+}
+
+type budgetStateRecorder struct {
+	observe.BaseObserver
+	events []observe.BudgetStateEvent
+}
+
+func (r *budgetStateRecorder) OnBudgetStateChange(_ context.Context, ev observe.BudgetStateEvent) {
+	r.events = append(r.events, ev)
+}
+
+func newBudgetStateExecutor(t *testing.T, sb *switchBudget, keys ...policy.PolicyKey) (*Executor, *budgetStateRecorder, *fakeClock) {
+	t.Helper()
+	budgets := budget.NewRegistry()
+	budgets.MustRegister("b", sb)
+
+	opts := []ExecutorOption{WithBudgetRegistry(budgets)}
+	for _, key := range keys {
+		opts = append(opts, WithPolicyKey(key, policy.MaxAttempts(2), policy.Budget("b")))
+	}
+	exec := NewExecutor(opts...)
+	rec := &budgetStateRecorder{}
+	clock := &fakeClock{now: time.Unix(100, 0)}
+	exec.observer = rec
+	exec.clock = clock.Now
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+	return exec, rec, clock
+}
+
+func failingOp(context.Context) error { return errors.New("fail") }
+
+func TestExecutor_BudgetStateEvents(t *testing.T) {
+	key := policy.PolicyKey{Name: "budget_state"}
+	sb := &switchBudget{reason: budget.ReasonBudgetDenied}
+	exec, rec, clock := newBudgetStateExecutor(t, sb, key)
+
+	_ = exec.Do(context.Background(), key, failingOp)
+	sb.deny.Store(true)
+
+	// Primary attempts keep flowing while every retry is denied.
+	for i := 0; i < 3; i++ {
+		_ = exec.Do(context.Background(), key, failingOp)
+		clock.Advance(10 * time.Second)
+	}
+	if len(rec.events) != 1 {
+		t.Fatalf("events=%+v, want 1 exhaustion event and no recovery from primary attempts", rec.events)
+	}
+	ev := rec.events[0]
+	if !ev.Exhausted || ev.BudgetName != "b" || ev.Reason != budget.ReasonBudgetDenied || ev.Key != key || !ev.Since.Equal(time.Unix(100, 0)) {
+		t.Fatalf("exhaustion event=%+v", ev)
+	}
+
+	sb.deny.Store(false)
+	_ = exec.Do(context.Background(), key, failingOp)
+	_ = exec.Do(context.Background(), key, failingOp)
+	if len(rec.events) != 2 {
+		t.Fatalf("events=%d, want 2", len(rec.events))
+	}
+	ev = rec.events[1]
+	if ev.Exhausted || ev.Reason != "" || ev.Duration != 30*time.Second || !ev.Since.Equal(time.Unix(100, 0)) {
+		t.Fatalf("recovery event=%+v", ev)
+	}
+}
+
+func TestExecutor_BudgetStateEvents_PerKeyAndTenant(t *testing.T) {
+	a := policy.PolicyKey{Name: "budget_state_a"}
+	b := policy.PolicyKey{Name: "budget_state_b"}
+	sb := &switchBudget{reason: budget.ReasonBudgetDenied}
+	exec, rec, _ := newBudgetStateExecutor(t, sb, a, b)
+
+	sb.deny.Store(true)
+	_ = exec.Do(context.Background(), a, failingOp)
+	sb.deny.Store(false)
+	_ = exec.Do(context.Background(), b, failingOp)
+	_ = exec.Do(policy.WithTenant(context.Background(), "t1"), a, failingOp)
+	if len(rec.events) != 1 || !rec.events[0].Exhausted || rec.events[0].Key != a {
+		t.Fatalf("events=%+v, want only key a exhausted", rec.events)
+	}
+
+	_ = exec.Do(context.Background(), a, failingOp)
+	if len(rec.events) != 2 || rec.events[1].Exhausted || rec.events[1].Key != a {
+		t.Fatalf("events=%+v, want key a recovered", rec.events)
+	}
+}
+
+func TestExecutor_BudgetStateEvents_IgnorePriorityShedding(t *testing.T) {
+	key := policy.PolicyKey{Name: "budget_state_shed"}
+	sb := &switchBudget{reason: budget.ReasonPriorityShed}
+	sb.deny.Store(true)
+	exec, rec, _ := newBudgetStateExecutor(t, sb, key)
+
+	_ = exec.Do(context.Background(), key, failingOp)
+	if len(rec.events) != 0 {
+		t.Fatalf("events=%+v, want none for priority shedding", rec.events)
+	}
+}
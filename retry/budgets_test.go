@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -38,6 +39,27 @@ func (b *countingReleaseBudget) AllowAttempt(_ context.Context, _ policy.PolicyK
 	}
 }
 
+type tokenReleaseBudget struct {
+	next     atomic.Uint64
+	released []uint64
+	mu       sync.Mutex
+}
+
+func (b *tokenReleaseBudget) AllowAttempt(_ context.Context, _ policy.PolicyKey, _ int, _ budget.AttemptKind, _ policy.BudgetRef) budget.Decision {
+	return budget.Decision{
+		Allowed:  true,
+		Reason:   budget.ReasonAllowed,
+		Releaser: b,
+		Token:    b.next.Add(1),
+	}
+}
+
+func (b *tokenReleaseBudget) ReleaseAttempt(token uint64) {
+	b.mu.Lock()
+	b.released = append(b.released, token)
+	b.mu.Unlock()
+}
+
 type budgetEventObserver struct {
 	events []observe.BudgetDecisionEvent
 }
@@ -277,6 +299,114 @@ func TestExecutor_AllowAttempt_ReleaseIsIdempotent(t *testing.T) {
 	}
 }
 
+func TestExecutor_BudgetReleaser_CalledWithTokenPerAttempt(t *testing.T) {
+	key := policy.PolicyKey{Name: "x"}
+
+	tb := &tokenReleaseBudget{}
+	budgets := budget.NewRegistry()
+	budgets.MustRegister("b", tb)
+
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Budgets: budgets,
+		Provider: &controlplane.StaticProvider{
+			Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+				key: {
+					Key: key,
+					Retry: policy.RetryPolicy{
+						MaxAttempts: 3,
+						Budget:      policy.BudgetRef{Name: "b", Cost: 1},
+					},
+				},
+			},
+		},
+	})
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+	_, err := DoValue[int](context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, errors.New("nope")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	if len(tb.released) != 3 {
+		t.Fatalf("released=%v, want 3 tokens", tb.released)
+	}
+	for i, tok := range tb.released {
+		if tok != uint64(i+1) {
+			t.Fatalf("released[%d]=%d, want %d", i, tok, i+1)
+		}
+	}
+}
+
+func TestExecutor_AllowAttempt_ReleaserDoesNotAllocate(t *testing.T) {
+	key := policy.PolicyKey{Name: "x"}
+	tb := &tokenReleaseBudget{released: make([]uint64, 0, 1024)}
+	budgets := budget.NewRegistry()
+	budgets.MustRegister("b", tb)
+
+	exec := NewExecutorFromOptions(ExecutorOptions{Budgets: budgets})
+	ctx := context.Background()
+	ref := policy.BudgetRef{Name: "b", Cost: 1}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		d, _ := exec.allowAttempt(ctx, key, ref, 0, budget.KindRetry)
+		d.Done()
+	})
+	if allocs != 0 {
+		t.Fatalf("allocs=%v, want 0", allocs)
+	}
+}
+
+type priorityRecordingBudget struct {
+	seen []policy.Priority
+}
+
+func (b *priorityRecordingBudget) AllowAttempt(ctx context.Context, _ policy.PolicyKey, _ int, _ budget.AttemptKind, _ policy.BudgetRef) budget.Decision {
+	p, _ := policy.PriorityFromContext(ctx)
+	b.seen = append(b.seen, p)
+	return budget.Decision{Allowed: true, Reason: budget.ReasonAllowed}
+}
+
+func TestExecutor_BudgetSeesPriority(t *testing.T) {
+	key := policy.PolicyKey{Name: "x"}
+	rb := &priorityRecordingBudget{}
+	budgets := budget.NewRegistry()
+	budgets.MustRegister("b", rb)
+
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Budgets: budgets,
+		Provider: &controlplane.StaticProvider{
+			Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+				key: {
+					Key:      key,
+					Priority: policy.PriorityLow,
+					Retry: policy.RetryPolicy{
+						MaxAttempts: 1,
+						Budget:      policy.BudgetRef{Name: "b", Cost: 1},
+					},
+				},
+			},
+		},
+	})
+	op := func(context.Context) (int, error) { return 1, nil }
+
+	if _, err := DoValue[int](context.Background(), exec, key, op); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := policy.WithPriority(context.Background(), policy.PriorityHigh)
+	if _, err := DoValue[int](ctx, exec, key, op); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []policy.Priority{policy.PriorityLow, policy.PriorityHigh}
+	if len(rb.seen) != len(want) || rb.seen[0] != want[0] || rb.seen[1] != want[1] {
+		t.Fatalf("seen=%v, want %v", rb.seen, want)
+	}
+}
+
 type panicBudget struct{}
 
 func (panicBudget) AllowAttempt(context.Context, policy.PolicyKey, int, budget.AttemptKind, policy.BudgetRef) budget.Decision {
@@ -399,3 +529,182 @@ func TestExecutor_AllowAttempt_DefaultReason(t *testing.T) {
 		t.Fatalf("decision=%+v ok=%v, want denied/budget_denied", d, ok)
 	}
 }
+
+func TestExecutor_MultipleBudgets_AllMustAllow(t *testing.T) {
+	key := policy.ParseKey("svc.MultiBudget")
+
+	perKey := &countingReleaseBudget{}
+	global := &tokenReleaseBudget{}
+	budgets := budget.NewRegistry()
+	budgets.MustRegister("per-key", perKey)
+	budgets.MustRegister("global", global)
+	budgets.MustRegister("deny-retries", denySecondAttemptBudget{})
+
+	pol := policy.New(key.String(), policy.MaxAttempts(3), policy.Budget("per-key"), policy.AlsoBudget("global"))
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Budgets:  budgets,
+		Provider: &controlplane.StaticProvider{Policies: map[policy.PolicyKey]policy.EffectivePolicy{key: pol}},
+	})
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+	calls := 0
+	err := exec.Do(context.Background(), key, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("err=%v calls=%d, want success after 3 calls", err, calls)
+	}
+	if got := atomic.LoadInt32(&perKey.releases); got != 3 {
+		t.Fatalf("per-key releases=%d, want 3", got)
+	}
+	if len(global.released) != 3 {
+		t.Fatalf("global released=%v, want 3 tokens", global.released)
+	}
+
+	// A denial by the last budget releases the ones that allowed and names
+	// the denying budget.
+	denied := policy.New(key.String(), policy.MaxAttempts(3), policy.Budget("per-key"), policy.AlsoBudget("global"), policy.AlsoBudget("deny-retries"))
+	exec = NewExecutorFromOptions(ExecutorOptions{
+		Budgets:  budgets,
+		Provider: &controlplane.StaticProvider{Policies: map[policy.PolicyKey]policy.EffectivePolicy{key: denied}},
+	})
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+	perKey.allowCalls, perKey.releases = 0, 0
+
+	_, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, errors.New("transient")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(tl.Attempts) != 2 {
+		t.Fatalf("attempts=%d, want 2", len(tl.Attempts))
+	}
+	rec := tl.Attempts[1]
+	if rec.BudgetAllowed || rec.BudgetName != "deny-retries" || rec.BudgetReason != budget.ReasonBudgetDenied {
+		t.Fatalf("denied record=%+v, want deny-retries denial", rec)
+	}
+	if tl.Attempts[0].BudgetName != "" {
+		t.Fatalf("allowed record names budget %q", tl.Attempts[0].BudgetName)
+	}
+	if calls, releases := atomic.LoadInt32(&perKey.allowCalls), atomic.LoadInt32(&perKey.releases); calls != 2 || releases != 2 {
+		t.Fatalf("per-key allowCalls=%d releases=%d, want 2 and 2", calls, releases)
+	}
+}
+
+func TestExecutor_RatioBudget_TracksPrimaryAttempts(t *testing.T) {
+	for _, timeline := range []bool{false, true} {
+		key := policy.PolicyKey{Name: "ratio"}
+		ratio := budget.NewRatioBudget(0.5, time.Minute, 0)
+
+		budgets := budget.NewRegistry()
+		budgets.MustRegister("ratio", ratio)
+
+		exec := newTestExecutor(t, key, policy.EffectivePolicy{
+			Key: key,
+			Retry: policy.RetryPolicy{
+				MaxAttempts: 2,
+				Budget:      policy.BudgetRef{Name: "ratio"},
+			},
+		})
+		exec.budgets = budgets
+
+		ctx := context.Background()
+		if timeline {
+			ctx, _ = observe.RecordTimeline(ctx)
+		}
+		calls := 0
+		for i := 0; i < 4; i++ {
+			_ = exec.Do(ctx, key, func(context.Context) error {
+				calls++
+				return errors.New("fail")
+			})
+		}
+
+		// Half a retry per request: the second and fourth calls may retry.
+		requests, retries := ratio.Counts()
+		if requests != 4 || retries != 2 {
+			t.Fatalf("timeline=%v: counts=%d,%d, want 4,2", timeline, requests, retries)
+		}
+		if calls != 6 {
+			t.Fatalf("timeline=%v: calls=%d, want 6", timeline, calls)
+		}
+	}
+}
+
+func TestExecutor_AdaptiveBudget_SuppressesRetriesDuringOutage(t *testing.T) {
+	for _, timeline := range []bool{false, true} {
+		key := policy.PolicyKey{Name: "adaptive"}
+		adaptive := budget.NewAdaptiveBudget(100, 100, time.Minute)
+
+		budgets := budget.NewRegistry()
+		budgets.MustRegister("adaptive", adaptive)
+
+		exec := newTestExecutor(t, key, policy.EffectivePolicy{
+			Key: key,
+			Retry: policy.RetryPolicy{
+				MaxAttempts: 3,
+				Budget:      policy.BudgetRef{Name: "adaptive"},
+			},
+		})
+		exec.budgets = budgets
+
+		ctx := context.Background()
+		if timeline {
+			ctx, _ = observe.RecordTimeline(ctx)
+		}
+		calls := 0
+		for i := 0; i < 10; i++ {
+			_ = exec.Do(ctx, key, func(context.Context) error {
+				calls++
+				return errors.New("down")
+			})
+		}
+
+		// The first three calls retry twice each. The fourth call's primary
+		// attempt is the tenth failed sample, and from then on only primary
+		// attempts run.
+		if rate, ok := adaptive.SuccessRate(); !ok || rate != 0 {
+			t.Fatalf("timeline=%v: rate=%v ok=%v, want 0 true", timeline, rate, ok)
+		}
+		if calls != 3*3+7 {
+			t.Fatalf("timeline=%v: calls=%d, want 16", timeline, calls)
+		}
+	}
+}
+
+func TestExecutor_ConcurrencyBudget_ReleasesSlots(t *testing.T) {
+	for _, timeline := range []bool{false, true} {
+		key := policy.PolicyKey{Name: "concurrency"}
+		slots := budget.NewConcurrencyBudget(1)
+
+		exec := NewExecutor(
+			WithBudget("slots", slots),
+			WithPolicyKey(key, policy.MaxAttempts(3), policy.Budget("slots")),
+		)
+		exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+		ctx := context.Background()
+		if timeline {
+			ctx, _ = observe.RecordTimeline(ctx)
+		}
+		calls := 0
+		_ = exec.Do(ctx, key, func(context.Context) error {
+			calls++
+			if got := slots.InFlight(); calls > 1 && got != 1 {
+				t.Errorf("timeline=%v: inFlight=%d during retry, want 1", timeline, got)
+			}
+			return errors.New("fail")
+		})
+		if calls != 3 {
+			t.Fatalf("timeline=%v: calls=%d, want 3", timeline, calls)
+		}
+		if got := slots.InFlight(); got != 0 {
+			t.Fatalf("timeline=%v: inFlight=%d after call, want 0", timeline, got)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/aponysus/recourse/bulkhead"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+// acquireBulkhead admits the call into the key's bulkhead before the first
+// attempt. It returns the bulkhead (nil when none applies) so the caller can
+// release its slot when the call, including hedges, completes.
+func (e *Executor) acquireBulkhead(ctx context.Context, key policy.PolicyKey, cfg policy.ConcurrencyPolicy) (*bulkhead.Bulkhead, bulkhead.Decision, bool) {
+	b := e.bulkheads.Get(key, cfg)
+	if b == nil {
+		return nil, bulkhead.Decision{Allowed: true, Reason: bulkhead.ReasonNoBulkhead}, true
+	}
+
+	d := b.Acquire(ctx, cfg.MaxWait)
+	if bo, ok := e.observer.(observe.BulkheadObserver); ok {
+		bo.OnBulkheadDecision(ctx, observe.BulkheadDecisionEvent{
+			Key:         key,
+			MaxInFlight: b.MaxInFlight(),
+			Allowed:     d.Allowed,
+			Reason:      d.Reason,
+			Waited:      d.Waited,
+		})
+	}
+	if !d.Allowed {
+		return nil, d, false
+	}
+	return b, d, true
+}
@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/bulkhead"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+type bulkheadRecorder struct {
+	observe.BaseObserver
+	events []observe.BulkheadDecisionEvent
+}
+
+func (r *bulkheadRecorder) OnBulkheadDecision(_ context.Context, ev observe.BulkheadDecisionEvent) {
+	r.events = append(r.events, ev)
+}
+
+func TestExecutor_Bulkhead_RejectsExcessCalls(t *testing.T) {
+	key := policy.PolicyKey{Name: "bh"}
+	exec, _ := newRecordingExecutor(t, policy.New("bh", policy.Bulkhead(1, 0)))
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- exec.Do(context.Background(), key, func(context.Context) error {
+			close(entered)
+			<-unblock
+			return nil
+		})
+	}()
+	<-entered
+
+	for _, timeline := range []bool{false, true} {
+		_, _, err := doValueInternal(context.Background(), exec, key, func(context.Context) (int, error) {
+			t.Fatal("rejected call should not run")
+			return 0, nil
+		}, timeline)
+		var full BulkheadFullError
+		if !errors.As(err, &full) || full.Reason != bulkhead.ReasonFull || full.MaxInFlight != 1 {
+			t.Fatalf("timeline=%v: err=%v, want BulkheadFullError", timeline, err)
+		}
+	}
+
+	close(unblock)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := exec.Do(context.Background(), key, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("slot should be released after the call: %v", err)
+	}
+}
+
+func TestExecutor_Bulkhead_ReportsDecisionsToObserver(t *testing.T) {
+	key := policy.PolicyKey{Name: "bh"}
+	exec, _ := newRecordingExecutor(t, policy.New("bh", policy.Bulkhead(3, 0)))
+	rec := &bulkheadRecorder{}
+	exec.observer = rec
+
+	if err := exec.Do(context.Background(), key, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rec.events) != 1 || !rec.events[0].Allowed || rec.events[0].MaxInFlight != 3 || rec.events[0].Key != key {
+		t.Fatalf("events=%+v, want one allowed decision", rec.events)
+	}
+}
+
+func TestExecutor_Bulkhead_QueuesWithinMaxWait(t *testing.T) {
+	key := policy.PolicyKey{Name: "bh"}
+	exec, _ := newRecordingExecutor(t, policy.New("bh", policy.Bulkhead(1, time.Second)))
+
+	entered := make(chan struct{})
+	go func() {
+		_ = exec.Do(context.Background(), key, func(context.Context) error {
+			close(entered)
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+	}()
+	<-entered
+
+	if err := exec.Do(context.Background(), key, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("queued call should be admitted: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestExecutor_CacheResults_ServesFreshResult(t *testing.T) {
+	key := policy.PolicyKey{Name: "cfg"}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	exec, _ := newRecordingExecutor(t, policy.New("cfg", policy.MaxAttempts(1), policy.CacheResults(time.Minute)), WithClock(clock.Now))
+
+	calls := 0
+	op := func(context.Context) (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	if v, err := DoValue(context.Background(), exec, key, op); err != nil || v != 1 {
+		t.Fatalf("v=%d err=%v, want 1", v, err)
+	}
+
+	clock.Advance(30 * time.Second)
+	val, tl, err := doWithTimeline(context.Background(), exec, key, op)
+	if err != nil || val != 1 || calls != 1 {
+		t.Fatalf("val=%d err=%v calls=%d, want cached 1 without executing", val, err, calls)
+	}
+	if tl.Attributes["cache"] != "hit" || len(tl.Attempts) != 0 {
+		t.Fatalf("attrs=%v attempts=%d, want cache hit without attempts", tl.Attributes, len(tl.Attempts))
+	}
+
+	clock.Advance(time.Minute)
+	if v, err := DoValue(context.Background(), exec, key, op); err != nil || v != 2 || calls != 2 {
+		t.Fatalf("v=%d err=%v calls=%d, want re-executed after TTL", v, err, calls)
+	}
+}
+
+func TestExecutor_CacheResults_ServesStaleOnExhaustion(t *testing.T) {
+	key := policy.PolicyKey{Name: "cfg"}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	exec, _ := newRecordingExecutor(t, policy.New("cfg",
+		policy.MaxAttempts(2),
+		policy.CacheResults(time.Second),
+		policy.CachedFallback(time.Hour),
+	), WithClock(clock.Now))
+
+	if _, err := DoValue(context.Background(), exec, key, func(context.Context) (string, error) { return "v1", nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	val, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (string, error) {
+		return "", errors.New("backend down")
+	})
+	if err != nil || val != "v1" || tl.Fallback != "cached" || len(tl.Attempts) != 2 {
+		t.Fatalf("val=%q err=%v fallback=%q attempts=%d, want stale v1 after 2 attempts", val, err, tl.Fallback, len(tl.Attempts))
+	}
+}
+
+func TestExecutor_CacheResults_TypeMismatchIsMiss(t *testing.T) {
+	key := policy.PolicyKey{Name: "cfg"}
+	exec, _ := newRecordingExecutor(t, policy.New("cfg", policy.MaxAttempts(1), policy.CacheResults(time.Minute)))
+
+	if _, err := DoValue(context.Background(), exec, key, func(context.Context) (string, error) { return "s", nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, err := DoValue(context.Background(), exec, key, func(context.Context) (int, error) { return 7, nil }); err != nil || v != 7 {
+		t.Fatalf("v=%d err=%v, want executed 7", v, err)
+	}
+}
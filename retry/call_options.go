@@ -0,0 +1,171 @@
+package retry
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+// CallOption customizes a single Do or DoValue call.
+type CallOption func(*callConfig)
+
+type callConfig struct {
+	// fallback is a func(context.Context, error) (T, error) for the call's T.
+	fallback any
+
+	// classifier replaces the policy's classifier for the call.
+	classifier classify.Classifier
+
+	overrides []policyOverride
+
+	// chainedFrom is the key whose key fallback started this call, if any.
+	chainedFrom string
+
+	// counters are the call's runtime counters.
+	counters callCounters
+}
+
+// policyOverride changes one field of the resolved policy for a single call.
+type policyOverride struct {
+	field string // dot-delimited field path, as in NormalizationInfo.ChangedFields
+	apply func(*policy.EffectivePolicy)
+}
+
+func newCallConfig(opts []CallOption) callConfig {
+	var cfg callConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return cfg
+}
+
+// WithFallbackValue sets a fallback that produces the call's result when all
+// attempts are exhausted or the circuit is open, for example by serving a
+// cached response. It takes precedence over the policy's fallback.
+//
+// T must match the result type of the DoValue call; a mismatched fallback is
+// ignored and recorded as "fallback_type_mismatch". If fn returns an error, the
+// call returns its original error.
+func WithFallbackValue[T any](fn func(ctx context.Context, err error) (T, error)) CallOption {
+	return func(c *callConfig) {
+		if fn != nil {
+			c.fallback = fn
+		}
+	}
+}
+
+// WithFallback is WithFallbackValue for Executor.Do: when fn returns nil, the
+// call succeeds despite the failure.
+func WithFallback(fn func(ctx context.Context, err error) error) CallOption {
+	if fn == nil {
+		return func(*callConfig) {}
+	}
+	return WithFallbackValue(func(ctx context.Context, err error) (struct{}, error) {
+		return struct{}{}, fn(ctx, err)
+	})
+}
+
+// OverrideClassifier classifies this call's attempts with c instead of the
+// policy's classifier. Pair it with classify.Typed to inspect the typed result:
+//
+//	retry.DoValue(ctx, exec, key, op, retry.OverrideClassifier(classify.Typed(
+//		func(resp *Response, err error) classify.Outcome { ... })))
+//
+// The policy's retryable status and code lists, retry-on, abort-on and reason
+// overrides still apply on top of c.
+func OverrideClassifier(c classify.Classifier) CallOption {
+	return func(cfg *callConfig) {
+		if c != nil {
+			cfg.classifier = c
+		}
+	}
+}
+
+// OverrideMaxAttempts overrides Retry.MaxAttempts for this call.
+func OverrideMaxAttempts(n int) CallOption {
+	return overridePolicy("retry.max_attempts", func(p *policy.EffectivePolicy) {
+		p.Retry.MaxAttempts = n
+	})
+}
+
+// OverrideTimeoutPerAttempt overrides Retry.TimeoutPerAttempt for this call.
+func OverrideTimeoutPerAttempt(d time.Duration) CallOption {
+	return overridePolicy("retry.timeout_per_attempt", func(p *policy.EffectivePolicy) {
+		p.Retry.TimeoutPerAttempt = d
+	})
+}
+
+// OverrideOverallTimeout overrides Retry.OverallTimeout for this call.
+func OverrideOverallTimeout(d time.Duration) CallOption {
+	return overridePolicy("retry.overall_timeout", func(p *policy.EffectivePolicy) {
+		p.Retry.OverallTimeout = d
+	})
+}
+
+// OverrideBackoff overrides Retry.InitialBackoff and Retry.MaxBackoff for this call.
+func OverrideBackoff(initial, max time.Duration) CallOption {
+	return overridePolicy("retry.backoff", func(p *policy.EffectivePolicy) {
+		p.Retry.InitialBackoff = initial
+		p.Retry.MaxBackoff = max
+	})
+}
+
+// Idempotent marks this call's operation as safe to run more than once
+// concurrently, allowing hedging under policies or executors that require it.
+func Idempotent() CallOption {
+	return overridePolicy("idempotent", func(p *policy.EffectivePolicy) {
+		p.Idempotent = true
+	})
+}
+
+// OverrideHedging enables or disables hedging for this call. Disabling it
+// also makes a soft per-attempt timeout cancel attempts like a regular one.
+func OverrideHedging(enabled bool) CallOption {
+	return overridePolicy("hedge.enabled", func(p *policy.EffectivePolicy) {
+		p.Hedge.Enabled = enabled
+		if !enabled {
+			p.Retry.SoftTimeoutPerAttempt = false
+		}
+	})
+}
+
+// OverridePolicy applies arbitrary policy options to the resolved policy for this call.
+func OverridePolicy(opts ...policy.Option) CallOption {
+	return overridePolicy("custom", func(p *policy.EffectivePolicy) {
+		for _, opt := range opts {
+			opt(p)
+		}
+	})
+}
+
+func overridePolicy(field string, apply func(*policy.EffectivePolicy)) CallOption {
+	return func(c *callConfig) {
+		c.overrides = append(c.overrides, policyOverride{field: field, apply: apply})
+	}
+}
+
+// hasOverrides reports whether the call overrides its resolved policy.
+func (c *callConfig) hasOverrides() bool {
+	return c != nil && len(c.overrides) > 0
+}
+
+// applyOverrides merges the call's overrides onto pol and normalizes the
+// result, so overrides are clamped like any other policy. It also returns the
+// overridden field paths, comma-separated, for the timeline.
+func (c *callConfig) applyOverrides(pol policy.EffectivePolicy) (policy.EffectivePolicy, string, error) {
+	fields := make([]string, 0, len(c.overrides))
+	for _, o := range c.overrides {
+		o.apply(&pol)
+		fields = append(fields, o.field)
+	}
+	pol, err := pol.Normalize()
+	if err != nil {
+		return policy.EffectivePolicy{}, "", err
+	}
+	return pol, strings.Join(fields, ","), nil
+}
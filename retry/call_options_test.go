@@ -0,0 +1,146 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/fallback"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestDoValue_WithFallbackValue(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	exec, _ := newRecordingExecutor(t, policy.New("fb", policy.MaxAttempts(2)))
+
+	boom := errors.New("boom")
+	var gotErr error
+	ctx, capture := observe.RecordTimeline(context.Background())
+	val, err := DoValue(ctx, exec, key, func(context.Context) (string, error) {
+		return "", boom
+	}, WithFallbackValue(func(_ context.Context, err error) (string, error) {
+		gotErr = err
+		return "cached", nil
+	}))
+
+	if err != nil || val != "cached" {
+		t.Fatalf("val=%q err=%v, want cached", val, err)
+	}
+	if !errors.Is(gotErr, boom) {
+		t.Fatalf("fallback err=%v, want boom", gotErr)
+	}
+	tl := capture.Timeline()
+	if tl == nil || tl.Fallback != "call" || !errors.Is(tl.FallbackErr, boom) || len(tl.Attempts) != 2 {
+		t.Fatalf("timeline=%+v, want call fallback after 2 attempts", tl)
+	}
+}
+
+func TestDoValue_WithFallbackValue_NotUsedOnSuccess(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	exec, _ := newRecordingExecutor(t, policy.New("fb", policy.MaxAttempts(2)))
+
+	val, err := DoValue(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 1, nil
+	}, WithFallbackValue(func(context.Context, error) (int, error) {
+		t.Fatal("fallback should not run on success")
+		return 0, nil
+	}))
+	if err != nil || val != 1 {
+		t.Fatalf("val=%d err=%v, want 1", val, err)
+	}
+}
+
+func TestDoValue_WithFallbackValue_OverridesPolicyFallback(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	reg := fallback.NewRegistry()
+	reg.MustRegister("policy", fallback.Value(1))
+	exec, _ := newRecordingExecutor(t, policy.New("fb", policy.MaxAttempts(1), policy.StaticFallback("policy")), WithFallbackRegistry(reg))
+
+	val, err := DoValue(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, errors.New("boom")
+	}, WithFallbackValue(func(context.Context, error) (int, error) { return 2, nil }))
+	if err != nil || val != 2 {
+		t.Fatalf("val=%d err=%v, want per-call fallback 2", val, err)
+	}
+}
+
+func TestDoValue_WithFallbackValue_ErrorsAndMismatch(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	exec, _ := newRecordingExecutor(t, policy.New("fb", policy.MaxAttempts(1)))
+	boom := errors.New("boom")
+	op := func(context.Context) (int, error) { return 0, boom }
+
+	_, tl, err := doValueInternal(context.Background(), exec, key, op, true,
+		WithFallbackValue(func(context.Context, error) (int, error) { return 0, errors.New("cache miss") }))
+	if !errors.Is(err, boom) || tl.Attributes["fallback_error"] != fallbackFailed {
+		t.Fatalf("err=%v attrs=%v, want original error and fallback_failed", err, tl.Attributes)
+	}
+
+	_, tl, err = doValueInternal(context.Background(), exec, key, op, true,
+		WithFallbackValue(func(context.Context, error) (string, error) { return "x", nil }))
+	if !errors.Is(err, boom) || tl.Attributes["fallback_error"] != fallbackTypeMismatch {
+		t.Fatalf("err=%v attrs=%v, want original error and fallback_type_mismatch", err, tl.Attributes)
+	}
+}
+
+func TestDo_WithFallback(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	exec, _ := newRecordingExecutor(t, policy.New("fb", policy.MaxAttempts(1)))
+
+	err := exec.Do(context.Background(), key, func(context.Context) error {
+		return errors.New("boom")
+	}, WithFallback(func(context.Context, error) error { return nil }))
+	if err != nil {
+		t.Fatalf("err=%v, want fallback to absorb the failure", err)
+	}
+}
+
+func TestDoValue_OverrideMaxAttempts(t *testing.T) {
+	key := policy.PolicyKey{Name: "ov"}
+	exec, _ := newRecordingExecutor(t, policy.New("ov", policy.MaxAttempts(5)))
+
+	calls := 0
+	ctx, capture := observe.RecordTimeline(context.Background())
+	_, err := DoValue(ctx, exec, key, func(context.Context) (int, error) {
+		calls++
+		return 0, errors.New("boom")
+	}, OverrideMaxAttempts(1), OverrideTimeoutPerAttempt(time.Second))
+
+	if err == nil || calls != 1 {
+		t.Fatalf("calls=%d err=%v, want 1 failing call", calls, err)
+	}
+	tl := capture.Timeline()
+	if tl == nil || tl.Attributes["policy_overrides"] != "retry.max_attempts,retry.timeout_per_attempt" {
+		t.Fatalf("timeline attributes=%v, want policy_overrides", tl.Attributes)
+	}
+}
+
+func TestDoValue_OverrideMaxAttempts_FastPath(t *testing.T) {
+	key := policy.PolicyKey{Name: "ov"}
+	exec, _ := newRecordingExecutor(t, policy.New("ov", policy.MaxAttempts(5)))
+
+	calls := 0
+	_, err := DoValue(context.Background(), exec, key, func(context.Context) (int, error) {
+		calls++
+		return 0, errors.New("boom")
+	}, OverrideMaxAttempts(2))
+	if err == nil || calls != 2 {
+		t.Fatalf("calls=%d err=%v, want 2 failing calls", calls, err)
+	}
+}
+
+func TestDoValue_OverrideIsNormalized(t *testing.T) {
+	key := policy.PolicyKey{Name: "ov"}
+	exec, _ := newRecordingExecutor(t, policy.New("ov", policy.MaxAttempts(2)))
+
+	calls := 0
+	_, err := DoValue(context.Background(), exec, key, func(context.Context) (int, error) {
+		calls++
+		return 0, errors.New("boom")
+	}, OverrideMaxAttempts(1000))
+	if err == nil || calls != 10 {
+		t.Fatalf("calls=%d err=%v, want attempts clamped to 10", calls, err)
+	}
+}
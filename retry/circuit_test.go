@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/aponysus/recourse/circuit"
+	"github.com/aponysus/recourse/classify"
 	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/observe"
 	"github.com/aponysus/recourse/policy"
 )
 
@@ -184,3 +186,90 @@ func (f *fakeClock) Now() time.Time {
 func (f *fakeClock) Advance(d time.Duration) {
 	f.now = f.now.Add(d)
 }
+
+func TestExecutor_CircuitBreaker_PerTenant(t *testing.T) {
+	key := policy.PolicyKey{Name: "tenant_circuit"}
+	pol := policy.EffectivePolicy{
+		Key:   key,
+		Retry: policy.RetryPolicy{MaxAttempts: 1},
+		Circuit: policy.CircuitPolicy{
+			Enabled:   true,
+			Threshold: 1,
+			Cooldown:  time.Minute,
+			PerTenant: true,
+		},
+	}
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Provider: &controlplane.StaticProvider{
+			Policies: map[policy.PolicyKey]policy.EffectivePolicy{key: pol},
+		},
+	})
+
+	noisy := policy.WithTenant(context.Background(), "noisy")
+	quiet := policy.WithTenant(context.Background(), "quiet")
+
+	_ = exec.Do(noisy, key, func(context.Context) error { return errors.New("fail") })
+
+	var circuitErr CircuitOpenError
+	if err := exec.Do(noisy, key, func(context.Context) error { return nil }); !errors.As(err, &circuitErr) {
+		t.Fatalf("err=%v, want CircuitOpenError for the noisy tenant", err)
+	}
+	if err := exec.Do(quiet, key, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("quiet tenant err=%v, want success", err)
+	}
+}
+
+type overloadClassifier struct{}
+
+func (overloadClassifier) Classify(_ any, err error) classify.Outcome {
+	out := classify.AlwaysRetryOnError{}.Classify(nil, err)
+	if err != nil && err.Error() == "overloaded" {
+		out.Reason = "overloaded"
+		out.TripCircuit = true
+	}
+	return out
+}
+
+func TestExecutor_CircuitBreaker_TripCircuit(t *testing.T) {
+	key := policy.PolicyKey{Name: "circuit_trip"}
+	pol := policy.EffectivePolicy{
+		Key:     key,
+		Retry:   policy.RetryPolicy{MaxAttempts: 5, Jitter: policy.JitterNone},
+		Circuit: policy.CircuitPolicy{Enabled: true, Threshold: 10, Cooldown: time.Minute},
+	}
+	reg := circuit.NewRegistry()
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Provider:          &controlplane.StaticProvider{Policies: map[policy.PolicyKey]policy.EffectivePolicy{key: pol}},
+		Circuits:          reg,
+		DefaultClassifier: overloadClassifier{},
+	})
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+	calls := 0
+	ctx, capture := observe.RecordTimeline(context.Background())
+	_, err := DoValue[int](ctx, exec, key, func(context.Context) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errors.New("transient")
+		}
+		return 0, errors.New("overloaded")
+	})
+	if err == nil || err.Error() != "overloaded" || calls != 2 {
+		t.Fatalf("err=%v calls=%d, want overloaded after 2 calls", err, calls)
+	}
+	if got := reg.Get(key, pol.Circuit).State(); got != circuit.StateOpen {
+		t.Fatalf("state=%v, want open", got)
+	}
+	if got := capture.Timeline().Attributes["circuit_tripped"]; got != "true" {
+		t.Fatalf("circuit_tripped=%q, want true", got)
+	}
+
+	_, err = DoValue[int](context.Background(), exec, key, func(context.Context) (int, error) {
+		t.Fatal("should not execute")
+		return 0, nil
+	})
+	var circuitErr CircuitOpenError
+	if !errors.As(err, &circuitErr) {
+		t.Fatalf("err=%v, want CircuitOpenError", err)
+	}
+}
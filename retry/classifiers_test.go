@@ -270,3 +270,182 @@ func TestExecutor_BackoffOverride_UsesRetryAfter(t *testing.T) {
 		t.Fatalf("sleep=%v, want 200ms", sleeps[0])
 	}
 }
+
+func TestExecutor_PolicyRetryableHTTPStatuses(t *testing.T) {
+	key := policy.ParseKey("svc.codes")
+	for _, tc := range []struct {
+		status int
+		calls  int
+	}{
+		{409, 3},
+		{503, 1},
+	} {
+		exec := newTestExecutor(t, key, policy.New(key.String(), policy.MaxAttempts(3), policy.Classifier(classify.ClassifierHTTP), policy.RetryableHTTPStatuses(409)))
+		calls := 0
+		_ = exec.Do(context.Background(), key, func(context.Context) error {
+			calls++
+			return stubHTTPError{status: tc.status, method: "GET"}
+		})
+		if calls != tc.calls {
+			t.Fatalf("status %d: calls=%d, want %d", tc.status, calls, tc.calls)
+		}
+	}
+}
+
+type throttleOnceClassifier struct {
+	seen []classify.AttemptContext
+}
+
+func (c *throttleOnceClassifier) Classify(value any, err error) classify.Outcome {
+	return classify.HTTPClassifier{}.Classify(value, err)
+}
+
+func (c *throttleOnceClassifier) ClassifyAttempt(value any, err error, ac classify.AttemptContext) classify.Outcome {
+	c.seen = append(c.seen, ac)
+	var he classify.HTTPError
+	if ac.Attempt > 0 && errors.As(err, &he) && he.HTTPStatusCode() == 429 {
+		return classify.Outcome{Kind: classify.OutcomeNonRetryable, Reason: "throttled_again"}
+	}
+	return c.Classify(value, err)
+}
+
+func TestExecutor_AttemptClassifier_StricterOnLaterAttempts(t *testing.T) {
+	for _, timeline := range []bool{false, true} {
+		key := policy.PolicyKey{Name: "x"}
+		c := &throttleOnceClassifier{}
+		reg := classify.NewRegistry()
+		reg.Register("throttle-once", c)
+		exec := NewExecutorFromOptions(ExecutorOptions{
+			Classifiers: reg,
+			Provider: &controlplane.StaticProvider{
+				Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+					key: {Key: key, Retry: policy.RetryPolicy{MaxAttempts: 5, ClassifierName: "throttle-once", Jitter: policy.JitterNone}},
+				},
+			},
+		})
+		exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+		ctx := context.Background()
+		if timeline {
+			ctx, _ = observe.RecordTimeline(ctx)
+		}
+		calls := 0
+		_, err := DoValue[int](ctx, exec, key, func(context.Context) (int, error) {
+			calls++
+			return 0, stubHTTPError{status: 429, method: "GET"}
+		})
+		if err == nil || calls != 2 {
+			t.Fatalf("timeline=%v: calls=%d err=%v, want 2 calls and an error", timeline, calls, err)
+		}
+		if len(c.seen) != 2 || c.seen[0].Attempt != 0 || c.seen[1].Attempt != 1 || c.seen[1].Key != key {
+			t.Fatalf("timeline=%v: seen=%+v", timeline, c.seen)
+		}
+	}
+}
+
+func TestExecutor_OverrideClassifier_Typed(t *testing.T) {
+	type response struct{ status string }
+
+	for _, timeline := range []bool{false, true} {
+		key := policy.PolicyKey{Name: "x"}
+		exec := newTestExecutor(t, key, policy.EffectivePolicy{
+			Key:   key,
+			Retry: policy.RetryPolicy{MaxAttempts: 5, ClassifierName: "does_not_exist", Jitter: policy.JitterNone},
+		})
+
+		ctx := context.Background()
+		if timeline {
+			ctx, _ = observe.RecordTimeline(ctx)
+		}
+		calls := 0
+		resp, err := DoValue(ctx, exec, key, func(context.Context) (response, error) {
+			calls++
+			if calls < 3 {
+				return response{status: "busy"}, nil
+			}
+			return response{status: "ok"}, nil
+		}, OverrideClassifier(classify.Typed(func(resp response, err error) classify.Outcome {
+			if err == nil && resp.status == "busy" {
+				return classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "busy"}
+			}
+			return classify.AlwaysRetryOnError{}.Classify(resp, err)
+		})))
+		if err != nil || resp.status != "ok" || calls != 3 {
+			t.Fatalf("timeline=%v: resp=%+v err=%v calls=%d, want ok after 3 calls", timeline, resp, err, calls)
+		}
+	}
+}
+
+type remainingAttemptsClassifier struct {
+	remaining []int
+	calls     int
+}
+
+func (c *remainingAttemptsClassifier) Classify(_ any, err error) classify.Outcome {
+	out := classify.AlwaysRetryOnError{}.Classify(nil, err)
+	if c.calls < len(c.remaining) {
+		out.MaxAdditionalAttempts = c.remaining[c.calls]
+	}
+	c.calls++
+	return out
+}
+
+func TestExecutor_MaxAdditionalAttempts(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		remaining []int
+		want      int
+	}{
+		{"unset", nil, 5},
+		{"once more", []int{1}, 2},
+		{"later outcome cannot raise the cap", []int{2, 10}, 3},
+		{"later outcome lowers the cap", []int{3, 1}, 3},
+		{"above the policy limit", []int{10}, 5},
+	} {
+		for _, timeline := range []bool{false, true} {
+			key := policy.PolicyKey{Name: "x"}
+			c := &remainingAttemptsClassifier{remaining: tc.remaining}
+			exec := newTestExecutor(t, key, policy.EffectivePolicy{
+				Key:   key,
+				Retry: policy.RetryPolicy{MaxAttempts: 5, Jitter: policy.JitterNone},
+			})
+			ctx := context.Background()
+			if timeline {
+				ctx, _ = observe.RecordTimeline(ctx)
+			}
+			calls := 0
+			_, err := DoValue(ctx, exec, key, func(context.Context) (int, error) {
+				calls++
+				return 0, errors.New("busy")
+			}, OverrideClassifier(c))
+			if err == nil || calls != tc.want {
+				t.Fatalf("%s (timeline=%v): calls=%d err=%v, want %d calls", tc.name, timeline, calls, err, tc.want)
+			}
+		}
+	}
+}
+
+func TestExecutor_MissingClassifier_DoesNotMutateCachedOutcome(t *testing.T) {
+	key := policy.PolicyKey{Name: "x"}
+	cached := classify.Cached(classify.HTTPClassifier{}, nil)
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		DefaultClassifier: cached,
+		Provider: &controlplane.StaticProvider{
+			Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+				key: {Key: key, Retry: policy.RetryPolicy{MaxAttempts: 2, ClassifierName: "does_not_exist", Jitter: policy.JitterNone}},
+			},
+		},
+	})
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+	ctx, capture := observe.RecordTimeline(context.Background())
+	_, _ = DoValue[int](ctx, exec, key, func(context.Context) (int, error) {
+		return 0, stubHTTPError{status: 503, method: "GET"}
+	})
+	if got := capture.Timeline().Attempts[0].Outcome.Attributes["classifier_not_found"]; got != "true" {
+		t.Fatalf("classifier_not_found=%q, want true", got)
+	}
+	if out := cached.Classify(nil, stubHTTPError{status: 503, method: "GET"}); out.Attributes["classifier_not_found"] != "" {
+		t.Fatalf("cached outcome was modified: %v", out.Attributes)
+	}
+}
@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/aponysus/recourse/observe"
+)
+
+// Close stops the executor from accepting new calls, which then fail with
+// ErrExecutorClosed. It waits for in-flight calls, including attempts and
+// hedges still running after their call returned, and then flushes the
+// observer if it implements observe.Flusher.
+//
+// If ctx is done before in-flight work finishes, Close returns ctx.Err().
+// Close may be called more than once; each call waits for the same work.
+func (e *Executor) Close(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	e.ensureInitialized()
+
+	e.closeOnce.Do(func() {
+		e.drained = make(chan struct{})
+		e.closed.Store(true)
+		if e.active.Load() == 0 {
+			e.signalDrained()
+		}
+	})
+
+	select {
+	case <-e.drained:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if f, ok := e.observer.(observe.Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// enter registers a call, or a goroutine spawned by one. New calls are refused
+// once the executor is closed; goroutines of calls already in flight are not.
+func (e *Executor) enter(spawned bool) bool {
+	e.active.Add(1)
+	if !spawned && e.closed.Load() {
+		e.leave()
+		return false
+	}
+	return true
+}
+
+// leave releases a registration made by enter.
+func (e *Executor) leave() {
+	if e.active.Add(-1) == 0 && e.closed.Load() {
+		e.signalDrained()
+	}
+}
+
+func (e *Executor) signalDrained() {
+	e.drainOnce.Do(func() { close(e.drained) })
+}
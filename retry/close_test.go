@@ -0,0 +1,120 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+type flushingObserver struct {
+	observe.BaseObserver
+	flushes int
+}
+
+func (o *flushingObserver) Flush(context.Context) error {
+	o.flushes++
+	return nil
+}
+
+func TestExecutorClose_RejectsNewCalls(t *testing.T) {
+	obs := &flushingObserver{}
+	exec := NewExecutor(WithObserver(obs))
+
+	if err := exec.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if obs.flushes != 1 {
+		t.Fatalf("flushes = %d, want 1", obs.flushes)
+	}
+
+	called := false
+	err := exec.Do(context.Background(), policy.ParseKey("svc.Closed"), func(context.Context) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrExecutorClosed) {
+		t.Fatalf("err = %v, want ErrExecutorClosed", err)
+	}
+	if called {
+		t.Fatal("operation ran on a closed executor")
+	}
+
+	if err := exec.Close(context.Background()); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
+
+func TestExecutorClose_WaitsForInFlightCalls(t *testing.T) {
+	exec := NewExecutor()
+	key := policy.ParseKey("svc.InFlight")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- exec.Do(context.Background(), key, func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := exec.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close = %v, want DeadlineExceeded while a call is in flight", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("in-flight call: %v", err)
+	}
+	if err := exec.Close(context.Background()); err != nil {
+		t.Fatalf("Close after drain: %v", err)
+	}
+}
+
+func TestExecutorClose_WaitsForHedges(t *testing.T) {
+	key := policy.ParseKey("svc.CloseHedge")
+	exec := newTestExecutor(t, key, policy.EffectivePolicy{
+		Key:   key,
+		Retry: policy.RetryPolicy{MaxAttempts: 1},
+		Hedge: policy.HedgePolicy{Enabled: true, MaxHedges: 1, TriggerName: "immediate"},
+	})
+	setImmediateTrigger(exec)
+
+	hedgeStarted := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	_, err := DoValue[string](context.Background(), exec, key, func(ctx context.Context) (string, error) {
+		if info, _ := observe.AttemptFromContext(ctx); info.IsHedge {
+			once.Do(func() { close(hedgeStarted) })
+			<-release // ignores cancellation, outliving the call
+			return "", ctx.Err()
+		}
+		if !waitForSignal(hedgeStarted) {
+			return "", errors.New("hedge did not start")
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := exec.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close = %v, want DeadlineExceeded while a hedge runs", err)
+	}
+
+	close(release)
+	if err := exec.Close(context.Background()); err != nil {
+		t.Fatalf("Close after hedge finished: %v", err)
+	}
+}
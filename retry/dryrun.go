@@ -0,0 +1,86 @@
+package retry
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aponysus/recourse/budget"
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/hedge"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+// recordDryRun records on tl what the executor would do after the first
+// attempt of a dry-run call, without doing it:
+//
+//   - "dry_run_decision": "success", "stop" for terminal outcomes, "exhausted"
+//     when no retry is left, "backoff_budget_exhausted",
+//     "deadline_insufficient", "budget_denied" or "retry".
+//   - "dry_run_backoff": the backoff before the retry.
+//   - "dry_run_budget": the retry budget's denial reason.
+//   - "dry_run_budget_unchecked": budgets that cannot be peeked, comma-separated.
+//   - "dry_run_hedge": "spawn" if the hedge trigger would have launched a
+//     hedge while the attempt ran, otherwise "none".
+//
+// The retry budgets are peeked (see budget.Peeker), so the preview consumes
+// nothing, notifies no observer, and cannot throttle real retries. Budgets that
+// cannot be peeked are assumed to allow the retry.
+func (e *Executor) recordDryRun(ctx context.Context, key policy.PolicyKey, pol policy.EffectivePolicy, out classify.Outcome, backoff time.Duration, jitterFn policy.JitterFunc, tl *observe.Timeline) {
+	e.setAttribute(&tl.Attributes, "dry_run", "true")
+
+	if pol.Hedge.Enabled && len(tl.Attempts) > 0 {
+		spawn := "none"
+		if trig, err := e.resolveTrigger(pol.Hedge); err == nil {
+			rec := tl.Attempts[len(tl.Attempts)-1]
+			should, _ := trig.ShouldSpawnHedge(hedge.HedgeState{
+				CallStart:        tl.Start,
+				AttemptStart:     rec.StartTime,
+				AttemptsLaunched: 1,
+				MaxHedges:        pol.Hedge.MaxHedges,
+				Elapsed:          rec.EndTime.Sub(rec.StartTime),
+				Snapshot:         e.getTracker(key).Snapshot(),
+				HedgeDelay:       pol.Hedge.HedgeDelay,
+			})
+			if should {
+				spawn = "spawn"
+			}
+		}
+		e.setAttribute(&tl.Attributes, "dry_run_hedge", spawn)
+	}
+
+	ov := pol.ReasonOverrides[out.Reason]
+	switch {
+	case out.Kind == classify.OutcomeSuccess:
+		e.setAttribute(&tl.Attributes, "dry_run_decision", "success")
+		return
+	case out.Kind != classify.OutcomeRetryable:
+		e.setAttribute(&tl.Attributes, "dry_run_decision", "stop")
+		return
+	case attemptLimit(pol.Retry) <= 1 || ov.MaxAttempts == 1:
+		e.setAttribute(&tl.Attributes, "dry_run_decision", "exhausted")
+		return
+	}
+
+	sleepFor := e.cooldownSleep(key, pol.Retry, max(computeSleep(e.adaptiveBackoff(key, pol.Retry, backoff), pol.Retry, out, e.jitter, jitterFn), ov.MinBackoff))
+	e.setAttribute(&tl.Attributes, "dry_run_backoff", sleepFor.String())
+	if backoffBudgetExhausted(pol.Retry, 0, sleepFor) {
+		e.setAttribute(&tl.Attributes, "dry_run_decision", "backoff_budget_exhausted")
+		return
+	}
+	if _, short := deadlineInsufficient(ctx, sleepFor); short {
+		e.setAttribute(&tl.Attributes, "dry_run_decision", "deadline_insufficient")
+		return
+	}
+	decision, ok, unchecked := e.peekAttempts(ctx, key, pol.Retry.Budget, pol.Retry.Budgets, 1, budget.KindRetry)
+	if len(unchecked) > 0 {
+		e.setAttribute(&tl.Attributes, "dry_run_budget_unchecked", strings.Join(unchecked, ","))
+	}
+	if !ok {
+		e.setAttribute(&tl.Attributes, "dry_run_decision", "budget_denied")
+		e.setAttribute(&tl.Attributes, "dry_run_budget", decision.Reason)
+		return
+	}
+	e.setAttribute(&tl.Attributes, "dry_run_decision", "retry")
+}
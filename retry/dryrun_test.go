@@ -0,0 +1,137 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/budget"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestExecutor_DryRun(t *testing.T) {
+	key := policy.ParseKey("svc.DryRun")
+	pol := policy.New(key.String(),
+		policy.DryRun(),
+		policy.MaxAttempts(3),
+		policy.ConstantBackoff(50*time.Millisecond),
+		policy.EnableHedging(),
+		policy.HedgeDelay(10*time.Millisecond),
+	)
+	pol.Circuit = policy.CircuitPolicy{Enabled: true, Threshold: 1, Cooldown: time.Minute}
+	exec := newTestExecutor(t, key, pol)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	exec.clock = clock.Now
+	exec.sleep = func(context.Context, time.Duration) error {
+		t.Fatal("dry run slept")
+		return nil
+	}
+
+	boom := errors.New("boom")
+	calls := 0
+	_, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		calls++
+		clock.Advance(20 * time.Millisecond)
+		return 0, boom
+	})
+	if !errors.Is(err, boom) || calls != 1 || len(tl.Attempts) != 1 {
+		t.Fatalf("err=%v calls=%d attempts=%d, want one failed attempt", err, calls, len(tl.Attempts))
+	}
+	for k, want := range map[string]string{
+		"dry_run":          "true",
+		"dry_run_decision": "retry",
+		"dry_run_backoff":  "50ms",
+		"dry_run_circuit":  "closed",
+		"dry_run_hedge":    "spawn",
+	} {
+		if got := tl.Attributes[k]; got != want {
+			t.Errorf("%s=%q, want %q (attrs %v)", k, got, want, tl.Attributes)
+		}
+	}
+
+	// The failure was not recorded, so the breaker is still closed, and an
+	// open breaker is reported without rejecting the call.
+	cb := exec.circuits.GetForTenant(key, "", pol.Circuit)
+	if got := cb.State().String(); got != "closed" {
+		t.Fatalf("circuit=%s, want closed after a dry run", got)
+	}
+	cb.RecordFailure(context.Background())
+	_, tl, err = doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 1, nil
+	})
+	if err != nil || tl.Attributes["dry_run_circuit"] != "open" || tl.Attributes["dry_run_decision"] != "success" {
+		t.Fatalf("err=%v attrs=%v, want success with the open circuit reported", err, tl.Attributes)
+	}
+}
+
+func TestExecutor_DryRun_Terminal(t *testing.T) {
+	fatal := errors.New("fatal")
+	key := policy.ParseKey("svc.DryRunTerminal")
+	exec := NewExecutor(
+		WithPolicy(key.String(), policy.DryRun(), policy.MaxAttempts(3), policy.Classifier("fatal")),
+		WithClassifier("fatal", fatalClassifier{fatal: fatal}),
+	)
+
+	calls := 0
+	err := exec.Do(context.Background(), key, func(context.Context) error {
+		calls++
+		return fatal
+	})
+	if !errors.Is(err, fatal) || calls != 1 {
+		t.Fatalf("err=%v calls=%d, want fatal after one call", err, calls)
+	}
+
+	_, tl, _ := doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, fatal
+	})
+	if got := tl.Attributes["dry_run_decision"]; got != "stop" {
+		t.Fatalf("dry_run_decision=%q, want stop", got)
+	}
+}
+
+func TestExecutor_DryRun_PeeksBudgets(t *testing.T) {
+	key := policy.ParseKey("svc.DryRunBudget")
+	quota := budget.NewFixedWindowBudget(1, time.Hour)
+	counting := &countingReleaseBudget{}
+	obs := &budgetEventObserver{}
+
+	exec := NewExecutor(
+		WithBudget("quota", quota),
+		WithBudget("counting", counting),
+		WithObserver(obs),
+		WithPolicyKey(key, policy.DryRun(), policy.MaxAttempts(3), policy.Budget("quota"), policy.AlsoBudget("counting")),
+	)
+	boom := errors.New("boom")
+	op := func(context.Context) (int, error) { return 0, boom }
+
+	for i := 0; i < 3; i++ {
+		_, tl, _ := doWithTimeline(context.Background(), exec, key, op)
+		if got := tl.Attributes["dry_run_decision"]; got != "retry" {
+			t.Fatalf("call %d: dry_run_decision=%q, want retry (attrs %v)", i, got, tl.Attributes)
+		}
+		if got := tl.Attributes["dry_run_budget_unchecked"]; got != "counting" {
+			t.Fatalf("call %d: dry_run_budget_unchecked=%q, want counting", i, got)
+		}
+	}
+	if remaining, _ := quota.Remaining(key); remaining != 1 {
+		t.Fatalf("remaining=%d, want the preview to consume nothing", remaining)
+	}
+	// Only the three real primary attempts reached the budgets and the observer.
+	if n := atomic.LoadInt32(&counting.allowCalls); n != 3 {
+		t.Fatalf("allowCalls=%d, want 3", n)
+	}
+	if len(obs.events) != 6 {
+		t.Fatalf("budget events=%d, want 6 for the primary attempts only", len(obs.events))
+	}
+
+	denied := NewExecutor(
+		WithBudget("quota", budget.NewFixedWindowBudget(0, time.Hour)),
+		WithPolicyKey(key, policy.DryRun(), policy.MaxAttempts(3), policy.Budget("quota")),
+	)
+	_, tl, _ := doWithTimeline(context.Background(), denied, key, op)
+	if tl.Attributes["dry_run_decision"] != "budget_denied" || tl.Attributes["dry_run_budget"] != budget.ReasonBudgetDenied {
+		t.Fatalf("attrs=%v, want budget_denied", tl.Attributes)
+	}
+}
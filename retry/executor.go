@@ -4,25 +4,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
+	"math"
+	"math/rand/v2"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aponysus/recourse/budget"
+	"github.com/aponysus/recourse/bulkhead"
 	"github.com/aponysus/recourse/circuit"
 	"github.com/aponysus/recourse/classify"
 	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/fallback"
 	"github.com/aponysus/recourse/hedge"
+	"github.com/aponysus/recourse/internal"
 	"github.com/aponysus/recourse/observe"
 	"github.com/aponysus/recourse/policy"
+	"github.com/aponysus/recourse/ratelimit"
 )
 
 var (
 	// ErrNoPolicy is returned when no policy is found and missing policy mode is FailureDeny.
 	ErrNoPolicy = errors.New("recourse: no policy found")
 
+	// ErrDeadlineInsufficient matches a DeadlineInsufficientError.
+	ErrDeadlineInsufficient = errors.New("recourse: deadline insufficient")
+
+	// ErrBackoffBudgetExhausted matches a BackoffBudgetExhaustedError.
+	ErrBackoffBudgetExhausted = errors.New("recourse: backoff budget exhausted")
+
+	// ErrExecutorClosed is returned for calls started after Executor.Close.
+	ErrExecutorClosed = errors.New("recourse: executor closed")
+
 	// errHedgingRequiresTimeline is an internal sentinel used to switch from fast path to strict path.
 	errHedgingRequiresTimeline = errors.New("recourse: hedging requires timeline")
 )
@@ -66,19 +82,87 @@ type Executor struct {
 	budgets               *budget.Registry
 	triggers              *hedge.Registry
 	circuits              *circuit.Registry
+	rateLimiters          *ratelimit.Registry
+	fallbacks             *fallback.Registry
+	jitters               *policy.JitterRegistry
+	bulkheads             *bulkhead.Registry
+	attemptMiddleware     []AttemptMiddleware
 	missingPolicyMode     FailureMode
 	missingClassifierMode FailureMode
 	missingBudgetMode     FailureMode
 	missingTriggerMode    FailureMode
+	missingLimiterMode    FailureMode
+	missingJitterMode     FailureMode
 	recoverPanics         bool
+	retryPanics           bool
+	poolTimelines         bool
+	faultInjection        bool
+	requireIdempotent     bool
+	maxConcurrentHedges   int
+	namedErrors           map[string]error
+	defaultPolicy         policy.EffectivePolicy
+	minPolicyVersion      uint64
+	pinnedPolicyVersion   uint64
+	jitter                *jitterRand
+
+	initOnce sync.Once
+
+	// classifierCache maps RetryPolicy.ClassifierName to a resolvedClassifier.
+	classifierCache sync.Map
+
+	// ruleCache maps RetryRule.When to its compiled *expr.Program, or to the
+	// compile error.
+	ruleCache sync.Map
 
 	trackerMu sync.RWMutex
 	trackers  map[policy.PolicyKey]hedge.LatencyTracker
+
+	// cooldowns holds, per key, the UnixNano time until which pushback applies.
+	cooldownMu sync.RWMutex
+	cooldowns  map[policy.PolicyKey]*atomic.Int64
+
+	// adaptiveFloors holds, per key, the adaptive backoff floor in nanoseconds
+	// for policies with Retry.AdaptiveBackoff.
+	adaptiveMu     sync.RWMutex
+	adaptiveFloors map[policy.PolicyKey]*atomic.Int64
+
+	// resultCache holds the last successful result per key for policies that
+	// cache results or use cached fallbacks.
+	resultMu    sync.RWMutex
+	resultCache map[policy.PolicyKey]cachedResult
+
+	// budgetStates maps a budgetStateKey to the UnixNano time the budget
+	// became exhausted, for observe.BudgetStateObserver. Entries are removed
+	// on recovery.
+	budgetStates sync.Map
+
+	// hedgeSlots counts outstanding hedges when maxConcurrentHedges is set.
+	hedgeSlots atomic.Int64
+
+	// stats and keyStats back Stats, executor-wide and per key. keyStats
+	// maps a policy.PolicyKey to its *statCounters, for at most maxStatKeys
+	// keys, counted by keyStatCount.
+	stats        statCounters
+	keyStats     sync.Map
+	keyStatCount atomic.Int64
+
+	// active counts in-flight calls and the attempt and hedge goroutines they
+	// spawn, so Close can wait for them. drained is closed once the executor is
+	// closed and active reaches zero.
+	active    atomic.Int64
+	closed    atomic.Bool
+	closeOnce sync.Once
+	drainOnce sync.Once
+	drained   chan struct{}
 }
 
 type executorConfig struct {
 	opts           ExecutorOptions
 	staticPolicies map[policy.PolicyKey]policy.EffectivePolicy
+
+	// sharedClassifiers is set while opts.Classifiers is inherited from the
+	// executor With was called on, so WithClassifier must not write to it.
+	sharedClassifiers bool
 }
 
 // ExecutorOptions configures an Executor.
@@ -96,6 +180,87 @@ type ExecutorOptions struct {
 	MissingBudgetMode     FailureMode
 	MissingTriggerMode    FailureMode
 	RecoverPanics         bool
+
+	// RetryPanics makes operation panics recovered under RecoverPanics
+	// retryable (reason "panic_retryable") instead of terminal.
+	RetryPanics bool
+
+	// RateLimiters resolves EffectivePolicy.RateLimit names. Calls whose limiter
+	// is missing are handled by MissingRateLimiterMode (default FailureDeny).
+	RateLimiters           *ratelimit.Registry
+	MissingRateLimiterMode FailureMode
+
+	// Fallbacks resolves EffectivePolicy.Fallback names in static and handler modes.
+	Fallbacks *fallback.Registry
+
+	// Jitters resolves custom RetryPolicy.Jitter names. Defaults to
+	// policy.DefaultJitters, which Normalize also consults. Calls whose jitter
+	// is missing are handled by MissingJitterMode (default FailureFallback,
+	// which uses full jitter).
+	Jitters           *policy.JitterRegistry
+	MissingJitterMode FailureMode
+
+	// Bulkheads holds the per-key bulkheads for EffectivePolicy.Concurrency.
+	// Executors sharing a registry share concurrency limits. Defaults to a new registry.
+	Bulkheads *bulkhead.Registry
+
+	// Sleep waits for backoff between attempts and for injected latency. It must
+	// return early with ctx.Err() when ctx is done. Defaults to a timer-based
+	// sleep; tests can substitute virtual time.
+	Sleep func(ctx context.Context, d time.Duration) error
+
+	// AttemptMiddleware wraps every attempt, outermost first (see AttemptMiddleware).
+	// Calls on an executor with middleware always record a timeline.
+	AttemptMiddleware []AttemptMiddleware
+
+	// PoolTimelines reuses timeline attempt slices and attribute maps across calls.
+	// When enabled, observers must not retain Timeline.Attempts or Timeline.Attributes
+	// after OnSuccess/OnFailure return; timelines returned to the caller are never pooled.
+	PoolTimelines bool
+
+	// JitterSeed seeds the executor's jitter generator for reproducible backoff
+	// sequences. Zero uses independently seeded per-goroutine generators.
+	JitterSeed int64
+
+	// Rand, when set, is the source of jitter and fault injection randomness,
+	// taking precedence over JitterSeed. Supply a seeded source such as
+	// rand.NewPCG for deterministic backoff in tests. The executor serializes
+	// access, so the source need not be safe for concurrent use.
+	Rand rand.Source
+
+	// FaultInjection allows policies to inject faults (EffectivePolicy.FaultInjection).
+	// It is off by default so a remote policy alone cannot inject failures.
+	FaultInjection bool
+
+	// RequireIdempotentHedges refuses to hedge operations not marked idempotent,
+	// by policy (EffectivePolicy.Idempotent) or per call (Idempotent), for every
+	// key. Policies can require it individually with Hedge.RequireIdempotent.
+	RequireIdempotentHedges bool
+
+	// Errors names sentinel errors for EffectivePolicy.RetryOn and AbortOn
+	// (ErrorMatcher.Errors). The names "context.Canceled",
+	// "context.DeadlineExceeded", "io.EOF" and "io.ErrUnexpectedEOF" are built in.
+	Errors map[string]error
+
+	// MaxConcurrentHedges caps hedged attempts outstanding at once across all keys,
+	// so a latency spike across many keys cannot multiply outbound load. Hedges
+	// over the cap are not launched and are reported to observers implementing
+	// observe.HedgeObserver. Zero means no cap.
+	MaxConcurrentHedges int
+
+	// DefaultPolicy is the policy used for keys the provider has no policy for
+	// (with MissingPolicyMode FailureFallback, or when the provider returns a
+	// zero policy). Its Key is replaced by the call's key. The zero value uses
+	// policy.DefaultPolicyFor. It also serves as the Default of the static
+	// provider built from WithPolicy options.
+	DefaultPolicy policy.EffectivePolicy
+
+	// MinPolicyVersion rejects provider policies whose Meta.Version is lower,
+	// including unversioned ones. PinnedPolicyVersion, when set, rejects every
+	// version but that one. Rejected policies are handled like provider errors
+	// under MissingPolicyMode, with a *PolicyVersionError.
+	MinPolicyVersion    uint64
+	PinnedPolicyVersion uint64
 }
 
 // NewExecutor creates an Executor with default options.
@@ -109,6 +274,7 @@ func NewExecutor(opts ...ExecutorOption) *Executor {
 	if cfg.opts.Provider == nil && len(cfg.staticPolicies) > 0 {
 		cfg.opts.Provider = &controlplane.StaticProvider{
 			Policies: cfg.staticPolicies,
+			Default:  cfg.opts.DefaultPolicy,
 		}
 	}
 
@@ -121,21 +287,59 @@ func NewExecutorFromOptions(opts ExecutorOptions) *Executor {
 		provider:              opts.Provider,
 		observer:              opts.Observer,
 		clock:                 opts.Clock,
+		sleep:                 opts.Sleep,
 		classifiers:           opts.Classifiers,
 		defaultClassifier:     opts.DefaultClassifier,
 		budgets:               opts.Budgets,
 		triggers:              opts.Triggers,
 		circuits:              opts.Circuits,
-		missingPolicyMode:     normalizeFailureMode(opts.MissingPolicyMode, FailureDeny),
-		missingClassifierMode: normalizeFailureMode(opts.MissingClassifierMode, FailureFallback),
-		missingBudgetMode:     normalizeFailureMode(opts.MissingBudgetMode, FailureDeny),
-		missingTriggerMode:    normalizeFailureMode(opts.MissingTriggerMode, FailureFallback),
+		rateLimiters:          opts.RateLimiters,
+		fallbacks:             opts.Fallbacks,
+		jitters:               opts.Jitters,
+		bulkheads:             opts.Bulkheads,
+		attemptMiddleware:     opts.AttemptMiddleware,
+		missingPolicyMode:     opts.MissingPolicyMode,
+		missingClassifierMode: opts.MissingClassifierMode,
+		missingBudgetMode:     opts.MissingBudgetMode,
+		missingTriggerMode:    opts.MissingTriggerMode,
+		missingLimiterMode:    opts.MissingRateLimiterMode,
+		missingJitterMode:     opts.MissingJitterMode,
 		recoverPanics:         opts.RecoverPanics,
-		trackers:              make(map[policy.PolicyKey]hedge.LatencyTracker),
-	}
+		retryPanics:           opts.RetryPanics,
+		poolTimelines:         opts.PoolTimelines,
+		faultInjection:        opts.FaultInjection,
+		requireIdempotent:     opts.RequireIdempotentHedges,
+		maxConcurrentHedges:   opts.MaxConcurrentHedges,
+		namedErrors:           opts.Errors,
+		defaultPolicy:         opts.DefaultPolicy,
+		minPolicyVersion:      opts.MinPolicyVersion,
+		pinnedPolicyVersion:   opts.PinnedPolicyVersion,
+		jitter:                newJitterRand(opts.JitterSeed),
+	}
+	if opts.Rand != nil {
+		e.jitter = newJitterRandFrom(opts.Rand)
+	}
+	e.ensureInitialized()
+	return e
+}
+
+// ensureInitialized applies defaults exactly once, so zero-value or partially
+// constructed executors are usable without rebuilding them on every call.
+func (e *Executor) ensureInitialized() {
+	e.initOnce.Do(e.applyDefaults)
+}
+
+// applyDefaults fills in every dependency and failure mode left unset.
+func (e *Executor) applyDefaults() {
+	e.missingPolicyMode = normalizeFailureMode(e.missingPolicyMode, FailureDeny)
+	e.missingClassifierMode = normalizeFailureMode(e.missingClassifierMode, FailureFallback)
+	e.missingBudgetMode = normalizeFailureMode(e.missingBudgetMode, FailureDeny)
+	e.missingTriggerMode = normalizeFailureMode(e.missingTriggerMode, FailureFallback)
+	e.missingLimiterMode = normalizeFailureMode(e.missingLimiterMode, FailureDeny)
+	e.missingJitterMode = normalizeFailureMode(e.missingJitterMode, FailureFallback)
 
 	if e.provider == nil {
-		e.provider = &controlplane.StaticProvider{}
+		e.provider = &controlplane.StaticProvider{Default: e.defaultPolicy}
 	}
 	if e.observer == nil {
 		e.observer = &observe.NoopObserver{}
@@ -156,11 +360,24 @@ func NewExecutorFromOptions(opts ExecutorOptions) *Executor {
 	if e.circuits == nil {
 		e.circuits = circuit.NewRegistry()
 	}
+	if e.bulkheads == nil {
+		e.bulkheads = bulkhead.NewRegistry()
+	}
+	if e.jitters == nil {
+		e.jitters = policy.DefaultJitters
+	}
 	if e.defaultClassifier == nil {
 		e.defaultClassifier = classify.AlwaysRetryOnError{}
 	}
+	if e.jitter == nil {
+		e.jitter = newJitterRand(0)
+	}
 
-	return e
+	e.trackerMu.Lock()
+	if e.trackers == nil {
+		e.trackers = make(map[policy.PolicyKey]hedge.LatencyTracker)
+	}
+	e.trackerMu.Unlock()
 }
 
 // Validator for PanicError etc.
@@ -200,6 +417,36 @@ func (e *NoClassifierError) Error() string {
 	return fmt.Sprintf("recourse: classifier not found: %s", e.Name)
 }
 
+// NoTriggerError is returned when a policy's hedge trigger is not registered
+// and MissingTriggerMode is FailureDeny.
+type NoTriggerError struct {
+	Name string
+}
+
+func (e *NoTriggerError) Error() string {
+	return fmt.Sprintf("recourse: hedge trigger not found: %s", e.Name)
+}
+
+// NoJitterError is returned when a policy's custom jitter is not registered
+// and MissingJitterMode is FailureDeny.
+type NoJitterError struct {
+	Name policy.JitterKind
+}
+
+func (e *NoJitterError) Error() string {
+	return fmt.Sprintf("recourse: jitter not found: %s", e.Name)
+}
+
+// tripCircuit opens cb for an outcome with TripCircuit set. Breakers that
+// cannot be opened directly record a failure instead.
+func tripCircuit(ctx context.Context, cb circuit.CircuitBreaker) {
+	if t, ok := cb.(circuit.Tripper); ok {
+		t.Trip(ctx)
+		return
+	}
+	cb.RecordFailure(ctx)
+}
+
 // CircuitOpenError is returned when a circuit breaker prevents execution.
 type CircuitOpenError struct {
 	State  circuit.State
@@ -210,6 +457,83 @@ func (e CircuitOpenError) Error() string {
 	return fmt.Sprintf("recourse: circuit %s: %s", e.State, e.Reason)
 }
 
+// RateLimitedError is returned when a rate limiter rejects a call before its first attempt.
+type RateLimitedError struct {
+	Limiter    string
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e RateLimitedError) Error() string {
+	return fmt.Sprintf("recourse: rate limiter %s: %s", e.Limiter, e.Reason)
+}
+
+// DeadlineInsufficientError is returned when the backoff before the next retry
+// would outlast the context deadline, so the executor stops instead of sleeping
+// into the deadline. With RetryPolicy.SkipInsufficientDeadline it is also
+// returned when the next attempt is expected to outlast the deadline; Expected
+// is then the expected attempt duration. Err is the last attempt's error, nil
+// if no attempt ran. It matches both ErrDeadlineInsufficient and
+// context.DeadlineExceeded.
+type DeadlineInsufficientError struct {
+	Backoff   time.Duration
+	Expected  time.Duration
+	Remaining time.Duration
+	Err       error
+}
+
+func (e *DeadlineInsufficientError) Error() string {
+	if e.Expected > 0 {
+		msg := fmt.Sprintf("recourse: deadline_insufficient: expected attempt duration %s exceeds remaining %s", e.Expected, e.Remaining)
+		if e.Err != nil {
+			msg += ": " + e.Err.Error()
+		}
+		return msg
+	}
+	return fmt.Sprintf("recourse: deadline_insufficient: backoff %s exceeds remaining %s: %v", e.Backoff, e.Remaining, e.Err)
+}
+
+func (e *DeadlineInsufficientError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DeadlineInsufficientError) Is(target error) bool {
+	return target == ErrDeadlineInsufficient || target == context.DeadlineExceeded
+}
+
+// BackoffBudgetExhaustedError is returned when the backoff before the next
+// retry would take the call's total backoff past
+// RetryPolicy.MaxCumulativeBackoff. Spent is the backoff already slept and Err
+// is the last attempt's error. It matches ErrBackoffBudgetExhausted.
+type BackoffBudgetExhaustedError struct {
+	Backoff time.Duration
+	Spent   time.Duration
+	Limit   time.Duration
+	Err     error
+}
+
+func (e *BackoffBudgetExhaustedError) Error() string {
+	return fmt.Sprintf("recourse: backoff_budget_exhausted: backoff %s after %s exceeds limit %s: %v", e.Backoff, e.Spent, e.Limit, e.Err)
+}
+
+func (e *BackoffBudgetExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *BackoffBudgetExhaustedError) Is(target error) bool {
+	return target == ErrBackoffBudgetExhausted
+}
+
+// BulkheadFullError is returned when a key's bulkhead rejects a call.
+type BulkheadFullError struct {
+	MaxInFlight int
+	Reason      string
+}
+
+func (e BulkheadFullError) Error() string {
+	return fmt.Sprintf("recourse: bulkhead full (max %d in flight): %s", e.MaxInFlight, e.Reason)
+}
+
 // ExecutorOption configures an Executor.
 type ExecutorOption func(*executorConfig)
 
@@ -234,10 +558,18 @@ func WithClock(f func() time.Time) ExecutorOption {
 	}
 }
 
+// WithSleep sets the function used to wait between attempts (see ExecutorOptions.Sleep).
+func WithSleep(f func(ctx context.Context, d time.Duration) error) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.Sleep = f
+	}
+}
+
 // WithClassifiers sets the classifier registry.
 func WithClassifiers(r *classify.Registry) ExecutorOption {
 	return func(c *executorConfig) {
 		c.opts.Classifiers = r
+		c.sharedClassifiers = false
 	}
 }
 
@@ -248,13 +580,19 @@ func WithDefaultClassifier(cls classify.Classifier) ExecutorOption {
 	}
 }
 
-// WithClassifier adds or replaces a classifier in the registry.
+// WithClassifier adds or replaces a classifier in the registry. A frozen
+// registry, or one inherited through Executor.With, is cloned first, so the
+// classifier is added to this executor's copy only.
 func WithClassifier(name string, cls classify.Classifier) ExecutorOption {
 	return func(c *executorConfig) {
-		if c.opts.Classifiers == nil {
+		switch {
+		case c.opts.Classifiers == nil:
 			c.opts.Classifiers = classify.NewRegistry()
+		case c.sharedClassifiers || c.opts.Classifiers.Frozen():
+			c.opts.Classifiers = c.opts.Classifiers.Clone()
 		}
-		c.opts.Classifiers.Register(name, cls)
+		c.sharedClassifiers = false
+		_ = c.opts.Classifiers.Register(name, cls) // cannot fail on an unfrozen registry
 	}
 }
 
@@ -265,6 +603,17 @@ func WithBudgetRegistry(r *budget.Registry) ExecutorOption {
 	}
 }
 
+// WithBudget adds or replaces a budget in the budget registry. It panics if
+// name is empty or b is nil.
+func WithBudget(name string, b budget.Budget) ExecutorOption {
+	return func(c *executorConfig) {
+		if c.opts.Budgets == nil {
+			c.opts.Budgets = budget.NewRegistry()
+		}
+		c.opts.Budgets.MustRegister(name, b)
+	}
+}
+
 // WithHedgeTriggerRegistry sets the hedge trigger registry.
 func WithHedgeTriggerRegistry(r *hedge.Registry) ExecutorOption {
 	return func(c *executorConfig) {
@@ -279,6 +628,42 @@ func WithCircuitRegistry(r *circuit.Registry) ExecutorOption {
 	}
 }
 
+// WithRateLimiterRegistry sets the rate limiter registry.
+func WithRateLimiterRegistry(r *ratelimit.Registry) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.RateLimiters = r
+	}
+}
+
+// WithBulkheadRegistry sets the bulkhead registry.
+func WithBulkheadRegistry(r *bulkhead.Registry) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.Bulkheads = r
+	}
+}
+
+// WithAttemptMiddleware appends middleware that wraps every attempt.
+// Middleware added first runs outermost.
+func WithAttemptMiddleware(mw ...AttemptMiddleware) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.AttemptMiddleware = append(c.opts.AttemptMiddleware, mw...)
+	}
+}
+
+// WithFallbackRegistry sets the fallback handler registry.
+func WithFallbackRegistry(r *fallback.Registry) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.Fallbacks = r
+	}
+}
+
+// WithJitterRegistry sets the registry that resolves custom jitter names.
+func WithJitterRegistry(r *policy.JitterRegistry) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.Jitters = r
+	}
+}
+
 // WithMissingPolicyMode sets the mode for handling missing policies.
 func WithMissingPolicyMode(mode FailureMode) ExecutorOption {
 	return func(c *executorConfig) {
@@ -300,6 +685,31 @@ func WithMissingBudgetMode(mode FailureMode) ExecutorOption {
 	}
 }
 
+// WithMissingTriggerMode sets the mode for handling missing hedge triggers.
+// FailureDeny fails calls whose policy hedges on an unregistered trigger; the
+// other modes fall back to fixed delay hedging.
+func WithMissingTriggerMode(mode FailureMode) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.MissingTriggerMode = mode
+	}
+}
+
+// WithMissingRateLimiterMode sets the mode for handling missing rate limiters.
+func WithMissingRateLimiterMode(mode FailureMode) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.MissingRateLimiterMode = mode
+	}
+}
+
+// WithMissingJitterMode sets the mode for handling missing custom jitters:
+// FailureDeny fails the call with NoJitterError, FailureAllow sleeps the
+// unjittered backoff, and FailureFallback uses full jitter.
+func WithMissingJitterMode(mode FailureMode) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.MissingJitterMode = mode
+	}
+}
+
 // WithRecoverPanics sets whether to capture and report panics in user code.
 func WithRecoverPanics(recover bool) ExecutorOption {
 	return func(c *executorConfig) {
@@ -307,6 +717,90 @@ func WithRecoverPanics(recover bool) ExecutorOption {
 	}
 }
 
+// WithRetryPanics sets whether operation panics recovered under RecoverPanics
+// are retried rather than ending the call.
+func WithRetryPanics(retry bool) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.RetryPanics = retry
+	}
+}
+
+// WithTimelinePooling sets whether timeline buffers are reused across calls.
+// See ExecutorOptions.PoolTimelines for the observer contract this implies.
+func WithTimelinePooling(enabled bool) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.PoolTimelines = enabled
+	}
+}
+
+// WithJitterSeed seeds the executor's jitter generator for reproducible backoff.
+func WithJitterSeed(seed int64) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.JitterSeed = seed
+	}
+}
+
+// WithRand sets the source of jitter randomness, overriding WithJitterSeed.
+func WithRand(src rand.Source) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.Rand = src
+	}
+}
+
+// WithFaultInjection sets whether policies may inject faults for game days.
+func WithFaultInjection(enabled bool) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.FaultInjection = enabled
+	}
+}
+
+// WithRequireIdempotentHedges sets whether hedging is limited to operations marked idempotent.
+func WithRequireIdempotentHedges(enabled bool) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.RequireIdempotentHedges = enabled
+	}
+}
+
+// WithMaxConcurrentHedges caps hedged attempts outstanding at once across all keys.
+func WithMaxConcurrentHedges(n int) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.MaxConcurrentHedges = n
+	}
+}
+
+// WithDefaultPolicy sets the policy used for keys without one of their own,
+// in place of policy.DefaultPolicyFor. See ExecutorOptions.DefaultPolicy.
+func WithDefaultPolicy(pol policy.EffectivePolicy) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.DefaultPolicy = pol
+	}
+}
+
+// WithMinPolicyVersion rejects provider policies older than version. See
+// ExecutorOptions.MinPolicyVersion.
+func WithMinPolicyVersion(version uint64) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.MinPolicyVersion = version
+	}
+}
+
+// WithPinnedPolicyVersion rejects provider policies other than version. See
+// ExecutorOptions.PinnedPolicyVersion.
+func WithPinnedPolicyVersion(version uint64) ExecutorOption {
+	return func(c *executorConfig) {
+		c.opts.PinnedPolicyVersion = version
+	}
+}
+
+// WithNamedError names a sentinel error for policy RetryOn and AbortOn matchers.
+func WithNamedError(name string, err error) ExecutorOption {
+	return func(c *executorConfig) {
+		errs := internal.CopyMap(&c.opts.Errors, 1)
+		errs[name] = err
+		c.opts.Errors = errs
+	}
+}
+
 // WithPolicy adds a static policy for a string key (e.g. "svc.Method").
 func WithPolicy(key string, opts ...policy.Option) ExecutorOption {
 	return func(c *executorConfig) {
@@ -338,52 +832,63 @@ func normalizeFailureMode(mode FailureMode, defaultMode FailureMode) FailureMode
 	}
 }
 
-func (e *Executor) Do(ctx context.Context, key policy.PolicyKey, op Operation) error {
+func (e *Executor) Do(ctx context.Context, key policy.PolicyKey, op Operation, opts ...CallOption) error {
 	_, err := DoValue[struct{}](ctx, e, key, func(ctx context.Context) (struct{}, error) {
 		return struct{}{}, op(ctx)
-	})
+	}, opts...)
 	return err
 }
 
-func DoValue[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op OperationValue[T]) (T, error) {
-	val, _, err := doValueInternal(ctx, exec, key, op, false)
+func DoValue[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op OperationValue[T], opts ...CallOption) (T, error) {
+	val, _, err := doValueInternal(ctx, exec, key, op, false, opts...)
 	return val, err
 }
 
-func doValueInternal[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op OperationValue[T], wantTimeline bool) (T, observe.Timeline, error) {
+func doValueInternal[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op OperationValue[T], wantTimeline bool, opts ...CallOption) (val T, tl observe.Timeline, err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
 	if exec == nil {
 		exec = NewExecutor()
-	} else if exec.provider == nil || exec.clock == nil || exec.sleep == nil || exec.observer == nil || exec.classifiers == nil || exec.defaultClassifier == nil {
-		exec = NewExecutorFromOptions(ExecutorOptions{
-			Provider:              exec.provider,
-			Observer:              exec.observer,
-			Clock:                 exec.clock,
-			Classifiers:           exec.classifiers,
-			DefaultClassifier:     exec.defaultClassifier,
-			Budgets:               exec.budgets,
-			Triggers:              exec.triggers,
-			Circuits:              exec.circuits,
-			MissingPolicyMode:     exec.missingPolicyMode,
-			MissingBudgetMode:     exec.missingBudgetMode,
-			MissingClassifierMode: exec.missingClassifierMode,
-			MissingTriggerMode:    exec.missingTriggerMode,
-			RecoverPanics:         exec.recoverPanics,
-		})
+	} else {
+		exec.ensureInitialized()
 	}
+	if !exec.enter(false) {
+		var zero T
+		return zero, observe.Timeline{}, ErrExecutorClosed
+	}
+	defer exec.leave()
+
+	counters := exec.callCounters(key)
+	counters.add(func(c *statCounters) {
+		c.calls.Add(1)
+		c.inFlightCalls.Add(1)
+	})
+	defer func() {
+		failed := err != nil
+		counters.add(func(c *statCounters) {
+			c.inFlightCalls.Add(-1)
+			if failed {
+				c.failures.Add(1)
+			} else {
+				c.successes.Add(1)
+			}
+		})
+	}()
+
+	cfg := newCallConfig(opts)
+	cfg.counters = counters
 
 	capture, hasCapture := observe.TimelineCaptureFromContext(ctx)
-	fullTimeline := wantTimeline || hasCapture || !isNoopObserver(exec.observer)
+	fullTimeline := wantTimeline || hasCapture || !isNoopObserver(exec.observer) || cfg.fallback != nil || len(exec.attemptMiddleware) > 0
 
 	if !fullTimeline {
 		// Use a wrapped op that suppresses capture to prevent implicit capture in nested calls.
 		fastOp := func(c context.Context) (T, error) {
 			return op(observe.WithoutTimelineCapture(c))
 		}
-		val, err := doValueFast(ctx, exec, key, fastOp)
+		val, err := doValueFast(ctx, exec, key, fastOp, &cfg)
 
 		// Fallback check
 		if err == errHedgingRequiresTimeline {
@@ -400,9 +905,12 @@ func doValueInternal[T any](ctx context.Context, exec *Executor, key policy.Poli
 		return op(observe.WithoutTimelineCapture(c))
 	}
 
-	val, tl, err := doValueWithTimeline(ctx, exec, key, safeOp)
+	val, tl, err = doValueWithTimeline(ctx, exec, key, safeOp, &cfg)
 	if capture != nil {
 		observe.StoreTimelineCapture(capture, &tl)
+	} else if exec.poolTimelines && !wantTimeline {
+		// Observers have returned and nobody else holds the timeline.
+		tl.Release()
 	}
 	// Record latency if we have a valid policy key and tracking is enabled.
 	return val, tl, err
@@ -427,25 +935,67 @@ func (e *Executor) getTracker(key policy.PolicyKey) hedge.LatencyTracker {
 	return t
 }
 
-func doValueFast[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op OperationValue[T]) (T, error) {
+func doValueFast[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op OperationValue[T], cfg *callConfig) (T, error) {
 	var zero T
+	var counters callCounters
+	if cfg != nil {
+		counters = cfg.counters
+	}
 
 	pol, err := resolvePolicyFast(ctx, exec, key)
 	if err != nil {
 		return zero, err
 	}
+	if cfg.hasOverrides() {
+		if pol, _, err = cfg.applyOverrides(pol); err != nil {
+			return zero, err
+		}
+	}
+	ctx = withPolicyPriority(ctx, pol)
 
-	if pol.Hedge.Enabled {
+	if pol.Hedge.Enabled || pol.Retry.SoftTimeoutPerAttempt {
 		return zero, errHedgingRequiresTimeline
 	}
 	if pol.Circuit.Enabled {
 		return zero, errHedgingRequiresTimeline // Reuse sentinel for now to force full path
 	}
+	if exec.faultsEnabled(pol) {
+		return zero, errHedgingRequiresTimeline // Injected faults are recorded on the timeline
+	}
+	if pol.DryRun {
+		return zero, errHedgingRequiresTimeline // Dry-run decisions are recorded on the timeline
+	}
+	if pol.Fallback.Mode != "" {
+		return zero, errHedgingRequiresTimeline // Fallbacks are recorded on the timeline
+	}
+	if pol.Cache.TTL > 0 {
+		return zero, errHedgingRequiresTimeline // Cache hits are recorded on the timeline
+	}
 
-	classifier, _, err := resolveClassifier(exec, pol)
+	classifier, _, err := resolveCallClassifier(exec, pol, cfg)
 	if err != nil {
 		return zero, err
 	}
+	jitterFn, _, err := resolveJitter(exec, pol.Retry)
+	if err != nil {
+		return zero, err
+	}
+
+	limiter, rl, ok := exec.allowCall(ctx, key, pol.RateLimit)
+	if !ok {
+		counters.add(countDenial)
+		return zero, RateLimitedError{Limiter: pol.RateLimit.Name, Reason: rl.Reason, RetryAfter: rl.RetryAfter}
+	}
+	feedback := limiterFeedback(limiter)
+
+	bh, bd, ok := exec.acquireBulkhead(ctx, key, pol.Concurrency)
+	if !ok {
+		counters.add(countDenial)
+		return zero, BulkheadFullError{MaxInFlight: pol.Concurrency.MaxInFlight, Reason: bd.Reason}
+	}
+	if bh != nil {
+		defer bh.Release()
+	}
 
 	if pol.Retry.OverallTimeout > 0 {
 		var cancel context.CancelFunc
@@ -453,41 +1003,54 @@ func doValueFast[T any](ctx context.Context, exec *Executor, key policy.PolicyKe
 		defer cancel()
 	}
 
-	maxAttempts := pol.Retry.MaxAttempts
-	if maxAttempts <= 0 {
-		maxAttempts = 1
-	}
+	maxAttempts := attemptLimit(pol.Retry)
 
 	backoff := pol.Retry.InitialBackoff
 
 	var last T
 	var lastErr error
+	var lastOutcome *classify.Outcome
+	var totalBackoff time.Duration
+	callStart := exec.clock()
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
 		if err := ctx.Err(); err != nil {
 			return last, err
 		}
+		if expected, remaining, short := exec.attemptDeadlineInsufficient(ctx, key, pol.Retry); short {
+			return last, &DeadlineInsufficientError{Expected: expected, Remaining: remaining, Err: lastErr}
+		}
 
-		decision, ok := exec.allowAttempt(ctx, key, pol.Retry.Budget, attempt, budget.KindRetry)
+		if attempt == 0 {
+			exec.trackRequest(ctx, key, pol)
+		}
+		decision, ok := exec.allowAttempts(ctx, key, pol.Retry.Budget, pol.Retry.Budgets, attempt, budget.KindRetry)
 		// Check if attempt is allowed by budget.
 		if !ok {
+			counters.add(countDenial)
 			return last, errors.New(decision.Reason)
 		}
-
-		release := decision.Release
+		if attempt > 0 {
+			counters.add(countRetry)
+		} else {
+			counters.add(countAttempt)
+		}
 
 		attemptCtx := ctx
 		cancelAttempt := func() {}
-		if pol.Retry.TimeoutPerAttempt > 0 {
-			attemptCtx, cancelAttempt = context.WithTimeout(ctx, pol.Retry.TimeoutPerAttempt)
+		if timeout := attemptTimeout(ctx, pol.Retry, attempt, maxAttempts, backoff); timeout > 0 {
+			attemptCtx, cancelAttempt = context.WithTimeout(ctx, timeout)
 		}
 
 		// Inject attempt info for observability.
 		attemptCtx = observe.WithAttemptInfo(attemptCtx, observe.AttemptInfo{
-			RetryIndex: attempt,
-			Attempt:    attempt,
-			IsHedge:    false,
-			PolicyID:   pol.ID,
+			RetryIndex:  attempt,
+			Attempt:     attempt,
+			IsHedge:     false,
+			PolicyID:    pol.ID,
+			PrevErr:     lastErr,
+			PrevOutcome: lastOutcome,
+			Backoff:     totalBackoff,
 		})
 
 		var val T
@@ -495,11 +1058,9 @@ func doValueFast[T any](ctx context.Context, exec *Executor, key policy.PolicyKe
 
 		func() {
 			defer cancelAttempt()
-			if release != nil {
-				defer release()
-			}
+			defer decision.Done()
 			start := exec.clock()
-			val, err = op(attemptCtx)
+			val, err = callOperation(exec, attemptCtx, key, op)
 			// Feed latency tracker
 			exec.getTracker(key).Observe(exec.clock().Sub(start))
 		}()
@@ -507,10 +1068,18 @@ func doValueFast[T any](ctx context.Context, exec *Executor, key policy.PolicyKe
 		last = val
 		lastErr = err
 
-		out, panicErr := classifyWithRecovery(exec.recoverPanics, classifier, val, err, key)
+		ac := classify.AttemptContext{Key: key, Attempt: attempt, Elapsed: exec.clock().Sub(callStart)}
+		out, panicErr := classifyWithRecovery(exec.recoverPanics, classifier, val, err, ac)
 		if panicErr != nil {
 			return last, panicErr
 		}
+		exec.classifyOperationPanic(&out, err)
+		exec.overrideOutcome(pol, &out, err)
+		markAbortedByCaller(ctx, &out)
+		exec.applyPushback(key, &out, err)
+		exec.adaptBackoff(key, pol.Retry, out)
+		observeLimiter(ctx, feedback, key, out)
+		exec.observeBudgets(ctx, key, pol, out)
 
 		if out.Kind == classify.OutcomeSuccess {
 			return val, nil
@@ -525,30 +1094,53 @@ func doValueFast[T any](ctx context.Context, exec *Executor, key policy.PolicyKe
 			return last, terminalError(ctx, lastErr, out)
 		}
 
-		if attempt == maxAttempts-1 {
+		maxAttempts = capAttempts(maxAttempts, attempt, out)
+		ov := pol.ReasonOverrides[out.Reason]
+		if attempt == maxAttempts-1 || (ov.MaxAttempts > 0 && attempt+1 >= ov.MaxAttempts) {
 			return last, terminalError(ctx, lastErr, out)
 		}
 
-		sleepFor := computeSleep(backoff, pol.Retry, out)
+		sleepFor := exec.cooldownSleep(key, pol.Retry, max(computeSleep(exec.adaptiveBackoff(key, pol.Retry, backoff), pol.Retry, out, exec.jitter, jitterFn), ov.MinBackoff))
+		if backoffBudgetExhausted(pol.Retry, totalBackoff, sleepFor) {
+			return last, &BackoffBudgetExhaustedError{Backoff: sleepFor, Spent: totalBackoff, Limit: pol.Retry.MaxCumulativeBackoff, Err: terminalError(ctx, lastErr, out)}
+		}
+		if remaining, short := deadlineInsufficient(ctx, sleepFor); short {
+			return last, &DeadlineInsufficientError{Backoff: sleepFor, Remaining: remaining, Err: terminalError(ctx, lastErr, out)}
+		}
 		if sleepFor > 0 {
 			if err := exec.sleep(ctx, sleepFor); err != nil {
 				return last, err
 			}
 		}
+		totalBackoff += sleepFor
+		lastOutcome = &out
 
-		backoff = nextBackoff(backoff, pol.Retry.BackoffMultiplier, pol.Retry.MaxBackoff)
+		backoff = advanceBackoff(pol.Retry, backoff, sleepFor)
 	}
 
 	return last, lastErr
 }
 
-func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op OperationValue[T]) (T, observe.Timeline, error) {
+func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op OperationValue[T], cfg *callConfig) (T, observe.Timeline, error) {
 	var zero T
+	var counters callCounters
+	if cfg != nil {
+		counters = cfg.counters
+	}
 
 	start := exec.clock()
 
 	// 1. Resolve Policy
 	pol, attrs, err := resolvePolicyWithAttributes(ctx, exec, key)
+	if err == nil && cfg.hasOverrides() {
+		var fields string
+		if pol, fields, err = cfg.applyOverrides(pol); err == nil {
+			exec.setAttribute(&attrs, "policy_overrides", fields)
+		}
+	}
+	if cfg != nil && cfg.chainedFrom != "" {
+		exec.setAttribute(&attrs, "fallback_from", cfg.chainedFrom)
+	}
 	if err != nil {
 		tl := observe.Timeline{
 			Key:        key,
@@ -560,17 +1152,103 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 			FinalErr:   err,
 		}
 		exec.observer.OnStart(ctx, key, pol)
-		exec.observer.OnFailure(ctx, key, tl)
+		exec.notifyFailure(ctx, key, &tl)
 		return zero, tl, err
 	}
+	ctx = withPolicyPriority(ctx, pol)
+
+	// 1a. Reject hedging on a missing trigger under FailureDeny
+	if pol.Hedge.Enabled {
+		if _, err := exec.resolveTrigger(pol.Hedge); err != nil {
+			tl := observe.Timeline{
+				Key:        key,
+				PolicyID:   pol.ID,
+				Start:      start,
+				End:        exec.clock(),
+				Attributes: attrs,
+				FinalErr:   err,
+			}
+			exec.setAttribute(&tl.Attributes, "trigger_name", pol.Hedge.TriggerName)
+			exec.setAttribute(&tl.Attributes, "trigger_error", "trigger_not_found")
+			exec.observer.OnStart(ctx, key, pol)
+			exec.notifyFailure(ctx, key, &tl)
+			return zero, tl, err
+		}
+	}
 
-	// 2. Check Circuit Breaker
+	// 1b. Serve a fresh cached result
+	if pol.Cache.TTL > 0 {
+		if val, ok := cachedValue[T](exec, key, pol.Cache.TTL); ok {
+			now := exec.clock()
+			tl := observe.Timeline{
+				Key:        key,
+				PolicyID:   pol.ID,
+				Start:      start,
+				End:        now,
+				Attributes: attrs,
+			}
+			exec.setAttribute(&tl.Attributes, "cache", "hit")
+			exec.observer.OnStart(ctx, key, pol)
+			exec.observer.OnSuccess(ctx, key, tl)
+			return val, tl, nil
+		}
+	}
+
+	// 2. Check Rate Limiter
+	limiter, rl, ok := exec.allowCall(ctx, key, pol.RateLimit)
+	if !ok {
+		counters.add(countDenial)
+		tl := observe.Timeline{
+			Key:        key,
+			PolicyID:   pol.ID,
+			Start:      start,
+			End:        exec.clock(),
+			Attributes: attrs,
+			Attempts:   nil,
+			FinalErr:   RateLimitedError{Limiter: pol.RateLimit.Name, Reason: rl.Reason, RetryAfter: rl.RetryAfter},
+		}
+		exec.observer.OnStart(ctx, key, pol)
+		exec.notifyFailure(ctx, key, &tl)
+		return zero, tl, tl.FinalErr
+	}
+	feedback := limiterFeedback(limiter)
+
+	// 2b. Check Bulkhead
+	bh, bd, ok := exec.acquireBulkhead(ctx, key, pol.Concurrency)
+	if !ok {
+		counters.add(countDenial)
+		tl := observe.Timeline{
+			Key:        key,
+			PolicyID:   pol.ID,
+			Start:      start,
+			End:        exec.clock(),
+			Attributes: attrs,
+			Attempts:   nil,
+			FinalErr:   BulkheadFullError{MaxInFlight: pol.Concurrency.MaxInFlight, Reason: bd.Reason},
+		}
+		exec.observer.OnStart(ctx, key, pol)
+		exec.notifyFailure(ctx, key, &tl)
+		return zero, tl, tl.FinalErr
+	}
+	if bh != nil {
+		defer bh.Release()
+	}
+
+	// 3. Check Circuit Breaker
 	var cb circuit.CircuitBreaker
+	halfOpen := false
 	if pol.Circuit.Enabled {
-		cb = exec.circuits.Get(key, pol.Circuit)
-		if cb != nil {
+		tenant, _ := policy.TenantFromContext(ctx)
+		cb = exec.circuits.GetForTenant(key, tenant, pol.Circuit)
+		if cb != nil && pol.DryRun {
+			// Report the breaker's state without taking a probe slot or
+			// recording the attempt.
+			exec.setAttribute(&attrs, "dry_run_circuit", cb.State().String())
+			cb = nil
+		} else if cb != nil {
 			decision := cb.Allow(ctx)
 			if !decision.Allowed {
+				counters.add(countDenial)
 				tl := observe.Timeline{
 					Key:        key,
 					PolicyID:   pol.ID,
@@ -580,20 +1258,35 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 					Attempts:   nil,
 					FinalErr:   CircuitOpenError{State: decision.State, Reason: decision.Reason},
 				}
-				tl.Attributes["circuit_state"] = decision.State.String()
+				exec.setAttribute(&tl.Attributes, "circuit_state", decision.State.String())
 				exec.observer.OnStart(ctx, key, pol)
-				exec.observer.OnFailure(ctx, key, tl)
+				if val, ok := applyFallback(ctx, exec, key, pol, op, cfg, &tl); ok {
+					exec.observer.OnSuccess(ctx, key, tl)
+					return val, tl, nil
+				}
+				exec.notifyFailure(ctx, key, &tl)
 				return zero, tl, tl.FinalErr
 			}
 			// If allowed, we proceed.
 			// Half-open state might affect hedging later.
 			if decision.State == circuit.StateHalfOpen {
 				pol.Hedge.Enabled = false
+				halfOpen = true
 			}
 		}
 	}
 
-	classifier, cmeta, err := resolveClassifier(exec, pol)
+	// Hedges run the operation concurrently, which is only safe when it is idempotent.
+	nonIdempotent := !pol.Idempotent && (pol.Hedge.RequireIdempotent || exec.requireIdempotent)
+	if pol.Hedge.Enabled && nonIdempotent {
+		pol.Hedge.Enabled = false
+		exec.setAttribute(&attrs, "hedge_suppressed_non_idempotent", "true")
+	}
+	// A soft per-attempt timeout hedges the slow attempt; when hedging is
+	// ruled out it falls back to cancelling it.
+	softTimeout := pol.Retry.SoftTimeoutPerAttempt && !halfOpen && !nonIdempotent
+
+	classifier, cmeta, err := resolveCallClassifier(exec, pol, cfg)
 	if err != nil {
 		tl := observe.Timeline{
 			Key:        key,
@@ -605,11 +1298,29 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 			FinalErr:   err,
 		}
 		if cmeta.requested != "" {
-			tl.Attributes["classifier_name"] = cmeta.requested
+			exec.setAttribute(&tl.Attributes, "classifier_name", cmeta.requested)
 		}
-		tl.Attributes["classifier_error"] = "classifier_not_found"
+		exec.setAttribute(&tl.Attributes, "classifier_error", "classifier_not_found")
 		exec.observer.OnStart(ctx, key, pol)
-		exec.observer.OnFailure(ctx, key, tl)
+		exec.notifyFailure(ctx, key, &tl)
+		return zero, tl, err
+	}
+
+	jitterFn, jitterMissing, err := resolveJitter(exec, pol.Retry)
+	if jitterMissing {
+		exec.setAttribute(&attrs, "jitter_not_found", string(pol.Retry.Jitter))
+	}
+	if err != nil {
+		tl := observe.Timeline{
+			Key:        key,
+			PolicyID:   pol.ID,
+			Start:      start,
+			End:        exec.clock(),
+			Attributes: attrs,
+			FinalErr:   err,
+		}
+		exec.observer.OnStart(ctx, key, pol)
+		exec.notifyFailure(ctx, key, &tl)
 		return zero, tl, err
 	}
 
@@ -619,17 +1330,14 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 		defer cancel()
 	}
 
-	maxAttempts := pol.Retry.MaxAttempts
-	if maxAttempts <= 0 {
-		maxAttempts = 1
-	}
+	maxAttempts := attemptLimit(pol.Retry)
 
 	tl := observe.Timeline{
 		Key:        key,
 		PolicyID:   pol.ID,
 		Start:      start,
 		Attributes: attrs,
-		Attempts:   make([]observe.AttemptRecord, 0, maxAttempts),
+		Attempts:   exec.newAttempts(maxAttempts),
 	}
 	exec.observer.OnStart(ctx, key, pol)
 
@@ -637,7 +1345,7 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 
 	var last T
 	var lastErr error
-	var lastBackoff time.Duration
+	prior := priorAttempt{callStart: start, counters: counters}
 
 	var tlMu sync.Mutex
 	var done bool
@@ -649,6 +1357,8 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 		}
 		tl.Attempts = append(tl.Attempts, rec)
 		exec.observer.OnAttempt(ctx, key, rec)
+		observeLimiter(ctx, feedback, key, rec.Outcome)
+		exec.observeBudgets(ctx, key, pol, rec.Outcome)
 
 		// Feed latency tracker
 		tracker := exec.getTracker(key)
@@ -662,22 +1372,64 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 			tl.End = exec.clock()
 			tl.FinalErr = err
 			tlMu.Unlock()
-			exec.observer.OnFailure(ctx, key, tl)
+			exec.notifyFailure(ctx, key, &tl)
 			// Context canceled before attempt.
 			return last, tl, err
 		}
 
-		opAny := func(c context.Context) (any, error) { return op(c) }
+		if expected, remaining, short := exec.attemptDeadlineInsufficient(ctx, key, pol.Retry); short {
+			// The attempt is not expected to finish before the deadline;
+			// don't load the downstream with it.
+			now := exec.clock()
+			terr := &DeadlineInsufficientError{Expected: expected, Remaining: remaining, Err: lastErr}
+			rec := observe.AttemptRecord{
+				Attempt:       attempt,
+				StartTime:     now,
+				EndTime:       now,
+				Outcome:       classify.Outcome{Kind: classify.OutcomeAbort, Reason: "insufficient_deadline"},
+				Backoff:       prior.backoff,
+				BudgetAllowed: true,
+			}
+			tlMu.Lock()
+			tl.Attempts = append(tl.Attempts, rec)
+			exec.observer.OnAttempt(ctx, key, rec)
+			done = true
+			tl.End = now
+			tl.FinalErr = terr
+			tlMu.Unlock()
+			if val, ok := applyFallback(ctx, exec, key, pol, op, cfg, &tl); ok {
+				exec.observer.OnSuccess(ctx, key, tl)
+				return val, tl, nil
+			}
+			exec.notifyFailure(ctx, key, &tl)
+			return last, tl, terr
+		}
+
+		attemptPol := pol
+		if pol.Retry.AutoTimeoutPerAttempt {
+			attemptPol.Retry.TimeoutPerAttempt = attemptTimeout(ctx, pol.Retry, attempt, maxAttempts, backoff)
+		}
+
+		// Keys cooling down after pushback do not hedge.
+		coolingDown := exec.cooldownRemaining(key) > 0
+		if softTimeout && !coolingDown {
+			attemptPol = softTimeoutPolicy(attemptPol)
+		}
+		runGroup := doRetryGroup[T]
+		if !attemptPol.Hedge.Enabled || coolingDown || pol.DryRun {
+			runGroup = doSingleAttempt[T]
+		}
 
-		valAny, err, outcome, success := exec.doRetryGroup(
+		val, err, outcome, success := runGroup(
+			exec,
 			ctx,
 			key,
-			opAny,
-			pol,
+			op,
+			attemptPol,
 			attempt,
 			classifier,
 			cmeta,
-			lastBackoff,
+			prior,
 			recordAttempt,
 		)
 
@@ -692,21 +1444,50 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 			tl.End = exec.clock()
 			tl.FinalErr = nil
 			tlMu.Unlock()
+			if pol.Fallback.Mode == policy.FallbackCached || pol.Cache.TTL > 0 {
+				exec.rememberResult(key, val)
+			}
+			if pol.DryRun {
+				exec.recordDryRun(ctx, key, attemptPol, outcome, backoff, jitterFn, &tl)
+			}
 			exec.observer.OnSuccess(ctx, key, tl)
-			return valAny.(T), tl, nil
+			return val, tl, nil
+		}
+
+		if pol.DryRun {
+			terr := terminalError(ctx, err, outcome)
+			tlMu.Lock()
+			done = true
+			tl.End = exec.clock()
+			tl.FinalErr = terr
+			exec.recordDryRun(ctx, key, attemptPol, outcome, backoff, jitterFn, &tl)
+			tlMu.Unlock()
+			exec.notifyFailure(ctx, key, &tl)
+			return val, tl, terr
 		}
 
 		prevErr := lastErr
 		lastErr = err
+		prior.err = err
+		prior.outcome = &outcome
 
 		isTerminal := false
 		if outcome.Kind == classify.OutcomeAbort || outcome.Kind == classify.OutcomeNonRetryable {
 			isTerminal = true
 		}
+		// A tripped circuit ends the call: its retries would be denied anyway.
+		tripped := outcome.TripCircuit && cb != nil
+		if tripped {
+			isTerminal = true
+			tripCircuit(ctx, cb)
+			tlMu.Lock()
+			exec.setAttribute(&tl.Attributes, "circuit_tripped", "true")
+			tlMu.Unlock()
+		}
 
 		if isTerminal {
 			// Record failure to circuit breaker (unless it's an abort/cancellation).
-			if cb != nil && outcome.Kind != classify.OutcomeAbort {
+			if cb != nil && !tripped && outcome.Kind != classify.OutcomeAbort {
 				cb.RecordFailure(ctx)
 			}
 
@@ -720,11 +1501,13 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 			tl.End = exec.clock()
 			tl.FinalErr = terr
 			tlMu.Unlock()
-			exec.observer.OnFailure(ctx, key, tl)
+			exec.notifyFailure(ctx, key, &tl)
 
 			return last, tl, terr
 		}
-		if attempt == maxAttempts-1 {
+		maxAttempts = capAttempts(maxAttempts, attempt, outcome)
+		ov := pol.ReasonOverrides[outcome.Reason]
+		if attempt == maxAttempts-1 || (ov.MaxAttempts > 0 && attempt+1 >= ov.MaxAttempts) {
 			// Max attempts reached, still failing.
 			if cb != nil && outcome.Kind != classify.OutcomeAbort {
 				cb.RecordFailure(ctx)
@@ -736,12 +1519,58 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 			tl.End = exec.clock()
 			tl.FinalErr = terr
 			tlMu.Unlock()
-			exec.observer.OnFailure(ctx, key, tl)
+			if val, ok := applyFallback(ctx, exec, key, pol, op, cfg, &tl); ok {
+				exec.observer.OnSuccess(ctx, key, tl)
+				return val, tl, nil
+			}
+			exec.notifyFailure(ctx, key, &tl)
+			return last, tl, terr
+		}
+
+		sleepFor := exec.cooldownSleep(key, pol.Retry, max(computeSleep(exec.adaptiveBackoff(key, pol.Retry, backoff), pol.Retry, outcome, exec.jitter, jitterFn), ov.MinBackoff))
+		if backoffBudgetExhausted(pol.Retry, prior.totalBackoff, sleepFor) {
+			// Retrying would sleep past the call's backoff budget.
+			if cb != nil {
+				cb.RecordFailure(ctx)
+			}
+
+			terr := &BackoffBudgetExhaustedError{Backoff: sleepFor, Spent: prior.totalBackoff, Limit: pol.Retry.MaxCumulativeBackoff, Err: terminalError(ctx, lastErr, outcome)}
+			tlMu.Lock()
+			done = true
+			tl.End = exec.clock()
+			tl.FinalErr = terr
+			exec.setAttribute(&tl.Attributes, "stop_reason", "backoff_budget_exhausted")
+			tlMu.Unlock()
+			if val, ok := applyFallback(ctx, exec, key, pol, op, cfg, &tl); ok {
+				exec.observer.OnSuccess(ctx, key, tl)
+				return val, tl, nil
+			}
+			exec.notifyFailure(ctx, key, &tl)
 			return last, tl, terr
 		}
+		prior.backoff = sleepFor
+		prior.totalBackoff += sleepFor
+		if remaining, short := deadlineInsufficient(ctx, sleepFor); short {
+			// The next attempt could not start before the deadline; stop now
+			// rather than burn the remaining time sleeping.
+			if cb != nil {
+				cb.RecordFailure(ctx)
+			}
 
-		sleepFor := computeSleep(backoff, pol.Retry, outcome)
-		lastBackoff = sleepFor
+			terr := &DeadlineInsufficientError{Backoff: sleepFor, Remaining: remaining, Err: terminalError(ctx, lastErr, outcome)}
+			tlMu.Lock()
+			done = true
+			tl.End = exec.clock()
+			tl.FinalErr = terr
+			exec.setAttribute(&tl.Attributes, "stop_reason", "deadline_insufficient")
+			tlMu.Unlock()
+			if val, ok := applyFallback(ctx, exec, key, pol, op, cfg, &tl); ok {
+				exec.observer.OnSuccess(ctx, key, tl)
+				return val, tl, nil
+			}
+			exec.notifyFailure(ctx, key, &tl)
+			return last, tl, terr
+		}
 		if sleepFor > 0 {
 			if err := exec.sleep(ctx, sleepFor); err != nil {
 				tlMu.Lock()
@@ -749,12 +1578,12 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 				tl.End = exec.clock()
 				tl.FinalErr = err
 				tlMu.Unlock()
-				exec.observer.OnFailure(ctx, key, tl)
+				exec.notifyFailure(ctx, key, &tl)
 				return last, tl, err
 			}
 		}
 
-		backoff = nextBackoff(backoff, pol.Retry.BackoffMultiplier, pol.Retry.MaxBackoff)
+		backoff = advanceBackoff(pol.Retry, backoff, sleepFor)
 	}
 
 	tlMu.Lock()
@@ -762,12 +1591,37 @@ func doValueWithTimeline[T any](ctx context.Context, exec *Executor, key policy.
 	tl.End = exec.clock()
 	tl.FinalErr = lastErr
 	tlMu.Unlock()
-	exec.observer.OnFailure(ctx, key, tl)
+	exec.notifyFailure(ctx, key, &tl)
 	return last, tl, lastErr
 }
 
+// notifyFailure stamps tl with the fingerprint of its final error and reports
+// it to the observer.
+func (e *Executor) notifyFailure(ctx context.Context, key policy.PolicyKey, tl *observe.Timeline) {
+	var reason string
+	if n := len(tl.Attempts); n > 0 {
+		reason = tl.Attempts[n-1].Outcome.Reason
+	}
+	tl.FinalErrFingerprint = observe.Fingerprint(tl.FinalErr, reason)
+	e.observer.OnFailure(ctx, key, *tl)
+}
+
+// withPolicyPriority attaches the policy's default priority to ctx unless the
+// caller already set one, so budgets and circuit breakers see the same priority.
+func withPolicyPriority(ctx context.Context, pol policy.EffectivePolicy) context.Context {
+	if pol.Priority == "" {
+		return ctx
+	}
+	if _, ok := policy.PriorityFromContext(ctx); ok {
+		return ctx
+	}
+	return policy.WithPriority(ctx, pol.Priority)
+}
+
+// resolvePolicyWithAttributes resolves the policy for key. The returned
+// attributes map is nil unless resolution recorded something.
 func resolvePolicyWithAttributes(ctx context.Context, exec *Executor, key policy.PolicyKey) (policy.EffectivePolicy, map[string]string, error) {
-	attrs := make(map[string]string)
+	var attrs map[string]string
 
 	var pol policy.EffectivePolicy
 	var err error
@@ -787,6 +1641,11 @@ func resolvePolicyWithAttributes(ctx context.Context, exec *Executor, key policy
 		}
 		pol, err = exec.provider.GetEffectivePolicy(ctx, key)
 	}()
+	if err == nil {
+		if err = exec.checkPolicyVersion(key, pol); err != nil {
+			pol = policy.EffectivePolicy{}
+		}
+	}
 
 	if err != nil {
 		switch exec.missingPolicyMode {
@@ -795,15 +1654,38 @@ func resolvePolicyWithAttributes(ctx context.Context, exec *Executor, key policy
 		case FailureAllow:
 			pol = policy.EffectivePolicy{Key: key, Retry: policy.RetryPolicy{MaxAttempts: 1}}
 		case FailureFallback:
-			if isZeroEffectivePolicy(pol) {
-				pol = policy.DefaultPolicyFor(key)
+			if pol.IsZero() {
+				pol = exec.defaultPolicyFor(key)
 			}
 		}
 	}
-	if isZeroEffectivePolicy(pol) {
-		pol = policy.DefaultPolicyFor(key)
+	if pol.IsZero() {
+		pol = exec.defaultPolicyFor(key)
 	}
 	pol.Key = key
+	if m := pol.Meta.MatchedKey; m != (policy.PolicyKey{}) && m != key {
+		exec.setAttribute(&attrs, "policy_match", m.String())
+	}
+	if pol.Meta.Selector != "" {
+		exec.setAttribute(&attrs, "policy_selector", pol.Meta.Selector)
+	}
+	if pol.Meta.Version != 0 {
+		exec.setAttribute(&attrs, "policy_version", strconv.FormatUint(pol.Meta.Version, 10))
+	}
+	if pol.Meta.Revision != "" {
+		exec.setAttribute(&attrs, "policy_revision", pol.Meta.Revision)
+	}
+	var verErr *PolicyVersionError
+	if errors.As(err, &verErr) {
+		exec.setAttribute(&attrs, "policy_error", "version_rejected")
+	}
+
+	if len(pol.Windows) > 0 {
+		var window string
+		if pol, window = pol.AtTime(exec.clock()); window != "" {
+			exec.setAttribute(&attrs, "policy_window", window)
+		}
+	}
 
 	pol, normErr := pol.Normalize()
 	if normErr != nil {
@@ -814,10 +1696,10 @@ func resolvePolicyWithAttributes(ctx context.Context, exec *Executor, key policy
 			pol = policy.EffectivePolicy{Key: key, Retry: policy.RetryPolicy{MaxAttempts: 1}}
 			pol, _ = pol.Normalize()
 		case FailureFallback:
-			pol = policy.DefaultPolicyFor(key)
+			pol = exec.defaultPolicyFor(key)
 			pol, _ = pol.Normalize()
 		}
-		attrs["policy_error"] = fmt.Sprintf("normalization_failed: %v", normErr)
+		exec.setAttribute(&attrs, "policy_error", fmt.Sprintf("normalization_failed: %v", normErr))
 	}
 
 	return pol, attrs, nil
@@ -845,6 +1727,11 @@ func resolvePolicyFast(ctx context.Context, exec *Executor, key policy.PolicyKey
 		}
 		pol, err = exec.provider.GetEffectivePolicy(ctx, key)
 	}()
+	if err == nil {
+		if err = exec.checkPolicyVersion(key, pol); err != nil {
+			pol = policy.EffectivePolicy{}
+		}
+	}
 
 	if err != nil {
 		switch exec.missingPolicyMode {
@@ -853,15 +1740,18 @@ func resolvePolicyFast(ctx context.Context, exec *Executor, key policy.PolicyKey
 		case FailureAllow:
 			pol = policy.EffectivePolicy{Key: key, Retry: policy.RetryPolicy{MaxAttempts: 1}}
 		case FailureFallback:
-			if isZeroEffectivePolicy(pol) {
-				pol = policy.DefaultPolicyFor(key)
+			if pol.IsZero() {
+				pol = exec.defaultPolicyFor(key)
 			}
 		}
 	}
-	if isZeroEffectivePolicy(pol) {
-		pol = policy.DefaultPolicyFor(key)
+	if pol.IsZero() {
+		pol = exec.defaultPolicyFor(key)
 	}
 	pol.Key = key
+	if len(pol.Windows) > 0 {
+		pol, _ = pol.AtTime(exec.clock())
+	}
 
 	pol, normErr := pol.Normalize()
 	if normErr != nil {
@@ -872,7 +1762,7 @@ func resolvePolicyFast(ctx context.Context, exec *Executor, key policy.PolicyKey
 			pol = policy.EffectivePolicy{Key: key, Retry: policy.RetryPolicy{MaxAttempts: 1}}
 			pol, _ = pol.Normalize()
 		case FailureFallback:
-			pol = policy.DefaultPolicyFor(key)
+			pol = exec.defaultPolicyFor(key)
 			pol, _ = pol.Normalize()
 		}
 	}
@@ -893,6 +1783,44 @@ func policyErrorKind(err error) string {
 	}
 }
 
+func (e *Executor) newAttempts(n int) []observe.AttemptRecord {
+	n = min(n, maxPreallocatedAttempts)
+	if e.poolTimelines {
+		return observe.PooledAttempts(n)
+	}
+	return make([]observe.AttemptRecord, 0, n)
+}
+
+// maxPreallocatedAttempts caps the attempt records preallocated for a call, so
+// policies with unlimited attempts grow their timeline as they go.
+const maxPreallocatedAttempts = 16
+
+// attemptLimit returns the number of attempts a call may make under pol.
+// Unlimited policies are bounded only by the context.
+func attemptLimit(pol policy.RetryPolicy) int {
+	switch {
+	case pol.MaxAttempts == policy.UnlimitedAttempts:
+		return math.MaxInt
+	case pol.MaxAttempts <= 0:
+		return 1
+	default:
+		return pol.MaxAttempts
+	}
+}
+
+// setAttribute writes a timeline attribute, allocating the map on first write
+// so calls that record nothing produce no attribute garbage.
+func (e *Executor) setAttribute(attrs *map[string]string, key, value string) {
+	if *attrs == nil {
+		if e.poolTimelines {
+			*attrs = observe.PooledAttributes()
+		} else {
+			*attrs = make(map[string]string, 2)
+		}
+	}
+	(*attrs)[key] = value
+}
+
 func isNoopObserver(obs observe.Observer) bool {
 	switch obs.(type) {
 	case observe.NoopObserver, *observe.NoopObserver:
@@ -902,11 +1830,15 @@ func isNoopObserver(obs observe.Observer) bool {
 	}
 }
 
-func isZeroEffectivePolicy(pol policy.EffectivePolicy) bool {
-	return pol.Key == (policy.PolicyKey{}) &&
-		pol.ID == "" &&
-		pol.Retry == (policy.RetryPolicy{}) &&
-		pol.Hedge == (policy.HedgePolicy{})
+// defaultPolicyFor returns the fallback policy for key: the executor's
+// DefaultPolicy when set, policy.DefaultPolicyFor otherwise.
+func (e *Executor) defaultPolicyFor(key policy.PolicyKey) policy.EffectivePolicy {
+	if e.defaultPolicy.IsZero() {
+		return policy.DefaultPolicyFor(key)
+	}
+	pol := e.defaultPolicy
+	pol.Key = key
+	return pol
 }
 
 func sleepWithContext(ctx context.Context, d time.Duration) error {
@@ -944,15 +1876,34 @@ func nextBackoff(current time.Duration, multiplier float64, max time.Duration) t
 	return next
 }
 
-func applyJitter(backoff time.Duration, kind policy.JitterKind) time.Duration {
+// advanceBackoff returns the backoff for the next retry. Decorrelated jitter
+// grows from the previous sleep instead of multiplying the backoff.
+func advanceBackoff(pol policy.RetryPolicy, backoff, slept time.Duration) time.Duration {
+	if pol.Jitter == policy.JitterDecorrelated && slept > 0 {
+		return slept
+	}
+	return nextBackoff(backoff, pol.BackoffMultiplier, pol.MaxBackoff)
+}
+
+// decorrelatedJitter returns a random sleep in [base, 3*prev), the AWS
+// "decorrelated jitter" algorithm.
+func decorrelatedJitter(base, prev time.Duration, rng *jitterRand) time.Duration {
+	hi := 3 * prev
+	if hi <= base {
+		return base
+	}
+	return base + time.Duration(rng.Float64()*float64(hi-base))
+}
+
+func applyJitter(backoff time.Duration, kind policy.JitterKind, rng *jitterRand) time.Duration {
 	switch kind {
 	case policy.JitterNone, "":
 		return backoff
 	case policy.JitterFull:
-		return time.Duration(rand.Float64() * float64(backoff))
+		return time.Duration(rng.Float64() * float64(backoff))
 	case policy.JitterEqual:
 		half := float64(backoff) / 2
-		return time.Duration(half + rand.Float64()*half)
+		return time.Duration(half + rng.Float64()*half)
 	default:
 		return backoff
 	}
@@ -963,12 +1914,83 @@ type classifierMeta struct {
 	notFound  bool
 }
 
+// resolvedClassifier is a cached resolveClassifier result, valid while the
+// classifier registry version and missing-classifier mode are unchanged.
+type resolvedClassifier struct {
+	version    uint64
+	mode       FailureMode
+	classifier classify.Classifier
+	meta       classifierMeta
+	err        error
+}
+
+// resolveCallClassifier is resolveClassifier, preferring the call's
+// OverrideClassifier classifier over the policy's.
+func resolveCallClassifier(exec *Executor, pol policy.EffectivePolicy, cfg *callConfig) (classify.Classifier, classifierMeta, error) {
+	if cfg == nil || cfg.classifier == nil {
+		return resolveClassifier(exec, pol)
+	}
+	return withRetryableCodes(cfg.classifier, pol), classifierMeta{}, nil
+}
+
+// resolveClassifier resolves the policy's classifier and applies the policy's
+// retryable status and code lists to it.
 func resolveClassifier(exec *Executor, pol policy.EffectivePolicy) (classify.Classifier, classifierMeta, error) {
+	c, meta, err := resolveNamedClassifier(exec, pol)
+	if err != nil {
+		return c, meta, err
+	}
+	return withRetryableCodes(c, pol), meta, nil
+}
+
+// withRetryableCodes applies the policy's retryable status and code lists to
+// c when it supports them.
+func withRetryableCodes(c classify.Classifier, pol policy.EffectivePolicy) classify.Classifier {
+	if pol.Retry.RetryableHTTPStatuses == nil && pol.Retry.RetryableGRPCCodes == nil {
+		return c
+	}
+	if cc, ok := c.(classify.CodesClassifier); ok {
+		c = cc.WithRetryableCodes(classify.RetryableCodes{
+			HTTPStatuses: pol.Retry.RetryableHTTPStatuses,
+			GRPCCodes:    pol.Retry.RetryableGRPCCodes,
+		})
+	}
+	return c
+}
+
+func resolveNamedClassifier(exec *Executor, pol policy.EffectivePolicy) (classify.Classifier, classifierMeta, error) {
+	if pol.Retry.ClassifierName == "" {
+		return exec.defaultClassifier, classifierMeta{}, nil
+	}
+
+	version := exec.classifiers.Version()
+	if v, ok := exec.classifierCache.Load(pol.Retry.ClassifierName); ok {
+		if rc := v.(*resolvedClassifier); rc.version == version && rc.mode == exec.missingClassifierMode {
+			return rc.classifier, rc.meta, rc.err
+		}
+	}
+
+	c, meta, err, cacheable := lookupClassifier(exec, pol)
+	if cacheable {
+		exec.classifierCache.Store(pol.Retry.ClassifierName, &resolvedClassifier{
+			version:    version,
+			mode:       exec.missingClassifierMode,
+			classifier: c,
+			meta:       meta,
+			err:        err,
+		})
+	}
+	return c, meta, err
+}
+
+// lookupClassifier resolves the policy's classifier from the registry. Results
+// are cacheable unless the registry lookup panicked.
+func lookupClassifier(exec *Executor, pol policy.EffectivePolicy) (classify.Classifier, classifierMeta, error, bool) {
 	meta := classifierMeta{requested: strings.TrimSpace(pol.Retry.ClassifierName)}
 
 	classifier := exec.defaultClassifier
 	if meta.requested == "" {
-		return classifier, meta, nil
+		return classifier, meta, nil, true
 	}
 
 	var c classify.Classifier
@@ -995,23 +2017,23 @@ func resolveClassifier(exec *Executor, pol policy.EffectivePolicy) (classify.Cla
 		meta.notFound = true
 		switch exec.missingClassifierMode {
 		case FailureDeny:
-			return nil, meta, panicErr
+			return nil, meta, panicErr, false
 		default:
 			// Fallback
-			return classifier, meta, nil
+			return classifier, meta, nil, false
 		}
 	}
 
 	if ok {
-		return c, meta, nil
+		return c, meta, nil, true
 	}
 
 	meta.notFound = true
 	switch exec.missingClassifierMode {
 	case FailureDeny:
-		return nil, meta, &NoClassifierError{Name: meta.requested}
+		return nil, meta, &NoClassifierError{Name: meta.requested}, true
 	default:
-		return classifier, meta, nil
+		return classifier, meta, nil, true
 	}
 }
 
@@ -1019,29 +2041,33 @@ func annotateClassifierFallback(out *classify.Outcome, meta classifierMeta) {
 	if out == nil || !meta.notFound || meta.requested == "" {
 		return
 	}
-	if out.Attributes == nil {
-		out.Attributes = make(map[string]string, 3)
+	// Copy rather than modify: classifiers may share attribute maps between
+	// outcomes (see classify.Cached).
+	attrs := make(map[string]string, len(out.Attributes)+3)
+	for k, v := range out.Attributes {
+		attrs[k] = v
 	}
+	out.Attributes = attrs
 	out.Attributes["classifier_not_found"] = "true"
 	out.Attributes["classifier_name"] = meta.requested
 	out.Attributes["classifier_fallback"] = "default"
 }
 
-func classifyWithRecovery(recoverPanics bool, classifier classify.Classifier, value any, err error, key policy.PolicyKey) (out classify.Outcome, panicErr error) {
+func classifyWithRecovery(recoverPanics bool, classifier classify.Classifier, value any, err error, ac classify.AttemptContext) (out classify.Outcome, panicErr error) {
 	if recoverPanics {
 		defer func() {
 			if r := recover(); r != nil {
 				out = classify.Outcome{Kind: classify.OutcomeAbort, Reason: "panic_in_classifier"}
 				panicErr = &PanicError{
 					Component: "classifier",
-					Key:       key,
+					Key:       ac.Key,
 					Value:     r,
 					Stack:     debug.Stack(),
 				}
 			}
 		}()
 	}
-	out = classifier.Classify(value, err)
+	out = classify.ClassifyAttempt(classifier, value, err, ac)
 	if out.Kind == classify.OutcomeUnknown {
 		if out.Reason == "" {
 			out.Reason = "unknown_outcome"
@@ -1080,11 +2106,26 @@ func terminalError(ctx context.Context, opErr error, out classify.Outcome) error
 	return errors.New("recourse: operation failed")
 }
 
-func computeSleep(backoff time.Duration, pol policy.RetryPolicy, out classify.Outcome) time.Duration {
+func computeSleep(backoff time.Duration, pol policy.RetryPolicy, out classify.Outcome, rng *jitterRand, custom policy.JitterFunc) time.Duration {
 	if out.BackoffOverride > 0 {
 		return capBackoff(out.BackoffOverride, pol.MaxBackoff)
 	}
-	return capBackoff(applyJitter(backoff, pol.Jitter), pol.MaxBackoff)
+	if pol.Jitter == policy.JitterDecorrelated {
+		return capBackoff(decorrelatedJitter(pol.InitialBackoff, backoff, rng), pol.MaxBackoff)
+	}
+	if custom != nil {
+		return capBackoff(custom(backoff, rng.Float64), pol.MaxBackoff)
+	}
+	return capBackoff(applyJitter(backoff, pol.Jitter, rng), pol.MaxBackoff)
+}
+
+// capAttempts lowers the call's attempt limit so that at most
+// out.MaxAdditionalAttempts attempts follow attempt.
+func capAttempts(limit, attempt int, out classify.Outcome) int {
+	if out.MaxAdditionalAttempts > 0 {
+		return min(limit, attempt+1+out.MaxAdditionalAttempts)
+	}
+	return limit
 }
 
 func capBackoff(d, max time.Duration) time.Duration {
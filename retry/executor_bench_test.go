@@ -111,3 +111,20 @@ func BenchmarkDoValue_FastPath_NoTimeline(b *testing.B) {
 		_, _ = DoValue(ctx, exec, key, op)
 	}
 }
+
+func BenchmarkDoValue_Hedged_Success(b *testing.B) {
+	key := policy.ParseKey("bench.hedged")
+	pol := benchmarkPolicy(1)
+	pol.Hedge = policy.HedgePolicy{Enabled: true, MaxHedges: 1, HedgeDelay: time.Hour}
+	exec := benchmarkExecutor(key, pol, &observe.NoopObserver{})
+	ctx := context.Background()
+	op := func(context.Context) (int, error) { return 1, nil }
+
+	b.ReportAllocs()
+	_, _ = DoValue(ctx, exec, key, op)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = DoValue(ctx, exec, key, op)
+	}
+}
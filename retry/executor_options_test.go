@@ -42,6 +42,31 @@ func TestNewExecutorFromOptions_Defaults(t *testing.T) {
 	}
 }
 
+func TestZeroValueExecutor_InitializedInPlace(t *testing.T) {
+	exec := &Executor{}
+	key := policy.ParseKey("svc.zero")
+
+	if err := exec.Do(context.Background(), key, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exec.provider == nil || exec.observer == nil || exec.classifiers == nil || exec.trackers == nil {
+		t.Fatal("expected defaults to be applied to the executor itself")
+	}
+	if exec.missingPolicyMode != FailureDeny || exec.missingClassifierMode != FailureFallback {
+		t.Fatalf("modes=%v/%v, want deny/fallback", exec.missingPolicyMode, exec.missingClassifierMode)
+	}
+
+	// Defaults are applied once; later calls reuse the same dependencies.
+	provider := exec.provider
+	tracker := exec.getTracker(key)
+	if err := exec.Do(context.Background(), key, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exec.provider != provider || exec.getTracker(key) != tracker {
+		t.Fatal("expected executor state to persist across calls")
+	}
+}
+
 func TestNewExecutor_OptionWiring(t *testing.T) {
 	obs := &optionObserver{}
 	classReg := classify.NewRegistry()
@@ -59,6 +84,7 @@ func TestNewExecutor_OptionWiring(t *testing.T) {
 		WithCircuitRegistry(circuitReg),
 		WithMissingClassifierMode(FailureAllow),
 		WithMissingBudgetMode(FailureAllowUnsafe),
+		WithMissingTriggerMode(FailureDeny),
 	)
 
 	if exec.observer != obs {
@@ -70,6 +96,9 @@ func TestNewExecutor_OptionWiring(t *testing.T) {
 	if exec.missingClassifierMode != FailureAllow || exec.missingBudgetMode != FailureAllowUnsafe {
 		t.Fatalf("unexpected missing modes: %v/%v", exec.missingClassifierMode, exec.missingBudgetMode)
 	}
+	if exec.missingTriggerMode != FailureDeny {
+		t.Fatalf("missingTriggerMode=%v, want FailureDeny", exec.missingTriggerMode)
+	}
 	if _, ok := exec.defaultClassifier.(testClassifier); !ok {
 		t.Fatalf("expected default classifier to be testClassifier, got %T", exec.defaultClassifier)
 	}
@@ -131,3 +160,42 @@ func TestExecutorErrorTypes(t *testing.T) {
 		t.Fatalf("unexpected circuit error: %q", ce.Error())
 	}
 }
+
+func TestWithBudget_CreatesRegistry(t *testing.T) {
+	exec := NewExecutor(WithBudget("slots", budget.NewConcurrencyBudget(1)))
+	if _, ok := exec.budgets.Get("slots"); !ok {
+		t.Fatalf("expected slots budget to be registered")
+	}
+}
+
+func TestWithClassifier_FrozenRegistryIsCloned(t *testing.T) {
+	reg := classify.NewRegistry()
+	reg.Register("base", testClassifier{})
+	reg.Freeze()
+
+	exec := NewExecutor(WithClassifiers(reg), WithClassifier("custom", testClassifier{}))
+	if exec.classifiers == reg {
+		t.Fatal("expected WithClassifier to clone the frozen registry")
+	}
+	for _, name := range []string{"base", "custom"} {
+		if _, ok := exec.classifiers.Get(name); !ok {
+			t.Fatalf("expected %q to be registered", name)
+		}
+	}
+	if _, ok := reg.Get("custom"); ok {
+		t.Fatal("frozen registry was changed")
+	}
+}
+
+func TestWithClassifier_DoesNotChangeInheritedRegistry(t *testing.T) {
+	reg := classify.NewRegistry()
+	base := NewExecutor(WithClassifiers(reg))
+
+	derived := base.With(WithClassifier("custom", testClassifier{}))
+	if _, ok := derived.classifiers.Get("custom"); !ok {
+		t.Fatal("expected custom classifier on the derived executor")
+	}
+	if _, ok := base.classifiers.Get("custom"); ok {
+		t.Fatal("WithClassifier on a derived executor changed the base registry")
+	}
+}
@@ -260,11 +260,9 @@ func TestExecutor_OverallTimeout_StopsLoop(t *testing.T) {
 		},
 	})
 
-	sleepCalled := make(chan struct{})
 	exec.sleep = func(ctx context.Context, _ time.Duration) error {
-		close(sleepCalled)
-		<-ctx.Done()
-		return ctx.Err()
+		t.Fatal("should not sleep past the overall timeout")
+		return nil
 	}
 
 	calls := 0
@@ -272,13 +270,12 @@ func TestExecutor_OverallTimeout_StopsLoop(t *testing.T) {
 		calls++
 		return errors.New("nope")
 	})
-	<-sleepCalled
 
 	if calls != 1 {
 		t.Fatalf("calls=%d, want 1", calls)
 	}
-	if !errors.Is(err, context.DeadlineExceeded) {
-		t.Fatalf("err=%v, want deadline exceeded", err)
+	if !errors.Is(err, ErrDeadlineInsufficient) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v, want deadline insufficient", err)
 	}
 }
 
@@ -385,6 +382,41 @@ func TestExecutor_MissingPolicyMode_Deny(t *testing.T) {
 	}
 }
 
+func TestExecutor_WithDefaultPolicy(t *testing.T) {
+	key := policy.ParseKey("svc.Unconfigured")
+	def := policy.New("defaults", policy.MaxAttempts(5))
+
+	countCalls := func(exec *Executor, timeline bool) int {
+		exec.sleep = func(context.Context, time.Duration) error { return nil }
+		calls := 0
+		_, _, _ = doValueInternal(context.Background(), exec, key, func(context.Context) (int, error) {
+			calls++
+			return 0, errors.New("nope")
+		}, timeline)
+		return calls
+	}
+
+	for _, timeline := range []bool{false, true} {
+		missing := NewExecutor(
+			WithProvider(stubProvider{err: controlplane.ErrPolicyNotFound}),
+			WithMissingPolicyMode(FailureFallback),
+			WithDefaultPolicy(def),
+		)
+		if calls := countCalls(missing, timeline); calls != 5 {
+			t.Fatalf("timeline=%v: provider miss calls=%d, want 5", timeline, calls)
+		}
+
+		static := NewExecutor(WithPolicy("svc.Other"), WithDefaultPolicy(def))
+		if calls := countCalls(static, timeline); calls != 5 {
+			t.Fatalf("timeline=%v: static miss calls=%d, want 5", timeline, calls)
+		}
+	}
+
+	if calls := countCalls(NewExecutor(WithMissingPolicyMode(FailureFallback)), false); calls != policy.DefaultPolicyFor(key).Retry.MaxAttempts {
+		t.Fatalf("calls=%d, want the built-in default", calls)
+	}
+}
+
 func TestExecutor_MissingPolicyMode_Fallback_PrefersReturnedPolicy(t *testing.T) {
 	key := policy.PolicyKey{Name: "x"}
 	exec := NewExecutorFromOptions(ExecutorOptions{
@@ -433,3 +465,39 @@ type stubProvider struct {
 func (p stubProvider) GetEffectivePolicy(context.Context, policy.PolicyKey) (policy.EffectivePolicy, error) {
 	return p.pol, p.err
 }
+
+func TestExecutor_RecordsWildcardPolicyMatch(t *testing.T) {
+	exec := NewExecutor(WithProvider(&controlplane.StaticProvider{Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+		policy.ParseKey("svc.*"): {Retry: policy.RetryPolicy{MaxAttempts: 1}},
+	}}))
+
+	_, tl, err := doWithTimeline(context.Background(), exec, policy.ParseKey("svc.method"), func(context.Context) (struct{}, error) {
+		return struct{}{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tl.Attributes["policy_match"] != "svc.*" {
+		t.Fatalf("attributes=%v, want policy_match=svc.*", tl.Attributes)
+	}
+}
+
+func TestExecutor_RecordsPolicySelector(t *testing.T) {
+	key := policy.ParseKey("svc.method")
+	exec := NewExecutor(WithProvider(&controlplane.StaticProvider{
+		Labels: map[policy.PolicyKey]policy.Labels{key: {"tier": "critical"}},
+		Selectors: []controlplane.SelectorPolicy{
+			{Selector: policy.Selector{"tier": "critical"}, Policy: policy.EffectivePolicy{Retry: policy.RetryPolicy{MaxAttempts: 1}}},
+		},
+	}))
+
+	_, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (struct{}, error) {
+		return struct{}{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tl.Attributes["policy_selector"] != "tier=critical" {
+		t.Fatalf("attributes=%v, want policy_selector=tier=critical", tl.Attributes)
+	}
+}
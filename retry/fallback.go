@@ -0,0 +1,203 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/aponysus/recourse/internal"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+// Timeline attribute reasons recorded when a configured fallback could not
+// serve the call.
+const (
+	fallbackNotFound     = "fallback_not_found"
+	fallbackCacheMiss    = "fallback_cache_miss"
+	fallbackFailed       = "fallback_failed"
+	fallbackTypeMismatch = "fallback_type_mismatch"
+	fallbackPanic        = "fallback_panic"
+	fallbackCycle        = "fallback_cycle"
+)
+
+type cachedResult struct {
+	val any
+	at  time.Time
+}
+
+// fallbackCall is the Timeline.Fallback source for per-call fallbacks.
+const fallbackCall = "call"
+
+// applyFallback serves the call's fallback (see WithFallbackValue), or else
+// pol's fallback, for a call that failed with tl.FinalErr. Key fallbacks run
+// op again under another policy key.
+// On success it moves the error to tl.FallbackErr and records the fallback
+// source; otherwise it records why the fallback did not apply and leaves tl
+// for the failure path.
+func applyFallback[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, pol policy.EffectivePolicy, op OperationValue[T], cfg *callConfig, tl *observe.Timeline) (T, bool) {
+	var zero T
+	var val T
+	var source, failure string
+
+	switch {
+	case cfg != nil && cfg.fallback != nil:
+		source = fallbackCall
+		val, failure = callFallback[T](ctx, exec, cfg.fallback, tl.FinalErr)
+	case pol.Fallback.Mode == policy.FallbackKey:
+		source = string(pol.Fallback.Mode)
+		val, failure = chainFallback(ctx, exec, key, pol.Fallback.Key, op, tl)
+	case pol.Fallback.Mode != "":
+		source = string(pol.Fallback.Mode)
+		var v any
+		if v, failure = exec.fallbackValue(ctx, key, pol.Fallback, tl.FinalErr); failure == "" {
+			var ok bool
+			if val, ok = asResult[T](v); !ok {
+				failure = fallbackTypeMismatch
+			}
+		}
+	default:
+		return zero, false
+	}
+
+	if failure != "" {
+		exec.setAttribute(&tl.Attributes, "fallback_error", failure)
+		return zero, false
+	}
+
+	tl.Fallback = source
+	tl.FallbackErr = tl.FinalErr
+	tl.FinalErr = nil
+	tl.End = exec.clock()
+	return val, true
+}
+
+// fallbackValue produces the raw fallback value, or a failure reason.
+func (e *Executor) fallbackValue(ctx context.Context, key policy.PolicyKey, fb policy.FallbackPolicy, err error) (v any, failure string) {
+	if fb.Mode == policy.FallbackCached {
+		v, ok := e.lookupResult(key, fb.MaxAge)
+		if !ok {
+			return nil, fallbackCacheMiss
+		}
+		return v, ""
+	}
+
+	if e.fallbacks == nil {
+		return nil, fallbackNotFound
+	}
+	h, ok := e.fallbacks.Get(fb.Name)
+	if !ok || internal.IsTypedNil(h) {
+		return nil, fallbackNotFound
+	}
+
+	if e.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				v, failure = nil, fallbackPanic
+			}
+		}()
+	}
+	v, herr := h.Fallback(ctx, key, err)
+	if herr != nil {
+		return nil, fallbackFailed
+	}
+	return v, ""
+}
+
+// fallbackChainKey is the context key for the policy keys a chain of key
+// fallbacks has run under, starting with the original call's key.
+type fallbackChainKey struct{}
+
+// chainFallback runs op under the policy for target with a fresh timeline,
+// stored in tl.Chained. The chained timeline records the failed key in its
+// "fallback_from" attribute, and tl records target in "fallback_key". A key
+// already in the chain is not run again.
+func chainFallback[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, target string, op OperationValue[T], tl *observe.Timeline) (val T, failure string) {
+	next := policy.ParseKey(target)
+	exec.setAttribute(&tl.Attributes, "fallback_key", next.String())
+
+	chain, _ := ctx.Value(fallbackChainKey{}).([]policy.PolicyKey)
+	if len(chain) == 0 {
+		chain = []policy.PolicyKey{key}
+	}
+	for _, k := range chain {
+		if k == next {
+			return val, fallbackCycle
+		}
+	}
+	chain = append(chain[:len(chain):len(chain)], next)
+	ctx = context.WithValue(ctx, fallbackChainKey{}, chain)
+
+	v, chained, err := doValueWithTimeline(ctx, exec, next, op, &callConfig{chainedFrom: key.String(), counters: exec.callCounters(next)})
+	tl.Chained = &chained
+	if err != nil {
+		return val, fallbackFailed
+	}
+	return v, ""
+}
+
+// callFallback runs a per-call fallback set by WithFallbackValue.
+func callFallback[T any](ctx context.Context, exec *Executor, fallback any, err error) (val T, failure string) {
+	fn, ok := fallback.(func(context.Context, error) (T, error))
+	if !ok {
+		return val, fallbackTypeMismatch
+	}
+
+	if exec.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				var zero T
+				val, failure = zero, fallbackPanic
+			}
+		}()
+	}
+	v, ferr := fn(ctx, err)
+	if ferr != nil {
+		return val, fallbackFailed
+	}
+	return v, ""
+}
+
+// rememberResult stores a successful result for policies that cache results
+// or use cached fallbacks.
+func (e *Executor) rememberResult(key policy.PolicyKey, val any) {
+	e.resultMu.Lock()
+	if e.resultCache == nil {
+		e.resultCache = make(map[policy.PolicyKey]cachedResult)
+	}
+	e.resultCache[key] = cachedResult{val: val, at: e.clock()}
+	e.resultMu.Unlock()
+}
+
+// lookupResult returns the last successful result for key if it is no older
+// than maxAge (0 means no limit).
+func (e *Executor) lookupResult(key policy.PolicyKey, maxAge time.Duration) (any, bool) {
+	e.resultMu.RLock()
+	c, ok := e.resultCache[key]
+	e.resultMu.RUnlock()
+	if !ok || (maxAge > 0 && e.clock().Sub(c.at) > maxAge) {
+		return nil, false
+	}
+	return c.val, true
+}
+
+// cachedValue returns the cached result for key when it is younger than ttl
+// and of the call's result type.
+func cachedValue[T any](exec *Executor, key policy.PolicyKey, ttl time.Duration) (T, bool) {
+	v, ok := exec.lookupResult(key, ttl)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return asResult[T](v)
+}
+
+// asResult converts a fallback value to the call's result type. A nil value
+// yields the zero value.
+func asResult[T any](v any) (T, bool) {
+	if v == nil {
+		var zero T
+		return zero, true
+	}
+	t, ok := v.(T)
+	return t, ok
+}
@@ -0,0 +1,190 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/fallback"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestExecutor_Fallback_StaticOnExhaustion(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	reg := fallback.NewRegistry()
+	reg.MustRegister("empty", fallback.Value("default"))
+	exec, _ := newRecordingExecutor(t, policy.New("fb", policy.MaxAttempts(2), policy.StaticFallback("empty")), WithFallbackRegistry(reg))
+
+	boom := errors.New("boom")
+	val, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (string, error) {
+		return "", boom
+	})
+	if err != nil || val != "default" {
+		t.Fatalf("val=%q err=%v, want default fallback", val, err)
+	}
+	if tl.Fallback != "static" || !errors.Is(tl.FallbackErr, boom) || tl.FinalErr != nil {
+		t.Fatalf("timeline fallback=%q fallbackErr=%v finalErr=%v", tl.Fallback, tl.FallbackErr, tl.FinalErr)
+	}
+	if len(tl.Attempts) != 2 {
+		t.Fatalf("attempts=%d, want 2", len(tl.Attempts))
+	}
+}
+
+func TestExecutor_Fallback_FastPathUsesFallback(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	reg := fallback.NewRegistry()
+	reg.MustRegister("zero", fallback.Value(7))
+	exec, _ := newRecordingExecutor(t, policy.New("fb", policy.MaxAttempts(1), policy.StaticFallback("zero")), WithFallbackRegistry(reg))
+
+	val, err := DoValue[int](context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, errors.New("boom")
+	})
+	if err != nil || val != 7 {
+		t.Fatalf("val=%d err=%v, want 7", val, err)
+	}
+}
+
+func TestExecutor_Fallback_HandlerOnCircuitOpen(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	pol := policy.New("fb", policy.MaxAttempts(1), policy.HandlerFallback("degraded"))
+	pol.Circuit = policy.CircuitPolicy{Enabled: true, Threshold: 1, Cooldown: time.Minute}
+
+	var gotErr error
+	reg := fallback.NewRegistry()
+	reg.MustRegister("degraded", fallback.HandlerFunc(func(_ context.Context, _ policy.PolicyKey, err error) (any, error) {
+		gotErr = err
+		return "degraded", nil
+	}))
+	exec, _ := newRecordingExecutor(t, pol, WithFallbackRegistry(reg))
+
+	// Trip the circuit; the failure itself is also served by the fallback.
+	_, _ = DoValue[string](context.Background(), exec, key, func(context.Context) (string, error) {
+		return "", errors.New("boom")
+	})
+
+	val, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (string, error) {
+		t.Fatal("operation should not run while the circuit is open")
+		return "", nil
+	})
+	if err != nil || val != "degraded" || tl.Fallback != "handler" {
+		t.Fatalf("val=%q err=%v fallback=%q, want degraded handler", val, err, tl.Fallback)
+	}
+	var open CircuitOpenError
+	if !errors.As(gotErr, &open) || !errors.As(tl.FallbackErr, &open) {
+		t.Fatalf("handler err=%v fallbackErr=%v, want CircuitOpenError", gotErr, tl.FallbackErr)
+	}
+}
+
+func TestExecutor_Fallback_CachedResult(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	exec, _ := newRecordingExecutor(t, policy.New("fb", policy.MaxAttempts(1), policy.CachedFallback(time.Minute)), WithClock(clock.Now))
+
+	fail := func(context.Context) (int, error) { return 0, errors.New("boom") }
+
+	// Nothing cached yet.
+	_, tl, err := doWithTimeline(context.Background(), exec, key, fail)
+	if err == nil || tl.Attributes["fallback_error"] != fallbackCacheMiss {
+		t.Fatalf("err=%v attrs=%v, want cache miss", err, tl.Attributes)
+	}
+
+	if v, err := DoValue[int](context.Background(), exec, key, func(context.Context) (int, error) { return 42, nil }); err != nil || v != 42 {
+		t.Fatalf("v=%d err=%v, want 42", v, err)
+	}
+
+	clock.Advance(30 * time.Second)
+	val, tl, err := doWithTimeline(context.Background(), exec, key, fail)
+	if err != nil || val != 42 || tl.Fallback != "cached" {
+		t.Fatalf("val=%d err=%v fallback=%q, want cached 42", val, err, tl.Fallback)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := DoValue[int](context.Background(), exec, key, fail); err == nil {
+		t.Fatalf("expected stale cached result to be ignored")
+	}
+}
+
+func TestExecutor_Fallback_TypeMismatchReturnsError(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	reg := fallback.NewRegistry()
+	reg.MustRegister("wrong", fallback.Value("not an int"))
+	exec, _ := newRecordingExecutor(t, policy.New("fb", policy.MaxAttempts(1), policy.StaticFallback("wrong")), WithFallbackRegistry(reg))
+
+	boom := errors.New("boom")
+	_, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("err=%v, want boom", err)
+	}
+	if tl.Fallback != "" || tl.Attributes["fallback_error"] != fallbackTypeMismatch {
+		t.Fatalf("fallback=%q attrs=%v, want type mismatch", tl.Fallback, tl.Attributes)
+	}
+}
+
+func TestExecutor_Fallback_MissingHandler(t *testing.T) {
+	key := policy.PolicyKey{Name: "fb"}
+	exec, _ := newRecordingExecutor(t, policy.New("fb", policy.MaxAttempts(1), policy.HandlerFallback("missing")))
+
+	ctx, capture := observe.RecordTimeline(context.Background())
+	if err := exec.Do(ctx, key, func(context.Context) error { return errors.New("boom") }); err == nil {
+		t.Fatalf("expected error")
+	}
+	if tl := capture.Timeline(); tl == nil || tl.Attributes["fallback_error"] != fallbackNotFound {
+		t.Fatalf("timeline=%+v, want fallback_not_found", tl)
+	}
+}
+
+func TestExecutor_Fallback_KeyChain(t *testing.T) {
+	primary := policy.New("db.Read", policy.PolicyID("primary"), policy.MaxAttempts(2), policy.KeyFallback("cache.Read"))
+	secondary := policy.New("cache.Read", policy.PolicyID("cache"), policy.MaxAttempts(1))
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Provider: &controlplane.StaticProvider{Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+			primary.Key:   primary,
+			secondary.Key: secondary,
+		}},
+	})
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+	boom := errors.New("db down")
+	val, tl, err := doWithTimeline(context.Background(), exec, primary.Key, func(ctx context.Context) (string, error) {
+		if info, _ := observe.AttemptFromContext(ctx); info.PolicyID == "cache" {
+			return "cached", nil
+		}
+		return "", boom
+	})
+	if err != nil || val != "cached" {
+		t.Fatalf("val=%q err=%v, want cached result from the fallback key", val, err)
+	}
+	if tl.Fallback != "key" || !errors.Is(tl.FallbackErr, boom) || len(tl.Attempts) != 2 || tl.Attributes["fallback_key"] != "cache.Read" {
+		t.Fatalf("timeline fallback=%q err=%v attempts=%d attrs=%v", tl.Fallback, tl.FallbackErr, len(tl.Attempts), tl.Attributes)
+	}
+	chained := tl.Chained
+	if chained == nil || chained.Key != secondary.Key || len(chained.Attempts) != 1 || chained.Attributes["fallback_from"] != "db.Read" {
+		t.Fatalf("chained timeline = %+v, want one cache.Read attempt from db.Read", chained)
+	}
+}
+
+func TestExecutor_Fallback_KeyCycle(t *testing.T) {
+	a := policy.New("svc.A", policy.MaxAttempts(1), policy.KeyFallback("svc.B"))
+	b := policy.New("svc.B", policy.MaxAttempts(1), policy.KeyFallback("svc.A"))
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Provider: &controlplane.StaticProvider{Policies: map[policy.PolicyKey]policy.EffectivePolicy{a.Key: a, b.Key: b}},
+	})
+
+	calls := 0
+	boom := errors.New("boom")
+	_, tl, err := doWithTimeline(context.Background(), exec, a.Key, func(context.Context) (int, error) {
+		calls++
+		return 0, boom
+	})
+	if !errors.Is(err, boom) || calls != 2 {
+		t.Fatalf("err=%v calls=%d, want boom after A and B ran once", err, calls)
+	}
+	if tl.Attributes["fallback_error"] != "fallback_failed" || tl.Chained == nil || tl.Chained.Attributes["fallback_error"] != "fallback_cycle" {
+		t.Fatalf("attrs=%v chained=%+v, want the cycle stopped at B", tl.Attributes, tl.Chained)
+	}
+}
@@ -0,0 +1,60 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+// FaultInjectedError is the attempt error for failures injected by
+// EffectivePolicy.FaultInjection.
+type FaultInjectedError struct {
+	Reason string
+}
+
+func (e FaultInjectedError) Error() string {
+	return "recourse: fault injected (" + e.Reason + ")"
+}
+
+// faultsEnabled reports whether pol injects faults on this executor.
+func (e *Executor) faultsEnabled(pol policy.EffectivePolicy) bool {
+	return e.faultInjection && pol.FaultInjection.Enabled
+}
+
+// injectFault applies the policy's fault injection before an attempt: it waits
+// out the added latency, then fails the attempt with probability ErrorRate.
+// injected reports an injected failure; a non-nil err with injected false means
+// ctx ended during the added latency.
+func (e *Executor) injectFault(ctx context.Context, pol policy.EffectivePolicy) (injected bool, err error) {
+	if !e.faultsEnabled(pol) {
+		return false, nil
+	}
+	fi := pol.FaultInjection
+	if fi.Latency > 0 {
+		if err := e.sleep(ctx, fi.Latency); err != nil {
+			return false, err
+		}
+	}
+	if fi.ErrorRate > 0 && e.jitter.Float64() < fi.ErrorRate {
+		return true, FaultInjectedError{Reason: faultReason(fi)}
+	}
+	return false, nil
+}
+
+// injectedOutcome classifies an injected failure as retryable so experiments
+// exercise the retry, hedge, and budget paths.
+func injectedOutcome(fi policy.FaultInjectionPolicy) classify.Outcome {
+	return classify.Outcome{
+		Kind:       classify.OutcomeRetryable,
+		Reason:     faultReason(fi),
+		Attributes: map[string]string{"fault_injected": "true"},
+	}
+}
+
+func faultReason(fi policy.FaultInjectionPolicy) string {
+	if fi.Reason != "" {
+		return fi.Reason
+	}
+	return "fault_injected"
+}
@@ -0,0 +1,117 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+func faultPolicy(fi policy.FaultInjectionPolicy) policy.EffectivePolicy {
+	return policy.EffectivePolicy{
+		Key:            policy.PolicyKey{Name: "faulty"},
+		Retry:          policy.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+		FaultInjection: fi,
+	}
+}
+
+func TestExecutor_FaultInjection_FailsAttemptsWithoutCallingOp(t *testing.T) {
+	pol := faultPolicy(policy.FaultInjectionPolicy{
+		Enabled:   true,
+		ErrorRate: 1,
+		Reason:    "game_day",
+	})
+	exec, _ := newRecordingExecutor(t, pol, WithFaultInjection(true))
+	key := pol.Key
+
+	calls := 0
+	ctx, capture := observe.RecordTimeline(context.Background())
+	err := exec.Do(ctx, key, func(context.Context) error {
+		calls++
+		return nil
+	})
+
+	var fe FaultInjectedError
+	if !errors.As(err, &fe) || fe.Reason != "game_day" {
+		t.Fatalf("err=%v, want FaultInjectedError(game_day)", err)
+	}
+	if calls != 0 {
+		t.Fatalf("calls=%d, want 0", calls)
+	}
+	tl := capture.Timeline()
+	if tl == nil || len(tl.Attempts) != 2 {
+		t.Fatalf("timeline=%+v, want 2 attempts", tl)
+	}
+	for _, rec := range tl.Attempts {
+		if rec.Outcome.Reason != "game_day" || rec.Outcome.Attributes["fault_injected"] != "true" {
+			t.Fatalf("outcome=%+v, want injected game_day", rec.Outcome)
+		}
+	}
+}
+
+func TestExecutor_FaultInjection_FastPathRoutesThroughTimeline(t *testing.T) {
+	pol := faultPolicy(policy.FaultInjectionPolicy{Enabled: true, ErrorRate: 1})
+	exec, _ := newRecordingExecutor(t, pol, WithFaultInjection(true))
+	key := pol.Key
+
+	calls := 0
+	err := exec.Do(context.Background(), key, func(context.Context) error {
+		calls++
+		return nil
+	})
+
+	var fe FaultInjectedError
+	if !errors.As(err, &fe) || fe.Reason != "fault_injected" {
+		t.Fatalf("err=%v, want FaultInjectedError(fault_injected)", err)
+	}
+	if calls != 0 {
+		t.Fatalf("calls=%d, want 0", calls)
+	}
+}
+
+func TestExecutor_FaultInjection_AddsLatency(t *testing.T) {
+	pol := faultPolicy(policy.FaultInjectionPolicy{
+		Enabled: true,
+		Latency: 50 * time.Millisecond,
+	})
+	exec, sleeps := newRecordingExecutor(t, pol, WithFaultInjection(true))
+	key := pol.Key
+
+	calls := 0
+	if err := exec.Do(context.Background(), key, func(context.Context) error {
+		calls++
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls=%d, want 1", calls)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != 50*time.Millisecond {
+		t.Fatalf("sleeps=%v, want [50ms]", *sleeps)
+	}
+}
+
+func TestExecutor_FaultInjection_RequiresExecutorOptIn(t *testing.T) {
+	pol := faultPolicy(policy.FaultInjectionPolicy{
+		Enabled:   true,
+		ErrorRate: 1,
+		Latency:   time.Second,
+	})
+	exec, sleeps := newRecordingExecutor(t, pol, WithFaultInjection(false))
+	key := pol.Key
+
+	calls := 0
+	if err := exec.Do(context.Background(), key, func(context.Context) error {
+		calls++
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 || len(*sleeps) != 0 {
+		t.Fatalf("calls=%d sleeps=%v, want untouched call", calls, *sleeps)
+	}
+}
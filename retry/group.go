@@ -24,20 +24,58 @@ type groupResult[T any] struct {
 	panicErr error
 }
 
+// priorAttempt carries what earlier retry attempts of a call left behind for
+// the next one.
+type priorAttempt struct {
+	err          error
+	outcome      *classify.Outcome
+	backoff      time.Duration // Backoff slept right before this attempt.
+	totalBackoff time.Duration // Backoff slept by the call so far.
+	callStart    time.Time     // When the call started.
+	counters     callCounters  // The call's runtime counters.
+}
+
+// percentileMinSamples is the number of latency samples a key needs before
+// HedgeDelayPercentile replaces its HedgeDelay.
+const percentileMinSamples = 20
+
+// resolveTrigger returns the trigger named by the hedge policy. Without a
+// name it is a LatencyTrigger for HedgeDelayPercentile when set, and a
+// FixedDelayTrigger on HedgeDelay otherwise; a missing trigger falls back to
+// the fixed delay unless MissingTriggerMode is FailureDeny.
+func (e *Executor) resolveTrigger(hp policy.HedgePolicy) (hedge.Trigger, error) {
+	fixed := hedge.FixedDelayTrigger{Delay: hp.HedgeDelay}
+	if hp.TriggerName == "" {
+		if hp.HedgeDelayPercentile != "" {
+			return hedge.LatencyTrigger{Percentile: hp.HedgeDelayPercentile, MinSamples: percentileMinSamples}, nil
+		}
+		return fixed, nil
+	}
+	if e.triggers != nil {
+		if trig, ok := e.triggers.Get(hp.TriggerName); ok {
+			return trig, nil
+		}
+	}
+	if e.missingTriggerMode == FailureDeny {
+		return nil, &NoTriggerError{Name: hp.TriggerName}
+	}
+	return fixed, nil
+}
+
 // doRetryGroup executes a primary attempt and optional hedged attempts.
 // It returns the result of the "winning" attempt.
-func (e *Executor) doRetryGroup(
+func doRetryGroup[T any](
+	e *Executor,
 	ctx context.Context,
 	key policy.PolicyKey,
-// Generic helper for concurrent operations.
-	op OperationValue[any],
+	op OperationValue[T],
 	pol policy.EffectivePolicy,
 	retryIdx int,
 	classifier classify.Classifier,
 	cmeta classifierMeta,
-	lastBackoff time.Duration,
+	prior priorAttempt,
 	recordAttempt func(context.Context, observe.AttemptRecord),
-) (any, error, classify.Outcome, bool) {
+) (T, error, classify.Outcome, bool) {
 
 	// Check if hedging is enabled.
 
@@ -46,7 +84,7 @@ func (e *Executor) doRetryGroup(
 		maxHedges = pol.Hedge.MaxHedges
 	}
 
-	results := make(chan groupResult[any], 1+maxHedges)
+	results := make(chan groupResult[T], 1+maxHedges)
 
 	// Group-level context for cancellation.
 	groupCtx, cancelGroup := context.WithCancel(ctx)
@@ -61,126 +99,14 @@ func (e *Executor) doRetryGroup(
 		activeAttempts.Add(1)
 		attemptsLaunched.Add(1)
 
+		e.enter(true)
 		go func() {
+			defer e.leave()
 			defer activeAttempts.Add(-1)
-
-			start := e.clock()
-
-			// Budget Check
-			budgetKind := budget.KindRetry
-			budgetRef := pol.Retry.Budget
 			if isHedge {
-				budgetKind = budget.KindHedge
-				budgetRef = pol.Hedge.Budget
-			}
-
-			// Check budget for this attempt.
-
-			// AllowAttempt
-			decision, allowed := e.allowAttempt(groupCtx, key, budgetRef, retryIdx, budgetKind) // retryIdx is constant for group
-			if !allowed {
-				// Record budget denial
-				rec := observe.AttemptRecord{
-					Attempt:       retryIdx,
-					StartTime:     start,
-					EndTime:       e.clock(),
-					IsHedge:       isHedge,
-					HedgeIndex:    idx, // 0 for primary, 1..N for hedges
-					Outcome:       classify.Outcome{Kind: classify.OutcomeAbort, Reason: decision.Reason},
-					BudgetAllowed: false,
-					BudgetReason:  decision.Reason,
-					Backoff:       lastBackoff, // For primary only?
-				}
-				if isHedge {
-					rec.Backoff = 0 // Hedges don't strictly have "backoff" from previous retry
-				}
-
-				recordAttempt(groupCtx, rec)
-				results <- groupResult[any]{
-					err:     errors.New(decision.Reason),
-					outcome: classify.Outcome{Kind: classify.OutcomeAbort, Reason: decision.Reason},
-					start:   start,
-					end:     e.clock(),
-					isHedge: isHedge,
-					idx:     idx,
-				}
-				return
-			}
-
-			release := decision.Release
-			defer func() {
-				if release != nil {
-					release()
-				}
-			}()
-
-			// Attempt Context
-			attemptCtx := groupCtx
-			var cancelAttempt context.CancelFunc
-			if pol.Retry.TimeoutPerAttempt > 0 {
-				attemptCtx, cancelAttempt = context.WithTimeout(groupCtx, pol.Retry.TimeoutPerAttempt)
-			} else {
-				cancelAttempt = func() {}
+				defer e.releaseHedgeSlot()
 			}
-			defer cancelAttempt()
-
-			attemptCtx = observe.WithAttemptInfo(attemptCtx, observe.AttemptInfo{
-				RetryIndex: retryIdx,
-				Attempt:    retryIdx,
-				IsHedge:    isHedge,
-				HedgeIndex: idx,
-				PolicyID:   pol.ID,
-			})
-
-			if isHedge {
-				e.observer.OnHedgeSpawn(attemptCtx, key, observe.AttemptRecord{
-					Attempt:    retryIdx,
-					IsHedge:    true,
-					HedgeIndex: idx,
-				})
-			}
-
-			// Execute
-			var val any
-			var err error
-			val, err = op(attemptCtx)
-
-			end := e.clock()
-
-			// Classify
-			outcome, panicErr := classifyWithRecovery(e.recoverPanics, classifier, val, err, key)
-			annotateClassifierFallback(&outcome, cmeta)
-
-			// Record
-			rec := observe.AttemptRecord{
-				Attempt:       retryIdx,
-				StartTime:     start,
-				EndTime:       end,
-				Outcome:       outcome,
-				Err:           err,
-				Backoff:       lastBackoff, // Only meaningful for primary
-				BudgetAllowed: true,
-				BudgetReason:  decision.Reason,
-				IsHedge:       isHedge,
-				HedgeIndex:    idx,
-			}
-			if isHedge {
-				rec.Backoff = 0
-			}
-			recordAttempt(attemptCtx, rec)
-
-			res := groupResult[any]{
-				val:      val,
-				err:      err,
-				outcome:  outcome,
-				start:    start,
-				end:      end,
-				isHedge:  isHedge,
-				idx:      idx,
-				panicErr: panicErr,
-			}
-
-			results <- res
+			results <- runAttempt(e, groupCtx, key, op, pol, retryIdx, idx, isHedge, classifier, cmeta, prior, recordAttempt)
 		}()
 	}
 
@@ -189,24 +115,16 @@ func (e *Executor) doRetryGroup(
 
 	// 2. Hedge Loop
 	start := e.clock()
+	e.enter(true)
 	go func() {
+		defer e.leave()
 		// Assuming single threaded coordination for spawning
 		if !pol.Hedge.Enabled {
 			return
 		}
 
-		// Find trigger
-		var trig hedge.Trigger
-		if pol.Hedge.TriggerName != "" && e.triggers != nil {
-			var ok bool
-			trig, ok = e.triggers.Get(pol.Hedge.TriggerName)
-			_ = ok
-		}
-
-		// Fallback to fixed delay if no trigger found or Logic
-		if trig == nil {
-			trig = hedge.FixedDelayTrigger{Delay: pol.Hedge.HedgeDelay}
-		}
+		// Calls whose trigger is missing under FailureDeny never get here.
+		trig, _ := e.resolveTrigger(pol.Hedge)
 
 		// Loop
 		hedgesLaunched := 0
@@ -220,7 +138,7 @@ func (e *Executor) doRetryGroup(
 			case <-groupCtx.Done():
 				return
 			case <-timer.C:
-				if hedgesLaunched >= maxHedges {
+				if hedgesLaunched >= maxHedges || e.cooldownRemaining(key) > 0 {
 					return
 				}
 
@@ -235,6 +153,11 @@ func (e *Executor) doRetryGroup(
 
 				should, nextCheck := trig.ShouldSpawnHedge(state)
 				if should {
+					inFlight, ok := e.acquireHedgeSlot()
+					if !ok {
+						e.reportHedgeSuppressed(groupCtx, key, retryIdx, hedgesLaunched+1, inFlight)
+						return
+					}
 					hedgesLaunched++
 					launch(hedgesLaunched, true)
 
@@ -262,7 +185,7 @@ func (e *Executor) doRetryGroup(
 	// 3. All attempts fail.
 	// 4. Fail-fast threshold is reached.
 
-	var lastRel groupResult[any]
+	var lastRel groupResult[T]
 	failures := 0
 
 	for {
@@ -299,7 +222,211 @@ func (e *Executor) doRetryGroup(
 			// If active > 0, we have hope. Continue waiting.
 
 		case <-ctx.Done(): // Outer context cancelled
-			return nil, ctx.Err(), classify.Outcome{Kind: classify.OutcomeAbort, Reason: "context_canceled"}, false
+			var zero T
+			return zero, ctx.Err(), classify.Outcome{Kind: classify.OutcomeAbort, Reason: "context_canceled"}, false
+		}
+	}
+}
+
+// doSingleAttempt executes one attempt inline on the calling goroutine.
+// It is used instead of doRetryGroup when hedging is disabled, avoiding the
+// goroutine, channel, and counters the group coordination needs.
+func doSingleAttempt[T any](
+	e *Executor,
+	ctx context.Context,
+	key policy.PolicyKey,
+	op OperationValue[T],
+	pol policy.EffectivePolicy,
+	retryIdx int,
+	classifier classify.Classifier,
+	cmeta classifierMeta,
+	prior priorAttempt,
+	recordAttempt func(context.Context, observe.AttemptRecord),
+) (T, error, classify.Outcome, bool) {
+	res := runAttempt(e, ctx, key, op, pol, retryIdx, 0, false, classifier, cmeta, prior, recordAttempt)
+	if res.outcome.Kind == classify.OutcomeSuccess {
+		return res.val, nil, res.outcome, true
+	}
+	return res.val, res.err, res.outcome, false
+}
+
+// runAttempt performs the budget check, executes op under the attempt context,
+// classifies the result, and records the attempt.
+func runAttempt[T any](
+	e *Executor,
+	groupCtx context.Context,
+	key policy.PolicyKey,
+	op OperationValue[T],
+	pol policy.EffectivePolicy,
+	retryIdx int,
+	idx int,
+	isHedge bool,
+	classifier classify.Classifier,
+	cmeta classifierMeta,
+	prior priorAttempt,
+	recordAttempt func(context.Context, observe.AttemptRecord),
+) groupResult[T] {
+	start := e.clock()
+
+	// Budget Check
+	budgetKind := budget.KindRetry
+	budgetRef, extraBudgets := pol.Retry.Budget, pol.Retry.Budgets
+	if isHedge {
+		budgetKind = budget.KindHedge
+		budgetRef, extraBudgets = pol.Hedge.Budget, pol.Hedge.Budgets
+	}
+
+	if retryIdx == 0 && !isHedge {
+		e.trackRequest(groupCtx, key, pol)
+	}
+
+	// Check budget for this attempt.
+
+	// AllowAttempt
+	decision, allowed := e.allowAttempts(groupCtx, key, budgetRef, extraBudgets, retryIdx, budgetKind) // retryIdx is constant for group
+	if !allowed {
+		prior.counters.add(countDenial)
+		// Record budget denial
+		rec := observe.AttemptRecord{
+			Attempt:       retryIdx,
+			StartTime:     start,
+			EndTime:       e.clock(),
+			IsHedge:       isHedge,
+			HedgeIndex:    idx, // 0 for primary, 1..N for hedges
+			Outcome:       classify.Outcome{Kind: classify.OutcomeAbort, Reason: decision.Reason},
+			BudgetAllowed: false,
+			BudgetReason:  decision.Reason,
+			BudgetName:    decision.Budget,
+			Backoff:       prior.backoff, // For primary only?
+		}
+		if isHedge {
+			rec.Backoff = 0 // Hedges don't strictly have "backoff" from previous retry
 		}
+
+		recordAttempt(groupCtx, rec)
+		return groupResult[T]{
+			err:     errors.New(decision.Reason),
+			outcome: classify.Outcome{Kind: classify.OutcomeAbort, Reason: decision.Reason},
+			start:   start,
+			end:     e.clock(),
+			isHedge: isHedge,
+			idx:     idx,
+		}
+	}
+
+	defer decision.Done()
+
+	switch {
+	case isHedge:
+		prior.counters.add(func(c *statCounters) {
+			c.attempts.Add(1)
+			c.hedges.Add(1)
+			c.inFlightHedges.Add(1)
+		})
+		defer prior.counters.add(func(c *statCounters) { c.inFlightHedges.Add(-1) })
+	case retryIdx > 0:
+		prior.counters.add(countRetry)
+	default:
+		prior.counters.add(countAttempt)
+	}
+
+	// Attempt Context
+	attemptCtx := groupCtx
+	var cancelAttempt context.CancelFunc
+	if pol.Retry.TimeoutPerAttempt > 0 {
+		attemptCtx, cancelAttempt = context.WithTimeout(groupCtx, pol.Retry.TimeoutPerAttempt)
+	} else {
+		cancelAttempt = func() {}
+	}
+	defer cancelAttempt()
+
+	info := observe.AttemptInfo{
+		RetryIndex:  retryIdx,
+		Attempt:     retryIdx,
+		IsHedge:     isHedge,
+		HedgeIndex:  idx,
+		PolicyID:    pol.ID,
+		PrevErr:     prior.err,
+		PrevOutcome: prior.outcome,
+		Backoff:     prior.totalBackoff,
+	}
+	attemptCtx = observe.WithAttemptInfo(attemptCtx, info)
+
+	if isHedge {
+		e.observer.OnHedgeSpawn(attemptCtx, key, observe.AttemptRecord{
+			Attempt:    retryIdx,
+			IsHedge:    true,
+			HedgeIndex: idx,
+		})
+	}
+
+	// Execute, unless fault injection fails the attempt first
+	var val T
+	var outcome classify.Outcome
+	var panicErr error
+	var attempt *Attempt
+	injected, err := e.injectFault(attemptCtx, pol)
+	if err == nil {
+		if len(e.attemptMiddleware) > 0 {
+			attempt = &Attempt{Key: key, Info: info}
+			val, err = callOperation(e, attemptCtx, key, func(ctx context.Context) (T, error) {
+				return runWithMiddleware(ctx, e.attemptMiddleware, attempt, op)
+			})
+		} else {
+			val, err = callOperation(e, attemptCtx, key, op)
+		}
+	}
+
+	end := e.clock()
+
+	// Classify
+	if injected {
+		outcome = injectedOutcome(pol.FaultInjection)
+	} else {
+		ac := classify.AttemptContext{Key: key, Attempt: retryIdx, IsHedge: isHedge, Elapsed: end.Sub(prior.callStart)}
+		outcome, panicErr = classifyWithRecovery(e.recoverPanics, classifier, val, err, ac)
+		annotateClassifierFallback(&outcome, cmeta)
+		if panicErr == nil {
+			e.classifyOperationPanic(&outcome, err)
+			e.overrideOutcome(pol, &outcome, err)
+			markAbortedByCaller(groupCtx, &outcome)
+		}
+	}
+	e.applyPushback(key, &outcome, err)
+	e.adaptBackoff(key, pol.Retry, outcome)
+
+	// Record
+	rec := observe.AttemptRecord{
+		Attempt:       retryIdx,
+		StartTime:     start,
+		EndTime:       end,
+		Outcome:       outcome,
+		Err:           err,
+		Backoff:       prior.backoff, // Only meaningful for primary
+		BudgetAllowed: true,
+		BudgetReason:  decision.Reason,
+		IsHedge:       isHedge,
+		HedgeIndex:    idx,
+	}
+	if isHedge {
+		rec.Backoff = 0
+	}
+	if err != nil {
+		rec.ErrFingerprint = observe.Fingerprint(err, outcome.Reason)
+	}
+	if attempt != nil {
+		rec.Attributes = attempt.attrs
+	}
+	recordAttempt(attemptCtx, rec)
+
+	return groupResult[T]{
+		val:      val,
+		err:      err,
+		outcome:  outcome,
+		start:    start,
+		end:      end,
+		isHedge:  isHedge,
+		idx:      idx,
+		panicErr: panicErr,
 	}
 }
@@ -53,7 +53,8 @@ func TestDoRetryGroup_CancelOnFirstTerminal(t *testing.T) {
 	recordAttempt := func(context.Context, observe.AttemptRecord) {}
 	op := func(context.Context) (any, error) { return nil, errors.New("nope") }
 
-	_, err, out, success := exec.doRetryGroup(
+	_, err, out, success := doRetryGroup(
+		exec,
 		context.Background(),
 		key,
 		op,
@@ -61,7 +62,7 @@ func TestDoRetryGroup_CancelOnFirstTerminal(t *testing.T) {
 		0,
 		nonRetryableClassifier{},
 		classifierMeta{},
-		0,
+		priorAttempt{},
 		recordAttempt,
 	)
 
@@ -103,7 +104,8 @@ func TestDoRetryGroup_TriggerNextCheckDefault(t *testing.T) {
 		}
 	}
 
-	val, err, out, success := exec.doRetryGroup(
+	val, err, out, success := doRetryGroup(
+		exec,
 		context.Background(),
 		key,
 		op,
@@ -111,7 +113,7 @@ func TestDoRetryGroup_TriggerNextCheckDefault(t *testing.T) {
 		0,
 		successClassifier{},
 		classifierMeta{},
-		0,
+		priorAttempt{},
 		recordAttempt,
 	)
 
@@ -151,7 +153,8 @@ func TestDoRetryGroup_ContextCanceled(t *testing.T) {
 		cancel()
 	}()
 
-	_, err, out, success := exec.doRetryGroup(
+	_, err, out, success := doRetryGroup(
+		exec,
 		ctx,
 		key,
 		op,
@@ -159,7 +162,7 @@ func TestDoRetryGroup_ContextCanceled(t *testing.T) {
 		0,
 		successClassifier{},
 		classifierMeta{},
-		0,
+		priorAttempt{},
 		func(context.Context, observe.AttemptRecord) {},
 	)
 	close(unblock)
@@ -174,3 +177,170 @@ func TestDoRetryGroup_ContextCanceled(t *testing.T) {
 		t.Fatalf("out=%+v, want abort/context_canceled", out)
 	}
 }
+
+func TestDoSingleAttempt_RecordsAttemptInfo(t *testing.T) {
+	exec := NewExecutorFromOptions(ExecutorOptions{})
+	key := policy.PolicyKey{Name: "op"}
+	pol := policy.EffectivePolicy{
+		ID:    "pol-1",
+		Retry: policy.RetryPolicy{MaxAttempts: 3},
+	}
+
+	var info observe.AttemptInfo
+	op := func(ctx context.Context) (any, error) {
+		info, _ = observe.AttemptFromContext(ctx)
+		return "ok", nil
+	}
+
+	var recs []observe.AttemptRecord
+	val, err, out, success := doSingleAttempt(
+		exec,
+		context.Background(),
+		key,
+		op,
+		pol,
+		2,
+		successClassifier{},
+		classifierMeta{},
+		priorAttempt{backoff: 5 * time.Millisecond},
+		func(_ context.Context, rec observe.AttemptRecord) { recs = append(recs, rec) },
+	)
+
+	if !success || err != nil || val != "ok" || out.Kind != classify.OutcomeSuccess {
+		t.Fatalf("val=%v err=%v out=%+v success=%v, want ok success", val, err, out, success)
+	}
+	if info.Attempt != 2 || info.IsHedge || info.PolicyID != "pol-1" {
+		t.Fatalf("info=%+v, want attempt 2 primary pol-1", info)
+	}
+	if len(recs) != 1 || recs[0].Attempt != 2 || recs[0].Backoff != 5*time.Millisecond || !recs[0].BudgetAllowed {
+		t.Fatalf("recs=%+v, want one allowed record for attempt 2", recs)
+	}
+}
+
+func TestDoSingleAttempt_Failure(t *testing.T) {
+	exec := NewExecutorFromOptions(ExecutorOptions{})
+	key := policy.PolicyKey{Name: "op"}
+	pol := policy.EffectivePolicy{Retry: policy.RetryPolicy{MaxAttempts: 1}}
+	boom := errors.New("boom")
+
+	_, err, out, success := doSingleAttempt(
+		exec,
+		context.Background(),
+		key,
+		func(context.Context) (any, error) { return nil, boom },
+		pol,
+		0,
+		nonRetryableClassifier{},
+		classifierMeta{},
+		priorAttempt{},
+		func(context.Context, observe.AttemptRecord) {},
+	)
+
+	if success || !errors.Is(err, boom) || out.Kind != classify.OutcomeNonRetryable {
+		t.Fatalf("err=%v out=%+v success=%v, want non-retryable boom", err, out, success)
+	}
+}
+
+func TestDoRetryGroup_TypedResult(t *testing.T) {
+	type payload struct{ n int }
+
+	exec := NewExecutor()
+	key := policy.PolicyKey{Name: "op"}
+	pol := policy.EffectivePolicy{
+		Retry: policy.RetryPolicy{MaxAttempts: 1},
+		Hedge: policy.HedgePolicy{Enabled: true, MaxHedges: 1, HedgeDelay: time.Hour},
+	}
+
+	val, err, out, success := doRetryGroup(
+		exec,
+		context.Background(),
+		key,
+		func(context.Context) (payload, error) { return payload{n: 7}, nil },
+		pol,
+		0,
+		successClassifier{},
+		classifierMeta{},
+		priorAttempt{},
+		func(context.Context, observe.AttemptRecord) {},
+	)
+
+	if !success || err != nil {
+		t.Fatalf("success=%v err=%v, want success", success, err)
+	}
+	if val.n != 7 {
+		t.Fatalf("val=%+v, want n=7", val)
+	}
+	if out.Kind != classify.OutcomeSuccess {
+		t.Fatalf("out=%+v, want success", out)
+	}
+}
+
+func TestMissingTriggerMode(t *testing.T) {
+	key := policy.ParseKey("svc.trigger")
+
+	for _, tc := range []struct {
+		mode    FailureMode
+		wantErr bool
+	}{
+		{FailureModeUnknown, false},
+		{FailureFallback, false},
+		{FailureDeny, true},
+	} {
+		t.Run(failureModeString(tc.mode), func(t *testing.T) {
+			exec := NewExecutor(
+				WithPolicy(key.String(), policy.MaxAttempts(1), policy.HedgeTrigger("p42"), policy.HedgeDelay(time.Hour)),
+				WithMissingTriggerMode(tc.mode),
+			)
+			calls := 0
+			_, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (struct{}, error) {
+				calls++
+				return struct{}{}, nil
+			})
+
+			if !tc.wantErr {
+				if err != nil || calls != 1 {
+					t.Fatalf("err=%v calls=%d, want fixed delay hedging fallback", err, calls)
+				}
+				return
+			}
+			var nte *NoTriggerError
+			if !errors.As(err, &nte) || nte.Name != "p42" {
+				t.Fatalf("err=%v, want NoTriggerError for p42", err)
+			}
+			if calls != 0 || len(tl.Attempts) != 0 {
+				t.Fatalf("calls=%d attempts=%d, want none", calls, len(tl.Attempts))
+			}
+			if tl.Attributes["trigger_error"] != "trigger_not_found" {
+				t.Fatalf("attributes=%v", tl.Attributes)
+			}
+			if err := exec.Warm(context.Background(), key); !errors.As(err, &nte) {
+				t.Fatalf("Warm err=%v, want NoTriggerError", err)
+			}
+		})
+	}
+}
+
+func TestResolveTrigger_HedgeDelayPercentile(t *testing.T) {
+	exec := NewExecutor()
+	pol := policy.New("svc.pct", policy.HedgeDelayPercentile("P95"), policy.HedgeDelay(30*time.Millisecond))
+	if pol.Hedge.HedgeDelayPercentile != "p95" {
+		t.Fatalf("percentile=%q, want normalized p95", pol.Hedge.HedgeDelayPercentile)
+	}
+
+	trig, err := exec.resolveTrigger(pol.Hedge)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lt, ok := trig.(hedge.LatencyTrigger)
+	if !ok || lt.Percentile != "p95" || lt.MinSamples != percentileMinSamples {
+		t.Fatalf("trigger=%#v, want p95 LatencyTrigger", trig)
+	}
+
+	pol.Hedge.TriggerName = "custom"
+	triggers := hedge.NewRegistry()
+	triggers.Register("custom", hedge.FixedDelayTrigger{Delay: time.Second})
+	exec = NewExecutor(WithHedgeTriggerRegistry(triggers))
+	if trig, _ := exec.resolveTrigger(pol.Hedge); trig != (hedge.FixedDelayTrigger{Delay: time.Second}) {
+		t.Fatalf("trigger=%#v, want named trigger to take precedence", trig)
+	}
+}
@@ -0,0 +1,105 @@
+package retry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+// ErrAborted matches the error of a call aborted through its CallHandle.
+var ErrAborted = errors.New("recourse: aborted by caller")
+
+// AbortedError is returned by CallHandle.Wait when the call was aborted with
+// CallHandle.Abort. Err is the error the call stopped with, usually
+// context.Canceled. It matches ErrAborted.
+type AbortedError struct {
+	Reason string
+	Err    error
+}
+
+func (e *AbortedError) Error() string {
+	if e.Reason == "" {
+		return ErrAborted.Error()
+	}
+	return ErrAborted.Error() + ": " + e.Reason
+}
+
+func (e *AbortedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *AbortedError) Is(target error) bool {
+	return target == ErrAborted
+}
+
+// CallHandle controls a call started with DoValueWithHandle or DoWithHandle.
+type CallHandle[T any] struct {
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+
+	val T
+	err error
+}
+
+// DoValueWithHandle starts DoValue in a new goroutine and returns a handle to
+// abort it or wait for its result. It suits long-running background calls that
+// another goroutine may need to stop without threading a separate context.
+func DoValueWithHandle[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op OperationValue[T], opts ...CallOption) *CallHandle[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	h := &CallHandle[T]{cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(h.done)
+		defer cancel(nil)
+
+		h.val, h.err = DoValue(ctx, exec, key, op, opts...)
+		var aborted *AbortedError
+		if h.err != nil && errors.As(context.Cause(ctx), &aborted) {
+			h.err = &AbortedError{Reason: aborted.Reason, Err: h.err}
+		}
+	}()
+	return h
+}
+
+// DoWithHandle is DoValueWithHandle for an Operation.
+func (e *Executor) DoWithHandle(ctx context.Context, key policy.PolicyKey, op Operation, opts ...CallOption) *CallHandle[struct{}] {
+	return DoValueWithHandle(ctx, e, key, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, op(ctx)
+	}, opts...)
+}
+
+// Abort cancels the call's context, stopping in-flight attempts, hedges and
+// backoff. Attempts that fail because of it are recorded with reason
+// "aborted_by_caller", and Wait returns an *AbortedError carrying reason.
+// Abort has no effect once the call has completed.
+func (h *CallHandle[T]) Abort(reason string) {
+	h.cancel(&AbortedError{Reason: reason})
+}
+
+// Done is closed when the call completes.
+func (h *CallHandle[T]) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait blocks until the call completes and returns its result.
+func (h *CallHandle[T]) Wait() (T, error) {
+	<-h.done
+	return h.val, h.err
+}
+
+// markAbortedByCaller records a failed attempt of an aborted call as
+// "aborted_by_caller" rather than as an ordinary cancellation.
+func markAbortedByCaller(ctx context.Context, out *classify.Outcome) {
+	if out.Kind == classify.OutcomeSuccess || ctx.Err() == nil {
+		return
+	}
+	var aborted *AbortedError
+	if errors.As(context.Cause(ctx), &aborted) {
+		*out = classify.Outcome{Kind: classify.OutcomeAbort, Reason: "aborted_by_caller"}
+	}
+}
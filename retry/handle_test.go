@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestDoValueWithHandle_Completes(t *testing.T) {
+	exec := NewExecutor()
+	h := DoValueWithHandle(context.Background(), exec, policy.ParseKey("svc.Handle"), func(context.Context) (int, error) {
+		return 42, nil
+	})
+
+	val, err := h.Wait()
+	if err != nil || val != 42 {
+		t.Fatalf("Wait = %v, %v, want 42, nil", val, err)
+	}
+	h.Abort("too late") // no effect after completion
+	if _, err := h.Wait(); err != nil {
+		t.Fatalf("Wait after Abort = %v, want nil", err)
+	}
+}
+
+func TestDoValueWithHandle_Abort(t *testing.T) {
+	key := policy.ParseKey("svc.HandleAbort")
+	ctx, capture := observe.RecordTimeline(context.Background())
+
+	started := make(chan struct{})
+	h := NewExecutor().DoWithHandle(ctx, key, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-started
+	h.Abort("job superseded")
+
+	select {
+	case <-h.Done():
+	case <-time.After(time.Second):
+		t.Fatal("call did not stop after Abort")
+	}
+
+	_, err := h.Wait()
+	var aborted *AbortedError
+	if !errors.As(err, &aborted) || aborted.Reason != "job superseded" {
+		t.Fatalf("err = %v, want AbortedError with reason", err)
+	}
+	if !errors.Is(err, ErrAborted) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want ErrAborted wrapping context.Canceled", err)
+	}
+
+	tl := capture.Timeline()
+	if tl == nil || len(tl.Attempts) != 1 {
+		t.Fatalf("timeline = %+v, want one attempt", tl)
+	}
+	if got := tl.Attempts[0].Outcome.Reason; got != "aborted_by_caller" {
+		t.Fatalf("attempt reason = %q, want aborted_by_caller", got)
+	}
+}
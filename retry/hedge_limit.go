@@ -0,0 +1,50 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+// hedgeSuppressedMaxConcurrent is the HedgeSuppressedEvent reason for hedges
+// over the executor's MaxConcurrentHedges.
+const hedgeSuppressedMaxConcurrent = "max_concurrent_hedges"
+
+// acquireHedgeSlot reserves an executor-wide hedge slot. It reports the
+// outstanding hedge count, including the reserved slot when ok.
+func (e *Executor) acquireHedgeSlot() (inFlight int, ok bool) {
+	limit := int64(e.maxConcurrentHedges)
+	if limit <= 0 {
+		return 0, true
+	}
+	for {
+		n := e.hedgeSlots.Load()
+		if n >= limit {
+			return int(n), false
+		}
+		if e.hedgeSlots.CompareAndSwap(n, n+1) {
+			return int(n + 1), true
+		}
+	}
+}
+
+// releaseHedgeSlot frees a slot reserved by acquireHedgeSlot.
+func (e *Executor) releaseHedgeSlot() {
+	if e.maxConcurrentHedges > 0 {
+		e.hedgeSlots.Add(-1)
+	}
+}
+
+func (e *Executor) reportHedgeSuppressed(ctx context.Context, key policy.PolicyKey, attempt, hedgeIdx, inFlight int) {
+	if ho, ok := e.observer.(observe.HedgeObserver); ok {
+		ho.OnHedgeSuppressed(ctx, observe.HedgeSuppressedEvent{
+			Key:        key,
+			Attempt:    attempt,
+			HedgeIndex: hedgeIdx,
+			Reason:     hedgeSuppressedMaxConcurrent,
+			InFlight:   inFlight,
+			Limit:      e.maxConcurrentHedges,
+		})
+	}
+}
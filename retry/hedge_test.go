@@ -232,3 +232,161 @@ func setImmediateTrigger(exec *Executor) {
 	triggers.Register("immediate", immediateTrigger{})
 	exec.triggers = triggers
 }
+
+func TestExecutor_Hedge_RequireIdempotent(t *testing.T) {
+	key := policy.ParseKey("test.hedge.idempotent")
+	newPolicy := func(require bool) policy.EffectivePolicy {
+		return policy.EffectivePolicy{
+			Key:   key,
+			Retry: policy.RetryPolicy{MaxAttempts: 1},
+			Hedge: policy.HedgePolicy{
+				Enabled:           true,
+				MaxHedges:         1,
+				TriggerName:       "immediate",
+				RequireIdempotent: require,
+			},
+		}
+	}
+
+	countHedges := func(t *testing.T, exec *Executor, opts ...CallOption) (int32, observe.Timeline) {
+		t.Helper()
+		var hedges atomic.Int32
+		_, tl, err := doValueInternal(context.Background(), exec, key, func(ctx context.Context) (any, error) {
+			if info, _ := observe.AttemptFromContext(ctx); info.IsHedge {
+				hedges.Add(1)
+				return nil, nil
+			}
+			time.Sleep(20 * time.Millisecond)
+			return nil, nil
+		}, true, opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return hedges.Load(), tl
+	}
+
+	t.Run("policy suppresses", func(t *testing.T) {
+		exec := newTestExecutor(t, key, newPolicy(true))
+		setImmediateTrigger(exec)
+
+		hedges, tl := countHedges(t, exec)
+		if hedges != 0 {
+			t.Fatalf("hedges = %d, want 0", hedges)
+		}
+		if got := tl.Attributes["hedge_suppressed_non_idempotent"]; got != "true" {
+			t.Fatalf("hedge_suppressed_non_idempotent = %q, want true", got)
+		}
+	})
+
+	t.Run("executor suppresses", func(t *testing.T) {
+		exec := newTestExecutor(t, key, newPolicy(false))
+		exec.requireIdempotent = true
+		setImmediateTrigger(exec)
+
+		if hedges, _ := countHedges(t, exec); hedges != 0 {
+			t.Fatalf("hedges = %d, want 0", hedges)
+		}
+	})
+
+	t.Run("marked idempotent per call", func(t *testing.T) {
+		exec := newTestExecutor(t, key, newPolicy(true))
+		setImmediateTrigger(exec)
+
+		hedges, tl := countHedges(t, exec, Idempotent())
+		if hedges == 0 {
+			t.Fatal("expected a hedge for an idempotent call")
+		}
+		if _, ok := tl.Attributes["hedge_suppressed_non_idempotent"]; ok {
+			t.Fatal("unexpected hedge_suppressed_non_idempotent attribute")
+		}
+	})
+
+	t.Run("marked idempotent by policy", func(t *testing.T) {
+		pol := newPolicy(true)
+		pol.Idempotent = true
+		exec := newTestExecutor(t, key, pol)
+		setImmediateTrigger(exec)
+
+		if hedges, _ := countHedges(t, exec); hedges == 0 {
+			t.Fatal("expected a hedge for an idempotent policy")
+		}
+	})
+}
+
+type hedgeSuppressionObserver struct {
+	observe.BaseObserver
+	events chan observe.HedgeSuppressedEvent
+}
+
+func (o *hedgeSuppressionObserver) OnHedgeSuppressed(_ context.Context, ev observe.HedgeSuppressedEvent) {
+	o.events <- ev
+}
+
+func TestExecutor_Hedge_MaxConcurrentHedges(t *testing.T) {
+	key := policy.ParseKey("test.hedge.cap")
+	exec := newTestExecutor(t, key, policy.EffectivePolicy{
+		Key:   key,
+		Retry: policy.RetryPolicy{MaxAttempts: 1},
+		Hedge: policy.HedgePolicy{Enabled: true, MaxHedges: 1, TriggerName: "immediate"},
+	})
+	obs := &hedgeSuppressionObserver{events: make(chan observe.HedgeSuppressedEvent, 1)}
+	exec.observer = obs
+	exec.maxConcurrentHedges = 1
+	setImmediateTrigger(exec)
+
+	// The first call's hedge holds the only slot after the call returns.
+	hedgeStarted := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	_, err := DoValue[string](context.Background(), exec, key, func(ctx context.Context) (string, error) {
+		if info, _ := observe.AttemptFromContext(ctx); info.IsHedge {
+			once.Do(func() { close(hedgeStarted) })
+			<-release
+			return "", ctx.Err()
+		}
+		if !waitForSignal(hedgeStarted) {
+			return "", errors.New("hedge did not start")
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+
+	var hedges atomic.Int32
+	_, err = DoValue[string](context.Background(), exec, key, func(ctx context.Context) (string, error) {
+		if info, _ := observe.AttemptFromContext(ctx); info.IsHedge {
+			hedges.Add(1)
+			return "", nil
+		}
+		select {
+		case ev := <-obs.events:
+			obs.events <- ev
+		case <-time.After(time.Second):
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("second call: %v", err)
+	}
+	if hedges.Load() != 0 {
+		t.Fatalf("hedges = %d, want 0 while the cap is reached", hedges.Load())
+	}
+
+	select {
+	case ev := <-obs.events:
+		if ev.Key != key || ev.Reason != hedgeSuppressedMaxConcurrent || ev.InFlight != 1 || ev.Limit != 1 || ev.HedgeIndex != 1 {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	default:
+		t.Fatal("expected a hedge suppression event")
+	}
+
+	close(release)
+	if err := exec.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if n := exec.hedgeSlots.Load(); n != 0 {
+		t.Fatalf("hedge slots = %d after drain, want 0", n)
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aponysus/recourse/classify"
 	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/observe"
 	"github.com/aponysus/recourse/policy"
 )
 
@@ -132,28 +134,75 @@ func TestNextBackoff(t *testing.T) {
 func TestApplyJitterRanges(t *testing.T) {
 	backoff := 100 * time.Millisecond
 
-	if got := applyJitter(backoff, policy.JitterNone); got != backoff {
+	if got := applyJitter(backoff, policy.JitterNone, nil); got != backoff {
 		t.Fatalf("no jitter = %v, want %v", got, backoff)
 	}
-	if got := applyJitter(backoff, ""); got != backoff {
+	if got := applyJitter(backoff, "", nil); got != backoff {
 		t.Fatalf("empty jitter = %v, want %v", got, backoff)
 	}
 
-	got := applyJitter(backoff, policy.JitterFull)
+	got := applyJitter(backoff, policy.JitterFull, nil)
 	if got < 0 || got > backoff {
 		t.Fatalf("full jitter out of range: %v", got)
 	}
 
-	got = applyJitter(backoff, policy.JitterEqual)
+	got = applyJitter(backoff, policy.JitterEqual, nil)
 	if got < backoff/2 || got > backoff {
 		t.Fatalf("equal jitter out of range: %v", got)
 	}
 
-	if got = applyJitter(backoff, policy.JitterKind("odd")); got != backoff {
+	if got = applyJitter(backoff, policy.JitterKind("odd"), nil); got != backoff {
 		t.Fatalf("unknown jitter = %v, want %v", got, backoff)
 	}
 }
 
+func TestDecorrelatedJitter(t *testing.T) {
+	base := 10 * time.Millisecond
+	rng := newJitterRand(1)
+
+	prev := base
+	for i := 0; i < 20; i++ {
+		got := decorrelatedJitter(base, prev, rng)
+		if got < base || got >= 3*prev {
+			t.Fatalf("draw %d: %v not in [%v, %v)", i, got, base, 3*prev)
+		}
+		prev = got
+	}
+
+	if got := decorrelatedJitter(base, 0, rng); got != base {
+		t.Fatalf("zero previous sleep = %v, want base %v", got, base)
+	}
+}
+
+func TestExecutor_DecorrelatedJitterSleeps(t *testing.T) {
+	key := policy.ParseKey("svc.decorrelated")
+	exec := NewExecutor(
+		WithPolicy(key.String(),
+			policy.MaxAttempts(6),
+			policy.Backoff(10*time.Millisecond, 100*time.Millisecond, 2),
+			policy.Jitter(policy.JitterDecorrelated),
+		),
+		WithJitterSeed(3),
+	)
+	var sleeps []time.Duration
+	exec.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	_ = exec.Do(context.Background(), key, func(context.Context) error { return errors.New("fail") })
+
+	if len(sleeps) != 5 {
+		t.Fatalf("sleeps=%v, want 5", sleeps)
+	}
+	prev := 10 * time.Millisecond
+	for i, d := range sleeps {
+		if d < 10*time.Millisecond || d > 100*time.Millisecond || d > 3*prev {
+			t.Fatalf("sleep %d = %v outside [10ms, min(100ms, 3*%v)]", i, d, prev)
+		}
+		prev = d
+	}
+}
+
 func TestCapBackoff(t *testing.T) {
 	if got := capBackoff(-1*time.Second, 0); got != 0 {
 		t.Fatalf("negative backoff = %v, want 0", got)
@@ -170,17 +219,17 @@ func TestComputeSleep(t *testing.T) {
 	pol := policy.RetryPolicy{MaxBackoff: 200 * time.Millisecond, Jitter: policy.JitterNone}
 
 	out := classify.Outcome{BackoffOverride: 500 * time.Millisecond}
-	if got := computeSleep(100*time.Millisecond, pol, out); got != 200*time.Millisecond {
+	if got := computeSleep(100*time.Millisecond, pol, out, nil, nil); got != 200*time.Millisecond {
 		t.Fatalf("override capped = %v, want 200ms", got)
 	}
 
 	out.BackoffOverride = 50 * time.Millisecond
-	if got := computeSleep(100*time.Millisecond, pol, out); got != 50*time.Millisecond {
+	if got := computeSleep(100*time.Millisecond, pol, out, nil, nil); got != 50*time.Millisecond {
 		t.Fatalf("override = %v, want 50ms", got)
 	}
 
 	out.BackoffOverride = 0
-	if got := computeSleep(300*time.Millisecond, pol, out); got != 200*time.Millisecond {
+	if got := computeSleep(300*time.Millisecond, pol, out, nil, nil); got != 200*time.Millisecond {
 		t.Fatalf("backoff capped = %v, want 200ms", got)
 	}
 }
@@ -216,7 +265,7 @@ func (classifierUnknownWithReason) Classify(any, error) classify.Outcome {
 }
 
 func TestClassifyWithRecovery(t *testing.T) {
-	key := policy.PolicyKey{Name: "op"}
+	key := classify.AttemptContext{Key: policy.PolicyKey{Name: "op"}}
 
 	out, panicErr := classifyWithRecovery(false, classifierUnknownOutcome{}, nil, errors.New("err"), key)
 	if panicErr != nil {
@@ -299,6 +348,36 @@ func TestResolveClassifier(t *testing.T) {
 	}
 }
 
+func TestResolveClassifier_CachesUntilRegistryChanges(t *testing.T) {
+	reg := classify.NewRegistry()
+	exec := &Executor{
+		classifiers:           reg,
+		defaultClassifier:     classifierSuccess{},
+		missingClassifierMode: FailureFallback,
+	}
+	pol := policy.EffectivePolicy{Retry: policy.RetryPolicy{ClassifierName: "late"}}
+
+	cls, meta, err := resolveClassifier(exec, pol)
+	if err != nil || !meta.notFound {
+		t.Fatalf("unexpected resolve result: err=%v meta=%+v", err, meta)
+	}
+	if _, ok := cls.(classifierSuccess); !ok {
+		t.Fatalf("expected default classifier, got %T", cls)
+	}
+	if _, ok := exec.classifierCache.Load("late"); !ok {
+		t.Fatalf("expected resolution to be cached")
+	}
+
+	reg.Register("late", classifierRetryable{})
+	cls, meta, err = resolveClassifier(exec, pol)
+	if err != nil || meta.notFound {
+		t.Fatalf("unexpected resolve result after register: err=%v meta=%+v", err, meta)
+	}
+	if _, ok := cls.(classifierRetryable); !ok {
+		t.Fatalf("expected registered classifier after version change, got %T", cls)
+	}
+}
+
 type policyProviderStub struct {
 	pol policy.EffectivePolicy
 	err error
@@ -421,6 +500,34 @@ func TestResolvePolicyFast_NormalizationErrorDeny(t *testing.T) {
 	}
 }
 
+// newRecordingExecutor returns an Executor that serves pol under pol.Key and
+// records the sleeps it would take instead of waiting them out.
+func newRecordingExecutor(t *testing.T, pol policy.EffectivePolicy, opts ...ExecutorOption) (*Executor, *[]time.Duration) {
+	t.Helper()
+
+	var (
+		mu     sync.Mutex
+		sleeps []time.Duration
+	)
+	opts = append([]ExecutorOption{
+		WithProvider(&controlplane.StaticProvider{
+			Policies: map[policy.PolicyKey]policy.EffectivePolicy{pol.Key: pol},
+		}),
+	}, opts...)
+	opts = append(opts, WithSleep(func(_ context.Context, d time.Duration) error {
+		mu.Lock()
+		sleeps = append(sleeps, d)
+		mu.Unlock()
+		return nil
+	}))
+	return NewExecutor(opts...), &sleeps
+}
+
+// doWithTimeline runs op through the timeline path and returns its timeline.
+func doWithTimeline[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op OperationValue[T]) (T, observe.Timeline, error) {
+	return doValueInternal(ctx, exec, key, op, true)
+}
+
 const spinWaitIterations = 10000
 
 func spinWait(check func() bool) bool {
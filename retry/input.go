@@ -0,0 +1,36 @@
+package retry
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// InputMutator returns the input for a retry from the input of the previous
+// attempt. st describes the retry, including the previous attempt's error and
+// classification, so the mutator can rotate endpoints, refresh credentials or
+// shrink a page size in response to how it failed.
+type InputMutator[In any] func(ctx context.Context, in In, st State) In
+
+// DoValueWithInput runs op under the policy for key like DoValue, passing each
+// attempt an input. The first attempt gets in; before each retry, mutate
+// derives the next input from the previous one. Hedges of an attempt share its
+// input. A nil mutate reuses in for every attempt.
+func DoValueWithInput[In, T any](ctx context.Context, exec *Executor, key policy.PolicyKey, in In, op func(context.Context, In) (T, error), mutate InputMutator[In], opts ...CallOption) (T, error) {
+	var mu sync.Mutex
+	current, applied := in, 0
+
+	return DoValue(ctx, exec, key, func(ctx context.Context) (T, error) {
+		st, _ := StateFromContext(ctx)
+
+		mu.Lock()
+		if mutate != nil && st.Attempt > applied {
+			current, applied = mutate(ctx, current, st), st.Attempt
+		}
+		input := current
+		mu.Unlock()
+
+		return op(ctx, input)
+	}, opts...)
+}
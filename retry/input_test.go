@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestDoValueWithInput_MutatesBetweenAttempts(t *testing.T) {
+	key := policy.ParseKey("svc.Input")
+	exec := newTestExecutor(t, key, policy.New(key.String(), policy.MaxAttempts(3)))
+	errBusy := errors.New("replica busy")
+
+	var seen []string
+	var outcomes []classify.Outcome
+	val, err := DoValueWithInput(context.Background(), exec, key, "replica-0",
+		func(_ context.Context, replica string) (string, error) {
+			seen = append(seen, replica)
+			if replica != "replica-2" {
+				return "", errBusy
+			}
+			return "ok from " + replica, nil
+		},
+		func(_ context.Context, replica string, st State) string {
+			if !errors.Is(st.PrevErr, errBusy) {
+				t.Errorf("PrevErr=%v, want errBusy", st.PrevErr)
+			}
+			outcomes = append(outcomes, st.PrevOutcome)
+			return "replica-" + string(rune('0'+st.Attempt))
+		},
+	)
+	if err != nil || val != "ok from replica-2" {
+		t.Fatalf("val=%q err=%v", val, err)
+	}
+	if want := []string{"replica-0", "replica-1", "replica-2"}; len(seen) != 3 || seen[0] != want[0] || seen[1] != want[1] || seen[2] != want[2] {
+		t.Fatalf("inputs=%v, want %v", seen, want)
+	}
+	if len(outcomes) != 2 || outcomes[0].Kind != classify.OutcomeRetryable {
+		t.Fatalf("outcomes=%+v, want two retryable outcomes", outcomes)
+	}
+}
+
+func TestDoValueWithInput_NilMutatorReusesInput(t *testing.T) {
+	key := policy.ParseKey("svc.Input")
+	exec := newTestExecutor(t, key, policy.New(key.String(), policy.MaxAttempts(2)))
+
+	var seen []int
+	_, _ = DoValueWithInput(context.Background(), exec, key, 7, func(_ context.Context, n int) (int, error) {
+		seen = append(seen, n)
+		return 0, errors.New("nope")
+	}, nil)
+	if len(seen) != 2 || seen[0] != 7 || seen[1] != 7 {
+		t.Fatalf("inputs=%v, want [7 7]", seen)
+	}
+}
@@ -0,0 +1,100 @@
+package retry
+
+import (
+	"context"
+	"iter"
+	"sync"
+
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+// LoopAttempt is one attempt of a call driven by Attempts. The loop body runs
+// the operation under Context and reports its result with Report.
+type LoopAttempt struct {
+	ctx    context.Context
+	index  int
+	once   sync.Once
+	result chan error
+}
+
+// Context returns the attempt context. It carries the attempt's timeout and
+// metadata (see StateFromContext) and is cancelled when the call ends.
+func (a *LoopAttempt) Context() context.Context {
+	return a.ctx
+}
+
+// Index returns the 0-based attempt index.
+func (a *LoopAttempt) Index() int {
+	return a.index
+}
+
+// Report records the result of the attempt. Only the first call has an
+// effect; an attempt the loop body does not report counts as successful.
+func (a *LoopAttempt) Report(err error) {
+	a.once.Do(func() { a.result <- err })
+}
+
+// Attempts returns an iterator over the attempts of a call under the policy for
+// key, for callers who prefer a loop to a closure:
+//
+//	for attempt, err := range retry.Attempts(ctx, exec, key) {
+//		if err != nil {
+//			return err
+//		}
+//		attempt.Report(client.Send(attempt.Context(), req))
+//	}
+//
+// The executor classifies each reported error and handles budgets, backoff,
+// circuit breaking and observers as it does for Do. The loop ends after a
+// successful attempt. When the call fails, the final iteration yields a nil
+// LoopAttempt and the error Do would have returned. Breaking out of the loop
+// cancels the call.
+//
+// The body runs on the caller's goroutine, so hedging is disabled.
+func Attempts(ctx context.Context, exec *Executor, key policy.PolicyKey, opts ...CallOption) iter.Seq2[*LoopAttempt, error] {
+	return func(yield func(*LoopAttempt, error) bool) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		callCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		attempts := make(chan *LoopAttempt)
+		done := make(chan error, 1)
+		go func() {
+			done <- exec.Do(callCtx, key, func(attemptCtx context.Context) error {
+				info, _ := observe.AttemptFromContext(attemptCtx)
+				a := &LoopAttempt{ctx: attemptCtx, index: info.Attempt, result: make(chan error, 1)}
+				select {
+				case attempts <- a:
+				case <-attemptCtx.Done():
+					return attemptCtx.Err()
+				}
+				select {
+				case err := <-a.result:
+					return err
+				case <-attemptCtx.Done():
+					return attemptCtx.Err()
+				}
+			}, append(opts[:len(opts):len(opts)], OverrideHedging(false))...)
+		}()
+
+		for {
+			select {
+			case a := <-attempts:
+				if !yield(a, nil) {
+					cancel()
+					<-done
+					return
+				}
+				a.Report(nil)
+			case err := <-done:
+				if err != nil {
+					yield(nil, err)
+				}
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestAttempts_RetriesUntilSuccess(t *testing.T) {
+	key := policy.ParseKey("svc.Iter")
+	exec := newTestExecutor(t, key, policy.New(key.String(), policy.MaxAttempts(3)))
+
+	var indexes []int
+	for attempt, err := range Attempts(context.Background(), exec, key) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		indexes = append(indexes, attempt.Index())
+		if _, ok := StateFromContext(attempt.Context()); !ok {
+			t.Fatal("attempt context has no state")
+		}
+		if attempt.Index() < 2 {
+			attempt.Report(errors.New("transient"))
+			continue
+		}
+		attempt.Report(nil)
+	}
+	if len(indexes) != 3 || indexes[2] != 2 {
+		t.Fatalf("indexes=%v, want [0 1 2]", indexes)
+	}
+}
+
+func TestAttempts_YieldsFinalError(t *testing.T) {
+	key := policy.ParseKey("svc.Iter")
+	exec := newTestExecutor(t, key, policy.New(key.String(), policy.MaxAttempts(2)))
+	boom := errors.New("boom")
+
+	var runs int
+	var final error
+	for attempt, err := range Attempts(context.Background(), exec, key) {
+		if err != nil {
+			if attempt != nil {
+				t.Fatal("final iteration has an attempt")
+			}
+			final = err
+			break
+		}
+		runs++
+		attempt.Report(boom)
+	}
+	if runs != 2 || !errors.Is(final, boom) {
+		t.Fatalf("runs=%d final=%v, want 2 runs and boom", runs, final)
+	}
+
+	// A denied call yields its error without running an attempt.
+	denied := newTestExecutor(t, key, policy.New(key.String(), policy.Budget("missing")))
+	for attempt, err := range Attempts(context.Background(), denied, key) {
+		if attempt != nil || err == nil {
+			t.Fatalf("attempt=%v err=%v, want only the denial", attempt, err)
+		}
+	}
+}
+
+func TestAttempts_BreakCancelsCall(t *testing.T) {
+	key := policy.ParseKey("svc.Iter")
+	exec := newTestExecutor(t, key, policy.New(key.String(), policy.MaxAttempts(5)))
+
+	var attemptCtx context.Context
+	for attempt := range Attempts(context.Background(), exec, key) {
+		attemptCtx = attempt.Context()
+		break
+	}
+
+	select {
+	case <-attemptCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("breaking out of the loop did not cancel the attempt")
+	}
+}
@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// jitterRand is the per-executor randomness source for backoff jitter.
+//
+// Unseeded sources hand out independently seeded PCG generators from a
+// sync.Pool, so concurrent callers rarely share state or contend on a lock.
+// Seeded and caller-supplied sources use a single mutex-guarded generator so
+// the jitter sequence is reproducible.
+type jitterRand struct {
+	seeded bool
+
+	mu  sync.Mutex
+	rng *rand.Rand
+
+	pool sync.Pool
+}
+
+func newJitterRand(seed int64) *jitterRand {
+	j := &jitterRand{}
+	if seed != 0 {
+		j.seeded = true
+		j.rng = rand.New(rand.NewPCG(uint64(seed), uint64(seed)>>32|1))
+		return j
+	}
+	j.pool.New = func() any {
+		return rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return j
+}
+
+// newJitterRandFrom draws jitter from a caller-supplied source.
+func newJitterRandFrom(src rand.Source) *jitterRand {
+	return &jitterRand{seeded: true, rng: rand.New(src)}
+}
+
+// Float64 returns a uniform random number in [0, 1).
+// A nil source falls back to the package-level generator.
+func (j *jitterRand) Float64() float64 {
+	if j == nil {
+		return rand.Float64()
+	}
+	if j.seeded {
+		j.mu.Lock()
+		f := j.rng.Float64()
+		j.mu.Unlock()
+		return f
+	}
+	r := j.pool.Get().(*rand.Rand)
+	f := r.Float64()
+	j.pool.Put(r)
+	return f
+}
+
+// resolveJitter returns the custom jitter named by pol.Jitter, or nil for the
+// built-in kinds. A name missing from the executor's registry is handled by
+// MissingJitterMode, and missing reports it.
+func resolveJitter(exec *Executor, pol policy.RetryPolicy) (fn policy.JitterFunc, missing bool, err error) {
+	switch pol.Jitter {
+	case "", policy.JitterNone, policy.JitterFull, policy.JitterEqual, policy.JitterDecorrelated:
+		return nil, false, nil
+	}
+	if fn, ok := exec.jitters.Get(pol.Jitter); ok {
+		return fn, false, nil
+	}
+	switch exec.missingJitterMode {
+	case FailureDeny:
+		return nil, true, &NoJitterError{Name: pol.Jitter}
+	case FailureAllow, FailureAllowUnsafe:
+		return unjittered, true, nil
+	default:
+		return fullJitter, true, nil
+	}
+}
+
+func unjittered(backoff time.Duration, _ func() float64) time.Duration {
+	return backoff
+}
+
+func fullJitter(backoff time.Duration, rnd func() float64) time.Duration {
+	return time.Duration(rnd() * float64(backoff))
+}
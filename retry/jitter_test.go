@@ -0,0 +1,178 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestJitterRand_SeededIsReproducible(t *testing.T) {
+	a := newJitterRand(42)
+	b := newJitterRand(42)
+	for i := 0; i < 10; i++ {
+		if x, y := a.Float64(), b.Float64(); x != y {
+			t.Fatalf("draw %d: %v != %v", i, x, y)
+		}
+	}
+}
+
+func TestJitterRand_UnseededConcurrent(t *testing.T) {
+	j := newJitterRand(0)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if f := j.Float64(); f < 0 || f >= 1 {
+					t.Errorf("out of range: %v", f)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var nilRand *jitterRand
+	if f := nilRand.Float64(); f < 0 || f >= 1 {
+		t.Fatalf("nil source out of range: %v", f)
+	}
+}
+
+func TestExecutor_JitterSeed_DeterministicBackoff(t *testing.T) {
+	key := policy.ParseKey("svc.jitter")
+	run := func() []time.Duration {
+		exec := NewExecutor(
+			WithProvider(&controlplane.StaticProvider{Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+				key: {Retry: policy.RetryPolicy{
+					MaxAttempts:    4,
+					InitialBackoff: 100 * time.Millisecond,
+					MaxBackoff:     time.Second,
+					Jitter:         policy.JitterFull,
+				}},
+			}}),
+			WithJitterSeed(7),
+		)
+		var sleeps []time.Duration
+		exec.sleep = func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		}
+		_ = exec.Do(context.Background(), key, func(context.Context) error { return errors.New("fail") })
+		return sleeps
+	}
+
+	first, second := run(), run()
+	if len(first) != 3 {
+		t.Fatalf("sleeps=%v, want 3", first)
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("sleep %d differs: %v vs %v", i, first[i], second[i])
+		}
+	}
+}
+
+func TestExecutor_Rand_DeterministicBackoff(t *testing.T) {
+	key := policy.ParseKey("svc.rand")
+	run := func(opts ...ExecutorOption) []time.Duration {
+		exec := NewExecutor(append([]ExecutorOption{
+			WithPolicy(key.String(),
+				policy.MaxAttempts(4),
+				policy.Backoff(100*time.Millisecond, time.Second, 2),
+				policy.Jitter(policy.JitterFull),
+			),
+		}, opts...)...)
+		var sleeps []time.Duration
+		exec.sleep = func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		}
+		_ = exec.Do(context.Background(), key, func(context.Context) error { return errors.New("fail") })
+		return sleeps
+	}
+
+	first := run(WithRand(rand.NewPCG(1, 2)))
+	second := run(WithJitterSeed(99), WithRand(rand.NewPCG(1, 2)))
+	if len(first) != 3 {
+		t.Fatalf("sleeps=%v, want 3", first)
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("sleep %d differs: %v vs %v", i, first[i], second[i])
+		}
+	}
+
+	derived := NewExecutor(WithRand(rand.NewPCG(1, 2))).With()
+	if want := newJitterRandFrom(rand.NewPCG(1, 2)).Float64(); derived.jitter.Float64() != want {
+		t.Fatal("derived executor does not share the supplied source")
+	}
+}
+
+func TestExecutor_CustomJitter(t *testing.T) {
+	policy.RegisterJitter("retry_test_scaled", func(b time.Duration, rnd func() float64) time.Duration {
+		return b/2 + time.Duration(rnd()*float64(b/4))
+	})
+
+	key := policy.ParseKey("svc.custom_jitter")
+	run := func(opts ...ExecutorOption) ([]time.Duration, error) {
+		exec := NewExecutor(append([]ExecutorOption{
+			WithPolicy(key.String(),
+				policy.MaxAttempts(3),
+				policy.Backoff(100*time.Millisecond, time.Second, 2),
+				policy.Jitter("retry_test_scaled"),
+			),
+			WithJitterSeed(3),
+		}, opts...)...)
+		var sleeps []time.Duration
+		exec.sleep = func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		}
+		err := exec.Do(context.Background(), key, func(context.Context) error { return errors.New("fail") })
+		return sleeps, err
+	}
+
+	sleeps, _ := run()
+	if len(sleeps) != 2 {
+		t.Fatalf("sleeps=%v, want 2", sleeps)
+	}
+	for i, base := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		if sleeps[i] < base/2 || sleeps[i] >= base*3/4 {
+			t.Fatalf("sleep %d=%v, want [%v, %v)", i, sleeps[i], base/2, base*3/4)
+		}
+	}
+	again, _ := run()
+	if again[0] != sleeps[0] || again[1] != sleeps[1] {
+		t.Fatalf("seeded custom jitter not reproducible: %v vs %v", sleeps, again)
+	}
+
+	empty := WithJitterRegistry(policy.NewJitterRegistry())
+
+	sleeps, err := run(empty, WithMissingJitterMode(FailureAllow))
+	if err == nil || len(sleeps) != 2 || sleeps[0] != 100*time.Millisecond || sleeps[1] != 200*time.Millisecond {
+		t.Fatalf("allow: sleeps=%v err=%v, want unjittered", sleeps, err)
+	}
+
+	sleeps, err = run(empty, WithMissingJitterMode(FailureDeny))
+	var nje *NoJitterError
+	if !errors.As(err, &nje) || nje.Name != "retry_test_scaled" || len(sleeps) != 0 {
+		t.Fatalf("deny: sleeps=%v err=%v, want NoJitterError", sleeps, err)
+	}
+
+	exec := NewExecutor(
+		WithPolicy(key.String(), policy.MaxAttempts(2), policy.Jitter("retry_test_scaled")),
+		empty,
+	)
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+	_, tl, _ := doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) { return 0, errors.New("fail") })
+	if tl.Attributes["jitter_not_found"] != "retry_test_scaled" || len(tl.Attempts) != 2 {
+		t.Fatalf("fallback: attrs=%v attempts=%d", tl.Attributes, len(tl.Attempts))
+	}
+}
@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+// Attempt describes the attempt an AttemptMiddleware wraps.
+type Attempt struct {
+	Key  policy.PolicyKey
+	Info observe.AttemptInfo
+
+	attrs map[string]string
+}
+
+// Annotate records a key/value pair on the attempt's AttemptRecord.Attributes.
+func (a *Attempt) Annotate(key, value string) {
+	if a.attrs == nil {
+		a.attrs = make(map[string]string, 2)
+	}
+	a.attrs[key] = value
+}
+
+// AttemptHandler runs the rest of an attempt: later middleware, then the operation.
+type AttemptHandler func(ctx context.Context) (any, error)
+
+// AttemptMiddleware wraps every attempt, hedges included. It may derive a new
+// context for next (e.g. to attach refreshed credentials), short-circuit by
+// returning without calling next, and annotate the attempt record. The
+// attempt's result is classified as usual; a short-circuit value that is not of
+// the call's result type is replaced by the zero value.
+//
+// Middleware runs on the attempt goroutine and must be safe for concurrent use.
+type AttemptMiddleware func(ctx context.Context, a *Attempt, next AttemptHandler) (any, error)
+
+// runWithMiddleware runs op for attempt a through the executor's middleware
+// chain, outermost first.
+func runWithMiddleware[T any](ctx context.Context, mws []AttemptMiddleware, a *Attempt, op OperationValue[T]) (T, error) {
+	handler := func(ctx context.Context) (any, error) {
+		return op(ctx)
+	}
+	for i := len(mws) - 1; i >= 0; i-- {
+		mw, next := mws[i], handler
+		handler = func(ctx context.Context) (any, error) {
+			return mw(ctx, a, next)
+		}
+	}
+
+	res, err := handler(ctx)
+	val, _ := res.(T)
+	return val, err
+}
@@ -0,0 +1,90 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+type middlewareCtxKey struct{}
+
+func TestAttemptMiddleware_OrderContextAndAnnotations(t *testing.T) {
+	key := policy.PolicyKey{Name: "mw"}
+	var order []string
+	outer := func(ctx context.Context, a *Attempt, next AttemptHandler) (any, error) {
+		order = append(order, "outer")
+		a.Annotate("token", "refreshed")
+		return next(context.WithValue(ctx, middlewareCtxKey{}, "v"))
+	}
+	inner := func(ctx context.Context, a *Attempt, next AttemptHandler) (any, error) {
+		order = append(order, "inner")
+		if a.Key != key || a.Info.Attempt != 0 {
+			t.Errorf("attempt=%+v, want key %v attempt 0", a, key)
+		}
+		return next(ctx)
+	}
+	exec, _ := newRecordingExecutor(t, policy.New("mw", policy.MaxAttempts(1)), WithAttemptMiddleware(outer, inner))
+
+	val, tl, err := doValueInternal(context.Background(), exec, key, func(ctx context.Context) (string, error) {
+		v, _ := ctx.Value(middlewareCtxKey{}).(string)
+		return v, nil
+	}, true)
+	if err != nil || val != "v" {
+		t.Fatalf("val=%q err=%v, want v", val, err)
+	}
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Fatalf("order=%v, want [outer inner]", order)
+	}
+	if len(tl.Attempts) != 1 || tl.Attempts[0].Attributes["token"] != "refreshed" {
+		t.Fatalf("attempts=%+v, want token annotation", tl.Attempts)
+	}
+}
+
+func TestAttemptMiddleware_ShortCircuit(t *testing.T) {
+	key := policy.PolicyKey{Name: "mw"}
+	denied := errors.New("denied")
+	calls := 0
+	exec, _ := newRecordingExecutor(t, policy.New("mw", policy.MaxAttempts(1)),
+		WithAttemptMiddleware(func(context.Context, *Attempt, AttemptHandler) (any, error) {
+			return nil, denied
+		}))
+
+	val, err := DoValue(context.Background(), exec, key, func(context.Context) (int, error) {
+		calls++
+		return 1, nil
+	})
+	if !errors.Is(err, denied) || val != 0 || calls != 0 {
+		t.Fatalf("val=%d err=%v calls=%d, want short-circuit", val, err, calls)
+	}
+
+	exec, _ = newRecordingExecutor(t, policy.New("mw", policy.MaxAttempts(1)),
+		WithAttemptMiddleware(func(context.Context, *Attempt, AttemptHandler) (any, error) {
+			return 42, nil
+		}))
+	if val, err := DoValue(context.Background(), exec, key, func(context.Context) (int, error) {
+		calls++
+		return 1, nil
+	}); err != nil || val != 42 || calls != 0 {
+		t.Fatalf("val=%d err=%v calls=%d, want cached 42", val, err, calls)
+	}
+}
+
+func TestAttemptMiddleware_RunsOnEveryRetry(t *testing.T) {
+	key := policy.PolicyKey{Name: "mw"}
+	seen := 0
+	exec, _ := newRecordingExecutor(t, policy.New("mw", policy.MaxAttempts(3)),
+		WithAttemptMiddleware(func(ctx context.Context, a *Attempt, next AttemptHandler) (any, error) {
+			if a.Info.Attempt != seen {
+				t.Errorf("attempt=%d, want %d", a.Info.Attempt, seen)
+			}
+			seen++
+			return next(ctx)
+		}))
+
+	_ = exec.Do(context.Background(), key, func(context.Context) error { return errors.New("boom") })
+	if seen != 3 {
+		t.Fatalf("middleware ran %d times, want 3", seen)
+	}
+}
@@ -285,3 +285,46 @@ func (o *testObserver) OnFailure(_ context.Context, _ policy.PolicyKey, tl obser
 	o.failures++
 	o.lastFailure = tl
 }
+
+func TestTimelinePooling_CapturedTimelineIsNotReleased(t *testing.T) {
+	key := policy.PolicyKey{Name: "pooled"}
+	obs := &testObserver{}
+	exec := NewExecutor(
+		WithProvider(&controlplane.StaticProvider{
+			Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+				key: {Retry: policy.RetryPolicy{MaxAttempts: 2}},
+			},
+		}),
+		WithObserver(obs),
+		WithTimelinePooling(true),
+	)
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+	fail := true
+	op := func(context.Context) (int, error) {
+		if fail {
+			fail = false
+			return 0, errors.New("nope")
+		}
+		return 1, nil
+	}
+
+	ctx, capture := observe.RecordTimeline(context.Background())
+	if _, err := DoValue[int](ctx, exec, key, op); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A pooled call afterwards must not reuse the captured timeline's buffers.
+	fail = true
+	if _, err := DoValue[int](context.Background(), exec, key, op); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tl := capture.Timeline()
+	if tl == nil || len(tl.Attempts) != 2 {
+		t.Fatalf("captured timeline=%+v, want 2 attempts", tl)
+	}
+	if obs.successes != 2 {
+		t.Fatalf("successes=%d, want 2", obs.successes)
+	}
+}
@@ -73,3 +73,28 @@ func TestNewExecutor_StaticPolicies_WithClassifierRegistry(t *testing.T) {
 		t.Error("expected registry to be set")
 	}
 }
+
+func TestWithSleep_ReceivesBackoff(t *testing.T) {
+	var slept []time.Duration
+	exec := NewExecutor(
+		WithPolicy("test.sleep",
+			policy.MaxAttempts(3),
+			policy.ConstantBackoff(50*time.Millisecond),
+		),
+		WithSleep(func(_ context.Context, d time.Duration) error {
+			slept = append(slept, d)
+			return nil
+		}),
+	)
+
+	start := time.Now()
+	_ = exec.Do(context.Background(), policy.ParseKey("test.sleep"), func(context.Context) error {
+		return context.DeadlineExceeded
+	})
+	if len(slept) != 2 || slept[0] != 50*time.Millisecond || slept[1] != 50*time.Millisecond {
+		t.Fatalf("slept=%v, want two 50ms backoffs", slept)
+	}
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Fatalf("elapsed=%v, want virtual sleeps to return immediately", elapsed)
+	}
+}
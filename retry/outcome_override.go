@@ -0,0 +1,157 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+// builtinNamedErrors are the sentinel errors ErrorMatcher.Errors can name
+// without registering them on the executor.
+var builtinNamedErrors = map[string]error{
+	"context.Canceled":         context.Canceled,
+	"context.DeadlineExceeded": context.DeadlineExceeded,
+	"io.EOF":                   io.EOF,
+	"io.ErrUnexpectedEOF":      io.ErrUnexpectedEOF,
+}
+
+// overrideOutcome applies the policy's AbortOn and RetryOn matchers, then its
+// retry rules and ReasonOverrides, to a classified attempt.
+func (e *Executor) overrideOutcome(pol policy.EffectivePolicy, out *classify.Outcome, err error) {
+	if err != nil && (!pol.AbortOn.IsZero() || !pol.RetryOn.IsZero()) {
+		e.matchOutcome(pol, out, err)
+	}
+	if len(pol.Retry.Rules) > 0 && out.Kind != classify.OutcomeSuccess {
+		e.applyRules(pol.Retry.Rules, out, err)
+	}
+	abortOnReason(pol, out)
+}
+
+// abortOnReason ends the call on a retryable outcome whose reason has an
+// aborting ReasonOverride. The reason is kept, and the "reason_override"
+// attribute records the override.
+func abortOnReason(pol policy.EffectivePolicy, out *classify.Outcome) {
+	if out.Kind != classify.OutcomeRetryable || !pol.ReasonOverrides[out.Reason].Abort {
+		return
+	}
+	attrs := make(map[string]string, len(out.Attributes)+1)
+	for k, v := range out.Attributes {
+		attrs[k] = v
+	}
+	attrs["reason_override"] = "abort"
+	out.Kind = classify.OutcomeAbort
+	out.Attributes = attrs
+}
+
+// matchOutcome applies the policy's AbortOn and RetryOn matchers to a
+// classified failure. A match replaces the outcome kind and reason, and keeps
+// the classifier's reason in the "classifier_reason" attribute.
+func (e *Executor) matchOutcome(pol policy.EffectivePolicy, out *classify.Outcome, err error) {
+
+	if matched, ok := e.matchError(pol.AbortOn, err); ok {
+		*out = classify.Outcome{
+			Kind:       classify.OutcomeAbort,
+			Reason:     "abort_on_match",
+			Attributes: overrideAttributes(out.Reason, matched),
+		}
+		return
+	}
+	if matched, ok := e.matchError(pol.RetryOn, err); ok {
+		out.Kind = classify.OutcomeRetryable
+		out.Attributes = overrideAttributes(out.Reason, matched)
+		out.Reason = "retry_on_match"
+	}
+}
+
+// matcherClassifier applies a policy's AbortOn and RetryOn to another classifier.
+type matcherClassifier struct {
+	exec  *Executor
+	pol   policy.EffectivePolicy
+	inner classify.Classifier
+}
+
+func (c matcherClassifier) Classify(val any, err error) classify.Outcome {
+	out := c.inner.Classify(val, err)
+	c.exec.overrideOutcome(c.pol, &out, err)
+	return out
+}
+
+func (c matcherClassifier) ClassifyAttempt(val any, err error, ac classify.AttemptContext) classify.Outcome {
+	out := classify.ClassifyAttempt(c.inner, val, err, ac)
+	c.exec.overrideOutcome(c.pol, &out, err)
+	return out
+}
+
+func overrideAttributes(classifierReason, matched string) map[string]string {
+	return map[string]string{
+		"classifier_reason": classifierReason,
+		"matched":           matched,
+	}
+}
+
+// matchError reports which entry of m matched err, as "error:<name>",
+// "http_status:<code>" or "grpc_code:<code>".
+func (e *Executor) matchError(m policy.ErrorMatcher, err error) (string, bool) {
+	for _, name := range m.Errors {
+		target, ok := e.namedErrors[name]
+		if !ok {
+			target, ok = builtinNamedErrors[name]
+		}
+		if ok && errors.Is(err, target) {
+			return "error:" + name, true
+		}
+	}
+
+	if len(m.HTTPStatuses) > 0 {
+		var he classify.HTTPError
+		if errors.As(err, &he) {
+			status := he.HTTPStatusCode()
+			for _, want := range m.HTTPStatuses {
+				if status == want {
+					return "http_status:" + strconv.Itoa(status), true
+				}
+			}
+		}
+	}
+
+	if len(m.GRPCCodes) > 0 {
+		if code, ok := grpcCodeOf(err); ok {
+			for _, want := range m.GRPCCodes {
+				if strings.EqualFold(strings.ReplaceAll(want, "_", ""), code) {
+					return "grpc_code:" + want, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// grpcCodeOf returns the code name of the first gRPC status error in err's
+// chain, such as "Unavailable". It calls GRPCStatus().Code().String(), as
+// implemented by grpc status errors, without importing grpc.
+func grpcCodeOf(err error) (string, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		m := reflect.ValueOf(err).MethodByName("GRPCStatus")
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+			continue
+		}
+		st := m.Call(nil)[0]
+		if st.Kind() == reflect.Pointer && st.IsNil() {
+			continue
+		}
+		code := st.MethodByName("Code")
+		if !code.IsValid() || code.Type().NumIn() != 0 || code.Type().NumOut() != 1 {
+			continue
+		}
+		if s, ok := code.Call(nil)[0].Interface().(interface{ String() string }); ok {
+			return s.String(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,172 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+type fakeGRPCCode int
+
+func (c fakeGRPCCode) String() string {
+	if c == 14 {
+		return "Unavailable"
+	}
+	return "Internal"
+}
+
+type fakeGRPCStatus struct{ code fakeGRPCCode }
+
+func (s *fakeGRPCStatus) Code() fakeGRPCCode { return s.code }
+
+type fakeGRPCError struct{ code fakeGRPCCode }
+
+func (e fakeGRPCError) Error() string               { return "rpc error: " + e.code.String() }
+func (e fakeGRPCError) GRPCStatus() *fakeGRPCStatus { return &fakeGRPCStatus{code: e.code} }
+
+func TestOutcomeOverride_RetryOnNamedError(t *testing.T) {
+	errLocked := errors.New("row locked")
+	key := policy.ParseKey("svc.RetryOn")
+	exec := NewExecutor(
+		WithPolicy(key.String(),
+			policy.MaxAttempts(3),
+			policy.Classifier("fatal"),
+			policy.RetryOn(policy.ErrorMatcher{Errors: []string{"db.ErrLocked"}}),
+		),
+		WithNamedError("db.ErrLocked", errLocked),
+		WithClassifier("fatal", fatalClassifier{fatal: errLocked}),
+	)
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+	calls := 0
+	_, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		calls++
+		return 0, fmt.Errorf("update: %w", errLocked)
+	})
+	if !errors.Is(err, errLocked) {
+		t.Fatalf("err = %v, want row locked", err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+
+	out := tl.Attempts[0].Outcome
+	if out.Kind != classify.OutcomeRetryable || out.Reason != "retry_on_match" {
+		t.Fatalf("outcome = %+v, want retryable retry_on_match", out)
+	}
+	if out.Attributes["classifier_reason"] != "fatal" || out.Attributes["matched"] != "error:db.ErrLocked" {
+		t.Fatalf("attributes = %v", out.Attributes)
+	}
+}
+
+func TestOutcomeOverride_AbortOnHTTPStatus(t *testing.T) {
+	key := policy.ParseKey("svc.AbortOn")
+	exec := newTestExecutor(t, key, policy.New(key.String(),
+		policy.MaxAttempts(3),
+		policy.AbortOn(policy.ErrorMatcher{HTTPStatuses: []int{503}}),
+	))
+
+	calls := 0
+	err := exec.Do(context.Background(), key, func(context.Context) error {
+		calls++
+		return stubHTTPError{status: 503, method: "GET"}
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1 after abort", calls)
+	}
+}
+
+func TestOutcomeOverride_GRPCCodeAndPrecedence(t *testing.T) {
+	key := policy.ParseKey("svc.GRPC")
+	exec := newTestExecutor(t, key, policy.New(key.String(),
+		policy.MaxAttempts(3),
+		policy.RetryOn(policy.ErrorMatcher{GRPCCodes: []string{"unavailable", "INTERNAL"}}),
+		policy.AbortOn(policy.ErrorMatcher{GRPCCodes: []string{"INTERNAL"}}),
+	))
+
+	_, tl, _ := doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, fmt.Errorf("wrapped: %w", fakeGRPCError{code: 14})
+	})
+	if len(tl.Attempts) != 3 || tl.Attempts[0].Outcome.Attributes["matched"] != "grpc_code:UNAVAILABLE" {
+		t.Fatalf("attempts = %d, first outcome = %+v", len(tl.Attempts), tl.Attempts[0].Outcome)
+	}
+
+	_, tl, _ = doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, fakeGRPCError{code: 13}
+	})
+	if len(tl.Attempts) != 1 || tl.Attempts[0].Outcome.Reason != "abort_on_match" {
+		t.Fatalf("attempts = %d, first outcome = %+v", len(tl.Attempts), tl.Attempts[0].Outcome)
+	}
+}
+
+// reasonClassifier classifies every error as retryable with the error text as
+// the reason.
+type reasonClassifier struct{}
+
+func (reasonClassifier) Classify(_ any, err error) classify.Outcome {
+	if err == nil {
+		return classify.Outcome{Kind: classify.OutcomeSuccess, Reason: "success"}
+	}
+	return classify.Outcome{Kind: classify.OutcomeRetryable, Reason: err.Error()}
+}
+
+func TestReasonOverrides(t *testing.T) {
+	key := policy.ParseKey("svc.Reasons")
+	for _, timeline := range []bool{false, true} {
+		exec := NewExecutor(
+			WithPolicy(key.String(),
+				policy.MaxAttempts(5),
+				policy.Backoff(time.Millisecond, time.Millisecond, 1),
+				policy.Classifier("reasons"),
+				policy.OnReason("http_429", policy.ReasonOverride{MinBackoff: time.Second, MaxAttempts: 3}),
+				policy.OnReason("http_501", policy.ReasonOverride{Abort: true}),
+			),
+			WithClassifier("reasons", reasonClassifier{}),
+		)
+		var sleeps []time.Duration
+		exec.sleep = func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		}
+
+		run := func(reasons ...string) (int, observe.Timeline, error) {
+			calls := 0
+			_, tl, err := doValueInternal(context.Background(), exec, key, func(context.Context) (struct{}, error) {
+				reason := reasons[min(calls, len(reasons)-1)]
+				calls++
+				return struct{}{}, errors.New(reason)
+			}, timeline)
+			return calls, tl, err
+		}
+
+		sleeps = nil
+		if calls, _, _ := run("http_429"); calls != 3 || len(sleeps) != 2 || sleeps[0] != time.Second {
+			t.Fatalf("timeline=%v: 429 calls=%d sleeps=%v, want 3 calls with 1s backoff", timeline, calls, sleeps)
+		}
+
+		sleeps = nil
+		if calls, _, _ := run("http_503"); calls != 5 || sleeps[0] != time.Millisecond {
+			t.Fatalf("timeline=%v: 503 calls=%d sleeps=%v, want policy defaults", timeline, calls, sleeps)
+		}
+
+		calls, tl, err := run("http_503", "http_501")
+		if calls != 2 || err == nil {
+			t.Fatalf("timeline=%v: 501 calls=%d err=%v, want abort on second attempt", timeline, calls, err)
+		}
+		if timeline {
+			out := tl.Attempts[1].Outcome
+			if out.Kind != classify.OutcomeAbort || out.Reason != "http_501" || out.Attributes["reason_override"] != "abort" {
+				t.Fatalf("outcome=%+v, want aborted http_501", out)
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+// callOperation runs op, converting a panic into a *PanicError with component
+// "operation" when the executor recovers panics.
+func callOperation[T any](e *Executor, ctx context.Context, key policy.PolicyKey, op OperationValue[T]) (val T, err error) {
+	if e.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{
+					Component: "operation",
+					Key:       key,
+					Value:     r,
+					Stack:     debug.Stack(),
+				}
+			}
+		}()
+	}
+	return op(ctx)
+}
+
+// classifyOperationPanic overrides the classifier for an attempt whose
+// operation panicked: the attempt is terminal, or retryable with RetryPanics.
+func (e *Executor) classifyOperationPanic(out *classify.Outcome, err error) {
+	var panicErr *PanicError
+	if err == nil || !errors.As(err, &panicErr) || panicErr.Component != "operation" {
+		return
+	}
+	if e.retryPanics {
+		*out = classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "panic_retryable"}
+		return
+	}
+	*out = classify.Outcome{Kind: classify.OutcomeAbort, Reason: "panic_in_operation"}
+}
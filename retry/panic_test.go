@@ -102,3 +102,55 @@ func TestExecutor_RecoverPanics_Classifier(t *testing.T) {
 		t.Errorf("expected component classifier, got %s", panicErr.Component)
 	}
 }
+
+func TestExecutor_RecoverPanics_Operation(t *testing.T) {
+	key := policy.ParseKey("svc.PanicOp")
+	pol := policy.New(key.String(), policy.MaxAttempts(3))
+
+	for _, timeline := range []bool{false, true} {
+		exec := newTestExecutor(t, key, pol)
+		exec.recoverPanics = true
+
+		calls := 0
+		_, tl, err := doValueInternal(context.Background(), exec, key, func(context.Context) (int, error) {
+			calls++
+			panic("transient")
+		}, timeline)
+
+		var panicErr *PanicError
+		if !errors.As(err, &panicErr) || panicErr.Component != "operation" || panicErr.Value != "transient" {
+			t.Fatalf("timeline=%v: err=%v, want operation PanicError", timeline, err)
+		}
+		if calls != 1 {
+			t.Fatalf("timeline=%v: calls=%d, want a panic to end the call", timeline, calls)
+		}
+		if timeline && tl.Attempts[0].Outcome.Reason != "panic_in_operation" {
+			t.Fatalf("reason=%q, want panic_in_operation", tl.Attempts[0].Outcome.Reason)
+		}
+	}
+}
+
+func TestExecutor_RetryPanics(t *testing.T) {
+	key := policy.ParseKey("svc.PanicRetry")
+	pol := policy.New(key.String(), policy.MaxAttempts(3))
+
+	for _, timeline := range []bool{false, true} {
+		exec := newTestExecutor(t, key, pol).With(WithRecoverPanics(true), WithRetryPanics(true))
+		exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+		calls := 0
+		val, tl, err := doValueInternal(context.Background(), exec, key, func(context.Context) (int, error) {
+			calls++
+			if calls < 3 {
+				panic("transient")
+			}
+			return 7, nil
+		}, timeline)
+		if err != nil || val != 7 || calls != 3 {
+			t.Fatalf("timeline=%v: val=%d err=%v calls=%d, want success on the third call", timeline, val, err, calls)
+		}
+		if timeline && tl.Attempts[0].Outcome.Reason != "panic_retryable" {
+			t.Fatalf("reason=%q, want panic_retryable", tl.Attempts[0].Outcome.Reason)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+// applyPushback folds a pushback signal from out or err into out and, when one
+// is present, marks key as cooling down. The pushback doubles as the backoff
+// override for the call that received it.
+func (e *Executor) applyPushback(key policy.PolicyKey, out *classify.Outcome, err error) {
+	d, ok := classify.PushbackOf(*out, err)
+	if !ok {
+		return
+	}
+	out.Pushback = d
+	if out.BackoffOverride < d {
+		out.BackoffOverride = d
+	}
+	e.coolDown(key, d)
+}
+
+// coolDown extends the cooldown of key to at least d from now.
+func (e *Executor) coolDown(key policy.PolicyKey, d time.Duration) {
+	until := e.clock().Add(d).UnixNano()
+
+	e.cooldownMu.RLock()
+	c, ok := e.cooldowns[key]
+	e.cooldownMu.RUnlock()
+	if !ok {
+		e.cooldownMu.Lock()
+		if e.cooldowns == nil {
+			e.cooldowns = make(map[policy.PolicyKey]*atomic.Int64)
+		}
+		if c, ok = e.cooldowns[key]; !ok {
+			c = new(atomic.Int64)
+			e.cooldowns[key] = c
+		}
+		e.cooldownMu.Unlock()
+	}
+
+	for {
+		cur := c.Load()
+		if cur >= until || c.CompareAndSwap(cur, until) {
+			return
+		}
+	}
+}
+
+// cooldownRemaining reports how long key stays cooling down after pushback.
+// Cooling keys do not hedge, and retries of every call on them wait at least
+// this long.
+func (e *Executor) cooldownRemaining(key policy.PolicyKey) time.Duration {
+	e.cooldownMu.RLock()
+	c, ok := e.cooldowns[key]
+	e.cooldownMu.RUnlock()
+	if !ok {
+		return 0
+	}
+	rem := time.Duration(c.Load() - e.clock().UnixNano())
+	if rem <= 0 {
+		return 0
+	}
+	return rem
+}
+
+// cooldownSleep raises sleepFor to the remaining cooldown of key, capped by
+// the policy's MaxBackoff like any other backoff override.
+func (e *Executor) cooldownSleep(key policy.PolicyKey, pol policy.RetryPolicy, sleepFor time.Duration) time.Duration {
+	if rem := capBackoff(e.cooldownRemaining(key), pol.MaxBackoff); rem > sleepFor {
+		return rem
+	}
+	return sleepFor
+}
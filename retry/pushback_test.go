@@ -0,0 +1,129 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+type pushbackError struct{ d time.Duration }
+
+func (e pushbackError) Error() string           { return "pushback" }
+func (e pushbackError) Pushback() time.Duration { return e.d }
+
+type pushbackClassifier struct{ d time.Duration }
+
+func (c pushbackClassifier) Classify(_ any, err error) classify.Outcome {
+	if err == nil {
+		return classify.Outcome{Kind: classify.OutcomeSuccess, Reason: "success"}
+	}
+	return classify.Outcome{Kind: classify.OutcomeRetryable, Reason: "shed", Pushback: c.d}
+}
+
+func TestExecutor_Pushback_TypedErrorOverridesBackoffAndCoolsKey(t *testing.T) {
+	key := policy.PolicyKey{Name: "pushback"}
+	exec, sleeps := newRecordingExecutor(t, policy.EffectivePolicy{
+		Key:   key,
+		Retry: policy.RetryPolicy{MaxAttempts: 2, InitialBackoff: 10 * time.Millisecond, MaxBackoff: time.Second},
+	}, WithClock((&fakeClock{now: time.Unix(100, 0)}).Now))
+
+	calls := 0
+	_ = exec.Do(context.Background(), key, func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return pushbackError{d: 300 * time.Millisecond}
+		}
+		return nil
+	})
+
+	if len(*sleeps) != 1 || (*sleeps)[0] != 300*time.Millisecond {
+		t.Fatalf("sleeps=%v, want [300ms]", *sleeps)
+	}
+	if rem := exec.cooldownRemaining(key); rem != 300*time.Millisecond {
+		t.Fatalf("cooldown=%v, want 300ms", rem)
+	}
+	if rem := exec.cooldownRemaining(policy.PolicyKey{Name: "other"}); rem != 0 {
+		t.Fatalf("other key cooldown=%v, want 0", rem)
+	}
+}
+
+func TestExecutor_Pushback_OutcomeRecordedOnTimeline(t *testing.T) {
+	key := policy.PolicyKey{Name: "pushback"}
+	exec, sleeps := newRecordingExecutor(t, policy.EffectivePolicy{
+		Key:   key,
+		Retry: policy.RetryPolicy{MaxAttempts: 2, InitialBackoff: 10 * time.Millisecond},
+	}, WithClock((&fakeClock{now: time.Unix(100, 0)}).Now))
+	exec.defaultClassifier = pushbackClassifier{d: 150 * time.Millisecond}
+
+	ctx, capture := observe.RecordTimeline(context.Background())
+	_ = exec.Do(ctx, key, func(context.Context) error { return errors.New("overloaded") })
+
+	tl := capture.Timeline()
+	if tl == nil || len(tl.Attempts) != 2 {
+		t.Fatalf("timeline=%+v, want 2 attempts", tl)
+	}
+	out := tl.Attempts[0].Outcome
+	if out.Pushback != 150*time.Millisecond || out.BackoffOverride != 150*time.Millisecond {
+		t.Fatalf("outcome=%+v, want pushback and override of 150ms", out)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] != 150*time.Millisecond {
+		t.Fatalf("sleeps=%v, want [150ms]", *sleeps)
+	}
+}
+
+func TestExecutor_Pushback_DelaysRetriesOfOtherCalls(t *testing.T) {
+	key := policy.PolicyKey{Name: "pushback"}
+	exec, sleeps := newRecordingExecutor(t, policy.EffectivePolicy{
+		Key:   key,
+		Retry: policy.RetryPolicy{MaxAttempts: 2, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 400 * time.Millisecond},
+	}, WithClock((&fakeClock{now: time.Unix(100, 0)}).Now))
+
+	// Another call on the key received pushback.
+	exec.coolDown(key, time.Second)
+
+	_ = exec.Do(context.Background(), key, func(context.Context) error { return errors.New("transient") })
+
+	if len(*sleeps) != 1 || (*sleeps)[0] != 400*time.Millisecond {
+		t.Fatalf("sleeps=%v, want cooldown capped at MaxBackoff (400ms)", *sleeps)
+	}
+}
+
+func TestExecutor_Pushback_CoolDownOnlyExtends(t *testing.T) {
+	exec := NewExecutorFromOptions(ExecutorOptions{Clock: func() time.Time { return time.Unix(100, 0) }})
+	key := policy.PolicyKey{Name: "k"}
+
+	exec.coolDown(key, time.Second)
+	exec.coolDown(key, 100*time.Millisecond)
+	if rem := exec.cooldownRemaining(key); rem != time.Second {
+		t.Fatalf("cooldown=%v, want 1s", rem)
+	}
+}
+
+func TestExecutor_Pushback_SuppressesHedging(t *testing.T) {
+	key := policy.PolicyKey{Name: "pushback"}
+	exec, _ := newRecordingExecutor(t, policy.EffectivePolicy{
+		Key:   key,
+		Retry: policy.RetryPolicy{MaxAttempts: 1},
+		Hedge: policy.HedgePolicy{Enabled: true, MaxHedges: 2, HedgeDelay: time.Millisecond},
+	})
+	exec.coolDown(key, time.Minute)
+
+	var calls atomic.Int32
+	err := exec.Do(context.Background(), key, func(context.Context) error {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls=%d, want 1 (no hedges while cooling down)", got)
+	}
+}
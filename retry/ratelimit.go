@@ -0,0 +1,95 @@
+package retry
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/internal"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+	"github.com/aponysus/recourse/ratelimit"
+)
+
+// allowCall consults the policy's rate limiter before the first attempt.
+// It returns the limiter (nil when none applies) so callers can feed attempt
+// outcomes back to adaptive limiters.
+func (e *Executor) allowCall(ctx context.Context, key policy.PolicyKey, ref policy.RateLimitRef) (limiter ratelimit.Limiter, decision ratelimit.Decision, allowed bool) {
+	name := strings.TrimSpace(ref.Name)
+	if e == nil || name == "" {
+		return nil, ratelimit.Decision{Allowed: true, Reason: ratelimit.ReasonNoLimiter}, true
+	}
+
+	event := observe.RateLimitDecisionEvent{
+		Key:         key,
+		LimiterName: name,
+		Mode:        "standard",
+	}
+	emit := func(d ratelimit.Decision) {
+		if ro, ok := e.observer.(observe.RateLimitObserver); ok {
+			event.Allowed = d.Allowed
+			event.Reason = d.Reason
+			event.RetryAfter = d.RetryAfter
+			ro.OnRateLimitDecision(ctx, event)
+		}
+	}
+
+	var missingReason string
+	var l ratelimit.Limiter
+	var ok bool
+
+	if e.rateLimiters == nil {
+		missingReason = ratelimit.ReasonLimiterRegistryNil
+	} else if l, ok = e.rateLimiters.Get(name); !ok {
+		missingReason = ratelimit.ReasonLimiterNotFound
+	} else if internal.IsTypedNil(l) {
+		missingReason = ratelimit.ReasonLimiterNil
+	}
+
+	if missingReason != "" {
+		event.Mode = failureModeString(e.missingLimiterMode)
+		d := ratelimit.Decision{Reason: missingReason}
+		if e.missingLimiterMode == FailureAllow || e.missingLimiterMode == FailureAllowUnsafe {
+			d.Allowed = true
+		}
+		emit(d)
+		return nil, d, d.Allowed
+	}
+
+	if e.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				d := ratelimit.Decision{Allowed: false, Reason: ratelimit.ReasonPanicInLimiter}
+				emit(d)
+				limiter, decision, allowed = nil, d, false
+			}
+		}()
+	}
+
+	decision = l.Allow(ctx, key)
+	if decision.Reason == "" {
+		if decision.Allowed {
+			decision.Reason = ratelimit.ReasonAllowed
+		} else {
+			decision.Reason = ratelimit.ReasonRateLimited
+		}
+	}
+
+	emit(decision)
+	return l, decision, decision.Allowed
+}
+
+// observeLimiter reports an attempt outcome to an adaptive limiter. Aborts
+// (cancellation, budget denials) say nothing about downstream load and are skipped.
+func observeLimiter(ctx context.Context, fb ratelimit.Feedback, key policy.PolicyKey, out classify.Outcome) {
+	if fb == nil || out.Kind == classify.OutcomeAbort {
+		return
+	}
+	fb.Observe(ctx, key, out)
+}
+
+// limiterFeedback returns the adaptive feedback hook of l, if any.
+func limiterFeedback(l ratelimit.Limiter) ratelimit.Feedback {
+	fb, _ := l.(ratelimit.Feedback)
+	return fb
+}
@@ -0,0 +1,123 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+	"github.com/aponysus/recourse/ratelimit"
+)
+
+type countingLimiter struct {
+	allow    bool
+	calls    int
+	outcomes []classify.Outcome
+}
+
+func (l *countingLimiter) Allow(context.Context, policy.PolicyKey) ratelimit.Decision {
+	l.calls++
+	if !l.allow {
+		return ratelimit.Decision{Allowed: false, RetryAfter: time.Second}
+	}
+	return ratelimit.Decision{Allowed: true}
+}
+
+func (l *countingLimiter) Observe(_ context.Context, _ policy.PolicyKey, out classify.Outcome) {
+	l.outcomes = append(l.outcomes, out)
+}
+
+type rateLimitObserver struct {
+	observe.BaseObserver
+	events []observe.RateLimitDecisionEvent
+}
+
+func (o *rateLimitObserver) OnRateLimitDecision(_ context.Context, ev observe.RateLimitDecisionEvent) {
+	o.events = append(o.events, ev)
+}
+
+func TestRateLimit_GatesOncePerCall(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		obs  observe.Observer
+	}{
+		{"fast", nil},
+		{"timeline", &rateLimitObserver{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			key := policy.ParseKey("svc.op")
+			lim := &countingLimiter{allow: true}
+			limiters := ratelimit.NewRegistry()
+			limiters.MustRegister("api", lim)
+			exec, _ := newRecordingExecutor(t, policy.New(key.String(), policy.MaxAttempts(3), policy.RateLimit("api")), WithRateLimiterRegistry(limiters), WithObserver(tc.obs))
+
+			calls := 0
+			_, err := DoValue(context.Background(), exec, key, func(context.Context) (int, error) {
+				calls++
+				if calls < 3 {
+					return 0, errors.New("transient")
+				}
+				return 1, nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if lim.calls != 1 {
+				t.Fatalf("limiter calls=%d, want 1 per call", lim.calls)
+			}
+			if len(lim.outcomes) != 3 {
+				t.Fatalf("feedback outcomes=%d, want 3", len(lim.outcomes))
+			}
+		})
+	}
+}
+
+func TestRateLimit_DeniedCallDoesNotRun(t *testing.T) {
+	key := policy.ParseKey("svc.op")
+	lim := &countingLimiter{allow: false}
+	limiters := ratelimit.NewRegistry()
+	limiters.MustRegister("api", lim)
+	obs := &rateLimitObserver{}
+	exec, _ := newRecordingExecutor(t, policy.New(key.String(), policy.MaxAttempts(3), policy.RateLimit("api")), WithRateLimiterRegistry(limiters), WithObserver(obs))
+
+	ran := false
+	_, err := DoValue(context.Background(), exec, key, func(context.Context) (int, error) {
+		ran = true
+		return 1, nil
+	})
+	var rle RateLimitedError
+	if !errors.As(err, &rle) {
+		t.Fatalf("err=%v, want RateLimitedError", err)
+	}
+	if rle.Reason != ratelimit.ReasonRateLimited || rle.RetryAfter != time.Second || rle.Limiter != "api" {
+		t.Fatalf("err=%+v, want rate_limited from api with 1s retry-after", rle)
+	}
+	if ran {
+		t.Fatalf("operation ran despite rate limit")
+	}
+	if len(obs.events) != 1 || obs.events[0].Allowed || obs.events[0].Mode != "standard" {
+		t.Fatalf("events=%+v, want one denied standard event", obs.events)
+	}
+}
+
+func TestRateLimit_MissingLimiterMode(t *testing.T) {
+	key := policy.ParseKey("svc.op")
+	op := func(context.Context) (int, error) { return 1, nil }
+
+	pol := policy.New(key.String(), policy.MaxAttempts(3), policy.RateLimit("api"))
+	exec, _ := newRecordingExecutor(t, pol, WithRateLimiterRegistry(ratelimit.NewRegistry()))
+	_, err := DoValue(context.Background(), exec, key, op)
+	var rle RateLimitedError
+	if !errors.As(err, &rle) || rle.Reason != ratelimit.ReasonLimiterNotFound {
+		t.Fatalf("err=%v, want %q", err, ratelimit.ReasonLimiterNotFound)
+	}
+
+	exec, _ = newRecordingExecutor(t, pol)
+	exec.missingLimiterMode = FailureAllow
+	if _, err := DoValue(context.Background(), exec, key, op); err != nil {
+		t.Fatalf("err=%v, want allowed under FailureAllow", err)
+	}
+}
@@ -3,7 +3,6 @@ package retry
 import (
 	"context"
 	"errors"
-	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,31 +10,13 @@ import (
 	"github.com/aponysus/recourse/policy"
 )
 
-// Local mock since controlplane test helpers are internal.
-type MockSource struct {
-	GetPolicyFunc func(ctx context.Context, key policy.PolicyKey) (policy.EffectivePolicy, error)
-	Calls         int32
-}
-
-func (m *MockSource) GetPolicy(ctx context.Context, key policy.PolicyKey) (policy.EffectivePolicy, error) {
-	atomic.AddInt32(&m.Calls, 1)
-	if m.GetPolicyFunc != nil {
-		return m.GetPolicyFunc(ctx, key)
-	}
-	return policy.EffectivePolicy{}, controlplane.ErrPolicyNotFound
-}
-
 func TestExecutor_RemoteProvider_Integration(t *testing.T) {
 	key := policy.ParseKey("remote.integration")
 	expected := policy.EffectivePolicy{
 		Retry: policy.RetryPolicy{MaxAttempts: 5},
 	}
 
-	source := &MockSource{
-		GetPolicyFunc: func(ctx context.Context, k policy.PolicyKey) (policy.EffectivePolicy, error) {
-			return expected, nil
-		},
-	}
+	source := controlplane.NewFakeSource(controlplane.FakeStep{Policy: expected})
 
 	// Create RemoteProvider
 	provider := controlplane.NewRemoteProvider(source, controlplane.WithCacheTTL(100*time.Millisecond))
@@ -57,8 +38,8 @@ func TestExecutor_RemoteProvider_Integration(t *testing.T) {
 	if opCalls != 1 {
 		t.Errorf("expected 1 op call, got %d", opCalls)
 	}
-	if atomic.LoadInt32(&source.Calls) != 1 {
-		t.Errorf("expected 1 source call, got %d", source.Calls)
+	if source.Calls() != 1 {
+		t.Errorf("expected 1 source call, got %d", source.Calls())
 	}
 
 	// 2. Second call - should hit cache
@@ -67,8 +48,8 @@ func TestExecutor_RemoteProvider_Integration(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if atomic.LoadInt32(&source.Calls) != 1 {
-		t.Errorf("expected 1 source call (cached), got %d", source.Calls)
+	if source.Calls() != 1 {
+		t.Errorf("expected 1 source call (cached), got %d", source.Calls())
 	}
 
 	// 3. Wait for expiration
@@ -79,18 +60,14 @@ func TestExecutor_RemoteProvider_Integration(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if atomic.LoadInt32(&source.Calls) != 2 {
-		t.Errorf("expected 2 source calls (expired), got %d", source.Calls)
+	if source.Calls() != 2 {
+		t.Errorf("expected 2 source calls (expired), got %d", source.Calls())
 	}
 }
 
 func TestExecutor_RemoteProvider_NegativeLink(t *testing.T) {
 	key := policy.ParseKey("remote.missing")
-	source := &MockSource{
-		GetPolicyFunc: func(ctx context.Context, k policy.PolicyKey) (policy.EffectivePolicy, error) {
-			return policy.EffectivePolicy{}, controlplane.ErrPolicyNotFound
-		},
-	}
+	source := controlplane.NewFakeSource(controlplane.FakeStep{Err: controlplane.ErrPolicyNotFound})
 
 	provider := controlplane.NewRemoteProvider(source, controlplane.WithNegativeCacheTTL(100*time.Millisecond))
 
@@ -113,8 +90,8 @@ func TestExecutor_RemoteProvider_NegativeLink(t *testing.T) {
 		}
 	}
 
-	if atomic.LoadInt32(&source.Calls) != 1 {
-		t.Errorf("expected 1 source call, got %d", source.Calls)
+	if source.Calls() != 1 {
+		t.Errorf("expected 1 source call, got %d", source.Calls())
 	}
 
 	// 2. Second call - should fail fast from cache
@@ -122,7 +99,7 @@ func TestExecutor_RemoteProvider_NegativeLink(t *testing.T) {
 	if err == nil {
 		t.Error("expected error")
 	}
-	if atomic.LoadInt32(&source.Calls) != 1 {
-		t.Errorf("expected 1 source call (cached negative), got %d", source.Calls)
+	if source.Calls() != 1 {
+		t.Errorf("expected 1 source call (cached negative), got %d", source.Calls())
 	}
 }
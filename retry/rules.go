@@ -0,0 +1,128 @@
+package retry
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/internal/expr"
+	"github.com/aponysus/recourse/policy"
+)
+
+var outcomeKindNames = map[classify.OutcomeKind]string{
+	classify.OutcomeUnknown:      "unknown",
+	classify.OutcomeSuccess:      "success",
+	classify.OutcomeRetryable:    "retryable",
+	classify.OutcomeNonRetryable: "non_retryable",
+	classify.OutcomeAbort:        "abort",
+}
+
+// applyRules applies the first of rules whose expression holds for a failed
+// attempt. A rule with an action replaces the outcome kind and sets reason
+// "rule_match", keeping the classifier's reason in the "classifier_reason"
+// attribute; a rule backoff sets the outcome's BackoffOverride. The "rule"
+// attribute records the matching rule's index. Rules whose expressions fail
+// to evaluate are skipped.
+func (e *Executor) applyRules(rules []policy.RetryRule, out *classify.Outcome, err error) {
+	vars := ruleVars(out, err)
+	for i, rule := range rules {
+		prog, ok := e.ruleProgram(rule.When)
+		if !ok {
+			continue
+		}
+		if matched, evalErr := prog.Eval(vars); evalErr != nil || !matched {
+			continue
+		}
+
+		attrs := make(map[string]string, len(out.Attributes)+2)
+		for k, v := range out.Attributes {
+			attrs[k] = v
+		}
+		attrs["rule"] = strconv.Itoa(i)
+		switch rule.Action {
+		case policy.RuleRetry:
+			out.Kind = classify.OutcomeRetryable
+		case policy.RuleAbort:
+			out.Kind = classify.OutcomeAbort
+		}
+		if rule.Action != "" {
+			attrs["classifier_reason"] = out.Reason
+			out.Reason = "rule_match"
+		}
+		if rule.Backoff > 0 {
+			out.BackoffOverride = rule.Backoff
+		}
+		out.Attributes = attrs
+		return
+	}
+}
+
+// ruleDeclarations declares the rule variables without types; policies
+// type-check their rules when normalized.
+var ruleDeclarations = func() map[string]expr.Type {
+	decls := make(map[string]expr.Type)
+	for _, name := range policy.RuleVariables() {
+		decls[name] = expr.Any
+	}
+	return decls
+}()
+
+// ruleProgram returns the compiled expression for when, caching it on the
+// executor. Expressions that do not compile yield false.
+func (e *Executor) ruleProgram(when string) (*expr.Program, bool) {
+	if v, ok := e.ruleCache.Load(when); ok {
+		prog, ok := v.(*expr.Program)
+		return prog, ok
+	}
+	prog, err := expr.Compile(when, ruleDeclarations)
+	if err != nil {
+		e.ruleCache.Store(when, err)
+		return nil, false
+	}
+	e.ruleCache.Store(when, prog)
+	return prog, true
+}
+
+// ruleVars returns the expression variables for an attempt, as documented
+// by policy.RuleVariables.
+func ruleVars(out *classify.Outcome, err error) map[string]any {
+	status := 0
+	var he classify.HTTPError
+	if errors.As(err, &he) {
+		status = he.HTTPStatusCode()
+	} else if s, convErr := strconv.Atoi(out.Attributes["status"]); convErr == nil {
+		status = s
+	}
+
+	var grpcCode string
+	if code, ok := grpcCodeOf(err); ok {
+		grpcCode = canonicalGRPCCode(code)
+	}
+
+	attrs := out.Attributes
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+	return map[string]any{
+		"status":     status,
+		"grpc_code":  grpcCode,
+		"reason":     out.Reason,
+		"kind":       outcomeKindNames[out.Kind],
+		"attributes": attrs,
+	}
+}
+
+// canonicalGRPCCode converts a gRPC code's String form, such as
+// "DeadlineExceeded", to the canonical name "DEADLINE_EXCEEDED".
+func canonicalGRPCCode(code string) string {
+	var b strings.Builder
+	for i, r := range code {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
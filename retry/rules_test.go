@@ -0,0 +1,96 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestRetryRules(t *testing.T) {
+	key := policy.ParseKey("svc.Rules")
+	exec := NewExecutor(
+		WithPolicy(key.String(),
+			policy.MaxAttempts(3),
+			policy.Backoff(time.Millisecond, 10*time.Second, 1),
+			policy.Classifier("http"),
+			policy.Rule(`status == 503 && attributes["method"] == "POST"`, policy.RuleAbort, 0),
+			policy.Rule(`status in [409, 503]`, policy.RuleRetry, 2*time.Second),
+			policy.Rule(`grpc_code == "UNAVAILABLE"`, policy.RuleRetry, 0),
+		),
+	)
+	var sleeps []time.Duration
+	exec.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+
+	// 409 is not retryable by the HTTP classifier; the second rule retries it
+	// with its own backoff.
+	_, tl, _ := doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, stubHTTPError{status: 409, method: "GET"}
+	})
+	if len(tl.Attempts) != 3 || len(sleeps) != 2 || sleeps[0] != 2*time.Second {
+		t.Fatalf("attempts=%d sleeps=%v, want 3 attempts with 2s backoff", len(tl.Attempts), sleeps)
+	}
+	out := tl.Attempts[0].Outcome
+	if out.Kind != classify.OutcomeRetryable || out.Reason != "rule_match" || out.Attributes["rule"] != "1" || out.Attributes["classifier_reason"] != "http_non_retryable_status" {
+		t.Fatalf("outcome=%+v, want retryable rule_match from rule 1", out)
+	}
+
+	// The first matching rule wins.
+	calls := 0
+	err := exec.Do(context.Background(), key, func(context.Context) error {
+		calls++
+		return stubHTTPError{status: 503, method: "POST"}
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("calls=%d err=%v, want abort after one attempt", calls, err)
+	}
+
+	// gRPC codes are matched by canonical name.
+	sleeps = nil
+	_, tl, _ = doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, fmt.Errorf("wrapped: %w", fakeGRPCError{code: 14})
+	})
+	if len(tl.Attempts) != 3 || tl.Attempts[0].Outcome.Attributes["rule"] != "2" {
+		t.Fatalf("attempts=%d outcome=%+v, want 3 attempts via rule 2", len(tl.Attempts), tl.Attempts[0].Outcome)
+	}
+}
+
+func TestRetryRules_BackoffOnly(t *testing.T) {
+	key := policy.ParseKey("svc.RuleBackoff")
+	exec := newTestExecutor(t, key, policy.New(key.String(),
+		policy.MaxAttempts(2),
+		policy.Backoff(time.Millisecond, 10*time.Second, 1),
+		policy.Rule(`reason.startsWith("retryable")`, "", 3*time.Second),
+	))
+	var slept time.Duration
+	exec.sleep = func(_ context.Context, d time.Duration) error {
+		slept = d
+		return nil
+	}
+
+	_, tl, _ := doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, fmt.Errorf("boom")
+	})
+	out := tl.Attempts[0].Outcome
+	if slept != 3*time.Second || out.Reason != "retryable_error" || out.Attributes["rule"] != "0" {
+		t.Fatalf("slept=%v outcome=%+v, want 3s backoff keeping the reason", slept, out)
+	}
+}
+
+func TestCanonicalGRPCCode(t *testing.T) {
+	for in, want := range map[string]string{
+		"Unavailable":      "UNAVAILABLE",
+		"DeadlineExceeded": "DEADLINE_EXCEEDED",
+		"Canceled":         "CANCELED",
+	} {
+		if got := canonicalGRPCCode(in); got != want {
+			t.Errorf("canonicalGRPCCode(%q)=%q, want %q", in, got, want)
+		}
+	}
+}
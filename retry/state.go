@@ -0,0 +1,44 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/observe"
+)
+
+// State describes the attempt an operation is running as. Operations use it to
+// adjust to earlier failures, for example by switching to another replica
+// after a timeout.
+type State struct {
+	Attempt    int           // 0 for the first attempt.
+	IsHedge    bool          // The attempt is a hedge of Attempt.
+	HedgeIndex int           // 1..N for hedges, 0 for the primary.
+	PrevErr    error         // Error of the previous attempt; nil for the first.
+	Backoff    time.Duration // Total backoff slept by the call so far.
+
+	// PrevOutcome is the classification of the previous attempt; zero for
+	// the first.
+	PrevOutcome classify.Outcome
+}
+
+// StateFromContext returns the State of the attempt running under ctx. It
+// reports false when ctx was not created by an executor for an attempt.
+func StateFromContext(ctx context.Context) (State, bool) {
+	info, ok := observe.AttemptFromContext(ctx)
+	if !ok {
+		return State{}, false
+	}
+	st := State{
+		Attempt:    info.Attempt,
+		IsHedge:    info.IsHedge,
+		HedgeIndex: info.HedgeIndex,
+		PrevErr:    info.PrevErr,
+		Backoff:    info.Backoff,
+	}
+	if info.PrevOutcome != nil {
+		st.PrevOutcome = *info.PrevOutcome
+	}
+	return st, true
+}
@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/classify"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestStateFromContext(t *testing.T) {
+	key := policy.ParseKey("svc.State")
+	pol := policy.New(key.String(),
+		policy.MaxAttempts(3),
+		policy.Backoff(10*time.Millisecond, time.Second, 2),
+		policy.Jitter(policy.JitterNone),
+	)
+
+	for _, timeline := range []bool{false, true} {
+		t.Run(fmt.Sprintf("timeline=%v", timeline), func(t *testing.T) {
+			exec := newTestExecutor(t, key, pol)
+
+			var states []State
+			_, _, err := doValueInternal(context.Background(), exec, key, func(ctx context.Context) (int, error) {
+				st, ok := StateFromContext(ctx)
+				if !ok {
+					t.Fatal("missing state")
+				}
+				states = append(states, st)
+				return 0, fmt.Errorf("attempt %d failed", st.Attempt)
+			}, timeline)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			if len(states) != 3 {
+				t.Fatalf("states=%+v, want 3", states)
+			}
+			if states[0].PrevErr != nil || states[0].Backoff != 0 || states[0].PrevOutcome.Reason != "" {
+				t.Fatalf("first state=%+v, want no previous error or backoff", states[0])
+			}
+			for i, want := range []time.Duration{10 * time.Millisecond, 30 * time.Millisecond} {
+				st := states[i+1]
+				if st.Attempt != i+1 || st.IsHedge {
+					t.Fatalf("state %d=%+v, want primary attempt %d", i+1, st, i+1)
+				}
+				if st.PrevErr == nil || st.PrevErr.Error() != fmt.Sprintf("attempt %d failed", i) {
+					t.Fatalf("state %d PrevErr=%v", i+1, st.PrevErr)
+				}
+				if st.PrevOutcome.Kind != classify.OutcomeRetryable {
+					t.Fatalf("state %d PrevOutcome=%+v, want retryable", i+1, st.PrevOutcome)
+				}
+				if st.Backoff != want {
+					t.Fatalf("state %d Backoff=%v, want %v", i+1, st.Backoff, want)
+				}
+			}
+		})
+	}
+
+	if _, ok := StateFromContext(context.Background()); ok {
+		t.Fatal("expected no state outside an attempt")
+	}
+}
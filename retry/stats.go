@@ -0,0 +1,117 @@
+package retry
+
+import (
+	"sync/atomic"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// Counters are runtime counters for an executor or one of its keys.
+type Counters struct {
+	InFlightCalls  int64 // Calls currently executing.
+	InFlightHedges int64 // Hedged attempts currently executing.
+	Calls          int64 // Calls started.
+	Attempts       int64 // Attempts executed, including retries and hedges.
+	Retries        int64 // Attempts after a call's first, excluding hedges.
+	Hedges         int64 // Hedged attempts executed.
+	Denials        int64 // Calls or attempts denied by a rate limiter, bulkhead, open circuit or budget.
+	Successes      int64 // Calls that returned without error.
+	Failures       int64 // Calls that returned an error.
+}
+
+// Stats is a point-in-time snapshot of an executor's runtime counters.
+// Counters are read individually, so a snapshot taken during calls may be
+// slightly inconsistent across fields.
+type Stats struct {
+	Counters
+
+	// Keys holds the counters of each key the executor has served, up to
+	// 1024 keys; calls on further keys are counted executor-wide only.
+	Keys map[policy.PolicyKey]Counters
+}
+
+// maxStatKeys bounds the keys whose counters an executor keeps.
+const maxStatKeys = 1024
+
+// Stats returns a snapshot of the executor's runtime counters, for publishing
+// health metrics without writing an Observer.
+func (e *Executor) Stats() Stats {
+	s := Stats{Counters: e.stats.snapshot()}
+
+	s.Keys = make(map[policy.PolicyKey]Counters, e.keyStatCount.Load())
+	e.keyStats.Range(func(k, v any) bool {
+		s.Keys[k.(policy.PolicyKey)] = v.(*statCounters).snapshot()
+		return true
+	})
+	return s
+}
+
+// statCounters holds the live counters behind Counters.
+type statCounters struct {
+	inFlightCalls  atomic.Int64
+	inFlightHedges atomic.Int64
+	calls          atomic.Int64
+	attempts       atomic.Int64
+	retries        atomic.Int64
+	hedges         atomic.Int64
+	denials        atomic.Int64
+	successes      atomic.Int64
+	failures       atomic.Int64
+}
+
+func (c *statCounters) snapshot() Counters {
+	return Counters{
+		InFlightCalls:  c.inFlightCalls.Load(),
+		InFlightHedges: c.inFlightHedges.Load(),
+		Calls:          c.calls.Load(),
+		Attempts:       c.attempts.Load(),
+		Retries:        c.retries.Load(),
+		Hedges:         c.hedges.Load(),
+		Denials:        c.denials.Load(),
+		Successes:      c.successes.Load(),
+		Failures:       c.failures.Load(),
+	}
+}
+
+// callCounters are the counters one call updates: the executor-wide ones and
+// its key's, looked up once when the call starts. The zero value counts
+// nothing.
+type callCounters struct {
+	total, key *statCounters
+}
+
+// callCounters returns the counters for a call on key.
+func (e *Executor) callCounters(key policy.PolicyKey) callCounters {
+	return callCounters{total: &e.stats, key: e.keyCounters(key)}
+}
+
+// add applies f to each of the call's counters.
+func (c callCounters) add(f func(*statCounters)) {
+	if c.total != nil {
+		f(c.total)
+	}
+	if c.key != nil {
+		f(c.key)
+	}
+}
+
+// keyCounters returns key's counters, or nil once maxStatKeys other keys
+// have counters.
+func (e *Executor) keyCounters(key policy.PolicyKey) *statCounters {
+	if v, ok := e.keyStats.Load(key); ok {
+		return v.(*statCounters)
+	}
+	if e.keyStatCount.Add(1) > maxStatKeys {
+		e.keyStatCount.Add(-1)
+		return nil
+	}
+	v, loaded := e.keyStats.LoadOrStore(key, &statCounters{})
+	if loaded {
+		e.keyStatCount.Add(-1)
+	}
+	return v.(*statCounters)
+}
+
+func countDenial(c *statCounters)  { c.denials.Add(1) }
+func countAttempt(c *statCounters) { c.attempts.Add(1) }
+func countRetry(c *statCounters)   { c.attempts.Add(1); c.retries.Add(1) }
@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestExecutorStats_CountsCallsAndAttempts(t *testing.T) {
+	key := policy.ParseKey("svc.Stats")
+	exec := newTestExecutor(t, key, policy.New(key.String(), policy.MaxAttempts(3)))
+
+	calls := 0
+	err := exec.Do(context.Background(), key, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = exec.Do(context.Background(), key, func(context.Context) error {
+		return errors.New("still failing")
+	}, OverrideMaxAttempts(1))
+
+	want := Counters{Calls: 2, Attempts: 4, Retries: 2, Successes: 1, Failures: 1}
+	s := exec.Stats()
+	if s.Counters != want {
+		t.Fatalf("stats = %+v, want %+v", s.Counters, want)
+	}
+	if s.Keys[key] != want {
+		t.Fatalf("key stats = %+v, want %+v", s.Keys[key], want)
+	}
+}
+
+func TestExecutorStats_CountsDenials(t *testing.T) {
+	key := policy.ParseKey("svc.StatsDenied")
+	exec := newTestExecutor(t, key, policy.New(key.String(), policy.Budget("missing")))
+
+	called := false
+	err := exec.Do(context.Background(), key, func(context.Context) error {
+		called = true
+		return nil
+	})
+	if err == nil || called {
+		t.Fatalf("expected the budget to deny the attempt, err=%v called=%v", err, called)
+	}
+
+	s := exec.Stats()
+	if s.Denials != 1 || s.Attempts != 0 || s.Failures != 1 {
+		t.Fatalf("stats = %+v, want 1 denial, 0 attempts, 1 failure", s.Counters)
+	}
+}
+
+func TestExecutorStats_CountsHedges(t *testing.T) {
+	key := policy.ParseKey("svc.StatsHedge")
+	exec := newTestExecutor(t, key, policy.EffectivePolicy{
+		Key:   key,
+		Retry: policy.RetryPolicy{MaxAttempts: 1},
+		Hedge: policy.HedgePolicy{Enabled: true, MaxHedges: 1, TriggerName: "immediate"},
+	})
+	setImmediateTrigger(exec)
+
+	hedgeStarted := make(chan struct{})
+	var once sync.Once
+	_, err := DoValue[string](context.Background(), exec, key, func(ctx context.Context) (string, error) {
+		if info, _ := observe.AttemptFromContext(ctx); info.IsHedge {
+			once.Do(func() { close(hedgeStarted) })
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		if !waitForSignal(hedgeStarted) {
+			return "", errors.New("hedge did not start")
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Close waits for the hedge to observe cancellation and finish.
+	if err := exec.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	s := exec.Stats()
+	if s.Hedges != 1 || s.Attempts != 2 || s.InFlightHedges != 0 || s.InFlightCalls != 0 {
+		t.Fatalf("stats = %+v, want 1 hedge, 2 attempts, nothing in flight", s.Counters)
+	}
+}
+
+func TestExecutorStats_BoundsKeys(t *testing.T) {
+	exec := NewExecutor(WithMissingPolicyMode(FailureAllow))
+	for i := 0; i < maxStatKeys+10; i++ {
+		key := policy.PolicyKey{Namespace: "svc", Name: "op" + strconv.Itoa(i)}
+		_ = exec.Do(context.Background(), key, func(context.Context) error { return nil })
+	}
+
+	s := exec.Stats()
+	if len(s.Keys) != maxStatKeys {
+		t.Fatalf("tracked keys = %d, want %d", len(s.Keys), maxStatKeys)
+	}
+	if s.Calls != maxStatKeys+10 || s.Successes != maxStatKeys+10 {
+		t.Fatalf("stats = %+v, want every call counted executor-wide", s.Counters)
+	}
+}
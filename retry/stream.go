@@ -0,0 +1,71 @@
+package retry
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// StreamOperation reads a stream starting at cursor and passes each item to
+// yield together with the cursor that resumes the stream after that item.
+// cursor is empty on the first attempt; retries resume from the cursor of the
+// last item the handler accepted. It returns nil at the end of the stream.
+//
+// yield returns a non-nil error when the stream must stop (the handler failed
+// or the call was canceled); the operation should return promptly.
+type StreamOperation[T any] func(ctx context.Context, cursor string, yield func(item T, next string) error) error
+
+// DoStream runs a resumable streaming operation (gRPC server streams,
+// paginated HTTP APIs) under the policy for key. Each item is passed to
+// handle exactly once: when an attempt fails, the retry resumes from the
+// cursor of the last handled item instead of restarting the stream.
+//
+// A handle error stops the call without further retries and is returned as
+// is. Hedging is disabled for streams, since parallel attempts would deliver
+// duplicate items.
+func DoStream[T any](ctx context.Context, exec *Executor, key policy.PolicyKey, op StreamOperation[T], handle func(ctx context.Context, item T) error, opts ...CallOption) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		cursor    string
+		handleErr error
+	)
+
+	err := exec.Do(ctx, key, func(attemptCtx context.Context) error {
+		mu.Lock()
+		start := cursor
+		mu.Unlock()
+
+		return op(attemptCtx, start, func(item T, next string) error {
+			if err := attemptCtx.Err(); err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if handleErr != nil {
+				return handleErr
+			}
+			if err := handle(attemptCtx, item); err != nil {
+				// Cancel the call so the executor stops retrying.
+				handleErr = err
+				cancel()
+				return err
+			}
+			cursor = next
+			return nil
+		})
+	}, append(opts[:len(opts):len(opts)], OverrideHedging(false))...)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if handleErr != nil {
+		return handleErr
+	}
+	return err
+}
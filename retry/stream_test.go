@@ -0,0 +1,93 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// pagedStream serves items 0..n-1, failing once after failAt items when failAt >= 0.
+func pagedStream(n, failAt int, cursors *[]string) StreamOperation[int] {
+	failed := false
+	return func(ctx context.Context, cursor string, yield func(int, string) error) error {
+		*cursors = append(*cursors, cursor)
+		start := 0
+		if cursor != "" {
+			start, _ = strconv.Atoi(cursor)
+		}
+		for i := start; i < n; i++ {
+			if i == failAt && !failed {
+				failed = true
+				return errors.New("stream reset")
+			}
+			if err := yield(i, strconv.Itoa(i+1)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestDoStream_ResumesFromCursor(t *testing.T) {
+	key := policy.PolicyKey{Name: "stream"}
+	exec, _ := newRecordingExecutor(t, policy.New("stream", policy.MaxAttempts(3)))
+
+	var cursors []string
+	var got []int
+	err := DoStream(context.Background(), exec, key, pagedStream(5, 3, &cursors), func(_ context.Context, item int) error {
+		got = append(got, item)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 5 {
+		t.Fatalf("items=%v, want each of 0..4 exactly once", got)
+	}
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("items=%v, want each of 0..4 exactly once", got)
+		}
+	}
+	if len(cursors) != 2 || cursors[0] != "" || cursors[1] != "3" {
+		t.Fatalf("cursors=%q, want [\"\" \"3\"]", cursors)
+	}
+}
+
+func TestDoStream_HandlerErrorStopsRetries(t *testing.T) {
+	key := policy.PolicyKey{Name: "stream"}
+	exec, _ := newRecordingExecutor(t, policy.New("stream", policy.MaxAttempts(3)))
+
+	stop := errors.New("stop")
+	var cursors []string
+	err := DoStream(context.Background(), exec, key, pagedStream(5, -1, &cursors), func(_ context.Context, item int) error {
+		if item == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("err=%v, want handler error", err)
+	}
+	if len(cursors) != 1 {
+		t.Fatalf("attempts=%d, want 1", len(cursors))
+	}
+}
+
+func TestDoStream_ExhaustedReturnsLastError(t *testing.T) {
+	key := policy.PolicyKey{Name: "stream"}
+	exec, _ := newRecordingExecutor(t, policy.New("stream", policy.MaxAttempts(2)))
+
+	boom := errors.New("boom")
+	attempts := 0
+	err := DoStream(context.Background(), exec, key, func(context.Context, string, func(int, string) error) error {
+		attempts++
+		return boom
+	}, func(context.Context, int) error { return nil })
+	if !errors.Is(err, boom) || attempts != 2 {
+		t.Fatalf("err=%v attempts=%d, want boom after 2 attempts", err, attempts)
+	}
+}
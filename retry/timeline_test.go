@@ -3,6 +3,7 @@ package retry
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -45,7 +46,7 @@ func TestDoValueWithTimeline_CircuitOpenShortCircuits(t *testing.T) {
 	_, tl, err := doValueWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
 		called = true
 		return 1, nil
-	})
+	}, nil)
 
 	if called {
 		t.Fatal("op should not be called when circuit is open")
@@ -89,7 +90,7 @@ func TestDoValueWithTimeline_BudgetDeniedReturnsPreviousError(t *testing.T) {
 	_, tl, err := doValueWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
 		calls++
 		return 0, firstErr
-	})
+	}, nil)
 
 	if calls != 1 {
 		t.Fatalf("calls=%d, want 1", calls)
@@ -132,7 +133,7 @@ func TestDoValueWithTimeline_SleepErrorStopsRetry(t *testing.T) {
 	_, tl, err := doValueWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
 		calls++
 		return 0, errors.New("retryable")
-	})
+	}, nil)
 	if calls != 1 {
 		t.Fatalf("calls=%d, want 1", calls)
 	}
@@ -176,3 +177,55 @@ func TestDoValue_FallsBackToTimelineWhenCircuitEnabled(t *testing.T) {
 		t.Fatalf("calls=%d, want 1", calls)
 	}
 }
+
+func TestDoValueWithTimeline_AttributesLazilyAllocated(t *testing.T) {
+	key := policy.PolicyKey{Name: "lazy"}
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Provider: &controlplane.StaticProvider{
+			Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+				key: {Retry: policy.RetryPolicy{MaxAttempts: 1}},
+			},
+		},
+	})
+
+	_, tl, err := doValueWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 1, nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tl.Attributes != nil {
+		t.Fatalf("attributes=%v, want nil when nothing was recorded", tl.Attributes)
+	}
+}
+
+func TestDoValueWithTimeline_ErrorFingerprints(t *testing.T) {
+	key := policy.PolicyKey{Name: "fp"}
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Provider: &controlplane.StaticProvider{
+			Policies: map[policy.PolicyKey]policy.EffectivePolicy{
+				key: {Retry: policy.RetryPolicy{MaxAttempts: 2}},
+			},
+		},
+	})
+	exec.sleep = func(context.Context, time.Duration) error { return nil }
+
+	calls := 0
+	_, tl, err := doValueWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		calls++
+		return 0, fmt.Errorf("upstream request %d failed", calls)
+	}, nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(tl.Attempts) != 2 {
+		t.Fatalf("attempts=%d, want 2", len(tl.Attempts))
+	}
+	first, second := tl.Attempts[0].ErrFingerprint, tl.Attempts[1].ErrFingerprint
+	if first == "" || first != second {
+		t.Fatalf("attempt fingerprints=%q,%q, want equal and non-empty", first, second)
+	}
+	if tl.FinalErrFingerprint == "" {
+		t.Fatalf("expected final error fingerprint")
+	}
+}
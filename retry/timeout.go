@@ -0,0 +1,122 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// attemptTimeout returns the timeout for attempt (0-based) of maxAttempts.
+//
+// Without AutoTimeoutPerAttempt it is pol.TimeoutPerAttempt. With it, the
+// timeout is derived from the remaining ctx deadline according to
+// pol.DeadlineSplit, floored at MinTimeoutPerAttempt and capped by
+// TimeoutPerAttempt when that is set. The default even split divides the
+// remaining deadline minus the backoff still expected before the last attempt
+// across the remaining attempts. backoff is the base delay before the next
+// retry.
+func attemptTimeout(ctx context.Context, pol policy.RetryPolicy, attempt, maxAttempts int, backoff time.Duration) time.Duration {
+	if !pol.AutoTimeoutPerAttempt {
+		return pol.TimeoutPerAttempt
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return pol.TimeoutPerAttempt
+	}
+
+	var timeout time.Duration
+	switch remaining := time.Until(deadline); pol.DeadlineSplit {
+	case policy.DeadlineSplitRemaining:
+		timeout = remaining
+	case policy.DeadlineSplitFraction:
+		timeout = time.Duration(float64(remaining) * pol.DeadlineFraction)
+	default:
+		// With unlimited attempts each attempt may use the whole remaining deadline.
+		remainingAttempts := maxAttempts - attempt
+		if remainingAttempts < 1 || pol.MaxAttempts == policy.UnlimitedAttempts {
+			remainingAttempts = 1
+		}
+
+		var expectedBackoff time.Duration
+		for i := 1; i < remainingAttempts; i++ {
+			expectedBackoff += backoff
+			backoff = nextBackoff(backoff, pol.BackoffMultiplier, pol.MaxBackoff)
+		}
+		timeout = (remaining - expectedBackoff) / time.Duration(remainingAttempts)
+	}
+	if timeout < pol.MinTimeoutPerAttempt {
+		timeout = pol.MinTimeoutPerAttempt
+	}
+	if pol.TimeoutPerAttempt > 0 && timeout > pol.TimeoutPerAttempt {
+		timeout = pol.TimeoutPerAttempt
+	}
+	return timeout
+}
+
+// backoffBudgetExhausted reports whether sleeping for sleepFor after already
+// sleeping spent would exceed pol.MaxCumulativeBackoff.
+func backoffBudgetExhausted(pol policy.RetryPolicy, spent, sleepFor time.Duration) bool {
+	return pol.MaxCumulativeBackoff > 0 && spent+sleepFor > pol.MaxCumulativeBackoff
+}
+
+// deadlineInsufficient reports whether sleeping for sleepFor before the next
+// attempt would reach the ctx deadline, along with the time remaining.
+func deadlineInsufficient(ctx context.Context, sleepFor time.Duration) (time.Duration, bool) {
+	if sleepFor <= 0 {
+		return 0, false
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	remaining := time.Until(deadline)
+	return remaining, sleepFor >= remaining
+}
+
+// softTimeoutPolicy turns pol's per-attempt timeout into a hedge trigger:
+// attempts are no longer cancelled at TimeoutPerAttempt, and a hedge races the
+// slow attempt from that point. A policy that does not hedge gets one hedge;
+// one that does keeps its hedge settings, with a fixed HedgeDelay capped at
+// the timeout.
+func softTimeoutPolicy(pol policy.EffectivePolicy) policy.EffectivePolicy {
+	timeout := pol.Retry.TimeoutPerAttempt
+	if timeout <= 0 {
+		return pol
+	}
+	pol.Retry.TimeoutPerAttempt = 0
+	if !pol.Hedge.Enabled {
+		pol.Hedge.Enabled = true
+		pol.Hedge.MaxHedges = 1
+		pol.Hedge.TriggerName = ""
+		pol.Hedge.HedgeDelay = timeout
+		return pol
+	}
+	if pol.Hedge.TriggerName == "" && pol.Hedge.HedgeDelay > timeout {
+		pol.Hedge.HedgeDelay = timeout
+	}
+	return pol
+}
+
+// attemptDeadlineInsufficient reports whether, under SkipInsufficientDeadline,
+// the next attempt for key is expected to outlast the ctx deadline. The
+// expected duration is the key's median observed attempt latency, or
+// TimeoutPerAttempt while no latency has been observed.
+func (e *Executor) attemptDeadlineInsufficient(ctx context.Context, key policy.PolicyKey, pol policy.RetryPolicy) (expected, remaining time.Duration, short bool) {
+	if !pol.SkipInsufficientDeadline {
+		return 0, 0, false
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, 0, false
+	}
+	expected = e.getTracker(key).Snapshot().P50
+	if expected <= 0 {
+		expected = pol.TimeoutPerAttempt
+	}
+	if expected <= 0 {
+		return 0, 0, false
+	}
+	remaining = time.Until(deadline)
+	return expected, remaining, expected > remaining
+}
@@ -0,0 +1,385 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestAttemptTimeout_Disabled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	pol := policy.RetryPolicy{TimeoutPerAttempt: 50 * time.Millisecond}
+	if got := attemptTimeout(ctx, pol, 0, 3, time.Millisecond); got != 50*time.Millisecond {
+		t.Fatalf("timeout=%v, want fixed 50ms", got)
+	}
+}
+
+func TestAttemptTimeout_NoDeadline(t *testing.T) {
+	pol := policy.RetryPolicy{AutoTimeoutPerAttempt: true, MinTimeoutPerAttempt: time.Millisecond}
+	if got := attemptTimeout(context.Background(), pol, 0, 3, time.Millisecond); got != 0 {
+		t.Fatalf("timeout=%v, want 0 without a deadline", got)
+	}
+}
+
+func TestAttemptTimeout_SplitsRemainingBudget(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1300*time.Millisecond)
+	defer cancel()
+
+	pol := policy.RetryPolicy{
+		AutoTimeoutPerAttempt: true,
+		MinTimeoutPerAttempt:  time.Millisecond,
+		BackoffMultiplier:     2,
+		MaxBackoff:            time.Second,
+	}
+
+	// 3 attempts remain with 100ms + 200ms of expected backoff: (1300-300)/3 ≈ 333ms.
+	got := attemptTimeout(ctx, pol, 0, 3, 100*time.Millisecond)
+	if got > 334*time.Millisecond || got < 300*time.Millisecond {
+		t.Fatalf("timeout=%v, want ≈333ms", got)
+	}
+
+	// The last attempt gets everything that is left.
+	got = attemptTimeout(ctx, pol, 2, 3, 100*time.Millisecond)
+	if got > 1300*time.Millisecond || got < 1200*time.Millisecond {
+		t.Fatalf("timeout=%v, want ≈1.3s for the last attempt", got)
+	}
+}
+
+func TestAttemptTimeout_FloorAndCap(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	pol := policy.RetryPolicy{AutoTimeoutPerAttempt: true, MinTimeoutPerAttempt: 50 * time.Millisecond, BackoffMultiplier: 1}
+	if got := attemptTimeout(ctx, pol, 0, 3, time.Second); got != 50*time.Millisecond {
+		t.Fatalf("timeout=%v, want floor 50ms", got)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	pol.TimeoutPerAttempt = 200 * time.Millisecond
+	if got := attemptTimeout(ctx, pol, 0, 3, time.Millisecond); got != 200*time.Millisecond {
+		t.Fatalf("timeout=%v, want cap 200ms", got)
+	}
+}
+
+func TestAttemptTimeout_DeadlineSplit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	pol := policy.RetryPolicy{
+		AutoTimeoutPerAttempt: true,
+		MinTimeoutPerAttempt:  time.Millisecond,
+		DeadlineSplit:         policy.DeadlineSplitRemaining,
+	}
+	if got := attemptTimeout(ctx, pol, 0, 3, 100*time.Millisecond); got > time.Second || got < 900*time.Millisecond {
+		t.Fatalf("remaining split: timeout=%v, want ≈1s", got)
+	}
+
+	pol.DeadlineSplit = policy.DeadlineSplitFraction
+	pol.DeadlineFraction = 0.25
+	if got := attemptTimeout(ctx, pol, 0, 3, 100*time.Millisecond); got > 250*time.Millisecond || got < 225*time.Millisecond {
+		t.Fatalf("fraction split: timeout=%v, want ≈250ms", got)
+	}
+
+	pol.TimeoutPerAttempt = 100 * time.Millisecond
+	if got := attemptTimeout(ctx, pol, 0, 3, 100*time.Millisecond); got != 100*time.Millisecond {
+		t.Fatalf("fraction split: timeout=%v, want cap 100ms", got)
+	}
+}
+
+func TestDoValue_AutoPerAttemptTimeout(t *testing.T) {
+	key := policy.ParseKey("svc.auto")
+	pol := policy.New(key.String(),
+		policy.MaxAttempts(2),
+		policy.ConstantBackoff(time.Millisecond),
+		policy.OverallTimeout(time.Second),
+		policy.AutoPerAttemptTimeout(time.Millisecond),
+	)
+	exec := NewExecutorFromOptions(ExecutorOptions{
+		Provider: &controlplane.StaticProvider{Policies: map[policy.PolicyKey]policy.EffectivePolicy{key: pol}},
+	})
+
+	var remaining time.Duration
+	_, err := DoValue(context.Background(), exec, key, func(ctx context.Context) (int, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatalf("attempt context has no deadline")
+		}
+		remaining = time.Until(deadline)
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if remaining > 500*time.Millisecond {
+		t.Fatalf("attempt deadline in %v, want at most half of the overall timeout", remaining)
+	}
+}
+
+func TestDeadlineInsufficient(t *testing.T) {
+	if _, short := deadlineInsufficient(context.Background(), time.Second); short {
+		t.Fatalf("no deadline should never be insufficient")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, short := deadlineInsufficient(ctx, 10*time.Millisecond); short {
+		t.Fatalf("10ms backoff should fit in a 1s deadline")
+	}
+	if remaining, short := deadlineInsufficient(ctx, 2*time.Second); !short || remaining <= 0 {
+		t.Fatalf("remaining=%v short=%v, want insufficient", remaining, short)
+	}
+}
+
+func TestExecutor_DeadlineInsufficient_TimelineSkipsRetry(t *testing.T) {
+	key := policy.PolicyKey{Name: "deadline"}
+	exec, _ := newRecordingExecutor(t, policy.New("deadline",
+		policy.MaxAttempts(3),
+		policy.ConstantBackoff(time.Second),
+	))
+	exec.sleep = func(context.Context, time.Duration) error {
+		t.Fatal("should not sleep into the deadline")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	boom := errors.New("boom")
+	_, tl, err := doWithTimeline(ctx, exec, key, func(context.Context) (int, error) {
+		return 0, boom
+	})
+
+	var die *DeadlineInsufficientError
+	if !errors.As(err, &die) || !errors.Is(err, boom) || die.Backoff != time.Second {
+		t.Fatalf("err=%v, want DeadlineInsufficientError wrapping boom", err)
+	}
+	if len(tl.Attempts) != 1 || tl.Attributes["stop_reason"] != "deadline_insufficient" {
+		t.Fatalf("attempts=%d attrs=%v, want 1 attempt and stop_reason", len(tl.Attempts), tl.Attributes)
+	}
+}
+
+func TestExecutor_MaxCumulativeBackoff(t *testing.T) {
+	key := policy.PolicyKey{Name: "backoff-budget"}
+	exec, _ := newRecordingExecutor(t, policy.New("backoff-budget",
+		policy.MaxAttempts(10),
+		policy.ConstantBackoff(40*time.Millisecond),
+		policy.MaxCumulativeBackoff(100*time.Millisecond),
+	))
+	var slept time.Duration
+	exec.sleep = func(_ context.Context, d time.Duration) error {
+		slept += d
+		return nil
+	}
+
+	boom := errors.New("boom")
+	calls := 0
+	_, err := DoValue(context.Background(), exec, key, func(context.Context) (int, error) {
+		calls++
+		return 0, boom
+	})
+
+	var bbe *BackoffBudgetExhaustedError
+	if !errors.As(err, &bbe) || !errors.Is(err, ErrBackoffBudgetExhausted) || !errors.Is(err, boom) {
+		t.Fatalf("err=%v, want BackoffBudgetExhaustedError wrapping boom", err)
+	}
+	if calls != 3 || slept != 80*time.Millisecond {
+		t.Fatalf("calls=%d slept=%v, want 3 calls and 80ms of backoff", calls, slept)
+	}
+	if bbe.Spent != 80*time.Millisecond || bbe.Backoff != 40*time.Millisecond || bbe.Limit != 100*time.Millisecond {
+		t.Fatalf("err=%+v, want spent 80ms, backoff 40ms, limit 100ms", bbe)
+	}
+
+	slept = 0
+	_, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (int, error) {
+		return 0, boom
+	})
+	if !errors.Is(err, ErrBackoffBudgetExhausted) {
+		t.Fatalf("err=%v, want ErrBackoffBudgetExhausted", err)
+	}
+	if len(tl.Attempts) != 3 || tl.Attributes["stop_reason"] != "backoff_budget_exhausted" {
+		t.Fatalf("attempts=%d attrs=%v, want 3 attempts and stop_reason", len(tl.Attempts), tl.Attributes)
+	}
+}
+
+func TestSoftTimeoutPolicy(t *testing.T) {
+	pol := policy.New("svc.soft", policy.SoftPerAttemptTimeout(50*time.Millisecond))
+	got := softTimeoutPolicy(pol)
+	if got.Retry.TimeoutPerAttempt != 0 {
+		t.Fatalf("TimeoutPerAttempt=%v, want 0", got.Retry.TimeoutPerAttempt)
+	}
+	if !got.Hedge.Enabled || got.Hedge.MaxHedges != 1 || got.Hedge.HedgeDelay != 50*time.Millisecond {
+		t.Fatalf("hedge=%+v, want one hedge after 50ms", got.Hedge)
+	}
+
+	hedged := policy.New("svc.soft", policy.SoftPerAttemptTimeout(50*time.Millisecond), policy.EnableHedging(), policy.HedgeMaxAttempts(3), policy.HedgeDelay(time.Second))
+	if got := softTimeoutPolicy(hedged); got.Hedge.MaxHedges != 3 || got.Hedge.HedgeDelay != 50*time.Millisecond {
+		t.Fatalf("hedge=%+v, want 3 hedges with delay capped at 50ms", got.Hedge)
+	}
+}
+
+func TestExecutor_SoftPerAttemptTimeout_HedgesInsteadOfCancelling(t *testing.T) {
+	key := policy.ParseKey("svc.soft")
+	exec := newTestExecutor(t, key, policy.New(key.String(),
+		policy.MaxAttempts(1),
+		policy.SoftPerAttemptTimeout(10*time.Millisecond),
+	))
+
+	hedged := make(chan struct{})
+	checked := make(chan struct{})
+	primaryErr := make(chan error, 1)
+	_, tl, err := doWithTimeline(context.Background(), exec, key, func(ctx context.Context) (string, error) {
+		if info, _ := observe.AttemptFromContext(ctx); info.IsHedge {
+			// The primary is past its soft timeout; let it report whether it
+			// is still running before the hedge wins.
+			close(hedged)
+			<-checked
+			return "hedge", nil
+		}
+		select {
+		case <-hedged:
+			primaryErr <- ctx.Err()
+			close(checked)
+			<-ctx.Done()
+			return "", ctx.Err()
+		case <-ctx.Done():
+			primaryErr <- ctx.Err()
+			return "", ctx.Err()
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := <-primaryErr; err != nil {
+		t.Fatalf("primary was cancelled before the hedge ran: %v", err)
+	}
+	if len(tl.Attempts) < 1 || !tl.Attempts[0].IsHedge {
+		t.Fatalf("attempts=%+v, want the hedge to win", tl.Attempts)
+	}
+}
+
+func TestExecutor_SoftPerAttemptTimeout_NonIdempotentCancels(t *testing.T) {
+	key := policy.ParseKey("svc.soft")
+	exec := newTestExecutor(t, key, policy.New(key.String(),
+		policy.MaxAttempts(1),
+		policy.SoftPerAttemptTimeout(5*time.Millisecond),
+	))
+	exec.requireIdempotent = true
+
+	_, err := DoValue(context.Background(), exec, key, func(ctx context.Context) (int, error) {
+		if info, _ := observe.AttemptFromContext(ctx); info.IsHedge {
+			t.Error("hedged a non-idempotent operation")
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v, want the attempt cancelled at its timeout", err)
+	}
+}
+
+func TestExecutor_SkipInsufficientDeadline(t *testing.T) {
+	key := policy.ParseKey("svc.skip")
+
+	run := func(t *testing.T, pol policy.EffectivePolicy, observed time.Duration, timeline bool) (int, observe.Timeline, error) {
+		t.Helper()
+		exec := newTestExecutor(t, key, pol)
+		if observed > 0 {
+			for i := 0; i < 5; i++ {
+				exec.getTracker(key).Observe(observed)
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		calls := 0
+		_, tl, err := doValueInternal(ctx, exec, key, func(context.Context) (int, error) {
+			calls++
+			return 0, nil
+		}, timeline)
+		return calls, tl, err
+	}
+
+	for _, timeline := range []bool{false, true} {
+		skip := policy.New(key.String(), policy.SkipInsufficientDeadline(), policy.PerAttemptTimeout(time.Second))
+		calls, tl, err := run(t, skip, 0, timeline)
+		if calls != 0 || !errors.Is(err, ErrDeadlineInsufficient) {
+			t.Fatalf("timeline=%v: calls=%d err=%v, want the attempt skipped", timeline, calls, err)
+		}
+		var die *DeadlineInsufficientError
+		if !errors.As(err, &die) || die.Expected != time.Second {
+			t.Fatalf("timeline=%v: err=%#v, want expected duration 1s", timeline, err)
+		}
+		if timeline && (len(tl.Attempts) != 1 || tl.Attempts[0].Outcome.Reason != "insufficient_deadline") {
+			t.Fatalf("attempts=%+v, want one insufficient_deadline record", tl.Attempts)
+		}
+
+		// Observed latency takes precedence over the per-attempt timeout.
+		if calls, _, err := run(t, skip, time.Millisecond, timeline); calls != 1 || err != nil {
+			t.Fatalf("timeline=%v: calls=%d err=%v, want the fast key to run", timeline, calls, err)
+		}
+		onlyLatency := policy.New(key.String(), policy.SkipInsufficientDeadline())
+		if calls, _, err := run(t, onlyLatency, time.Second, timeline); calls != 0 || !errors.Is(err, ErrDeadlineInsufficient) {
+			t.Fatalf("timeline=%v: calls=%d err=%v, want the slow key skipped", timeline, calls, err)
+		}
+
+		// Without the option, attempts run regardless.
+		if calls, _, err := run(t, policy.New(key.String(), policy.PerAttemptTimeout(time.Second)), 0, timeline); calls != 1 || err != nil {
+			t.Fatalf("timeline=%v: calls=%d err=%v, want the attempt to run", timeline, calls, err)
+		}
+	}
+}
+
+func TestUnlimitedAttempts_RetriesUntilContextEnds(t *testing.T) {
+	key := policy.ParseKey("svc.unlimited")
+	pol := policy.New(key.String(), policy.MaxAttempts(policy.UnlimitedAttempts), policy.OverallTimeout(time.Hour))
+
+	for _, timeline := range []bool{false, true} {
+		exec := newTestExecutor(t, key, pol)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		_, tl, err := doValueInternal(ctx, exec, key, func(context.Context) (struct{}, error) {
+			calls++
+			if calls == 25 {
+				cancel()
+			}
+			return struct{}{}, errors.New("not ready")
+		}, timeline)
+		cancel()
+
+		if !errors.Is(err, context.Canceled) || calls != 25 {
+			t.Fatalf("timeline=%v: err=%v calls=%d, want context.Canceled after 25 calls", timeline, err, calls)
+		}
+		if timeline && len(tl.Attempts) != 25 {
+			t.Fatalf("attempts=%d, want 25", len(tl.Attempts))
+		}
+	}
+}
+
+func TestUnlimitedAttempts_BoundedByOverallTimeout(t *testing.T) {
+	key := policy.ParseKey("svc.unlimited")
+	exec := NewExecutor(WithPolicy(key.String(),
+		policy.MaxAttempts(policy.UnlimitedAttempts),
+		policy.OverallTimeout(50*time.Millisecond),
+		policy.Backoff(time.Millisecond, time.Millisecond, 1),
+	))
+
+	calls := 0
+	err := exec.Do(context.Background(), key, func(context.Context) error {
+		calls++
+		return errors.New("not ready")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v, want context.DeadlineExceeded", err)
+	}
+	if calls <= 10 {
+		t.Fatalf("calls=%d, want more than the default attempt cap", calls)
+	}
+}
@@ -0,0 +1,33 @@
+package retry
+
+import (
+	"fmt"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// PolicyVersionError reports a provider policy rejected by the executor's
+// MinPolicyVersion or PinnedPolicyVersion.
+type PolicyVersionError struct {
+	Key     policy.PolicyKey
+	Version uint64 // Version of the rejected policy.
+	Min     uint64
+	Pinned  uint64
+}
+
+func (e *PolicyVersionError) Error() string {
+	if e.Pinned != 0 && e.Version != e.Pinned {
+		return fmt.Sprintf("recourse: policy version %d for %s does not match pinned version %d", e.Version, e.Key, e.Pinned)
+	}
+	return fmt.Sprintf("recourse: policy version %d for %s is below minimum %d", e.Version, e.Key, e.Min)
+}
+
+// checkPolicyVersion rejects pol when it is older than the executor's minimum
+// version or differs from its pinned version.
+func (e *Executor) checkPolicyVersion(key policy.PolicyKey, pol policy.EffectivePolicy) error {
+	v := pol.Meta.Version
+	if (e.pinnedPolicyVersion != 0 && v != e.pinnedPolicyVersion) || v < e.minPolicyVersion {
+		return &PolicyVersionError{Key: key, Version: v, Min: e.minPolicyVersion, Pinned: e.pinnedPolicyVersion}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestPolicyVersion_RecordedInTimeline(t *testing.T) {
+	key := policy.ParseKey("svc.versioned")
+	pol := policy.New(key.String(), policy.MaxAttempts(1))
+	pol.Meta.Version = 7
+	pol.Meta.Revision = "rollout-42"
+	source := controlplane.NewFakeSource(controlplane.FakeStep{Policy: pol})
+	exec := NewExecutor(WithProvider(controlplane.NewRemoteProvider(source)))
+
+	_, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (struct{}, error) {
+		return struct{}{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tl.Attributes["policy_version"] != "7" || tl.Attributes["policy_revision"] != "rollout-42" {
+		t.Fatalf("attributes=%v", tl.Attributes)
+	}
+}
+
+func TestPolicyVersion_MinAndPinned(t *testing.T) {
+	key := policy.ParseKey("svc.versioned")
+	versioned := func(v uint64) policy.EffectivePolicy {
+		pol := policy.New(key.String(), policy.MaxAttempts(4))
+		pol.Meta.Version = v
+		return pol
+	}
+
+	cases := []struct {
+		name    string
+		version uint64
+		opts    []ExecutorOption
+		reject  bool
+	}{
+		{"at minimum", 3, []ExecutorOption{WithMinPolicyVersion(3)}, false},
+		{"below minimum", 2, []ExecutorOption{WithMinPolicyVersion(3)}, true},
+		{"unversioned below minimum", 0, []ExecutorOption{WithMinPolicyVersion(1)}, true},
+		{"pinned match", 5, []ExecutorOption{WithPinnedPolicyVersion(5)}, false},
+		{"pinned mismatch", 6, []ExecutorOption{WithPinnedPolicyVersion(5)}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := &controlplane.StaticProvider{Policies: map[policy.PolicyKey]policy.EffectivePolicy{key: versioned(tc.version)}}
+			exec := NewExecutor(append([]ExecutorOption{WithProvider(provider)}, tc.opts...)...)
+
+			pol, err := resolvePolicyFast(context.Background(), exec, key)
+			if !tc.reject {
+				if err != nil || pol.Retry.MaxAttempts != 4 {
+					t.Fatalf("maxAttempts=%d err=%v, want accepted policy", pol.Retry.MaxAttempts, err)
+				}
+				return
+			}
+			var verErr *PolicyVersionError
+			if !errors.Is(err, ErrNoPolicy) || !errors.As(err, &verErr) || verErr.Version != tc.version {
+				t.Fatalf("err=%v, want NoPolicyError wrapping PolicyVersionError", err)
+			}
+		})
+	}
+}
+
+func TestPolicyVersion_RejectedFallsBackToDefault(t *testing.T) {
+	key := policy.ParseKey("svc.versioned")
+	stale := policy.New(key.String(), policy.MaxAttempts(4))
+	stale.Meta.Version = 1
+	exec := NewExecutor(
+		WithProvider(&controlplane.StaticProvider{Policies: map[policy.PolicyKey]policy.EffectivePolicy{key: stale}}),
+		WithMinPolicyVersion(2),
+		WithMissingPolicyMode(FailureFallback),
+	)
+
+	pol, attrs, err := resolvePolicyWithAttributes(context.Background(), exec, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pol.Retry.MaxAttempts != policy.DefaultPolicyFor(key).Retry.MaxAttempts || pol.Meta.Version != 0 {
+		t.Fatalf("policy=%+v, want the default policy", pol.Retry)
+	}
+	if attrs["policy_error"] != "version_rejected" {
+		t.Fatalf("attributes=%v", attrs)
+	}
+}
@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+// Warm resolves the policies for keys ahead of traffic, so that providers
+// that cache, such as controlplane.RemoteProvider, hold them before the first
+// call. It also creates the per-key state calls would otherwise create on
+// first use: latency trackers, resolved classifiers and circuit breakers
+// (except per-tenant ones).
+//
+// Warm continues past keys that fail and returns their errors joined. A key
+// fails when its policy, classifier or custom jitter cannot be resolved under
+// the executor's failure modes, that is, when a call for it would fail the
+// same way.
+func (e *Executor) Warm(ctx context.Context, keys ...policy.PolicyKey) error {
+	if e == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	e.ensureInitialized()
+	if !e.enter(false) {
+		return ErrExecutorClosed
+	}
+	defer e.leave()
+
+	var errs []error
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		pol, _, err := resolvePolicyWithAttributes(ctx, e, key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("recourse: warm %s: %w", key, err))
+			continue
+		}
+		if _, _, err := resolveClassifier(e, pol); err != nil {
+			errs = append(errs, fmt.Errorf("recourse: warm %s: %w", key, err))
+			continue
+		}
+		if _, _, err := resolveJitter(e, pol.Retry); err != nil {
+			errs = append(errs, fmt.Errorf("recourse: warm %s: %w", key, err))
+			continue
+		}
+		if pol.Hedge.Enabled {
+			if _, err := e.resolveTrigger(pol.Hedge); err != nil {
+				errs = append(errs, fmt.Errorf("recourse: warm %s: %w", key, err))
+				continue
+			}
+		}
+		e.getTracker(key)
+		if pol.Circuit.Enabled && !pol.Circuit.PerTenant {
+			e.circuits.Get(key, pol.Circuit)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestExecutor_Warm(t *testing.T) {
+	key := policy.ParseKey("svc.Warm")
+	pol := policy.New(key.String(), policy.MaxAttempts(2))
+	pol.Circuit = policy.CircuitPolicy{Enabled: true, Threshold: 3, Cooldown: time.Second}
+	source := controlplane.NewFakeSource(controlplane.FakeStep{Policy: pol})
+	exec := NewExecutor(WithProvider(controlplane.NewRemoteProvider(source, controlplane.WithCacheTTL(time.Minute))))
+
+	if err := exec.Warm(context.Background(), key); err != nil {
+		t.Fatalf("Warm: %v", err)
+	}
+	if source.Calls() != 1 {
+		t.Fatalf("source calls=%d after Warm, want 1", source.Calls())
+	}
+	if _, ok := exec.trackers[key]; !ok {
+		t.Fatal("Warm did not create the latency tracker")
+	}
+
+	if err := exec.Do(context.Background(), key, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if source.Calls() != 1 {
+		t.Fatalf("source calls=%d after Do, want the warmed policy to be cached", source.Calls())
+	}
+}
+
+func TestExecutor_Warm_ReportsFailedKeys(t *testing.T) {
+	good, bad := policy.ParseKey("svc.Good"), policy.ParseKey("svc.Bad")
+	exec := NewExecutor(WithProvider(stubProvider{err: controlplane.ErrPolicyNotFound}))
+
+	err := exec.Warm(context.Background(), bad)
+	if !errors.Is(err, ErrNoPolicy) {
+		t.Fatalf("err=%v, want ErrNoPolicy", err)
+	}
+
+	exec = NewExecutor(WithPolicy(good.String()))
+	if err := exec.Warm(context.Background(), good, bad); err != nil {
+		t.Fatalf("err=%v, want static defaults to resolve every key", err)
+	}
+
+	_ = exec.Close(context.Background())
+	if err := exec.Warm(context.Background(), good); !errors.Is(err, ErrExecutorClosed) {
+		t.Fatalf("err=%v, want ErrExecutorClosed", err)
+	}
+}
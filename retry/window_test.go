@@ -0,0 +1,49 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/policy"
+)
+
+func TestPolicyWindow_SelectedByClock(t *testing.T) {
+	key := policy.ParseKey("svc.windowed")
+	pol := policy.New(key.String(),
+		policy.MaxAttempts(4),
+		policy.Window(policy.PolicyWindow{Name: "peak", Start: "09:00", End: "17:00", Retry: policy.RetryPolicy{MaxAttempts: 1}}),
+	)
+
+	cases := []struct {
+		name     string
+		now      time.Time
+		window   string
+		attempts int
+	}{
+		{"in window", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), "peak", 1},
+		{"outside window", time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC), "", 4},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			exec := newTestExecutor(t, key, pol)
+			exec.clock = func() time.Time { return tc.now }
+
+			_, tl, err := doWithTimeline(context.Background(), exec, key, func(context.Context) (struct{}, error) {
+				return struct{}{}, errors.New("boom")
+			})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tl.Attributes["policy_window"] != tc.window || len(tl.Attempts) != tc.attempts {
+				t.Fatalf("window=%q attempts=%d, want %q/%d", tl.Attributes["policy_window"], len(tl.Attempts), tc.window, tc.attempts)
+			}
+
+			fast, err := resolvePolicyFast(context.Background(), exec, key)
+			if err != nil || fast.Retry.MaxAttempts != tc.attempts {
+				t.Fatalf("fast path maxAttempts=%d err=%v", fast.Retry.MaxAttempts, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/aponysus/recourse/controlplane"
+	"github.com/aponysus/recourse/policy"
+)
+
+// With returns a derived executor that shares e's provider, registries and
+// options, with opts applied on top. Use it to give a subsystem its own
+// observer or failure modes without re-wiring a base executor.
+//
+// State held in registries (budgets, circuit breakers, bulkheads, rate
+// limiters) is shared with e. Per-key state kept by the executor itself, such
+// as latency trackers, pushback cooldowns and cached results, starts empty.
+// Options that add to a registry, such as WithBudget, add to the shared
+// registry, except WithClassifier, which adds to a copy of the classifier
+// registry. Static policies added with WithPolicy take precedence over e's
+// provider.
+func (e *Executor) With(opts ...ExecutorOption) *Executor {
+	if e == nil {
+		return NewExecutor(opts...)
+	}
+	e.ensureInitialized()
+
+	cfg := &executorConfig{opts: e.options(), sharedClassifiers: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if len(cfg.staticPolicies) > 0 {
+		cfg.opts.Provider = &layeredProvider{
+			static: controlplane.StaticProvider{Policies: cfg.staticPolicies},
+			base:   cfg.opts.Provider,
+		}
+	}
+
+	derived := NewExecutorFromOptions(cfg.opts)
+	if cfg.opts.JitterSeed == 0 && cfg.opts.Rand == nil {
+		derived.jitter = e.jitter
+	}
+	return derived
+}
+
+// options returns the options e was built with, after defaults.
+func (e *Executor) options() ExecutorOptions {
+	return ExecutorOptions{
+		Provider:                e.provider,
+		Observer:                e.observer,
+		Clock:                   e.clock,
+		Classifiers:             e.classifiers,
+		DefaultClassifier:       e.defaultClassifier,
+		Budgets:                 e.budgets,
+		Triggers:                e.triggers,
+		Circuits:                e.circuits,
+		MissingPolicyMode:       e.missingPolicyMode,
+		MissingClassifierMode:   e.missingClassifierMode,
+		MissingBudgetMode:       e.missingBudgetMode,
+		MissingTriggerMode:      e.missingTriggerMode,
+		RecoverPanics:           e.recoverPanics,
+		RetryPanics:             e.retryPanics,
+		RateLimiters:            e.rateLimiters,
+		MissingRateLimiterMode:  e.missingLimiterMode,
+		Fallbacks:               e.fallbacks,
+		Jitters:                 e.jitters,
+		MissingJitterMode:       e.missingJitterMode,
+		Bulkheads:               e.bulkheads,
+		Sleep:                   e.sleep,
+		AttemptMiddleware:       e.attemptMiddleware[:len(e.attemptMiddleware):len(e.attemptMiddleware)],
+		PoolTimelines:           e.poolTimelines,
+		FaultInjection:          e.faultInjection,
+		RequireIdempotentHedges: e.requireIdempotent,
+		MaxConcurrentHedges:     e.maxConcurrentHedges,
+		Errors:                  e.namedErrors,
+		DefaultPolicy:           e.defaultPolicy,
+		MinPolicyVersion:        e.minPolicyVersion,
+		PinnedPolicyVersion:     e.pinnedPolicyVersion,
+	}
+}
+
+// layeredProvider serves static policies added to a derived executor and
+// defers every other key to the base executor's provider.
+type layeredProvider struct {
+	static controlplane.StaticProvider
+	base   controlplane.PolicyProvider
+}
+
+func (p *layeredProvider) GetEffectivePolicy(ctx context.Context, key policy.PolicyKey) (policy.EffectivePolicy, error) {
+	if _, ok := p.static.Policies[key]; ok {
+		return p.static.GetEffectivePolicy(ctx, key)
+	}
+	return p.base.GetEffectivePolicy(ctx, key)
+}
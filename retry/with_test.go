@@ -0,0 +1,86 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aponysus/recourse/observe"
+	"github.com/aponysus/recourse/policy"
+)
+
+type startCountingObserver struct {
+	observe.BaseObserver
+	starts int
+}
+
+func (o *startCountingObserver) OnStart(context.Context, policy.PolicyKey, policy.EffectivePolicy) {
+	o.starts++
+}
+
+func TestExecutorWith_SharesProviderAndRegistries(t *testing.T) {
+	baseObs := &startCountingObserver{}
+	base := NewExecutor(
+		WithPolicy("svc.Base", policy.MaxAttempts(2)),
+		WithObserver(baseObs),
+	)
+	base.sleep = func(context.Context, time.Duration) error { return nil }
+
+	derivedObs := &startCountingObserver{}
+	derived := base.With(
+		WithObserver(derivedObs),
+		WithPolicy("svc.Derived", policy.MaxAttempts(1)),
+		WithMissingPolicyMode(FailureAllow),
+	)
+
+	if derived.classifiers != base.classifiers || derived.circuits != base.circuits || derived.bulkheads != base.bulkheads {
+		t.Fatal("expected derived executor to share registries")
+	}
+
+	errBoom := errors.New("boom")
+	calls := 0
+	op := func(context.Context) error {
+		calls++
+		return errBoom
+	}
+
+	if err := derived.Do(context.Background(), policy.ParseKey("svc.Base"), op); !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2 from the base provider's policy", calls)
+	}
+
+	calls = 0
+	if err := derived.Do(context.Background(), policy.ParseKey("svc.Derived"), op); !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1 from the derived static policy", calls)
+	}
+
+	if derivedObs.starts != 2 || baseObs.starts != 0 {
+		t.Fatalf("starts derived=%d base=%d, want 2 and 0", derivedObs.starts, baseObs.starts)
+	}
+	if derived.missingPolicyMode != FailureAllow || base.missingPolicyMode != FailureDeny {
+		t.Fatalf("missing policy mode derived=%v base=%v", derived.missingPolicyMode, base.missingPolicyMode)
+	}
+}
+
+func TestExecutorWith_DoesNotMutateBaseMiddleware(t *testing.T) {
+	noop := func(ctx context.Context, _ *Attempt, next AttemptHandler) (any, error) {
+		return next(ctx)
+	}
+	base := NewExecutor(WithAttemptMiddleware(noop, noop))
+	derived := base.With(WithAttemptMiddleware(noop))
+
+	if len(base.attemptMiddleware) != 2 || len(derived.attemptMiddleware) != 3 {
+		t.Fatalf("middleware base=%d derived=%d, want 2 and 3", len(base.attemptMiddleware), len(derived.attemptMiddleware))
+	}
+
+	other := base.With(WithAttemptMiddleware(noop))
+	if &other.attemptMiddleware[2] == &derived.attemptMiddleware[2] {
+		t.Fatal("derived executors share middleware backing arrays")
+	}
+}
@@ -86,6 +86,14 @@ func generateReasonCodes(root, outPath string) error {
 	if err != nil {
 		return err
 	}
+	rateLimitReasons, err := collectReasonConsts(filepath.Join(root, "ratelimit", "reasons.go"))
+	if err != nil {
+		return err
+	}
+	bulkheadReasons, err := collectReasonConsts(filepath.Join(root, "bulkhead", "reasons.go"))
+	if err != nil {
+		return err
+	}
 
 	outcomeReasons := newReasonSet()
 	paths := []string{
@@ -125,12 +133,12 @@ func generateReasonCodes(root, outPath string) error {
 		modeReasons[m] = struct{}{}
 	}
 
-	structs, err := collectStructFields(filepath.Join(root, "observe", "types.go"), []string{"Timeline", "AttemptRecord", "BudgetDecisionEvent"})
+	structs, err := collectStructFields(filepath.Join(root, "observe", "types.go"), []string{"Timeline", "AttemptRecord", "BudgetDecisionEvent", "RateLimitDecisionEvent", "BulkheadDecisionEvent", "HedgeSuppressedEvent"})
 	if err != nil {
 		return err
 	}
 
-	content, err := renderReasonsMarkdown(budgetReasons, circuitReasons, outcomeReasons, modeReasons, structs)
+	content, err := renderReasonsMarkdown(budgetReasons, circuitReasons, rateLimitReasons, bulkheadReasons, outcomeReasons, modeReasons, structs)
 	if err != nil {
 		return err
 	}
@@ -148,9 +156,18 @@ func generatePolicySchema(root, outPath string) error {
 
 	schemaStructs, err := collectStructFields(filepath.Join(root, "policy", "schema.go"), []string{
 		"BudgetRef",
+		"RateLimitRef",
 		"RetryPolicy",
+		"RetryRule",
+		"ErrorMatcher",
+		"ReasonOverride",
+		"PolicyWindow",
 		"HedgePolicy",
 		"CircuitPolicy",
+		"ConcurrencyPolicy",
+		"CachePolicy",
+		"FaultInjectionPolicy",
+		"FallbackPolicy",
 		"NormalizationInfo",
 		"Metadata",
 		"EffectivePolicy",
@@ -642,11 +659,25 @@ func collectExecutorModeDefaults(path string) (map[string]string, error) {
 		return nil, err
 	}
 	out := make(map[string]string)
-	fn := funcDeclByName(f, "NewExecutorFromOptions")
+	fn := funcDeclByName(f, "applyDefaults")
 	if fn == nil {
 		return out, nil
 	}
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if as, ok := n.(*ast.AssignStmt); ok {
+			if len(as.Lhs) != 1 || len(as.Rhs) != 1 {
+				return true
+			}
+			switch field := selectorOnIdent(as.Lhs[0], "e"); field {
+			case "missingPolicyMode", "missingClassifierMode", "missingBudgetMode", "missingTriggerMode", "missingLimiterMode":
+				def := defaultFailureMode(as.Rhs[0])
+				if def == "" {
+					def = exprString(as.Rhs[0])
+				}
+				out[field] = def
+			}
+			return true
+		}
 		cl, ok := n.(*ast.CompositeLit)
 		if !ok {
 			return true
@@ -685,7 +716,7 @@ func collectExecutorFallbacks(path string) (executorFallbacks, error) {
 		return executorFallbacks{}, err
 	}
 	out := executorFallbacks{Defaults: make(map[string]string)}
-	fn := funcDeclByName(f, "NewExecutorFromOptions")
+	fn := funcDeclByName(f, "applyDefaults")
 	if fn == nil || fn.Body == nil {
 		return out, nil
 	}
@@ -998,13 +1029,13 @@ func joinComments(groups ...*ast.CommentGroup) string {
 	return strings.Join(parts, " ")
 }
 
-func renderReasonsMarkdown(budgetReasons, circuitReasons []string, outcome reasonSet, modes map[string]struct{}, structs map[string][]structField) ([]byte, error) {
+func renderReasonsMarkdown(budgetReasons, circuitReasons, rateLimitReasons, bulkheadReasons []string, outcome reasonSet, modes map[string]struct{}, structs map[string][]structField) ([]byte, error) {
 	var buf bytes.Buffer
 
 	buf.WriteString("<!-- Generated by scripts/gen_reference.go; do not edit by hand. -->\n")
 	buf.WriteString("# Reason codes and timeline fields\n\n")
 
-	buf.WriteString("Generated from: `budget/reasons.go`, `circuit/types.go`, `classify/`, `retry/`, `integrations/grpc/grpc.go`, `observe/types.go`.\n\n")
+	buf.WriteString("Generated from: `budget/reasons.go`, `circuit/types.go`, `ratelimit/reasons.go`, `bulkhead/reasons.go`, `classify/`, `retry/`, `integrations/grpc/grpc.go`, `observe/types.go`.\n\n")
 	buf.WriteString("These reason codes and timeline fields are part of the v1 telemetry contract. Changes are breaking.\n\n")
 
 	buf.WriteString("## Outcome reasons\n\n")
@@ -1042,8 +1073,22 @@ func renderReasonsMarkdown(budgetReasons, circuitReasons []string, outcome reaso
 	}
 	buf.WriteString("\n")
 
+	buf.WriteString("## Rate limit reasons\n\n")
+	buf.WriteString("These values appear in `observe.RateLimitDecisionEvent.Reason` and on `retry.RateLimitedError.Reason`.\n\n")
+	for _, reason := range rateLimitReasons {
+		buf.WriteString("- `" + reason + "`\n")
+	}
+	buf.WriteString("\n")
+
+	buf.WriteString("## Bulkhead reasons\n\n")
+	buf.WriteString("These values appear in `observe.BulkheadDecisionEvent.Reason` and on `retry.BulkheadFullError.Reason`.\n\n")
+	for _, reason := range bulkheadReasons {
+		buf.WriteString("- `" + reason + "`\n")
+	}
+	buf.WriteString("\n")
+
 	buf.WriteString("## Budget decision modes\n\n")
-	buf.WriteString("These values appear in `observe.BudgetDecisionEvent.Mode`.\n\n")
+	buf.WriteString("These values appear in `observe.BudgetDecisionEvent.Mode` and `observe.RateLimitDecisionEvent.Mode`.\n\n")
 	for _, mode := range setToSorted(modes) {
 		buf.WriteString("- `" + mode + "`\n")
 	}
@@ -1053,6 +1098,9 @@ func renderReasonsMarkdown(budgetReasons, circuitReasons []string, outcome reaso
 	writeStruct(&buf, "Timeline", structs["Timeline"])
 	writeStruct(&buf, "AttemptRecord", structs["AttemptRecord"])
 	writeStruct(&buf, "BudgetDecisionEvent", structs["BudgetDecisionEvent"])
+	writeStruct(&buf, "RateLimitDecisionEvent", structs["RateLimitDecisionEvent"])
+	writeStruct(&buf, "BulkheadDecisionEvent", structs["BulkheadDecisionEvent"])
+	writeStruct(&buf, "HedgeSuppressedEvent", structs["HedgeSuppressedEvent"])
 
 	return buf.Bytes(), nil
 }
@@ -1067,9 +1115,18 @@ func renderPolicySchemaMarkdown(structs map[string][]structField, defaults map[s
 	buf.WriteString("## Types\n\n")
 	writeStructWithTags(&buf, "policy.PolicyKey", structs["PolicyKey"])
 	writeStructWithTags(&buf, "policy.BudgetRef", structs["BudgetRef"])
+	writeStructWithTags(&buf, "policy.RateLimitRef", structs["RateLimitRef"])
 	writeStructWithTags(&buf, "policy.RetryPolicy", structs["RetryPolicy"])
+	writeStructWithTags(&buf, "policy.RetryRule", structs["RetryRule"])
+	writeStructWithTags(&buf, "policy.ErrorMatcher", structs["ErrorMatcher"])
+	writeStructWithTags(&buf, "policy.ReasonOverride", structs["ReasonOverride"])
+	writeStructWithTags(&buf, "policy.PolicyWindow", structs["PolicyWindow"])
 	writeStructWithTags(&buf, "policy.HedgePolicy", structs["HedgePolicy"])
 	writeStructWithTags(&buf, "policy.CircuitPolicy", structs["CircuitPolicy"])
+	writeStructWithTags(&buf, "policy.ConcurrencyPolicy", structs["ConcurrencyPolicy"])
+	writeStructWithTags(&buf, "policy.CachePolicy", structs["CachePolicy"])
+	writeStructWithTags(&buf, "policy.FaultInjectionPolicy", structs["FaultInjectionPolicy"])
+	writeStructWithTags(&buf, "policy.FallbackPolicy", structs["FallbackPolicy"])
 	writeStructWithTags(&buf, "policy.NormalizationInfo", structs["NormalizationInfo"])
 	writeStructWithTags(&buf, "policy.Metadata", structs["Metadata"])
 	writeStructWithTags(&buf, "policy.EffectivePolicy", structs["EffectivePolicy"])
@@ -1188,7 +1245,7 @@ func renderDefaultsSafetyMarkdown(defaults, limits map[string]string, fallbacks
 	buf.WriteString("\n")
 
 	buf.WriteString("## Default failure modes\n\n")
-	buf.WriteString("Failure modes control what happens when policy, budgets, classifiers, or triggers are missing.\n\n")
+	buf.WriteString("Failure modes control what happens when policy, budgets, classifiers, triggers, or rate limiters are missing.\n\n")
 	buf.WriteString("| Missing mode | Default | Notes |\n")
 	buf.WriteString("|---|---|---|\n")
 	modeRows := []struct {
@@ -1200,9 +1257,10 @@ func renderDefaultsSafetyMarkdown(defaults, limits map[string]string, fallbacks
 		{"MissingBudgetMode", "missingBudgetMode", "Missing or invalid budgets deny attempts."},
 		{"MissingClassifierMode", "missingClassifierMode", "Fallback to the default classifier."},
 		{"MissingTriggerMode", "missingTriggerMode", "-"},
+		{"MissingRateLimiterMode", "missingLimiterMode", "Missing or invalid rate limiters reject calls."},
 	}
 	if hedgeFallback {
-		modeRows[3].Note = "Missing trigger falls back to fixed delay hedging."
+		modeRows[3].Note = "Missing trigger falls back to fixed delay hedging; `FailureDeny` fails the call (`NoTriggerError`)."
 	}
 	for _, row := range modeRows {
 		value := modeDefaults[row.Field]